@@ -515,7 +515,8 @@ func (c *ReplicaConfig) toInternalReplicaConfigWithOriginConfig(
 	}
 	if c.Mounter != nil {
 		res.Mounter = &config.MounterConfig{
-			WorkerNum: c.Mounter.WorkerNum,
+			WorkerNum:      c.Mounter.WorkerNum,
+			FetchBatchSize: c.Mounter.FetchBatchSize,
 		}
 	}
 	if c.Scheduler != nil {
@@ -851,7 +852,8 @@ func ToAPIReplicaConfig(c *config.ReplicaConfig) *ReplicaConfig {
 
 	if cloned.Mounter != nil {
 		res.Mounter = &MounterConfig{
-			WorkerNum: cloned.Mounter.WorkerNum,
+			WorkerNum:      cloned.Mounter.WorkerNum,
+			FetchBatchSize: cloned.Mounter.FetchBatchSize,
 		}
 	}
 	if cloned.Scheduler != nil {
@@ -896,7 +898,8 @@ type FilterConfig struct {
 
 // MounterConfig represents mounter config for a changefeed
 type MounterConfig struct {
-	WorkerNum int `json:"worker_num"`
+	WorkerNum      int  `json:"worker_num"`
+	FetchBatchSize *int `json:"fetch_batch_size,omitempty"`
 }
 
 // EventFilterRule is used by sql event filter and expression filter
@@ -390,6 +390,12 @@ func (s *Snapshot) IsIneligibleTableID(id int64) bool {
 	return s.inner.isIneligibleTableID(id)
 }
 
+// IsEligibleTable returns true if the table is eligible with the
+// force-replicate setting of the snapshot, the table needn't be in it.
+func (s *Snapshot) IsEligibleTable(tbInfo *model.TableInfo) bool {
+	return tbInfo.IsEligible(s.inner.forceReplicate)
+}
+
 // HandleDDL handles the given job.
 func (s *Snapshot) HandleDDL(job *timodel.Job) error {
 	if err := s.FillSchemaName(job); err != nil {
@@ -147,6 +147,8 @@ type CDCKVClient interface {
 
 	// RegionCount returns the number of captured regions.
 	RegionCount() uint64
+	// InitializedRegionCount returns the number of captured regions which have been initialized.
+	InitializedRegionCount() uint64
 	// ResolvedTs returns the current ingress resolved ts.
 	ResolvedTs() model.Ts
 	// CommitTs returns the current ingress commit ts.
@@ -178,6 +180,12 @@ type CDCClient struct {
 		v map[string]*tableStoreStat
 	}
 
+	// rangeLocks are the range locks of running event feed sessions.
+	rangeLocks struct {
+		sync.RWMutex
+		v map[*regionlock.RegionRangeLock]struct{}
+	}
+
 	// filterLoop is used in BDR mode, when it is true, tikv cdc component
 	// will filter data that are written by another TiCDC.
 	filterLoop bool
@@ -218,6 +226,7 @@ func NewCDCClient(
 		filterLoop: filterLoop,
 	}
 	c.tableStoreStats.v = make(map[string]*tableStoreStat)
+	c.rangeLocks.v = make(map[*regionlock.RegionRangeLock]struct{})
 	return c
 }
 
@@ -277,9 +286,27 @@ func (c *CDCClient) EventFeed(
 	eventCh chan<- model.RegionFeedEvent,
 ) error {
 	s := newEventFeedSession(c, span, lockResolver, ts, eventCh)
+	c.rangeLocks.Lock()
+	c.rangeLocks.v[s.rangeLock] = struct{}{}
+	c.rangeLocks.Unlock()
+	defer func() {
+		c.rangeLocks.Lock()
+		delete(c.rangeLocks.v, s.rangeLock)
+		c.rangeLocks.Unlock()
+	}()
 	return s.eventFeed(ctx)
 }
 
+// InitializedRegionCount returns the number of captured regions which have been initialized.
+func (c *CDCClient) InitializedRegionCount() (count uint64) {
+	c.rangeLocks.RLock()
+	defer c.rangeLocks.RUnlock()
+	for rangeLock := range c.rangeLocks.v {
+		count += rangeLock.InitializedCount()
+	}
+	return count
+}
+
 // RegionCount returns the number of captured regions.
 func (c *CDCClient) RegionCount() (totalCount uint64) {
 	c.tableStoreStats.RLock()
@@ -562,6 +562,20 @@ func (l *RegionRangeLock) RefCount() uint64 {
 	return uint64(len(l.regionIDLock))
 }
 
+// InitializedCount returns how many locked ranges have been initialized,
+// which means the incremental scan of the region has finished.
+func (l *RegionRangeLock) InitializedCount() (count uint64) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.rangeLock.Ascend(func(item *rangeLockEntry) bool {
+		if item.state.Initialzied.Load() {
+			count++
+		}
+		return true
+	})
+	return
+}
+
 // Stop stops the instance.
 func (l *RegionRangeLock) Stop() (drained bool) {
 	l.mu.Lock()
@@ -338,3 +338,23 @@ func BenchmarkOneMillionRegions(b *testing.B) {
 		l.CalculateMinResolvedTs()
 	}
 }
+
+func TestRegionRangeLockInitializedCount(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	l := NewRegionRangeLock(1, []byte("a"), []byte("h"), math.MaxUint64, "")
+	res1 := l.LockRange(ctx, []byte("a"), []byte("c"), 1, 1)
+	require.Equal(t, LockRangeStatusSuccess, res1.Status)
+	res2 := l.LockRange(ctx, []byte("c"), []byte("e"), 2, 1)
+	require.Equal(t, LockRangeStatusSuccess, res2.Status)
+	require.Equal(t, uint64(0), l.InitializedCount())
+
+	res1.LockedRange.Initialzied.Store(true)
+	require.Equal(t, uint64(1), l.InitializedCount())
+	res2.LockedRange.Initialzied.Store(true)
+	require.Equal(t, uint64(2), l.InitializedCount())
+
+	l.UnlockRange([]byte("a"), []byte("c"), 1, 1)
+	require.Equal(t, uint64(1), l.InitializedCount())
+}
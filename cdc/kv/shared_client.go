@@ -231,6 +231,16 @@ func (s *SharedClient) RegionCount(subID SubscriptionID) uint64 {
 	return 0
 }
 
+// InitializedRegionCount returns the number of initialized regions for the subscription.
+func (s *SharedClient) InitializedRegionCount(subID SubscriptionID) uint64 {
+	s.totalSpans.RLock()
+	defer s.totalSpans.RUnlock()
+	if rt := s.totalSpans.v[subID]; rt != nil {
+		return rt.rangeLock.InitializedCount()
+	}
+	return 0
+}
+
 // Run the client.
 func (s *SharedClient) Run(ctx context.Context) error {
 	s.clusterID = s.pd.GetClusterID(ctx)
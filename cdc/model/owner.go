@@ -75,6 +75,21 @@ type DDLJobEntry struct {
 	OpType OpType
 	CRTs   uint64
 	Err    error
+	// QueryDigest is the normalized digest of the query of Job, it's only
+	// set when the DDL puller is configured to compute it.
+	QueryDigest string
+	// RenamedTables is the tables renamed by Job if it's a rename tables job,
+	// the filtered out tables are excluded. It's nil for other jobs.
+	RenamedTables []RenamedTable
+}
+
+// RenamedTable is a table renamed by a rename tables DDL job.
+type RenamedTable struct {
+	TableID   int64
+	OldSchema string
+	OldTable  string
+	NewSchema string
+	NewTable  string
 }
 
 // TaskPosition records the process information of a capture
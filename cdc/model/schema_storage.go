@@ -14,7 +14,10 @@
 package model
 
 import (
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"strings"
 
 	"github.com/pingcap/log"
@@ -55,6 +58,8 @@ type TableInfo struct {
 
 	// Column name -> ColumnID
 	nameToColID map[string]int64
+	// lower case column name -> ColumnID, including the columns invisible to CDC
+	lowerNameToColID map[string]int64
 
 	hasUniqueColumn bool
 
@@ -84,6 +89,8 @@ type TableInfo struct {
 	// TableInfo.Columns order: a, b, c, d
 	// VirtualColumnsOffset will be [2, 3] (indices of virtual columns c and d)
 	VirtualColumnsOffset []int
+	// virtualColumnsOffsetSet is the set of VirtualColumnsOffset for fast lookup
+	virtualColumnsOffsetSet map[int]struct{}
 }
 
 // WrapTableInfo creates a TableInfo from a timodel.TableInfo
@@ -101,6 +108,7 @@ func WrapTableInfo(schemaID int64, schemaName string, version uint64, info *mode
 		Version:          version,
 		columnsOffset:    make(map[int64]int, len(info.Columns)),
 		nameToColID:      make(map[string]int64, len(info.Columns)),
+		lowerNameToColID: make(map[string]int64, len(info.Columns)),
 		indicesOffset:    make(map[int64]int, len(info.Indices)),
 		RowColumnsOffset: make(map[int64]int, len(info.Columns)),
 		ColumnsFlag:      make(map[int64]ColumnFlagType, len(info.Columns)),
@@ -114,6 +122,7 @@ func WrapTableInfo(schemaID int64, schemaName string, version uint64, info *mode
 
 	for i, col := range ti.Columns {
 		ti.columnsOffset[col.ID] = i
+		ti.lowerNameToColID[col.Name.L] = col.ID
 		pkIsHandle := false
 		if IsColCDCVisible(col) {
 			ti.nameToColID[col.Name.O] = col.ID
@@ -137,6 +146,10 @@ func WrapTableInfo(schemaID int64, schemaName string, version uint64, info *mode
 			}
 		} else {
 			ti.VirtualColumnsOffset = append(ti.VirtualColumnsOffset, i)
+			if ti.virtualColumnsOffsetSet == nil {
+				ti.virtualColumnsOffsetSet = make(map[int]struct{})
+			}
+			ti.virtualColumnsOffsetSet[i] = struct{}{}
 		}
 		ti.rowColInfos[i] = rowcodec.ColInfo{
 			ID:            col.ID,
@@ -362,6 +375,28 @@ func (ti *TableInfo) IndexByName(name string) ([]string, []int, bool) {
 	return names, offset, true
 }
 
+// IsVirtualColumn returns whether the column at the offset of TableInfo.Columns
+// is a virtual column, which is invisible to CDC.
+func (ti *TableInfo) IsVirtualColumn(offset int) bool {
+	_, ok := ti.virtualColumnsOffsetSet[offset]
+	return ok
+}
+
+// HasVirtualColumns returns whether the table has any virtual column.
+func (ti *TableInfo) HasVirtualColumns() bool {
+	return len(ti.VirtualColumnsOffset) > 0
+}
+
+// GetIndexInfo returns the index info by index ID
+func (ti *TableInfo) GetIndexInfo(indexID int64) (*model.IndexInfo, bool) {
+	for _, index := range ti.Indices {
+		if index.ID == indexID {
+			return index, true
+		}
+	}
+	return nil, false
+}
+
 // OffsetsByNames returns the column offsets of the corresponding columns by names
 // If any column does not exist, return false
 // Column is not case-sensitive on any platform, nor are column aliases.
@@ -388,9 +423,32 @@ func (ti *TableInfo) OffsetsByNames(names []string) ([]int, bool) {
 	return result, true
 }
 
-// GetPrimaryKeyColumnNames returns the primary key column names
+// ColumnIDByName returns the column ID of the corresponding column by name.
+// Column name is not case-sensitive, the same as OffsetsByNames.
+func (ti *TableInfo) ColumnIDByName(name string) (int64, bool) {
+	colID, ok := ti.lowerNameToColID[strings.ToLower(name)]
+	return colID, ok
+}
+
+// ColumnIDsByNames returns the column IDs of the corresponding columns by names
+// If any column does not exist, return false
+func (ti *TableInfo) ColumnIDsByNames(names []string) ([]int64, bool) {
+	result := make([]int64, 0, len(names))
+	for _, name := range names {
+		colID, ok := ti.ColumnIDByName(name)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, colID)
+	}
+	return result, true
+}
+
+// GetPrimaryKeyColumnNames returns the primary key column names in index order.
+// If there is no primary key, the first not null unique index is used, the same
+// as MySQL. It returns an empty slice if no such index exists.
 func (ti *TableInfo) GetPrimaryKeyColumnNames() []string {
-	var result []string
+	result := make([]string, 0)
 	if ti.PKIsHandle {
 		result = append(result, ti.GetPkColInfo().Name.O)
 		return result
@@ -405,6 +463,123 @@ func (ti *TableInfo) GetPrimaryKeyColumnNames() []string {
 	return result
 }
 
+// HandleKeyColumnIDs returns the IDs of the columns which make up the handle key,
+// that is the int primary key, the columns of the clustered primary key, or the
+// columns of the chosen not null unique index. It returns nil if the table is
+// not eligible.
+func (ti *TableInfo) HandleKeyColumnIDs() []int64 {
+	switch {
+	case ti.HandleIndexID == HandleIndexTableIneligible:
+		return nil
+	case ti.HandleIndexID == HandleIndexPKIsHandle:
+		// handleColID holds the int primary key when ti.PKIsHandle,
+		// or the columns of clustered index when ti.IsCommonHandle.
+		return append([]int64(nil), ti.handleColID...)
+	}
+	offset, ok := ti.indicesOffset[ti.HandleIndexID]
+	if !ok {
+		return nil
+	}
+	idx := ti.Indices[offset]
+	result := make([]int64, 0, len(idx.Columns))
+	for _, col := range idx.Columns {
+		result = append(result, ti.Columns[col.Offset].ID)
+	}
+	return result
+}
+
+// IsHandleKeyColumn returns whether the column is a part of the handle key.
+func (ti *TableInfo) IsHandleKeyColumn(colID int64) bool {
+	for _, id := range ti.HandleKeyColumnIDs() {
+		if id == colID {
+			return true
+		}
+	}
+	return false
+}
+
+// OnUpdateTimestampColumns returns the columns with the
+// `ON UPDATE CURRENT_TIMESTAMP` attribute, in the order of the table columns.
+func (ti *TableInfo) OnUpdateTimestampColumns() []*model.ColumnInfo {
+	var result []*model.ColumnInfo
+	for _, col := range ti.Columns {
+		if mysql.HasOnUpdateNowFlag(col.GetFlag()) {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
+// SelectStarColumns returns the columns a `SELECT *` on the table returns, in
+// the same order. Hidden columns and columns not in public state, such as the
+// ones being added or dropped, are skipped.
+func (ti *TableInfo) SelectStarColumns() []*model.ColumnInfo {
+	result := make([]*model.ColumnInfo, 0, len(ti.Columns))
+	for _, col := range ti.Columns {
+		if col.Hidden || col.State != model.StatePublic {
+			continue
+		}
+		result = append(result, col)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Offset < result[j].Offset
+	})
+	return result
+}
+
+// SchemaFingerprint returns a stable hash of the columns and indices of the
+// table, which only changes when the column names, types, flags or the index
+// definitions change. Volatile fields, such as the version, are ignored.
+func (ti *TableInfo) SchemaFingerprint() uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+	writeInt := func(v int64) {
+		binary.BigEndian.PutUint64(buf, uint64(v))
+		_, _ = h.Write(buf)
+	}
+	// strings are prefixed with their length to avoid ambiguity
+	writeString := func(s string) {
+		writeInt(int64(len(s)))
+		_, _ = h.Write([]byte(s))
+	}
+
+	writeInt(int64(len(ti.Columns)))
+	for _, col := range ti.Columns {
+		writeString(col.Name.O)
+		writeInt(int64(col.GetType()))
+		writeInt(int64(col.GetFlag()))
+		writeInt(int64(col.GetFlen()))
+		writeInt(int64(col.GetDecimal()))
+		writeString(col.GetCharset())
+		writeString(col.GetCollate())
+		elems := col.GetElems()
+		writeInt(int64(len(elems)))
+		for _, elem := range elems {
+			writeString(elem)
+		}
+		writeString(col.GeneratedExprString)
+	}
+
+	writeInt(int64(len(ti.Indices)))
+	for _, idx := range ti.Indices {
+		writeString(idx.Name.O)
+		var flag int64
+		if idx.Primary {
+			flag |= 1
+		}
+		if idx.Unique {
+			flag |= 2
+		}
+		writeInt(flag)
+		writeInt(int64(len(idx.Columns)))
+		for _, col := range idx.Columns {
+			writeString(col.Name.O)
+			writeInt(int64(col.Length))
+		}
+	}
+	return h.Sum64()
+}
+
 // GetSchemaName returns the schema name of the table
 func (ti *TableInfo) GetSchemaName() string {
 	return ti.TableName.Schema
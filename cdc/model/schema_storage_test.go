@@ -124,6 +124,92 @@ func TestHandleKeyPriority(t *testing.T) {
 	require.Equal(t, int64(8), info.HandleIndexID)
 }
 
+func TestHandleKeyColumnIDs(t *testing.T) {
+	t.Parallel()
+
+	ftPK := parser_types.NewFieldType(mysql.TypeLong)
+	ftPK.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	ftNotNull := parser_types.NewFieldType(mysql.TypeLong)
+	ftNotNull.SetFlag(mysql.NotNullFlag)
+	ftNull := parser_types.NewFieldType(mysql.TypeLong)
+
+	// int primary key is handle
+	tbl := &timodel.TableInfo{
+		Name: timodel.NewCIStr("t1"),
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("id"), Offset: 0, FieldType: *ftPK, State: timodel.StatePublic},
+			{ID: 2, Name: timodel.NewCIStr("a"), Offset: 1, FieldType: *ftNotNull, State: timodel.StatePublic},
+		},
+		PKIsHandle: true,
+	}
+	info := WrapTableInfo(1, "test", 0, tbl)
+	require.Equal(t, []int64{1}, info.HandleKeyColumnIDs())
+	require.True(t, info.IsHandleKeyColumn(1))
+	require.False(t, info.IsHandleKeyColumn(2))
+
+	// clustered primary key is handle
+	tbl = &timodel.TableInfo{
+		Name: timodel.NewCIStr("t2"),
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("a"), Offset: 0, FieldType: *ftNotNull, State: timodel.StatePublic},
+			{ID: 2, Name: timodel.NewCIStr("b"), Offset: 1, FieldType: *ftPK, State: timodel.StatePublic},
+			{ID: 3, Name: timodel.NewCIStr("c"), Offset: 2, FieldType: *ftPK, State: timodel.StatePublic},
+		},
+		Indices: []*timodel.IndexInfo{
+			{
+				ID:   1,
+				Name: timodel.NewCIStr("primary"),
+				Columns: []*timodel.IndexColumn{
+					{Name: timodel.NewCIStr("c"), Offset: 2},
+					{Name: timodel.NewCIStr("b"), Offset: 1},
+				},
+				Primary: true,
+				Unique:  true,
+			},
+		},
+		IsCommonHandle: true,
+	}
+	info = WrapTableInfo(1, "test", 0, tbl)
+	require.Equal(t, []int64{3, 2}, info.HandleKeyColumnIDs())
+	require.False(t, info.IsHandleKeyColumn(1))
+	require.True(t, info.IsHandleKeyColumn(2))
+	require.True(t, info.IsHandleKeyColumn(3))
+
+	// fallback to the not null unique index
+	tbl = &timodel.TableInfo{
+		Name: timodel.NewCIStr("t3"),
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("a"), Offset: 0, FieldType: *ftNull, State: timodel.StatePublic},
+			{ID: 2, Name: timodel.NewCIStr("b"), Offset: 1, FieldType: *ftNotNull, State: timodel.StatePublic},
+		},
+		Indices: []*timodel.IndexInfo{
+			{
+				ID:      1,
+				Name:    timodel.NewCIStr("a"),
+				Columns: []*timodel.IndexColumn{{Name: timodel.NewCIStr("a"), Offset: 0}},
+				Unique:  true,
+			},
+			{
+				ID:      2,
+				Name:    timodel.NewCIStr("b"),
+				Columns: []*timodel.IndexColumn{{Name: timodel.NewCIStr("b"), Offset: 1}},
+				Unique:  true,
+			},
+		},
+	}
+	info = WrapTableInfo(1, "test", 0, tbl)
+	require.Equal(t, int64(2), info.HandleIndexID)
+	require.Equal(t, []int64{2}, info.HandleKeyColumnIDs())
+	require.False(t, info.IsHandleKeyColumn(1))
+	require.True(t, info.IsHandleKeyColumn(2))
+
+	// no handle key
+	tbl.Indices = tbl.Indices[:1]
+	info = WrapTableInfo(1, "test", 0, tbl)
+	require.Nil(t, info.HandleKeyColumnIDs())
+	require.False(t, info.IsHandleKeyColumn(1))
+}
+
 func TestTableInfoGetterFuncs(t *testing.T) {
 	t.Parallel()
 
@@ -271,6 +357,59 @@ func TestTableInfoClone(t *testing.T) {
 	require.Equal(t, int64(10), info.SchemaID)
 }
 
+func TestOnUpdateTimestampColumns(t *testing.T) {
+	t.Parallel()
+
+	ftID := parser_types.NewFieldType(mysql.TypeLong)
+	ftID.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	ftUpdatedAt := parser_types.NewFieldType(mysql.TypeTimestamp)
+	ftUpdatedAt.SetFlag(mysql.OnUpdateNowFlag)
+	ftCreatedAt := parser_types.NewFieldType(mysql.TypeTimestamp)
+
+	tbl := timodel.TableInfo{
+		ID:         1071,
+		Name:       timodel.NewCIStr("t1"),
+		PKIsHandle: true,
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("id"), FieldType: *ftID, State: timodel.StatePublic},
+			{ID: 2, Name: timodel.NewCIStr("created_at"), FieldType: *ftCreatedAt, State: timodel.StatePublic},
+			{ID: 3, Name: timodel.NewCIStr("updated_at"), FieldType: *ftUpdatedAt, State: timodel.StatePublic},
+		},
+	}
+	info := WrapTableInfo(10, "test", 0, &tbl)
+	cols := info.OnUpdateTimestampColumns()
+	require.Len(t, cols, 1)
+	require.Equal(t, "updated_at", cols[0].Name.O)
+
+	tbl.Columns = tbl.Columns[:2]
+	info = WrapTableInfo(10, "test", 0, &tbl)
+	require.Empty(t, info.OnUpdateTimestampColumns())
+}
+
+func TestSelectStarColumns(t *testing.T) {
+	t.Parallel()
+
+	ft := parser_types.NewFieldType(mysql.TypeLong)
+	tbl := timodel.TableInfo{
+		ID:   1072,
+		Name: timodel.NewCIStr("t1"),
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("a"), Offset: 0, FieldType: *ft, State: timodel.StatePublic},
+			{ID: 4, Name: timodel.NewCIStr("d"), Offset: 3, FieldType: *ft, State: timodel.StatePublic},
+			{ID: 2, Name: timodel.NewCIStr("_V$_idx_0"), Offset: 1, FieldType: *ft, State: timodel.StatePublic, Hidden: true},
+			{ID: 3, Name: timodel.NewCIStr("c"), Offset: 2, FieldType: *ft, State: timodel.StatePublic},
+			{ID: 5, Name: timodel.NewCIStr("e"), Offset: 4, FieldType: *ft, State: timodel.StateWriteOnly},
+		},
+	}
+	info := WrapTableInfo(10, "test", 0, &tbl)
+	cols := info.SelectStarColumns()
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		names = append(names, col.Name.O)
+	}
+	require.Equal(t, []string{"a", "c", "d"}, names)
+}
+
 func TestIndexByName(t *testing.T) {
 	tableInfo := &TableInfo{
 		TableInfo: &timodel.TableInfo{
@@ -318,6 +457,55 @@ func TestIndexByName(t *testing.T) {
 	require.Equal(t, []int{0}, offsets)
 }
 
+func TestGetIndexInfo(t *testing.T) {
+	tableInfo := &TableInfo{
+		TableInfo: &timodel.TableInfo{
+			Indices: nil,
+		},
+	}
+	index, ok := tableInfo.GetIndexInfo(1)
+	require.False(t, ok)
+	require.Nil(t, index)
+
+	tableInfo = &TableInfo{
+		TableInfo: &timodel.TableInfo{
+			Indices: []*timodel.IndexInfo{
+				{
+					ID:   1,
+					Name: timodel.NewCIStr("idx1"),
+					Columns: []*timodel.IndexColumn{
+						{
+							Name: timodel.NewCIStr("col1"),
+						},
+					},
+				},
+				{
+					ID:   3,
+					Name: timodel.NewCIStr("idx3"),
+					Columns: []*timodel.IndexColumn{
+						{
+							Name: timodel.NewCIStr("col3"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	index, ok = tableInfo.GetIndexInfo(2)
+	require.False(t, ok)
+	require.Nil(t, index)
+
+	index, ok = tableInfo.GetIndexInfo(1)
+	require.True(t, ok)
+	require.Equal(t, "idx1", index.Name.O)
+
+	index, ok = tableInfo.GetIndexInfo(3)
+	require.True(t, ok)
+	require.Equal(t, "idx3", index.Name.O)
+	require.Equal(t, "col3", index.Columns[0].Name.O)
+}
+
 func TestColumnsByNames(t *testing.T) {
 	tableInfo := WrapTableInfo(100, "test", 100, &timodel.TableInfo{
 		Columns: []*timodel.ColumnInfo{
@@ -360,6 +548,54 @@ func TestColumnsByNames(t *testing.T) {
 	require.Equal(t, []int{1, 0, 2}, offsets)
 }
 
+func TestColumnIDsByNames(t *testing.T) {
+	tableInfo := WrapTableInfo(100, "test", 100, &timodel.TableInfo{
+		Columns: []*timodel.ColumnInfo{
+			{
+				Name:   timodel.NewCIStr("col2"),
+				ID:     1,
+				Offset: 0,
+			},
+			{
+				Name:   timodel.NewCIStr("col1"),
+				ID:     0,
+				Offset: 1,
+			},
+			{
+				Name:   timodel.NewCIStr("Col3"),
+				ID:     2,
+				Offset: 2,
+			},
+		},
+	})
+
+	colID, ok := tableInfo.ColumnIDByName("col2")
+	require.True(t, ok)
+	require.Equal(t, int64(1), colID)
+
+	colID, ok = tableInfo.ColumnIDByName("COL3")
+	require.True(t, ok)
+	require.Equal(t, int64(2), colID)
+
+	_, ok = tableInfo.ColumnIDByName("col-not-found")
+	require.False(t, ok)
+
+	names := []string{"col1", "col2", "col3"}
+	ids, ok := tableInfo.ColumnIDsByNames(names)
+	require.True(t, ok)
+	require.Equal(t, []int64{0, 1, 2}, ids)
+
+	names = []string{"col1", "col-not-found"}
+	ids, ok = tableInfo.ColumnIDsByNames(names)
+	require.False(t, ok)
+	require.Nil(t, ids)
+
+	names = []string{"Col1", "COL2", "CoL3"}
+	ids, ok = tableInfo.ColumnIDsByNames(names)
+	require.True(t, ok)
+	require.Equal(t, []int64{0, 1, 2}, ids)
+}
+
 func TestWrapTableInfoWithVirtualColumns(t *testing.T) {
 	t.Parallel()
 	ftNull := parser_types.NewFieldType(mysql.TypeUnspecified)
@@ -442,4 +678,151 @@ func TestWrapTableInfoWithVirtualColumns(t *testing.T) {
 
 	tableInfo := WrapTableInfo(100, "test", 1000, &tidbTableInfo)
 	require.Equal(t, []int{2}, tableInfo.VirtualColumnsOffset)
+	require.True(t, tableInfo.HasVirtualColumns())
+	require.True(t, tableInfo.IsVirtualColumn(2))
+	require.False(t, tableInfo.IsVirtualColumn(0))
+}
+
+func TestTableInfoVirtualColumnHelpers(t *testing.T) {
+	t.Parallel()
+	ft := parser_types.NewFieldType(mysql.TypeLong)
+
+	tidbTableInfo := timodel.TableInfo{
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("a"), FieldType: *ft, State: timodel.StatePublic},
+			{ID: 2, Name: timodel.NewCIStr("b"), FieldType: *ft, State: timodel.StatePublic, GeneratedExprString: "a + 1"},
+			{ID: 3, Name: timodel.NewCIStr("c"), FieldType: *ft, State: timodel.StatePublic, GeneratedExprString: "a + 2", GeneratedStored: true},
+			{ID: 4, Name: timodel.NewCIStr("d"), FieldType: *ft, State: timodel.StatePublic, GeneratedExprString: "a + 3"},
+		},
+	}
+	tableInfo := WrapTableInfo(100, "test", 1000, &tidbTableInfo)
+	require.Equal(t, []int{1, 3}, tableInfo.VirtualColumnsOffset)
+	require.True(t, tableInfo.HasVirtualColumns())
+	for offset, expected := range []bool{false, true, false, true} {
+		require.Equal(t, expected, tableInfo.IsVirtualColumn(offset))
+	}
+	require.False(t, tableInfo.IsVirtualColumn(4))
+
+	// table without virtual columns
+	tidbTableInfo.Columns = tidbTableInfo.Columns[:1]
+	tableInfo = WrapTableInfo(100, "test", 1000, &tidbTableInfo)
+	require.False(t, tableInfo.HasVirtualColumns())
+	require.False(t, tableInfo.IsVirtualColumn(0))
+}
+
+func TestSchemaFingerprint(t *testing.T) {
+	t.Parallel()
+
+	newTableInfo := func(version uint64, colType byte) *TableInfo {
+		ftID := parser_types.NewFieldType(mysql.TypeLong)
+		ftID.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+		ftName := parser_types.NewFieldType(colType)
+		ftName.SetFlen(32)
+		return WrapTableInfo(1, "test", version, &timodel.TableInfo{
+			ID:         100,
+			Name:       timodel.NewCIStr("t"),
+			PKIsHandle: true,
+			UpdateTS:   version,
+			Columns: []*timodel.ColumnInfo{
+				{ID: 1, Name: timodel.NewCIStr("id"), Offset: 0, FieldType: *ftID, State: timodel.StatePublic},
+				{ID: 2, Name: timodel.NewCIStr("name"), Offset: 1, FieldType: *ftName, State: timodel.StatePublic},
+			},
+			Indices: []*timodel.IndexInfo{
+				{
+					ID:      1,
+					Name:    timodel.NewCIStr("idx_name"),
+					Columns: []*timodel.IndexColumn{{Name: timodel.NewCIStr("name"), Offset: 1, Length: -1}},
+				},
+			},
+		})
+	}
+
+	fingerprint := newTableInfo(1, mysql.TypeVarchar).SchemaFingerprint()
+	require.Equal(t, fingerprint, newTableInfo(1, mysql.TypeVarchar).SchemaFingerprint())
+	// volatile fields are ignored
+	require.Equal(t, fingerprint, newTableInfo(2, mysql.TypeVarchar).SchemaFingerprint())
+
+	// changing the column type changes the fingerprint
+	require.NotEqual(t, fingerprint, newTableInfo(1, mysql.TypeBlob).SchemaFingerprint())
+
+	// changing the index definition changes the fingerprint
+	info := newTableInfo(1, mysql.TypeVarchar)
+	info.Indices[0].Unique = true
+	require.NotEqual(t, fingerprint, info.SchemaFingerprint())
+}
+
+func TestGetPrimaryKeyColumnNames(t *testing.T) {
+	t.Parallel()
+
+	ftPK := parser_types.NewFieldType(mysql.TypeLong)
+	ftPK.SetFlag(mysql.PriKeyFlag | mysql.NotNullFlag)
+	ftNotNull := parser_types.NewFieldType(mysql.TypeLong)
+	ftNotNull.SetFlag(mysql.NotNullFlag)
+
+	// int primary key is handle
+	info := WrapTableInfo(1, "test", 0, &timodel.TableInfo{
+		Name: timodel.NewCIStr("t1"),
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("a"), Offset: 0, FieldType: *ftNotNull, State: timodel.StatePublic},
+			{ID: 2, Name: timodel.NewCIStr("id"), Offset: 1, FieldType: *ftPK, State: timodel.StatePublic},
+		},
+		PKIsHandle: true,
+	})
+	require.Equal(t, []string{"id"}, info.GetPrimaryKeyColumnNames())
+
+	// common handle, the names are in the clustered index order
+	info = WrapTableInfo(1, "test", 0, &timodel.TableInfo{
+		Name: timodel.NewCIStr("t2"),
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("a"), Offset: 0, FieldType: *ftPK, State: timodel.StatePublic},
+			{ID: 2, Name: timodel.NewCIStr("b"), Offset: 1, FieldType: *ftPK, State: timodel.StatePublic},
+		},
+		Indices: []*timodel.IndexInfo{
+			{
+				ID:   1,
+				Name: timodel.NewCIStr("primary"),
+				Columns: []*timodel.IndexColumn{
+					{Name: timodel.NewCIStr("b"), Offset: 1},
+					{Name: timodel.NewCIStr("a"), Offset: 0},
+				},
+				Primary: true,
+				Unique:  true,
+			},
+		},
+		IsCommonHandle: true,
+	})
+	require.Equal(t, []string{"b", "a"}, info.GetPrimaryKeyColumnNames())
+
+	// only a not null unique index, which is treated as the primary key
+	tbl := &timodel.TableInfo{
+		Name: timodel.NewCIStr("t3"),
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("a"), Offset: 0, FieldType: *ftNotNull, State: timodel.StatePublic},
+			{ID: 2, Name: timodel.NewCIStr("b"), Offset: 1, FieldType: *parser_types.NewFieldType(mysql.TypeLong), State: timodel.StatePublic},
+		},
+		Indices: []*timodel.IndexInfo{
+			{
+				ID:      1,
+				Name:    timodel.NewCIStr("uk_b"),
+				Columns: []*timodel.IndexColumn{{Name: timodel.NewCIStr("b"), Offset: 1}},
+				Unique:  true,
+			},
+			{
+				ID:      2,
+				Name:    timodel.NewCIStr("uk_a"),
+				Columns: []*timodel.IndexColumn{{Name: timodel.NewCIStr("a"), Offset: 0}},
+				Unique:  true,
+			},
+		},
+	}
+	info = WrapTableInfo(1, "test", 0, tbl)
+	require.Equal(t, int64(2), info.HandleIndexID)
+	require.Equal(t, []string{"a"}, info.GetPrimaryKeyColumnNames())
+
+	// no primary key
+	tbl.Indices = tbl.Indices[:1]
+	info = WrapTableInfo(1, "test", 0, tbl)
+	names := info.GetPrimaryKeyColumnNames()
+	require.NotNil(t, names)
+	require.Empty(t, names)
 }
@@ -48,6 +48,9 @@ const (
 	MessageTypeDDL
 	// MessageTypeResolved is resolved type of message key
 	MessageTypeResolved
+	// MessageTypeSnapshotMarker is the initial DDL or snapshot marker emitted by
+	// some canal producers, it carries no changes and can be skipped.
+	MessageTypeSnapshotMarker
 )
 
 const (
@@ -53,7 +53,7 @@ type mockDDLPuller struct {
 	schemaStorage entry.SchemaStorage
 }
 
-func (m *mockDDLPuller) PopFrontDDL() (uint64, *timodel.Job) {
+func (m *mockDDLPuller) PopFrontDDL() (uint64, *timodel.Job, error) {
 	if len(m.ddlQueue) > 0 {
 		job := m.ddlQueue[0]
 		m.ddlQueue = m.ddlQueue[1:]
@@ -61,9 +61,9 @@ func (m *mockDDLPuller) PopFrontDDL() (uint64, *timodel.Job) {
 		if err != nil {
 			panic(fmt.Sprintf("handle ddl job failed: %v", err))
 		}
-		return job.BinlogInfo.FinishedTS, job
+		return job.BinlogInfo.FinishedTS, job, nil
 	}
-	return m.resolvedTs, nil
+	return m.resolvedTs, nil, nil
 }
 
 func (m *mockDDLPuller) Close() {}
@@ -73,13 +73,29 @@ func (m *mockDDLPuller) Run(ctx context.Context) error {
 	return nil
 }
 
-func (m *mockDDLPuller) ResolvedTs() model.Ts {
+func (m *mockDDLPuller) ResolvedTs() (model.Ts, error) {
 	if len(m.ddlQueue) > 0 {
-		return m.ddlQueue[0].BinlogInfo.FinishedTS
+		return m.ddlQueue[0].BinlogInfo.FinishedTS, nil
+	}
+	return m.resolvedTs, nil
+}
+
+func (m *mockDDLPuller) DropPendingDDLs(pred func(*timodel.Job) bool) int {
+	remained := m.ddlQueue[:0]
+	for _, job := range m.ddlQueue {
+		if !pred(job) {
+			remained = append(remained, job)
+		}
 	}
-	return m.resolvedTs
+	dropped := len(m.ddlQueue) - len(remained)
+	m.ddlQueue = remained
+	return dropped
 }
 
+func (m *mockDDLPuller) SetOnResolvedTsAdvanced(func(ts uint64)) {}
+
+func (m *mockDDLPuller) SetMinResolvedTs(uint64) {}
+
 type mockDDLSink struct {
 	// DDLSink
 	ddlExecuting *model.DDLEvent
@@ -292,7 +292,10 @@ func (m *ddlManager) tick(
 
 	// drain all ddl jobs from ddlPuller
 	for {
-		_, job := m.ddlPuller.PopFrontDDL()
+		_, job, err := m.ddlPuller.PopFrontDDL()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
 		// no more ddl jobs
 		if job == nil {
 			break
@@ -378,7 +381,10 @@ func (m *ddlManager) tick(
 	}
 
 	// advance resolvedTs
-	ddlRts := m.ddlPuller.ResolvedTs()
+	ddlRts, err := m.ddlPuller.ResolvedTs()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 	m.schema.AdvanceResolvedTs(ddlRts)
 	if m.redoDDLManager.Enabled() {
 		err := m.redoDDLManager.UpdateResolvedTs(ctx, ddlRts)
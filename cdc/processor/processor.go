@@ -22,6 +22,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
@@ -222,7 +223,10 @@ func (p *processor) AddTableSpan(
 		p.redo.r.AddTable(span, startTs)
 	}
 
-	p.sourceManager.r.AddTable(span, p.getTableName(ctx, span.TableID), startTs, table.GetReplicaTs)
+	err := p.sourceManager.r.AddTable(span, p.getTableName(ctx, span.TableID), startTs, table.GetReplicaTs)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
 	return true, nil
 }
 
@@ -682,10 +686,14 @@ func (p *processor) lazyInitImpl(etcdCtx cdcContext.Context) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	var fetchBatchSize int
+	if cfConfig.Mounter != nil {
+		fetchBatchSize = util.GetOrZero(cfConfig.Mounter.FetchBatchSize)
+	}
 	p.sourceManager.r = sourcemanager.New(
 		p.changefeedID, p.upstream, p.mg.r,
 		sortEngine, pullerSplitUpdateMode,
-		util.GetOrZero(cfConfig.BDRMode))
+		util.GetOrZero(cfConfig.BDRMode), fetchBatchSize)
 	p.sourceManager.name = "SourceManager"
 	p.sourceManager.changefeedID = p.changefeedID
 	p.sourceManager.spawn(prcCtx)
@@ -787,9 +795,11 @@ func (p *processor) initDDLHandler(ctx context.Context) error {
 
 	serverCfg := config.GetGlobalServerConfig()
 	ddlPuller, err := puller.NewDDLJobPuller(
-		ctx, p.upstream, ddlStartTs,
+		ctx, p.upstream, ddlStartTs, 0, /* maxDDLCommitTs */
+		nil, /* shouldApplyCreateTable */
 		serverCfg, p.changefeedID, schemaStorage,
 		f, false, /* isOwner */
+		clock.New(),
 	)
 	if err != nil {
 		return errors.Trace(err)
@@ -407,6 +407,32 @@ func TestTableExecutorAddingTableIndirectlyWithRedoEnabled(t *testing.T) {
 	require.Nil(t, p.agent)
 }
 
+func TestCollectStatsOfPausedTable(t *testing.T) {
+	ctx := cdcContext.NewBackendContext4Test(true)
+	liveness := model.LivenessCaptureAlive
+	p, tester, changefeed := initProcessor4Test(ctx, t, &liveness, false)
+
+	// init tick
+	checkChangefeedNormal(changefeed)
+	require.Nil(t, p.lazyInit(ctx))
+	createTaskPosition(changefeed, p.captureInfo)
+	tester.MustApplyPatches()
+
+	span := spanz.TableIDToComparableSpan(1)
+	ok, err := p.AddTableSpan(ctx, span, tablepb.Checkpoint{CheckpointTs: 20}, false)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	p.sourceManager.r.PauseTable(span)
+	status := p.GetTableSpanStatus(span, true)
+	require.Equal(t, span, status.Span)
+	require.Contains(t, status.Stats.StageCheckpoints, "puller-ingress")
+	require.NoError(t, p.sourceManager.r.ResumeTable(span))
+
+	require.Nil(t, p.Close())
+	tester.MustApplyPatches()
+}
+
 func TestProcessorError(t *testing.T) {
 	ctx := cdcContext.NewBackendContext4Test(true)
 	liveness := model.LivenessCaptureAlive
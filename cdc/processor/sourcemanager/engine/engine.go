@@ -81,6 +81,16 @@ type SortEngine interface {
 	SlotsAndHasher() (slotCount int, hasher func(tablepb.Span, int) int)
 }
 
+// UnresolvedEventsCleaner can be implemented by a SortEngine which keeps the
+// events added more than once as different events. Before pulling a table again
+// from its received resolved ts, the events after the resolved ts must be
+// cleaned, otherwise they will be fetched twice.
+type UnresolvedEventsCleaner interface {
+	// CleanUnresolvedByTable discards the events of the given table which are
+	// not resolved yet.
+	CleanUnresolvedByTable(span tablepb.Span)
+}
+
 // EventIterator is an iterator to fetch events from SortEngine.
 // It's unnecessary to be thread-safe.
 type EventIterator interface {
@@ -169,4 +179,8 @@ func (p Position) IsCommitFence() bool {
 type TableStats struct {
 	ReceivedMaxCommitTs   model.Ts
 	ReceivedMaxResolvedTs model.Ts
+	// PendingBytes is the approximate size of events held in memory by the
+	// engine for the table. For engines which store events on disk, it's the
+	// size of events buffered in memory which haven't been written yet.
+	PendingBytes int64
 }
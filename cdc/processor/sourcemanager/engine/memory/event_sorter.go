@@ -28,8 +28,9 @@ import (
 )
 
 var (
-	_ engine.SortEngine    = (*EventSorter)(nil)
-	_ engine.EventIterator = (*EventIter)(nil)
+	_ engine.SortEngine              = (*EventSorter)(nil)
+	_ engine.UnresolvedEventsCleaner = (*EventSorter)(nil)
+	_ engine.EventIterator           = (*EventIter)(nil)
 )
 
 // EventSorter accepts out-of-order raw kv entries and output sorted entries.
@@ -123,6 +124,16 @@ func (s *EventSorter) CleanByTable(span tablepb.Span, upperBound engine.Position
 	return nil
 }
 
+// CleanUnresolvedByTable implements engine.UnresolvedEventsCleaner.
+func (s *EventSorter) CleanUnresolvedByTable(span tablepb.Span) {
+	value, exists := s.tables.Load(span)
+	if !exists {
+		log.Panic("clean an unexist table", zap.Stringer("span", &span))
+	}
+
+	value.(*tableSorter).cleanUnresolved()
+}
+
 // CleanAllTables implements engine.SortEngine.
 func (s *EventSorter) CleanAllTables(upperBound engine.Position) error {
 	log.Panic("CleanAllTables should never be called")
@@ -131,8 +142,12 @@ func (s *EventSorter) CleanAllTables(upperBound engine.Position) error {
 
 // GetStatsByTable implements engine.SortEngine.
 func (s *EventSorter) GetStatsByTable(span tablepb.Span) engine.TableStats {
-	log.Panic("GetStatsByTable should never be called")
-	return engine.TableStats{}
+	value, exists := s.tables.Load(span)
+	if !exists {
+		log.Panic("get stats from an unexist table", zap.Stringer("span", &span))
+	}
+
+	return value.(*tableSorter).stats()
 }
 
 // Close implements engine.SortEngine.
@@ -238,6 +253,35 @@ func (s *tableSorter) fetch(
 	return iter
 }
 
+func (s *tableSorter) stats() engine.TableStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var stats engine.TableStats
+	if s.resolvedTs != nil {
+		stats.ReceivedMaxResolvedTs = *s.resolvedTs
+	}
+	account := func(event *model.PolymorphicEvent) {
+		if event.RawKV != nil {
+			stats.PendingBytes += event.RawKV.ApproximateDataSize()
+		}
+		if !event.IsResolved() && event.CRTs > stats.ReceivedMaxCommitTs {
+			stats.ReceivedMaxCommitTs = event.CRTs
+		}
+	}
+	for _, event := range s.unresolved {
+		account(event)
+	}
+	for _, event := range s.resolved {
+		account(event)
+	}
+	if stats.ReceivedMaxCommitTs < stats.ReceivedMaxResolvedTs {
+		// Same as the pebble engine, use the resolved ts if there is no write.
+		stats.ReceivedMaxCommitTs = stats.ReceivedMaxResolvedTs
+	}
+	return stats
+}
+
 func (s *tableSorter) clean(span tablepb.Span, upperBound engine.Position) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -253,6 +297,12 @@ func (s *tableSorter) clean(span tablepb.Span, upperBound engine.Position) {
 	s.resolved = s.resolved[startIdx:]
 }
 
+func (s *tableSorter) cleanUnresolved() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unresolved = nil
+}
+
 func eventLess(i *model.PolymorphicEvent, j *model.PolymorphicEvent) bool {
 	return model.ComparePolymorphicEvents(i, j)
 }
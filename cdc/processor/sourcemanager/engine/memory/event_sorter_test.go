@@ -208,3 +208,27 @@ func TestEventLess(t *testing.T) {
 		require.Equal(t, tc.expected, eventLess(tc.i, tc.j), "case %d", i)
 	}
 }
+
+func TestEventSorterGetStatsByTable(t *testing.T) {
+	t.Parallel()
+
+	es := New(context.Background())
+	span := spanz.TableIDToComparableSpan(1)
+	es.AddTable(span, 1)
+	stats := es.GetStatsByTable(span)
+	require.Equal(t, model.Ts(1), stats.ReceivedMaxResolvedTs)
+	require.Equal(t, int64(0), stats.PendingBytes)
+
+	raw := &model.RawKVEntry{CRTs: 3, OpType: model.OpTypePut, Key: []byte("key"), Value: []byte("value")}
+	es.Add(span, model.NewPolymorphicEvent(raw))
+	stats = es.GetStatsByTable(span)
+	require.Equal(t, model.Ts(3), stats.ReceivedMaxCommitTs)
+	require.Equal(t, raw.ApproximateDataSize(), stats.PendingBytes)
+
+	// Cleaned events are not counted.
+	es.Add(span, model.NewResolvedPolymorphicEvent(0, 3))
+	require.NoError(t, es.CleanByTable(span, engine.Position{StartTs: 2, CommitTs: 3}))
+	stats = es.GetStatsByTable(span)
+	require.Equal(t, model.Ts(3), stats.ReceivedMaxResolvedTs)
+	require.Equal(t, int64(0), stats.PendingBytes)
+}
@@ -173,7 +173,10 @@ func (s *EventSorter) Add(span tablepb.Span, events ...*model.PolymorphicEvent)
 				state.maxReceivedCommitTs.Store(maxCommitTs)
 			}
 		}
-		state.ch.In() <- eventWithTableID{uniqueID: state.uniqueID, span: span, event: event}
+		if event.RawKV != nil && !event.IsResolved() {
+			state.pendingBytes.Add(event.RawKV.ApproximateDataSize())
+		}
+		state.ch.In() <- eventWithTableID{uniqueID: state.uniqueID, span: span, event: event, state: state}
 	}
 }
 
@@ -276,6 +279,7 @@ func (s *EventSorter) GetStatsByTable(span tablepb.Span) engine.TableStats {
 	return engine.TableStats{
 		ReceivedMaxCommitTs:   maxCommitTs,
 		ReceivedMaxResolvedTs: maxResolvedTs,
+		PendingBytes:          state.pendingBytes.Load(),
 	}
 }
 
@@ -360,6 +364,7 @@ type eventWithTableID struct {
 	uniqueID uint32
 	span     tablepb.Span
 	event    *model.PolymorphicEvent
+	state    *tableState
 }
 
 type tableState struct {
@@ -369,6 +374,9 @@ type tableState struct {
 	// For statistics.
 	maxReceivedCommitTs   atomic.Uint64
 	maxReceivedResolvedTs atomic.Uint64
+	// pendingBytes is the size of events which are added but not written
+	// into pebble yet.
+	pendingBytes atomic.Int64
 
 	// Following fields are protected by mu.
 	mu      sync.RWMutex
@@ -386,12 +394,16 @@ func (s *EventSorter) handleEvents(
 	batch := db.NewBatch()
 	writeOpts := &pebble.WriteOptions{Sync: false}
 	newResolved := spanz.NewHashMap[model.Ts]()
+	batchBytes := make(map[*tableState]int64)
 
 	handleItem := func(item eventWithTableID) {
 		if item.event.IsResolved() {
 			newResolved.ReplaceOrInsert(item.span, item.event.CRTs)
 			return
 		}
+		if item.event.RawKV != nil {
+			batchBytes[item.state] += item.event.RawKV.ApproximateDataSize()
+		}
 		key := encoding.EncodeKey(item.uniqueID, uint64(item.span.TableID), item.event)
 		value, err := s.serde.Marshal(item.event, []byte{})
 		if err != nil {
@@ -472,6 +484,10 @@ func (s *EventSorter) handleEvents(
 			writeDuration.Observe(time.Since(start).Seconds())
 			batch = db.NewBatch()
 		}
+		for state, bytes := range batchBytes {
+			state.pendingBytes.Add(-bytes)
+			delete(batchBytes, state)
+		}
 
 		newResolved.Range(func(span tablepb.Span, resolved uint64) bool {
 			s.mu.RLock()
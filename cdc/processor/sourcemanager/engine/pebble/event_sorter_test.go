@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/pkg/chann"
 	"github.com/pingcap/tiflow/pkg/config"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/stretchr/testify/require"
@@ -182,3 +183,48 @@ func TestCleanData(t *testing.T) {
 	require.NoError(t, s.CleanByTable(spanz.TableIDToComparableSpan(2), engine.Position{}))
 	require.Nil(t, s.CleanByTable(span, engine.Position{}))
 }
+
+func TestPendingBytes(t *testing.T) {
+	newEvent := func(commitTs model.Ts) *model.PolymorphicEvent {
+		return model.NewPolymorphicEvent(&model.RawKVEntry{
+			OpType:  model.OpTypePut,
+			Key:     []byte("key"),
+			Value:   []byte("value"),
+			StartTs: commitTs - 1,
+			CRTs:    commitTs,
+		})
+	}
+
+	// Events are pending before being written by the background goroutines.
+	cf := model.ChangeFeedID{Namespace: "default", ID: "test"}
+	s := &EventSorter{
+		changefeedID: cf,
+		dbs:          []*pebble.DB{nil},
+		channs:       []*chann.DrainableChann[eventWithTableID]{chann.NewAutoDrainChann[eventWithTableID]()},
+		tables:       spanz.NewHashMap[*tableState](),
+	}
+	span := spanz.TableIDToComparableSpan(1)
+	s.AddTable(span, 0)
+	s.Add(span, newEvent(2), newEvent(3), model.NewResolvedPolymorphicEvent(0, 3))
+	require.Equal(t, int64(16), s.GetStatsByTable(span).PendingBytes)
+	s.channs[0].CloseAndDrain()
+
+	dbPath := filepath.Join(t.TempDir(), t.Name())
+	db, err := OpenPebble(1, dbPath, &config.DBConfig{Count: 1}, nil)
+	require.Nil(t, err)
+	defer func() { _ = db.Close() }()
+	s = New(cf, []*pebble.DB{db})
+	defer s.Close()
+	s.AddTable(span, 0)
+	resolvedTs := make(chan model.Ts, 1)
+	s.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolvedTs <- ts })
+	s.Add(span, newEvent(2), newEvent(3), model.NewResolvedPolymorphicEvent(0, 3))
+	select {
+	case ts := <-resolvedTs:
+		require.Equal(t, model.Ts(3), ts)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "resolved ts is not emitted")
+	}
+	// Events are written before the resolved ts is emitted.
+	require.Equal(t, int64(0), s.GetStatsByTable(span).PendingBytes)
+}
@@ -15,8 +15,14 @@ package sourcemanager
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/benbjohnson/clock"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/kv"
@@ -28,15 +34,32 @@ import (
 	"github.com/pingcap/tiflow/cdc/processor/tablepb"
 	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/retry"
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/txnutil"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
 	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 const defaultMaxBatchSize = 256
 
+const (
+	// pdRetryBackoffBaseDelayInMs and pdRetryMaxTries bound the retry of
+	// fetching the cluster ID from PD when the shared kv client is created.
+	pdRetryBackoffBaseDelayInMs = 100
+	pdRetryBackoffMaxDelayInMs  = 2000
+	pdRetryMaxTries             = 8
+)
+
+// errKVStorageNotReady is returned if the kv storage of the upstream is not a
+// tikv.Storage, retrying can't help in this case.
+var errKVStorageNotReady = errors.New("kv storage of upstream is not ready")
+
 type pullerWrapperCreator func(
 	changefeed model.ChangeFeedID,
 	span tablepb.Span,
@@ -82,6 +105,9 @@ type SourceManager struct {
 	engine engine.SortEngine
 	// Used to indicate whether the changefeed is in BDR mode.
 	bdrMode bool
+	// fetchBatchSize is the max number of events mounted in one batch by
+	// the iterators created by FetchByTable.
+	fetchBatchSize int
 
 	// if `config.GetGlobalServerConfig().KVClient.EnableMultiplexing` is true `tablePullers`
 	// will be used. Otherwise `multiplexingPuller` will be used instead.
@@ -89,7 +115,50 @@ type SourceManager struct {
 	tablePullers       tablePullers
 	multiplexingPuller multiplexingPuller
 
-	splitUpdateMode PullerSplitUpdateMode
+	// splitUpdateMode is a PullerSplitUpdateMode, it can be changed by SetSplitUpdateMode.
+	splitUpdateMode atomic.Int32
+	// splitUpdateGraceWindow is only used by PullerSplitUpdateModeAtStart,
+	// see isOldUpdateKVEntry for details.
+	splitUpdateGraceWindow time.Duration
+
+	// clock is used to record when events are fetched, it can be mocked in tests.
+	clock clock.Clock
+	// lastEventTimes records the last time events were fetched for each table.
+	lastEventTimes spanz.SyncMap
+	// tables records the names of all added tables.
+	tables spanz.SyncMap
+	// removeTableMu serializes removing tables from the engine against the
+	// lookups of tables which don't belong to the caller, see tableSorterStats.
+	removeTableMu sync.RWMutex
+	// numTables is the number of tables in `tables`.
+	numTables atomic.Int64
+	// shouldSplitKVEntries records how to split update kv entries for all added
+	// tables, it's used to subscribe tables again in ResumeTable.
+	shouldSplitKVEntries spanz.SyncMap
+	// pausedTables records the tables paused by PauseTable.
+	pausedTables spanz.SyncMap
+
+	// stallCheckInterval and stallThreshold configure the check of tables whose
+	// resolved ts stalls, it's disabled if either of them is 0.
+	stallCheckInterval time.Duration
+	stallThreshold     time.Duration
+	// resolvedTsProgresses records the resolved ts progress of tables, it's
+	// only accessed by checkStalledTables.
+	resolvedTsProgresses *spanz.HashMap[resolvedTsProgress]
+}
+
+// resolvedTsProgress records since when the resolved ts of a table is resolvedTs.
+type resolvedTsProgress struct {
+	resolvedTs model.Ts
+	since      time.Time
+	stalled    bool
+}
+
+// TableMemUsage is the memory consumed by a table in the engine.
+type TableMemUsage struct {
+	Span      tablepb.Span
+	TableName string
+	Bytes     int64
 }
 
 // New creates a new source manager.
@@ -100,9 +169,18 @@ func New(
 	engine engine.SortEngine,
 	splitUpdateMode PullerSplitUpdateMode,
 	bdrMode bool,
+	fetchBatchSize int,
 ) *SourceManager {
 	multiplexing := config.GetGlobalServerConfig().KVClient.EnableMultiplexing
-	return newSourceManager(changefeedID, up, mg, engine, splitUpdateMode, bdrMode, multiplexing, pullerwrapper.NewPullerWrapper)
+	mgr := newSourceManager(changefeedID, up, mg, engine, splitUpdateMode, bdrMode, multiplexing, pullerwrapper.NewPullerWrapper)
+	pullerConfig := config.GetGlobalServerConfig().Debug.Puller
+	mgr.splitUpdateGraceWindow = time.Duration(pullerConfig.SplitUpdateGraceWindow)
+	mgr.stallCheckInterval = time.Duration(pullerConfig.TableResolvedTsStallCheckInterval)
+	mgr.stallThreshold = time.Duration(pullerConfig.TableResolvedTsStallThreshold)
+	if fetchBatchSize > 0 {
+		mgr.fetchBatchSize = fetchBatchSize
+	}
+	return mgr
 }
 
 // NewForTest creates a new source manager for testing.
@@ -116,8 +194,24 @@ func NewForTest(
 	return newSourceManager(changefeedID, up, mg, engine, PullerSplitUpdateModeNone, bdrMode, false, pullerwrapper.NewPullerWrapperForTest)
 }
 
-func isOldUpdateKVEntry(raw *model.RawKVEntry, getReplicaTs func() model.Ts) bool {
-	return raw != nil && raw.IsUpdate() && raw.CRTs < getReplicaTs()
+// isOldUpdateKVEntry returns true if the update kv entry is committed before the
+// replicate ts, i.e. `CRTs < replicaTs`. The entry committed exactly at the
+// replicate ts is not an old one. With a positive graceWindow, the boundary is
+// moved forward by the window, so the entries committed within the window after
+// the replicate ts are also treated as old ones.
+func isOldUpdateKVEntry(
+	raw *model.RawKVEntry, getReplicaTs func() model.Ts, graceWindow time.Duration,
+) bool {
+	if raw == nil || !raw.IsUpdate() {
+		return false
+	}
+	boundary := getReplicaTs()
+	if graceWindow > 0 {
+		boundary = oracle.ComposeTS(
+			oracle.ExtractPhysical(boundary)+graceWindow.Milliseconds(),
+			oracle.ExtractLogical(boundary))
+	}
+	return raw.CRTs < boundary
 }
 
 func newSourceManager(
@@ -131,15 +225,19 @@ func newSourceManager(
 	pullerWrapperCreator pullerWrapperCreator,
 ) *SourceManager {
 	mgr := &SourceManager{
-		ready:           make(chan struct{}),
-		changefeedID:    changefeedID,
-		up:              up,
-		mg:              mg,
-		engine:          engine,
-		splitUpdateMode: splitUpdateMode,
-		bdrMode:         bdrMode,
-		multiplexing:    multiplexing,
+		ready:          make(chan struct{}),
+		changefeedID:   changefeedID,
+		up:             up,
+		mg:             mg,
+		engine:         engine,
+		bdrMode:        bdrMode,
+		fetchBatchSize: defaultMaxBatchSize,
+		multiplexing:   multiplexing,
+		clock:          clock.New(),
+
+		resolvedTsProgresses: spanz.NewHashMap[resolvedTsProgress](),
 	}
+	mgr.splitUpdateMode.Store(int32(splitUpdateMode))
 	if !multiplexing {
 		mgr.tablePullers.errChan = make(chan error, 16)
 		mgr.tablePullers.pullerWrapperCreator = pullerWrapperCreator
@@ -148,50 +246,232 @@ func newSourceManager(
 }
 
 // AddTable adds a table to the source manager. Start puller and register table to the engine.
-func (m *SourceManager) AddTable(span tablepb.Span, tableName string, startTs model.Ts, getReplicaTs func() model.Ts) {
+func (m *SourceManager) AddTable(span tablepb.Span, tableName string, startTs model.Ts, getReplicaTs func() model.Ts) error {
 	// Add table to the engine first, so that the engine can receive the events from the puller.
 	m.engine.AddTable(span, startTs)
+	// Only count newly added spans, the same span can be added again.
+	if _, loaded := m.tables.LoadOrStore(span, tableName); !loaded {
+		m.updateNumTables(1)
+	}
 
 	shouldSplitKVEntry := func(raw *model.RawKVEntry) bool {
 		if raw == nil || !raw.IsUpdate() {
 			return false
 		}
-		switch m.splitUpdateMode {
+		mode := PullerSplitUpdateMode(m.splitUpdateMode.Load())
+		switch mode {
 		case PullerSplitUpdateModeNone:
 			return false
 		case PullerSplitUpdateModeAlways:
 			return true
 		case PullerSplitUpdateModeAtStart:
-			return isOldUpdateKVEntry(raw, getReplicaTs)
+			return isOldUpdateKVEntry(raw, getReplicaTs, m.splitUpdateGraceWindow)
 		default:
-			log.Panic("Unknown split update mode", zap.Int32("mode", int32(m.splitUpdateMode)))
+			log.Panic("Unknown split update mode", zap.Int32("mode", int32(mode)))
 		}
 		log.Panic("Shouldn't reach here")
 		return false
 	}
 
+	if err := m.startPuller(span, tableName, startTs, shouldSplitKVEntry); err != nil {
+		m.cleanTable(span)
+		return errors.Trace(err)
+	}
+	m.shouldSplitKVEntries.Store(span, model.ShouldSplitKVEntry(shouldSplitKVEntry))
+	return nil
+}
+
+// startPuller starts to pull the given table from startTs.
+func (m *SourceManager) startPuller(
+	span tablepb.Span, tableName string, startTs model.Ts,
+	shouldSplitKVEntry model.ShouldSplitKVEntry,
+) error {
 	if m.multiplexing {
 		m.multiplexingPuller.puller.Subscribe([]tablepb.Span{span}, startTs, tableName, shouldSplitKVEntry)
-		return
+		return nil
 	}
 
 	p := m.tablePullers.pullerWrapperCreator(m.changefeedID, span, tableName, startTs, m.bdrMode, shouldSplitKVEntry)
-	p.Start(m.tablePullers.ctx, m.up, m.engine, m.tablePullers.errChan)
+	// Errors happened after the puller is started are still sent to errChan.
+	if err := p.Start(m.tablePullers.ctx, m.up, m.engine, m.tablePullers.errChan); err != nil {
+		log.Warn("SourceManager fails to start table puller",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Stringer("span", &span),
+			zap.Error(err))
+		p.Close()
+		return errors.Trace(err)
+	}
 	m.tablePullers.Store(span, p)
+	return nil
+}
+
+// PauseTable stops pulling the given table, so that a slow table can be
+// throttled without slowing down the whole changefeed. The table is still
+// registered in the engine, and events which have been received are kept.
+// It's a no-op if the table is not added or is paused already.
+func (m *SourceManager) PauseTable(span tablepb.Span) {
+	if _, ok := m.tables.Load(span); !ok {
+		log.Warn("SourceManager pauses an unexist table",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Stringer("span", &span))
+		return
+	}
+	if _, paused := m.pausedTables.LoadOrStore(span, struct{}{}); paused {
+		return
+	}
+
+	if m.multiplexing {
+		m.multiplexingPuller.puller.Unsubscribe([]tablepb.Span{span})
+	} else if wrapper, ok := m.tablePullers.Load(span); ok {
+		// Keep the puller, so that it can be resumed without pulling again.
+		wrapper.(pullerwrapper.Wrapper).Pause()
+	}
+	log.Info("SourceManager pauses table",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Stringer("span", &span))
+}
+
+// ResumeTable continues pulling a table paused by PauseTable. It's a no-op if
+// the table is not paused.
+//
+// In multiplexing mode, the table is subscribed again from the max resolved ts
+// received by the engine. Events after the resolved ts are discarded from the
+// engine before that if the engine can't deduplicate them.
+func (m *SourceManager) ResumeTable(span tablepb.Span) error {
+	if _, paused := m.pausedTables.LoadAndDelete(span); !paused {
+		return nil
+	}
+	tableName, ok := m.tables.Load(span)
+	if !ok {
+		// The table has been removed after it's paused.
+		return nil
+	}
+
+	if !m.multiplexing {
+		if wrapper, ok := m.tablePullers.Load(span); ok {
+			wrapper.(pullerwrapper.Wrapper).Resume()
+		}
+		log.Info("SourceManager resumes table",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Stringer("span", &span))
+		return nil
+	}
+
+	// The table has been unsubscribed, so no events are added concurrently.
+	if cleaner, ok := m.engine.(engine.UnresolvedEventsCleaner); ok {
+		cleaner.CleanUnresolvedByTable(span)
+	}
+	shouldSplitKVEntry, _ := m.shouldSplitKVEntries.Load(span)
+	startTs := m.engine.GetStatsByTable(span).ReceivedMaxResolvedTs
+	if err := m.startPuller(span, tableName.(string), startTs,
+		shouldSplitKVEntry.(model.ShouldSplitKVEntry)); err != nil {
+		m.pausedTables.Store(span, struct{}{})
+		return errors.Trace(err)
+	}
+	log.Info("SourceManager resumes table",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Stringer("span", &span),
+		zap.Uint64("startTs", startTs))
+	return nil
+}
+
+// SetSplitUpdateMode changes how to split update kv entries at puller without
+// restarting the changefeed. It takes effect on the entries pulled after the call,
+// for both the existing and the newly added tables. The entries which have been
+// pulled and buffered already keep their prior treatment.
+func (m *SourceManager) SetSplitUpdateMode(mode PullerSplitUpdateMode) {
+	switch mode {
+	case PullerSplitUpdateModeNone, PullerSplitUpdateModeAtStart, PullerSplitUpdateModeAlways:
+	default:
+		log.Warn("ignore unknown split update mode",
+			zap.String("namespace", m.changefeedID.Namespace),
+			zap.String("changefeed", m.changefeedID.ID),
+			zap.Int32("mode", int32(mode)))
+		return
+	}
+	old := PullerSplitUpdateMode(m.splitUpdateMode.Swap(int32(mode)))
+	log.Info("split update mode changed",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Int32("oldMode", int32(old)),
+		zap.Int32("newMode", int32(mode)))
 }
 
 // RemoveTable removes a table from the source manager. Stop puller and unregister table from the engine.
 func (m *SourceManager) RemoveTable(span tablepb.Span) {
 	if m.multiplexing {
-		m.multiplexingPuller.puller.Unsubscribe([]tablepb.Span{span})
-		m.engine.RemoveTable(span)
+		// Paused tables have been unsubscribed already.
+		if _, paused := m.pausedTables.Load(span); !paused {
+			m.multiplexingPuller.puller.Unsubscribe([]tablepb.Span{span})
+		}
+		m.cleanTable(span)
 		return
 	}
 
 	if wrapper, ok := m.tablePullers.LoadAndDelete(span); ok {
 		wrapper.(pullerwrapper.Wrapper).Close()
 	}
+	m.cleanTable(span)
+}
+
+// RemoveAllTables removes all tables from the source manager, it's cheaper than
+// calling RemoveTable for each table when the changefeed is being torn down.
+// It's still safe to call Close after it.
+func (m *SourceManager) RemoveAllTables() {
+	if m.multiplexing {
+		for _, span := range m.multiplexingPuller.puller.UnsubscribeAll() {
+			m.cleanTable(span)
+		}
+	} else {
+		m.tablePullers.Range(func(span tablepb.Span, _ interface{}) bool {
+			// the table can be removed by RemoveTable concurrently.
+			if wrapper, ok := m.tablePullers.LoadAndDelete(span); ok {
+				wrapper.(pullerwrapper.Wrapper).Close()
+				m.cleanTable(span)
+			}
+			return true
+		})
+	}
+
+	// Paused tables have been unsubscribed in multiplexing mode, clean them separately.
+	m.pausedTables.Range(func(span tablepb.Span, _ interface{}) bool {
+		if _, ok := m.pausedTables.LoadAndDelete(span); ok {
+			m.cleanTable(span)
+		}
+		return true
+	})
+}
+
+// cleanTable removes the table from the engine and cleans its states.
+func (m *SourceManager) cleanTable(span tablepb.Span) {
+	// Delete the table before removing it from the engine, so that it's not
+	// looked up in the engine by tableSorterStats after being removed.
+	m.removeTableMu.Lock()
+	_, added := m.tables.LoadAndDelete(span)
 	m.engine.RemoveTable(span)
+	m.removeTableMu.Unlock()
+	m.lastEventTimes.Delete(span)
+	// The span can be removed without being added, only count added ones.
+	if added {
+		m.updateNumTables(-1)
+	}
+	m.shouldSplitKVEntries.Delete(span)
+	m.pausedTables.Delete(span)
+}
+
+func (m *SourceManager) updateNumTables(delta int64) {
+	n := m.numTables.Add(delta)
+	tableCountGauge.WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID).Set(float64(n))
+}
+
+// NumTables returns the number of tables managed by the source manager.
+func (m *SourceManager) NumTables() int {
+	return int(m.numTables.Load())
 }
 
 // OnResolve just wrap the engine's OnResolve method.
@@ -205,7 +485,82 @@ func (m *SourceManager) FetchByTable(
 	quota *memquota.MemQuota,
 ) *engine.MountedEventIter {
 	iter := m.engine.FetchByTable(span, lowerBound, upperBound)
-	return engine.NewMountedEventIter(m.changefeedID, iter, m.mg, defaultMaxBatchSize, quota)
+	if iter != nil {
+		iter = &eventTimeRecordingIter{EventIterator: iter, span: span, mgr: m}
+	}
+	return engine.NewMountedEventIter(m.changefeedID, iter, m.mg, m.fetchBatchSize, quota)
+}
+
+// LastEventTime returns the last time events were fetched for the table.
+// The second return value is false if no event has been fetched yet.
+func (m *SourceManager) LastEventTime(span tablepb.Span) (time.Time, bool) {
+	v, ok := m.lastEventTimes.Load(span)
+	if !ok {
+		return time.Time{}, false
+	}
+	return v.(time.Time), true
+}
+
+// eventTimeRecordingIter wraps an engine.EventIterator and records the time
+// of the first event fetched by it into the source manager.
+type eventTimeRecordingIter struct {
+	engine.EventIterator
+	span     tablepb.Span
+	mgr      *SourceManager
+	recorded bool
+}
+
+// Next implements engine.EventIterator.
+func (i *eventTimeRecordingIter) Next() (*model.PolymorphicEvent, engine.Position, error) {
+	event, txnFinished, err := i.EventIterator.Next()
+	if event != nil && !i.recorded {
+		i.mgr.lastEventTimes.Store(i.span, i.mgr.clock.Now())
+		i.recorded = true
+	}
+	return event, txnFinished, err
+}
+
+// ForceAdvanceResolvedTs forcibly advances the resolved ts of the table in the
+// engine. It's a break-glass operation to unstick a table whose resolved ts is
+// stuck unexpectedly, events before ts which haven't been pulled will be lost.
+// The ts must be larger than the current resolved ts of the table, and must not
+// be smaller than the GC safe point of the upstream.
+func (m *SourceManager) ForceAdvanceResolvedTs(
+	ctx context.Context, span tablepb.Span, ts model.Ts,
+) error {
+	gcSafePoint, err := gc.GetGCSafePoint(ctx, m.up.PDClient)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ts < gcSafePoint {
+		return cerror.ErrInvalidForcedResolvedTs.GenWithStackByArgs(ts,
+			fmt.Sprintf("it's smaller than the GC safe point(%d)", gcSafePoint))
+	}
+
+	// Hold the lock to prevent the table from being removed from the engine
+	// concurrently.
+	m.removeTableMu.RLock()
+	defer m.removeTableMu.RUnlock()
+	if _, ok := m.tables.Load(span); !ok {
+		return cerror.ErrProcessorTableNotFound.GenWithStackByArgs()
+	}
+	resolvedTs := m.engine.GetStatsByTable(span).ReceivedMaxResolvedTs
+	if ts <= resolvedTs {
+		return cerror.ErrInvalidForcedResolvedTs.GenWithStackByArgs(ts,
+			fmt.Sprintf("it's not larger than the current resolved ts(%d)", resolvedTs))
+	}
+	log.Warn("Force advance the resolved ts of the table, "+
+		"events before it which haven't been pulled will be lost",
+		zap.String("namespace", m.changefeedID.Namespace),
+		zap.String("changefeed", m.changefeedID.ID),
+		zap.Stringer("span", &span),
+		zap.Uint64("resolvedTs", ts),
+		zap.Uint64("oldResolvedTs", resolvedTs),
+		zap.Uint64("gcSafePoint", gcSafePoint))
+	m.engine.Add(span, model.NewResolvedPolymorphicEvent(0, ts))
+	forceAdvancedResolvedTsCounter.
+		WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID).Inc()
+	return nil
 }
 
 // CleanByTable just wrap the engine's CleanByTable method.
@@ -229,22 +584,168 @@ func (m *SourceManager) GetTablePullerStats(span tablepb.Span) puller.Stats {
 	return p.(pullerwrapper.Wrapper).GetStats()
 }
 
+// GetAllPullerStats returns the puller stats of all tables.
+// It's safe to be called concurrently with AddTable and RemoveTable.
+func (m *SourceManager) GetAllPullerStats() *spanz.HashMap[puller.Stats] {
+	if m.multiplexing {
+		return m.multiplexingPuller.puller.MultiplexingPuller.AllStats()
+	}
+
+	result := spanz.NewHashMap[puller.Stats]()
+	m.tablePullers.Range(func(span tablepb.Span, value interface{}) bool {
+		result.ReplaceOrInsert(span, value.(pullerwrapper.Wrapper).GetStats())
+		return true
+	})
+	return result
+}
+
+// GetAggregatedPullerStats returns the puller stats aggregated across all tables,
+// see puller.SumStats for how the stats are aggregated.
+func (m *SourceManager) GetAggregatedPullerStats() puller.Stats {
+	var stats []puller.Stats
+	m.GetAllPullerStats().Range(func(_ tablepb.Span, s puller.Stats) bool {
+		stats = append(stats, s)
+		return true
+	})
+	return puller.SumStats(stats)
+}
+
+// TableResolveProgress returns how many regions of the table have been resolved,
+// i.e. finished the incremental scan, and the total region count of the table.
+// It can be used to show the bootstrap progress. ok is false if the table is unknown.
+func (m *SourceManager) TableResolveProgress(span tablepb.Span) (resolved, total int, ok bool) {
+	stats, ok := m.tablePullerStats(span)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stats.InitializedRegionCount), int(stats.RegionCount), true
+}
+
+// tablePullerStats is like GetTablePullerStats, but ok is false instead of
+// panicking if the table is unknown.
+func (m *SourceManager) tablePullerStats(span tablepb.Span) (stats puller.Stats, ok bool) {
+	if m.multiplexing {
+		if _, ok := m.tables.Load(span); !ok {
+			return puller.Stats{}, false
+		}
+		return m.multiplexingPuller.puller.MultiplexingPuller.Stats(span), true
+	}
+	p, ok := m.tablePullers.Load(span)
+	if !ok {
+		return puller.Stats{}, false
+	}
+	return p.(pullerwrapper.Wrapper).GetStats(), true
+}
+
+// SubscribedSpans returns a snapshot of the spans whose events are being
+// pulled, sorted by span. Paused tables are not included.
+func (m *SourceManager) SubscribedSpans() []tablepb.Span {
+	var spans []tablepb.Span
+	if m.multiplexing {
+		m.tables.Range(func(span tablepb.Span, _ interface{}) bool {
+			if _, paused := m.pausedTables.Load(span); !paused {
+				spans = append(spans, span)
+			}
+			return true
+		})
+	} else {
+		m.tablePullers.Range(func(span tablepb.Span, _ interface{}) bool {
+			if _, paused := m.pausedTables.Load(span); !paused {
+				spans = append(spans, span)
+			}
+			return true
+		})
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Less(&spans[j])
+	})
+	return spans
+}
+
+// tableSorterStats returns the sorter stats of the table. ok is false if the
+// table is not added or removed concurrently, instead of panicking in the
+// engine like GetTableSorterStats.
+func (m *SourceManager) tableSorterStats(span tablepb.Span) (stats engine.TableStats, ok bool) {
+	m.removeTableMu.RLock()
+	defer m.removeTableMu.RUnlock()
+	if _, ok := m.tables.Load(span); !ok {
+		return engine.TableStats{}, false
+	}
+	return m.engine.GetStatsByTable(span), true
+}
+
 // GetTableSorterStats returns the sorter stats of the table.
 func (m *SourceManager) GetTableSorterStats(span tablepb.Span) engine.TableStats {
 	return m.engine.GetStatsByTable(span)
 }
 
+// TablesByMemoryUsage returns all tables sorted by the memory they consume in
+// the engine in descending order, it can be used to throttle the heaviest tables.
+func (m *SourceManager) TablesByMemoryUsage() []TableMemUsage {
+	var usages []TableMemUsage
+	m.tables.Range(func(span tablepb.Span, value interface{}) bool {
+		stats, ok := m.tableSorterStats(span)
+		if !ok {
+			return true
+		}
+		usages = append(usages, TableMemUsage{
+			Span:      span,
+			TableName: value.(string),
+			Bytes:     stats.PendingBytes,
+		})
+		return true
+	})
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].Bytes > usages[j].Bytes
+	})
+	return usages
+}
+
+// TotalSorterMemoryBytes returns the total size of events held in memory by the
+// engine across all tables. For engines which store events on disk, only the
+// events which haven't been written yet are counted.
+func (m *SourceManager) TotalSorterMemoryBytes() uint64 {
+	var total uint64
+	m.tables.Range(func(span tablepb.Span, _ interface{}) bool {
+		if stats, ok := m.tableSorterStats(span); ok && stats.PendingBytes > 0 {
+			total += uint64(stats.PendingBytes)
+		}
+		return true
+	})
+	return total
+}
+
 // Run implements util.Runnable.
 func (m *SourceManager) Run(ctx context.Context, _ ...chan<- error) error {
+	if m.stallCheckInterval <= 0 || m.stallThreshold <= 0 {
+		return m.run(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		// Stop checking stalled tables even if the pullers exit without error.
+		defer cancel()
+		return m.run(ctx)
+	})
+	g.Go(func() error {
+		m.runStallChecker(ctx)
+		return nil
+	})
+	return g.Wait()
+}
+
+func (m *SourceManager) run(ctx context.Context) error {
 	if m.multiplexing {
-		serverConfig := config.GetGlobalServerConfig()
-		grpcPool := sharedconn.NewConnAndClientPool(m.up.SecurityConfig, kv.GetGlobalGrpcMetrics())
-		client := kv.NewSharedClient(
-			m.changefeedID, serverConfig, m.bdrMode,
-			m.up.PDClient, grpcPool, m.up.RegionCache, m.up.PDClock,
-			txnutil.NewLockerResolver(m.up.KVStorage.(tikv.Storage), m.changefeedID),
-		)
+		// Tolerate a brief unavailability of the upstream at the changefeed
+		// start, instead of failing the changefeed.
+		client, err := m.createSharedClient(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
 
+		serverConfig := config.GetGlobalServerConfig()
 		m.multiplexingPuller.puller = pullerwrapper.NewMultiplexingPullerWrapper(
 			m.changefeedID, client, m.engine,
 			int(serverConfig.KVClient.FrontierConcurrent),
@@ -258,12 +759,148 @@ func (m *SourceManager) Run(ctx context.Context, _ ...chan<- error) error {
 	close(m.ready)
 	select {
 	case err := <-m.tablePullers.errChan:
+		m.drainTablePullerErrors(err)
 		return err
 	case <-m.tablePullers.ctx.Done():
 		return m.tablePullers.ctx.Err()
 	}
 }
 
+// runStallChecker checks stalled tables every stallCheckInterval until ctx is done.
+func (m *SourceManager) runStallChecker(ctx context.Context) {
+	ticker := m.clock.Ticker(m.stallCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkStalledTables()
+		}
+	}
+}
+
+// checkStalledTables returns the tables whose resolved ts received by the
+// engine hasn't advanced for stallThreshold. A table is logged when it stalls
+// and when it advances again. Paused tables are not checked, since they don't
+// advance until resumed.
+func (m *SourceManager) checkStalledTables() []tablepb.Span {
+	now := m.clock.Now()
+	progresses := spanz.NewHashMap[resolvedTsProgress]()
+	var stalled []tablepb.Span
+	m.tables.Range(func(span tablepb.Span, tableName interface{}) bool {
+		if _, paused := m.pausedTables.Load(span); paused {
+			return true
+		}
+		stats, ok := m.tableSorterStats(span)
+		if !ok {
+			// The table is being removed.
+			return true
+		}
+		resolvedTs := stats.ReceivedMaxResolvedTs
+		progress, ok := m.resolvedTsProgresses.Get(span)
+		if !ok || resolvedTs > progress.resolvedTs {
+			if progress.stalled {
+				log.Info("SourceManager finds the resolved ts of table advances again",
+					zap.String("namespace", m.changefeedID.Namespace),
+					zap.String("changefeed", m.changefeedID.ID),
+					zap.Stringer("span", &span),
+					zap.String("tableName", tableName.(string)),
+					zap.Uint64("resolvedTs", resolvedTs),
+					zap.Duration("stalledDuration", now.Sub(progress.since)))
+			}
+			progress = resolvedTsProgress{resolvedTs: resolvedTs, since: now}
+		} else if now.Sub(progress.since) >= m.stallThreshold {
+			if !progress.stalled {
+				progress.stalled = true
+				pullerStats, _ := m.tablePullerStats(span)
+				log.Warn("SourceManager finds the resolved ts of table stalls",
+					zap.String("namespace", m.changefeedID.Namespace),
+					zap.String("changefeed", m.changefeedID.ID),
+					zap.Stringer("span", &span),
+					zap.String("tableName", tableName.(string)),
+					zap.Uint64("resolvedTs", resolvedTs),
+					zap.Duration("stalledDuration", now.Sub(progress.since)),
+					zap.Uint64("pullerResolvedTsIngress", pullerStats.ResolvedTsIngress),
+					zap.Uint64("pullerResolvedTsEgress", pullerStats.ResolvedTsEgress),
+					zap.Uint64("regionCount", pullerStats.RegionCount),
+					zap.Uint64("initializedRegionCount", pullerStats.InitializedRegionCount))
+			}
+			stalled = append(stalled, span)
+		}
+		progresses.ReplaceOrInsert(span, progress)
+		return true
+	})
+	// Tables which have been removed are dropped.
+	m.resolvedTsProgresses = progresses
+	stalledTableCountGauge.WithLabelValues(m.changefeedID.Namespace, m.changefeedID.ID).
+		Set(float64(len(stalled)))
+	return stalled
+}
+
+// createSharedClient creates the shared kv client. The client fetches the
+// cluster ID from PD as soon as it runs, so the cluster ID is fetched with a
+// bounded retry before creating the client.
+func (m *SourceManager) createSharedClient(ctx context.Context) (*kv.SharedClient, error) {
+	var client *kv.SharedClient
+	attempt := 0
+	err := retry.Do(ctx, func() error {
+		attempt++
+		if clusterID := m.up.PDClient.GetClusterID(ctx); clusterID == 0 {
+			err := errors.Errorf("cluster ID of upstream %d is unavailable", m.up.ID)
+			log.Warn("SourceManager fails to get the cluster ID from PD, will retry",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.Int("attempt", attempt),
+				zap.Error(err))
+			return err
+		}
+		var err error
+		client, err = m.newSharedClient()
+		return err
+	}, retry.WithBackoffBaseDelay(pdRetryBackoffBaseDelayInMs),
+		retry.WithBackoffMaxDelay(pdRetryBackoffMaxDelayInMs),
+		retry.WithMaxTries(pdRetryMaxTries),
+		retry.WithIsRetryableErr(func(err error) bool {
+			return errors.Cause(err) != errKVStorageNotReady && cerror.IsRetryableError(err)
+		}))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return client, nil
+}
+
+// newSharedClient creates the shared kv client with its own connection pool.
+func (m *SourceManager) newSharedClient() (*kv.SharedClient, error) {
+	storage, ok := m.up.KVStorage.(tikv.Storage)
+	if !ok {
+		return nil, errors.Annotatef(errKVStorageNotReady, "upstream %d", m.up.ID)
+	}
+	grpcPool := sharedconn.NewConnAndClientPool(m.up.SecurityConfig, kv.GetGlobalGrpcMetrics())
+	return kv.NewSharedClient(
+		m.changefeedID, config.GetGlobalServerConfig(), m.bdrMode,
+		m.up.PDClient, grpcPool, m.up.RegionCache, m.up.PDClock,
+		txnutil.NewLockerResolver(storage, m.changefeedID),
+	), nil
+}
+
+// drainTablePullerErrors drains and logs the errors sent by table pullers after
+// the first one, so that pullers can exit instead of blocking on a full channel.
+func (m *SourceManager) drainTablePullerErrors(first error) {
+	for {
+		select {
+		case err := <-m.tablePullers.errChan:
+			log.Warn("SourceManager drops the error of table puller",
+				zap.String("namespace", m.changefeedID.Namespace),
+				zap.String("changefeed", m.changefeedID.ID),
+				zap.NamedError("firstError", first),
+				zap.Error(err))
+		default:
+			return
+		}
+	}
+}
+
 // WaitForReady implements util.Runnable.
 func (m *SourceManager) WaitForReady(ctx context.Context) {
 	select {
@@ -279,6 +916,9 @@ func (m *SourceManager) Close() {
 		zap.String("namespace", m.changefeedID.Namespace),
 		zap.String("changefeed", m.changefeedID.ID))
 
+	tableCountGauge.DeleteLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+	stalledTableCountGauge.DeleteLabelValues(m.changefeedID.Namespace, m.changefeedID.ID)
+
 	start := time.Now()
 	if m.multiplexing {
 		m.multiplexingPuller.puller.Close()
@@ -0,0 +1,1017 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourcemanager
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/cdc/entry"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/processor/memquota"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine/memory"
+	pullerwrapper "github.com/pingcap/tiflow/cdc/processor/sourcemanager/puller"
+	"github.com/pingcap/tiflow/cdc/processor/tablepb"
+	"github.com/pingcap/tiflow/cdc/puller"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/spanz"
+	"github.com/pingcap/tiflow/pkg/txnutil/gc"
+	"github.com/pingcap/tiflow/pkg/upstream"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+func TestLastEventTime(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	span := spanz.TableIDToComparableSpan(1)
+	sortEngine := memory.New(context.Background())
+	mgr := NewForTest(changefeedID, nil, &entry.MockMountGroup{}, sortEngine, false)
+	mockClock := clock.NewMock()
+	mgr.clock = mockClock
+
+	sortEngine.AddTable(span, 0)
+	_, ok := mgr.LastEventTime(span)
+	require.False(t, ok)
+
+	quota := memquota.NewMemQuota(changefeedID, 1024*1024, "test")
+	defer quota.Close()
+	fetch := func(lowerBound, upperBound engine.Position) int {
+		iter := mgr.FetchByTable(span, lowerBound, upperBound, quota)
+		defer iter.Close()
+		count := 0
+		for {
+			event, _, err := iter.Next(context.Background())
+			require.NoError(t, err)
+			if event == nil {
+				return count
+			}
+			count++
+		}
+	}
+
+	mgr.Add(span, &model.PolymorphicEvent{
+		StartTs: 1,
+		CRTs:    2,
+		RawKV:   &model.RawKVEntry{OpType: model.OpTypePut, StartTs: 1, CRTs: 2},
+	}, model.NewResolvedPolymorphicEvent(0, 2))
+
+	mockClock.Add(time.Minute)
+	firstFetch := mockClock.Now()
+	require.Equal(t, 1, fetch(engine.Position{StartTs: 0, CommitTs: 1}, engine.Position{StartTs: 1, CommitTs: 2}))
+	lastEventTime, ok := mgr.LastEventTime(span)
+	require.True(t, ok)
+	require.Equal(t, firstFetch, lastEventTime)
+
+	// Fetching nothing shouldn't update the last event time.
+	mgr.Add(span, model.NewResolvedPolymorphicEvent(0, 3))
+	mockClock.Add(time.Minute)
+	require.Equal(t, 0, fetch(engine.Position{StartTs: 2, CommitTs: 3}, engine.Position{StartTs: 2, CommitTs: 3}))
+	lastEventTime, ok = mgr.LastEventTime(span)
+	require.True(t, ok)
+	require.Equal(t, firstFetch, lastEventTime)
+
+	mgr.RemoveTable(span)
+	_, ok = mgr.LastEventTime(span)
+	require.False(t, ok)
+}
+
+func TestForceAdvanceResolvedTs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	changefeedID := model.DefaultChangeFeedID("test")
+	span := spanz.TableIDToComparableSpan(1)
+	sortEngine := memory.New(context.Background())
+	gcSafePoint := model.Ts(100)
+	pdClient := &gc.MockPDClient{
+		UpdateGCSafePointFunc: func(_ context.Context, _ uint64) (uint64, error) {
+			return gcSafePoint, nil
+		},
+	}
+	mgr := NewForTest(changefeedID, upstream.NewUpstream4Test(pdClient),
+		&entry.MockMountGroup{}, sortEngine, false)
+
+	// The table must be added.
+	err := mgr.ForceAdvanceResolvedTs(ctx, span, 200)
+	require.True(t, cerror.ErrProcessorTableNotFound.Equal(err))
+	mgr.AddTable(span, "t1", 0, func() model.Ts { return 0 })
+
+	var resolvedTs model.Ts
+	mgr.OnResolve(func(_ tablepb.Span, ts model.Ts) { resolvedTs = ts })
+
+	// The ts can't be smaller than the GC safe point.
+	err = mgr.ForceAdvanceResolvedTs(ctx, span, gcSafePoint-1)
+	require.True(t, cerror.ErrInvalidForcedResolvedTs.Equal(err))
+	require.Equal(t, model.Ts(0), resolvedTs)
+
+	ts := model.Ts(200)
+	require.NoError(t, mgr.ForceAdvanceResolvedTs(ctx, span, ts))
+	require.Equal(t, ts, resolvedTs)
+
+	// The ts must be larger than the current resolved ts.
+	err = mgr.ForceAdvanceResolvedTs(ctx, span, ts)
+	require.True(t, cerror.ErrInvalidForcedResolvedTs.Equal(err))
+	err = mgr.ForceAdvanceResolvedTs(ctx, span, ts-1)
+	require.True(t, cerror.ErrInvalidForcedResolvedTs.Equal(err))
+
+	// Events before the forced resolved ts can be fetched.
+	iter := sortEngine.FetchByTable(span, engine.Position{StartTs: 0, CommitTs: 1},
+		engine.Position{StartTs: ts - 1, CommitTs: ts})
+	event, _, err := iter.Next()
+	require.NoError(t, err)
+	require.Nil(t, event)
+	require.NoError(t, iter.Close())
+}
+
+func TestTablesByMemoryUsage(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	sortEngine := memory.New(context.Background())
+	mgr := NewForTest(changefeedID, nil, &entry.MockMountGroup{}, sortEngine, false)
+	getReplicaTs := func() model.Ts { return 0 }
+
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	span3 := spanz.TableIDToComparableSpan(3)
+	mgr.AddTable(span1, "t1", 0, getReplicaTs)
+	mgr.AddTable(span2, "t2", 0, getReplicaTs)
+	mgr.AddTable(span3, "t3", 0, getReplicaTs)
+
+	addEvents := func(span tablepb.Span, count int) {
+		for i := 1; i <= count; i++ {
+			mgr.Add(span, &model.PolymorphicEvent{
+				StartTs: uint64(i),
+				CRTs:    uint64(i + 1),
+				RawKV: &model.RawKVEntry{
+					OpType:  model.OpTypePut,
+					Key:     []byte("key"),
+					Value:   []byte("value"),
+					StartTs: uint64(i),
+					CRTs:    uint64(i + 1),
+				},
+			})
+		}
+	}
+	addEvents(span1, 1)
+	addEvents(span2, 10)
+	addEvents(span3, 5)
+
+	usages := mgr.TablesByMemoryUsage()
+	require.Len(t, usages, 3)
+	require.Equal(t, []string{"t2", "t3", "t1"},
+		[]string{usages[0].TableName, usages[1].TableName, usages[2].TableName})
+	require.Equal(t, span2, usages[0].Span)
+	require.Greater(t, usages[0].Bytes, usages[1].Bytes)
+	require.Greater(t, usages[1].Bytes, usages[2].Bytes)
+
+	mgr.RemoveTable(span2)
+	usages = mgr.TablesByMemoryUsage()
+	require.Len(t, usages, 2)
+	require.Equal(t, "t3", usages[0].TableName)
+}
+
+type fakePullerWrapper struct {
+	stats   puller.Stats
+	paused  bool
+	onClose func()
+	onStart func(ctx context.Context, errCh chan<- error)
+	// startErr is returned by Start if it's not nil.
+	startErr error
+}
+
+func (f *fakePullerWrapper) Start(
+	ctx context.Context, up *upstream.Upstream,
+	eventSortEngine engine.SortEngine, errCh chan<- error,
+) error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+	if f.onStart != nil {
+		f.onStart(ctx, errCh)
+	}
+	return nil
+}
+
+func (f *fakePullerWrapper) GetStats() puller.Stats {
+	return f.stats
+}
+
+func (f *fakePullerWrapper) Pause() {
+	f.paused = true
+}
+
+func (f *fakePullerWrapper) Resume() {
+	f.paused = false
+}
+
+func (f *fakePullerWrapper) Close() {
+	if f.onClose != nil {
+		f.onClose()
+	}
+}
+
+func TestTableResolveProgress(t *testing.T) {
+	t.Parallel()
+
+	regionCounts := map[model.TableID]puller.Stats{
+		1: {RegionCount: 10, InitializedRegionCount: 4},
+		2: {RegionCount: 3, InitializedRegionCount: 3},
+	}
+	creator := func(
+		changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+		startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+	) pullerwrapper.Wrapper {
+		return &fakePullerWrapper{stats: regionCounts[span.TableID]}
+	}
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	sortEngine := &fakeSortEngine{}
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false, creator)
+	getReplicaTs := func() model.Ts { return 0 }
+
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	mgr.AddTable(span1, "t1", 0, getReplicaTs)
+	mgr.AddTable(span2, "t2", 0, getReplicaTs)
+
+	resolved, total, ok := mgr.TableResolveProgress(span1)
+	require.True(t, ok)
+	require.Equal(t, 4, resolved)
+	require.Equal(t, 10, total)
+
+	resolved, total, ok = mgr.TableResolveProgress(span2)
+	require.True(t, ok)
+	require.Equal(t, 3, resolved)
+	require.Equal(t, 3, total)
+
+	// unknown table
+	_, _, ok = mgr.TableResolveProgress(spanz.TableIDToComparableSpan(3))
+	require.False(t, ok)
+
+	mgr.RemoveTable(span1)
+	_, _, ok = mgr.TableResolveProgress(span1)
+	require.False(t, ok)
+}
+
+func TestGetAllPullerStats(t *testing.T) {
+	t.Parallel()
+
+	tableStats := map[model.TableID]puller.Stats{
+		1: {RegionCount: 10, InitializedRegionCount: 4, ResolvedTsIngress: 100, ResolvedTsEgress: 90},
+		2: {RegionCount: 3, InitializedRegionCount: 3, ResolvedTsIngress: 80, ResolvedTsEgress: 95},
+	}
+	creator := func(
+		changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+		startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+	) pullerwrapper.Wrapper {
+		return &fakePullerWrapper{stats: tableStats[span.TableID]}
+	}
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	sortEngine := memory.New(context.Background())
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false, creator)
+	getReplicaTs := func() model.Ts { return 0 }
+
+	require.Equal(t, 0, mgr.GetAllPullerStats().Len())
+	require.Equal(t, puller.Stats{}, mgr.GetAggregatedPullerStats())
+
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	mgr.AddTable(span1, "t1", 0, getReplicaTs)
+	mgr.AddTable(span2, "t2", 0, getReplicaTs)
+
+	stats := mgr.GetAllPullerStats()
+	require.Equal(t, 2, stats.Len())
+	require.Equal(t, tableStats[1], stats.GetV(span1))
+	require.Equal(t, tableStats[2], stats.GetV(span2))
+	require.Equal(t, puller.Stats{
+		RegionCount: 13, InitializedRegionCount: 7, ResolvedTsIngress: 80, ResolvedTsEgress: 90,
+	}, mgr.GetAggregatedPullerStats())
+
+	// tables are added and removed concurrently.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			span := spanz.TableIDToComparableSpan(int64(i + 10))
+			mgr.AddTable(span, "t", 0, getReplicaTs)
+			mgr.RemoveTable(span)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		require.GreaterOrEqual(t, mgr.GetAllPullerStats().Len(), 2)
+	}
+	wg.Wait()
+
+	mgr.RemoveTable(span1)
+	stats = mgr.GetAllPullerStats()
+	require.Equal(t, 1, stats.Len())
+	require.False(t, stats.Has(span1))
+}
+
+func TestIsOldUpdateKVEntry(t *testing.T) {
+	t.Parallel()
+
+	replicaTs := oracle.ComposeTS(1000, 1)
+	getReplicaTs := func() model.Ts { return replicaTs }
+	newUpdate := func(commitTs model.Ts) *model.RawKVEntry {
+		return &model.RawKVEntry{
+			OpType:   model.OpTypePut,
+			Value:    []byte("value"),
+			OldValue: []byte("old"),
+			CRTs:     commitTs,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		commitTs    model.Ts
+		graceWindow time.Duration
+		expected    bool
+	}{
+		{"below", replicaTs - 1, 0, true},
+		{"equal", replicaTs, 0, false},
+		{"above", replicaTs + 1, 0, false},
+		{"within grace", oracle.ComposeTS(1009, 1), 10 * time.Millisecond, true},
+		{"grace boundary", oracle.ComposeTS(1010, 1), 10 * time.Millisecond, false},
+		{"beyond grace", oracle.ComposeTS(1011, 0), 10 * time.Millisecond, false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected,
+			isOldUpdateKVEntry(newUpdate(tc.commitTs), getReplicaTs, tc.graceWindow), tc.name)
+	}
+
+	// not an update entry.
+	insert := &model.RawKVEntry{OpType: model.OpTypePut, Value: []byte("value"), CRTs: replicaTs - 1}
+	require.False(t, isOldUpdateKVEntry(insert, getReplicaTs, 0))
+	require.False(t, isOldUpdateKVEntry(nil, getReplicaTs, 0))
+}
+
+func TestSetSplitUpdateMode(t *testing.T) {
+	t.Parallel()
+
+	shouldSplitFuncs := make(map[model.TableID]model.ShouldSplitKVEntry)
+	creator := func(
+		changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+		startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+	) pullerwrapper.Wrapper {
+		shouldSplitFuncs[span.TableID] = shouldSplitKVEntry
+		return &fakePullerWrapper{}
+	}
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	sortEngine := memory.New(context.Background())
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false, creator)
+	getReplicaTs := func() model.Ts { return 100 }
+
+	mgr.AddTable(spanz.TableIDToComparableSpan(1), "t1", 0, getReplicaTs)
+	update := func(commitTs model.Ts) *model.RawKVEntry {
+		return &model.RawKVEntry{
+			OpType: model.OpTypePut, Value: []byte("v"), OldValue: []byte("o"), CRTs: commitTs,
+		}
+	}
+	require.False(t, shouldSplitFuncs[1](update(99)))
+	require.False(t, shouldSplitFuncs[1](update(101)))
+
+	// the existing table reflects the new mode.
+	mgr.SetSplitUpdateMode(PullerSplitUpdateModeAlways)
+	require.True(t, shouldSplitFuncs[1](update(99)))
+	require.True(t, shouldSplitFuncs[1](update(101)))
+
+	mgr.SetSplitUpdateMode(PullerSplitUpdateModeAtStart)
+	mgr.AddTable(spanz.TableIDToComparableSpan(2), "t2", 0, getReplicaTs)
+	for _, tableID := range []model.TableID{1, 2} {
+		require.True(t, shouldSplitFuncs[tableID](update(99)))
+		require.False(t, shouldSplitFuncs[tableID](update(101)))
+	}
+
+	// unknown mode is ignored.
+	mgr.SetSplitUpdateMode(PullerSplitUpdateMode(100))
+	require.True(t, shouldSplitFuncs[2](update(99)))
+	require.False(t, shouldSplitFuncs[2](update(101)))
+}
+
+func TestRemoveAllTables(t *testing.T) {
+	t.Parallel()
+
+	var closed []model.TableID
+	creator := func(
+		changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+		startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+	) pullerwrapper.Wrapper {
+		return &fakePullerWrapper{onClose: func() { closed = append(closed, span.TableID) }}
+	}
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	sortEngine := memory.New(context.Background())
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false, creator)
+	getReplicaTs := func() model.Ts { return 0 }
+
+	for i := 1; i <= 3; i++ {
+		mgr.AddTable(spanz.TableIDToComparableSpan(int64(i)), "t", 0, getReplicaTs)
+	}
+	require.Len(t, mgr.TablesByMemoryUsage(), 3)
+
+	mgr.RemoveAllTables()
+	require.ElementsMatch(t, []model.TableID{1, 2, 3}, closed)
+	require.Empty(t, mgr.TablesByMemoryUsage())
+	require.Equal(t, 0, mgr.GetAllPullerStats().Len())
+	_, _, ok := mgr.TableResolveProgress(spanz.TableIDToComparableSpan(1))
+	require.False(t, ok)
+
+	// wrappers are not closed again.
+	mgr.RemoveAllTables()
+	mgr.Close()
+	require.Len(t, closed, 3)
+}
+
+// fakeSortEngine is a SortEngine which returns the given pending bytes of tables,
+// and records the removed tables.
+type fakeSortEngine struct {
+	engine.SortEngine
+	pendingBytes map[model.TableID]int64
+	resolvedTs   map[model.TableID]model.Ts
+	removed      []model.TableID
+	iter         engine.EventIterator
+	// mu and tables are only used if strict is true, like the real engines,
+	// GetStatsByTable panics on the tables which are not added then.
+	strict bool
+	mu     sync.Mutex
+	tables map[model.TableID]struct{}
+}
+
+func (e *fakeSortEngine) FetchByTable(
+	span tablepb.Span, lowerBound, upperBound engine.Position,
+) engine.EventIterator {
+	return e.iter
+}
+
+func (e *fakeSortEngine) AddTable(span tablepb.Span, startTs model.Ts) {
+	if e.strict {
+		e.mu.Lock()
+		e.tables[span.TableID] = struct{}{}
+		e.mu.Unlock()
+	}
+}
+
+func (e *fakeSortEngine) RemoveTable(span tablepb.Span) {
+	if e.strict {
+		e.mu.Lock()
+		delete(e.tables, span.TableID)
+		e.mu.Unlock()
+		return
+	}
+	e.removed = append(e.removed, span.TableID)
+}
+
+func (e *fakeSortEngine) GetStatsByTable(span tablepb.Span) engine.TableStats {
+	if e.strict {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if _, ok := e.tables[span.TableID]; !ok {
+			panic("get stats from an unexist table")
+		}
+		return engine.TableStats{}
+	}
+	return engine.TableStats{
+		PendingBytes:          e.pendingBytes[span.TableID],
+		ReceivedMaxResolvedTs: e.resolvedTs[span.TableID],
+	}
+}
+
+func TestTotalSorterMemoryBytes(t *testing.T) {
+	t.Parallel()
+
+	sortEngine := &fakeSortEngine{pendingBytes: map[model.TableID]int64{1: 100, 2: 2048, 3: 0}}
+	changefeedID := model.DefaultChangeFeedID("test")
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false,
+		func(
+			changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+			startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+		) pullerwrapper.Wrapper {
+			return &fakePullerWrapper{}
+		})
+	getReplicaTs := func() model.Ts { return 0 }
+
+	require.Equal(t, uint64(0), mgr.TotalSorterMemoryBytes())
+	for i := 1; i <= 3; i++ {
+		mgr.AddTable(spanz.TableIDToComparableSpan(int64(i)), "t", 0, getReplicaTs)
+	}
+	require.Equal(t, uint64(2148), mgr.TotalSorterMemoryBytes())
+
+	mgr.RemoveTable(spanz.TableIDToComparableSpan(2))
+	require.Equal(t, uint64(100), mgr.TotalSorterMemoryBytes())
+}
+
+func TestRunDrainsTablePullerErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	creator := func(
+		changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+		startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+	) pullerwrapper.Wrapper {
+		return &fakePullerWrapper{onStart: func(ctx context.Context, errCh chan<- error) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				select {
+				case <-ctx.Done():
+				case errCh <- errors.New("puller error"):
+				}
+			}()
+		}}
+	}
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	sortEngine := memory.New(context.Background())
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false, creator)
+	mgr.tablePullers.ctx = ctx
+	getReplicaTs := func() model.Ts { return 0 }
+
+	// more errors than the capacity of the error channel.
+	errChanSize := cap(mgr.tablePullers.errChan)
+	for i := 1; i <= errChanSize+1; i++ {
+		mgr.AddTable(spanz.TableIDToComparableSpan(int64(i)), "t", 0, getReplicaTs)
+	}
+	require.Eventually(t, func() bool {
+		return len(mgr.tablePullers.errChan) == errChanSize
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err := mgr.Run(ctx)
+	require.ErrorContains(t, err, "puller error")
+	// all pullers can exit after draining, even if the context is not canceled.
+	wg.Wait()
+}
+
+func TestAddTableWithFailedPuller(t *testing.T) {
+	t.Parallel()
+
+	closed := false
+	creator := func(
+		changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+		startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+	) pullerwrapper.Wrapper {
+		if span.TableID == 2 {
+			return &fakePullerWrapper{
+				startErr: errors.New("region cache error"),
+				onClose:  func() { closed = true },
+			}
+		}
+		return &fakePullerWrapper{}
+	}
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	sortEngine := &fakeSortEngine{}
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false, creator)
+	getReplicaTs := func() model.Ts { return 0 }
+
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	require.NoError(t, mgr.AddTable(span1, "t1", 0, getReplicaTs))
+	err := mgr.AddTable(span2, "t2", 0, getReplicaTs)
+	require.ErrorContains(t, err, "region cache error")
+	require.True(t, closed)
+
+	// The failed table is rolled back, and the other one is not affected.
+	require.Equal(t, []model.TableID{2}, sortEngine.removed)
+	_, ok := mgr.tablePullers.Load(span2)
+	require.False(t, ok)
+	_, ok = mgr.tables.Load(span2)
+	require.False(t, ok)
+	_, ok = mgr.tablePullers.Load(span1)
+	require.True(t, ok)
+}
+
+func TestPauseAndResumeTable(t *testing.T) {
+	t.Parallel()
+
+	started := 0
+	closed := 0
+	wrapper := &fakePullerWrapper{
+		stats:   puller.Stats{RegionCount: 3},
+		onClose: func() { closed++ },
+	}
+	creator := func(
+		changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+		startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+	) pullerwrapper.Wrapper {
+		started++
+		return wrapper
+	}
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	sortEngine := memory.New(context.Background())
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false, creator)
+	getReplicaTs := func() model.Ts { return 0 }
+
+	span := spanz.TableIDToComparableSpan(1)
+	require.NoError(t, mgr.AddTable(span, "t", 5, getReplicaTs))
+	mgr.Add(span, model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType: model.OpTypePut, Key: []byte("a"), StartTs: 7, CRTs: 8,
+	}), model.NewResolvedPolymorphicEvent(0, 10), model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType: model.OpTypePut, Key: []byte("b"), StartTs: 11, CRTs: 12,
+	}))
+
+	// Pause the table twice, the puller is kept and paused.
+	mgr.PauseTable(span)
+	mgr.PauseTable(span)
+	require.True(t, wrapper.paused)
+	require.Equal(t, 0, closed)
+	require.Equal(t, model.Ts(10), sortEngine.GetStatsByTable(span).ReceivedMaxResolvedTs)
+	// Stats of paused tables can still be collected.
+	require.Equal(t, uint64(3), mgr.GetTablePullerStats(span).RegionCount)
+
+	// The puller is resumed instead of being restarted.
+	require.NoError(t, mgr.ResumeTable(span))
+	require.NoError(t, mgr.ResumeTable(span))
+	require.False(t, wrapper.paused)
+	require.Equal(t, 1, started)
+
+	// Events received before and after the pause can all be fetched once.
+	mgr.Add(span, model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType: model.OpTypePut, Key: []byte("c"), StartTs: 13, CRTs: 14,
+	}), model.NewResolvedPolymorphicEvent(0, 15))
+	iter := sortEngine.FetchByTable(span, engine.Position{StartTs: 0, CommitTs: 1},
+		engine.Position{StartTs: 14, CommitTs: 15})
+	var commitTss []model.Ts
+	for {
+		event, _, err := iter.Next()
+		require.NoError(t, err)
+		if event == nil {
+			break
+		}
+		commitTss = append(commitTss, event.CRTs)
+	}
+	require.NoError(t, iter.Close())
+	require.Equal(t, []model.Ts{8, 12, 14}, commitTss)
+
+	// Paused tables can be removed.
+	mgr.PauseTable(span)
+	mgr.RemoveAllTables()
+	require.Equal(t, 1, closed)
+	_, ok := mgr.tables.Load(span)
+	require.False(t, ok)
+	require.NoError(t, mgr.ResumeTable(span))
+	require.Equal(t, 1, started)
+}
+
+func TestCleanUnresolvedEventsBeforeResubscribing(t *testing.T) {
+	t.Parallel()
+
+	sortEngine := memory.New(context.Background())
+	span := spanz.TableIDToComparableSpan(1)
+	sortEngine.AddTable(span, 5)
+	sortEngine.Add(span, model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType: model.OpTypePut, Key: []byte("a"), StartTs: 7, CRTs: 8,
+	}), model.NewResolvedPolymorphicEvent(0, 10), model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType: model.OpTypePut, Key: []byte("b"), StartTs: 11, CRTs: 12,
+	}))
+
+	// The event after the resolved ts is pulled again after resubscribing.
+	sortEngine.CleanUnresolvedByTable(span)
+	sortEngine.Add(span, model.NewPolymorphicEvent(&model.RawKVEntry{
+		OpType: model.OpTypePut, Key: []byte("b"), StartTs: 11, CRTs: 12,
+	}), model.NewResolvedPolymorphicEvent(0, 15))
+
+	iter := sortEngine.FetchByTable(span, engine.Position{StartTs: 0, CommitTs: 1},
+		engine.Position{StartTs: 14, CommitTs: 15})
+	var commitTss []model.Ts
+	for {
+		event, _, err := iter.Next()
+		require.NoError(t, err)
+		if event == nil {
+			break
+		}
+		commitTss = append(commitTss, event.CRTs)
+	}
+	require.NoError(t, iter.Close())
+	require.Equal(t, []model.Ts{8, 12}, commitTss)
+}
+
+func TestNumTables(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test-num-tables")
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, &fakeSortEngine{},
+		PullerSplitUpdateModeNone, false, false,
+		func(
+			changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+			startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+		) pullerwrapper.Wrapper {
+			if span.TableID == 4 {
+				return &fakePullerWrapper{startErr: errors.New("start error")}
+			}
+			return &fakePullerWrapper{}
+		})
+	getReplicaTs := func() model.Ts { return 0 }
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, mgr.AddTable(spanz.TableIDToComparableSpan(int64(i)), "t", 0, getReplicaTs))
+	}
+	require.Equal(t, 3, mgr.NumTables())
+	// The table added again isn't counted twice.
+	require.NoError(t, mgr.AddTable(spanz.TableIDToComparableSpan(3), "t", 0, getReplicaTs))
+	require.Equal(t, 3, mgr.NumTables())
+	// The table fails to start isn't counted.
+	require.Error(t, mgr.AddTable(spanz.TableIDToComparableSpan(4), "t", 0, getReplicaTs))
+	require.Equal(t, 3, mgr.NumTables())
+
+	// Remove tables which are never added or removed already.
+	mgr.RemoveTable(spanz.TableIDToComparableSpan(5))
+	require.Equal(t, 3, mgr.NumTables())
+	mgr.RemoveTable(spanz.TableIDToComparableSpan(1))
+	mgr.RemoveTable(spanz.TableIDToComparableSpan(1))
+	require.Equal(t, 2, mgr.NumTables())
+
+	var metric dto.Metric
+	require.NoError(t, tableCountGauge.WithLabelValues(
+		changefeedID.Namespace, changefeedID.ID).Write(&metric))
+	require.Equal(t, float64(2), metric.GetGauge().GetValue())
+
+	mgr.RemoveAllTables()
+	require.Equal(t, 0, mgr.NumTables())
+}
+
+func TestSubscribedSpans(t *testing.T) {
+	t.Parallel()
+
+	mgr := newSourceManager(model.DefaultChangeFeedID("test"), nil, &entry.MockMountGroup{},
+		&fakeSortEngine{}, PullerSplitUpdateModeNone, false, false,
+		func(
+			changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+			startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+		) pullerwrapper.Wrapper {
+			return &fakePullerWrapper{}
+		})
+	getReplicaTs := func() model.Ts { return 0 }
+	require.Empty(t, mgr.SubscribedSpans())
+
+	spans := []tablepb.Span{
+		spanz.TableIDToComparableSpan(1),
+		spanz.TableIDToComparableSpan(2),
+		spanz.TableIDToComparableSpan(3),
+	}
+	for i := len(spans) - 1; i >= 0; i-- {
+		require.NoError(t, mgr.AddTable(spans[i], "t", 0, getReplicaTs))
+	}
+	require.Equal(t, spans, mgr.SubscribedSpans())
+
+	mgr.RemoveTable(spans[1])
+	require.Equal(t, []tablepb.Span{spans[0], spans[2]}, mgr.SubscribedSpans())
+
+	// Paused tables aren't subscribed.
+	mgr.PauseTable(spans[0])
+	require.Equal(t, []tablepb.Span{spans[2]}, mgr.SubscribedSpans())
+}
+
+// countingEventIter returns the given number of events and counts how many
+// events have been fetched.
+type countingEventIter struct {
+	total   int
+	fetched int
+}
+
+func (i *countingEventIter) Next() (*model.PolymorphicEvent, engine.Position, error) {
+	if i.fetched >= i.total {
+		return nil, engine.Position{}, nil
+	}
+	i.fetched++
+	ts := model.Ts(i.fetched)
+	event := model.NewPolymorphicEvent(&model.RawKVEntry{OpType: model.OpTypePut, StartTs: ts, CRTs: ts + 1})
+	return event, engine.Position{StartTs: ts, CommitTs: ts + 1}, nil
+}
+
+func (i *countingEventIter) Close() error { return nil }
+
+func TestFetchByTableBatchSize(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	quota := memquota.NewMemQuota(changefeedID, 1024*1024, "test")
+	defer quota.Close()
+	fetchFirstBatch := func(fetchBatchSize int) int {
+		iter := &countingEventIter{total: 1000}
+		mgr := New(changefeedID, nil, &entry.MockMountGroup{}, &fakeSortEngine{iter: iter},
+			PullerSplitUpdateModeNone, false, fetchBatchSize)
+		mountedIter := mgr.FetchByTable(spanz.TableIDToComparableSpan(1),
+			engine.Position{}, engine.Position{}, quota)
+		defer mountedIter.Close()
+		event, _, err := mountedIter.Next(context.Background())
+		require.NoError(t, err)
+		require.NotNil(t, event)
+		return iter.fetched
+	}
+
+	require.Equal(t, 16, fetchFirstBatch(16))
+	// The default batch size is used if it's not set.
+	require.Equal(t, defaultMaxBatchSize, fetchFirstBatch(0))
+}
+
+// flakyPDClient returns an invalid cluster ID for the first failures calls.
+type flakyPDClient struct {
+	gc.MockPDClient
+	failures int
+	calls    int
+	onCall   func()
+}
+
+func (c *flakyPDClient) GetClusterID(ctx context.Context) uint64 {
+	c.calls++
+	if c.onCall != nil {
+		c.onCall()
+	}
+	if c.calls <= c.failures {
+		return 0
+	}
+	return c.ClusterID
+}
+
+func TestCreateSharedClient(t *testing.T) {
+	t.Parallel()
+
+	changefeedID := model.DefaultChangeFeedID("test")
+	pdClient := &flakyPDClient{MockPDClient: gc.MockPDClient{ClusterID: 1}, failures: 2}
+	mgr := newSourceManager(changefeedID, &upstream.Upstream{PDClient: pdClient},
+		&entry.MockMountGroup{}, &fakeSortEngine{}, PullerSplitUpdateModeNone, false, true, nil)
+	// PD is retried until the cluster ID is available, then creating the client
+	// fails without retry since the kv storage is not ready.
+	_, err := mgr.createSharedClient(context.Background())
+	require.ErrorIs(t, err, errKVStorageNotReady)
+	require.Equal(t, 3, pdClient.calls)
+
+	// Retries stop once the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	pdClient = &flakyPDClient{failures: math.MaxInt, onCall: cancel}
+	mgr.up.PDClient = pdClient
+	_, err = mgr.createSharedClient(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, pdClient.calls)
+}
+
+func TestCheckStalledTables(t *testing.T) {
+	t.Parallel()
+
+	sortEngine := &fakeSortEngine{resolvedTs: map[model.TableID]model.Ts{1: 100, 2: 100, 3: 100}}
+	changefeedID := model.DefaultChangeFeedID("test-stalled-tables")
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false,
+		func(
+			changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+			startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+		) pullerwrapper.Wrapper {
+			return &fakePullerWrapper{}
+		})
+	mockClock := clock.NewMock()
+	mgr.clock = mockClock
+	mgr.stallThreshold = time.Minute
+	getReplicaTs := func() model.Ts { return 0 }
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	span3 := spanz.TableIDToComparableSpan(3)
+	for _, span := range []tablepb.Span{span1, span2, span3} {
+		require.NoError(t, mgr.AddTable(span, "t", 0, getReplicaTs))
+	}
+	// The resolved ts of paused tables doesn't advance as expected.
+	mgr.PauseTable(span3)
+	stalledTables := func() float64 {
+		return testutil.ToFloat64(stalledTableCountGauge.WithLabelValues(
+			changefeedID.Namespace, changefeedID.ID))
+	}
+
+	require.Empty(t, mgr.checkStalledTables())
+
+	mockClock.Add(30 * time.Second)
+	sortEngine.resolvedTs[1] = 200
+	require.Empty(t, mgr.checkStalledTables())
+
+	// The resolved ts of table 2 is frozen for the threshold.
+	mockClock.Add(30 * time.Second)
+	require.Equal(t, []tablepb.Span{span2}, mgr.checkStalledTables())
+	require.Equal(t, float64(1), stalledTables())
+
+	// Table 1 stalls too, and table 2 is still stalled.
+	mockClock.Add(30 * time.Second)
+	require.ElementsMatch(t, []tablepb.Span{span1, span2}, mgr.checkStalledTables())
+	require.Equal(t, float64(2), stalledTables())
+
+	// Table 2 advances again, and table 1 is removed.
+	sortEngine.resolvedTs[2] = 200
+	mgr.RemoveTable(span1)
+	require.Empty(t, mgr.checkStalledTables())
+	require.Equal(t, float64(0), stalledTables())
+	require.False(t, mgr.resolvedTsProgresses.Has(span1))
+	mockClock.Add(30 * time.Second)
+	require.Empty(t, mgr.checkStalledTables())
+}
+
+func TestRunStallChecker(t *testing.T) {
+	t.Parallel()
+
+	sortEngine := &fakeSortEngine{resolvedTs: map[model.TableID]model.Ts{1: 100}}
+	changefeedID := model.DefaultChangeFeedID("test-stall-checker")
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false,
+		func(
+			changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+			startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+		) pullerwrapper.Wrapper {
+			return &fakePullerWrapper{}
+		})
+	mockClock := clock.NewMock()
+	mgr.clock = mockClock
+	mgr.stallCheckInterval = 10 * time.Second
+	mgr.stallThreshold = time.Minute
+	require.NoError(t, mgr.AddTable(spanz.TableIDToComparableSpan(1), "t1", 0,
+		func() model.Ts { return 0 }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- mgr.Run(ctx)
+	}()
+	mgr.WaitForReady(ctx)
+	require.Eventually(t, func() bool {
+		mockClock.Add(10 * time.Second)
+		return testutil.ToFloat64(stalledTableCountGauge.WithLabelValues(
+			changefeedID.Namespace, changefeedID.ID)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+}
+
+func TestCheckStalledTablesWithRemovedTables(t *testing.T) {
+	t.Parallel()
+
+	sortEngine := &fakeSortEngine{strict: true, tables: make(map[model.TableID]struct{})}
+	changefeedID := model.DefaultChangeFeedID("test-stalled-removed-tables")
+	mgr := newSourceManager(changefeedID, nil, &entry.MockMountGroup{}, sortEngine,
+		PullerSplitUpdateModeNone, false, false,
+		func(
+			changefeed model.ChangeFeedID, span tablepb.Span, tableName string,
+			startTs model.Ts, bdrMode bool, shouldSplitKVEntry model.ShouldSplitKVEntry,
+		) pullerwrapper.Wrapper {
+			return &fakePullerWrapper{}
+		})
+	mgr.stallThreshold = time.Minute
+	getReplicaTs := func() model.Ts { return 0 }
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				mgr.checkStalledTables()
+			}
+		}
+	}()
+	// Tables are removed concurrently with the checks.
+	for i := 1; i <= 1000; i++ {
+		span := spanz.TableIDToComparableSpan(int64(i))
+		require.NoError(t, mgr.AddTable(span, "t", 0, getReplicaTs))
+		mgr.RemoveTable(span)
+	}
+	close(done)
+	wg.Wait()
+	require.Empty(t, mgr.checkStalledTables())
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourcemanager
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var forceAdvancedResolvedTsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "ticdc",
+	Subsystem: "source_manager",
+	Name:      "force_advanced_resolved_ts_total",
+	Help:      "The total number of forcibly advancing the resolved ts of tables",
+}, []string{"namespace", "changefeed"})
+
+var tableCountGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "ticdc",
+	Subsystem: "source_manager",
+	Name:      "table_count",
+	Help:      "The number of tables managed by the source manager",
+}, []string{"namespace", "changefeed"})
+
+var stalledTableCountGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "ticdc",
+	Subsystem: "source_manager",
+	Name:      "resolved_ts_stalled_table_count",
+	Help:      "The number of tables whose resolved ts doesn't advance beyond the threshold",
+}, []string{"namespace", "changefeed"})
+
+// InitMetrics registers all metrics in this file
+func InitMetrics(registry *prometheus.Registry) {
+	registry.MustRegister(forceAdvancedResolvedTsCounter)
+	registry.MustRegister(tableCountGauge)
+	registry.MustRegister(stalledTableCountGauge)
+}
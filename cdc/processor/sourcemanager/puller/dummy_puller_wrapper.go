@@ -40,11 +40,16 @@ func NewPullerWrapperForTest(
 }
 
 func (d *dummyPullerWrapper) Start(ctx context.Context, up *upstream.Upstream,
-	eventSortEngine engine.SortEngine, errCh chan<- error) {
+	eventSortEngine engine.SortEngine, errCh chan<- error) error {
+	return nil
 }
 
 func (d *dummyPullerWrapper) GetStats() puller.Stats {
 	return puller.Stats{}
 }
 
+func (d *dummyPullerWrapper) Pause() {}
+
+func (d *dummyPullerWrapper) Resume() {}
+
 func (d *dummyPullerWrapper) Close() {}
@@ -15,6 +15,7 @@ package puller
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tiflow/cdc/model"
@@ -30,13 +31,20 @@ import (
 // Wrapper is a wrapper of puller used by source manager.
 type Wrapper interface {
 	// Start the puller and send internal errors into `errChan`.
+	// An error is returned if the puller can't be started at all.
 	Start(
 		ctx context.Context,
 		up *upstream.Upstream,
 		eventSortEngine engine.SortEngine,
 		errChan chan<- error,
-	)
+	) error
 	GetStats() puller.Stats
+	// Pause stops consuming the events of the puller, so that the puller stops
+	// fetching new events when its buffers are full. Events which have been
+	// sent to the engine are kept.
+	Pause()
+	// Resume continues consuming the events of a paused puller.
+	Resume()
 	Close()
 }
 
@@ -55,6 +63,10 @@ type WrapperImpl struct {
 	cancel context.CancelFunc
 	// eg is used to wait the puller to exit.
 	eg *errgroup.Group
+
+	mu sync.Mutex
+	// resumed is closed if the wrapper isn't paused.
+	resumed chan struct{}
 }
 
 // NewPullerWrapper creates a new puller wrapper.
@@ -66,6 +78,8 @@ func NewPullerWrapper(
 	bdrMode bool,
 	shouldSplitKVEntry model.ShouldSplitKVEntry,
 ) Wrapper {
+	resumed := make(chan struct{})
+	close(resumed)
 	return &WrapperImpl{
 		changefeed:         changefeed,
 		span:               span,
@@ -73,6 +87,7 @@ func NewPullerWrapper(
 		startTs:            startTs,
 		bdrMode:            bdrMode,
 		shouldSplitKVEntry: shouldSplitKVEntry,
+		resumed:            resumed,
 	}
 }
 
@@ -83,7 +98,10 @@ func (n *WrapperImpl) Start(
 	up *upstream.Upstream,
 	eventSortEngine engine.SortEngine,
 	errChan chan<- error,
-) {
+) error {
+	if err := ctx.Err(); err != nil {
+		return cerrors.Trace(err)
+	}
 	ctx, n.cancel = context.WithCancel(ctx)
 	errorHandler := func(err error) {
 		select {
@@ -98,7 +116,7 @@ func (n *WrapperImpl) Start(
 
 	// NOTICE: always pull the old value internally
 	// See also: https://github.com/pingcap/tiflow/issues/2301.
-	n.p = puller.New(
+	p, err := puller.New(
 		ctx,
 		up.PDClient,
 		up.GrpcPool,
@@ -113,6 +131,12 @@ func (n *WrapperImpl) Start(
 		n.tableName,
 		n.bdrMode,
 	)
+	if err != nil {
+		n.cancel()
+		n.cancel = nil
+		return cerrors.Trace(err)
+	}
+	n.p = p
 
 	// Use errgroup to ensure all sub goroutines can exit without calling Close.
 	n.eg, ctx = errgroup.WithContext(ctx)
@@ -123,6 +147,11 @@ func (n *WrapperImpl) Start(
 	})
 	n.eg.Go(func() error {
 		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-n.resumedCh():
+			}
 			select {
 			case <-ctx.Done():
 				return nil
@@ -145,6 +174,7 @@ func (n *WrapperImpl) Start(
 			}
 		}
 	})
+	return nil
 }
 
 // GetStats returns the puller stats.
@@ -152,6 +182,34 @@ func (n *WrapperImpl) GetStats() puller.Stats {
 	return n.p.Stats()
 }
 
+// Pause implements Wrapper.
+func (n *WrapperImpl) Pause() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	select {
+	case <-n.resumed:
+		n.resumed = make(chan struct{})
+	default:
+	}
+}
+
+// Resume implements Wrapper.
+func (n *WrapperImpl) Resume() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	select {
+	case <-n.resumed:
+	default:
+		close(n.resumed)
+	}
+}
+
+func (n *WrapperImpl) resumedCh() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.resumed
+}
+
 // Close the puller wrapper.
 func (n *WrapperImpl) Close() {
 	if n.cancel == nil {
@@ -17,6 +17,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -25,6 +26,9 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	tidbkv "github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
 	timodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/entry"
@@ -61,6 +65,19 @@ type DDLJobPuller interface {
 
 	// Output the DDL job entry, it contains the DDL job and the error.
 	Output() <-chan *model.DDLJobEntry
+
+	// Stats returns the resolved ts of the KV puller and the DDL job puller.
+	Stats() DDLJobPullerStats
+}
+
+// DDLJobPullerStats is the stats of a DDLJobPuller. The gap between the two
+// resolved ts tells whether the KV layer is behind or the DDL processing is slow.
+type DDLJobPullerStats struct {
+	// KVResolvedTs is the resolved ts of the raw kv entries output by the
+	// underlying KV puller.
+	KVResolvedTs model.Ts
+	// ResolvedTs is the resolved ts processed by the DDL job puller.
+	ResolvedTs model.Ts
 }
 
 // Note: All unexported methods of `ddlJobPullerImpl` should
@@ -80,13 +97,34 @@ type ddlJobPullerImpl struct {
 	kvStorage     tidbkv.Storage
 	schemaStorage entry.SchemaStorage
 	resolvedTs    uint64
-	filter        filter.Filter
+	// maxDDLCommitTs is the upper bound of the finished ts of output DDL jobs,
+	// and the resolved ts doesn't exceed it either. 0 means no bound.
+	maxDDLCommitTs uint64
+	// shouldApplyCreateTable is consulted for create table jobs if it's not nil,
+	// the jobs it rejects are applied to the schema storage but not output.
+	shouldApplyCreateTable func(job *timodel.Job) bool
+	filter                 filter.Filter
+	// isLegacyFormatJob tells whether a raw kv entry is a DDL job of the legacy
+	// DDL list, the meta of `tidb_ddl_job` is initialized only for non-legacy
+	// ones. It's entry.IsLegacyFormatJob by default and can be overridden in
+	// case the heuristic misdetects jobs of clusters upgraded from old versions.
+	isLegacyFormatJob func(rawKV *model.RawKVEntry) bool
 	// ddlJobsTable is initialized when receive the first concurrent DDL job.
 	// It holds the info of table `tidb_ddl_jobs` of upstream TiDB.
 	ddlJobsTable *model.TableInfo
 	// It holds the column id of `job_meta` in table `tidb_ddl_jobs`.
 	jobMetaColumnID int64
 	outputCh        chan *model.DDLJobEntry
+	// computeDDLDigest indicates whether to attach the query digest to the
+	// output DDL job entries.
+	computeDDLDigest bool
+	// resolvedTsMinInterval is the minimum interval between handling
+	// consecutive resolved ts entries in non-multiplexing mode, 0 means no limit.
+	resolvedTsMinInterval time.Duration
+	clock                 clock.Clock
+	// renamedTables is the tables retained by the last handled rename tables
+	// job, it's attached to the output entry of the job.
+	renamedTables []model.RenamedTable
 }
 
 // Run starts the DDLJobPuller.
@@ -112,13 +150,17 @@ func (p *ddlJobPullerImpl) handleRawKVEntry(ctx context.Context, ddlRawKV *model
 		return nil
 	}
 
+	crts := ddlRawKV.CRTs
 	if ddlRawKV.OpType == model.OpTypeResolved {
+		if p.maxDDLCommitTs > 0 && crts > p.maxDDLCommitTs {
+			crts = p.maxDDLCommitTs
+		}
 		// Only nil in unit test case.
 		if p.schemaStorage != nil {
-			p.schemaStorage.AdvanceResolvedTs(ddlRawKV.CRTs)
+			p.schemaStorage.AdvanceResolvedTs(crts)
 		}
-		if ddlRawKV.CRTs > p.getResolvedTs() {
-			p.setResolvedTs(ddlRawKV.CRTs)
+		if crts > p.getResolvedTs() {
+			p.setResolvedTs(crts)
 		}
 	}
 
@@ -127,6 +169,7 @@ func (p *ddlJobPullerImpl) handleRawKVEntry(ctx context.Context, ddlRawKV *model
 		return errors.Trace(err)
 	}
 
+	p.renamedTables = nil
 	if job != nil {
 		skip, err := p.handleJob(job)
 		if err != nil {
@@ -145,9 +188,24 @@ func (p *ddlJobPullerImpl) handleRawKVEntry(ctx context.Context, ddlRawKV *model
 	jobEntry := &model.DDLJobEntry{
 		Job:    job,
 		OpType: ddlRawKV.OpType,
-		CRTs:   ddlRawKV.CRTs,
+		CRTs:   crts,
 		Err:    err,
 	}
+	if job != nil && job.Type == timodel.ActionRenameTables {
+		jobEntry.RenamedTables = p.renamedTables
+	}
+	if job != nil && p.computeDDLDigest {
+		digest, err := ddlQueryDigest(job.Query)
+		if err != nil {
+			// The digest is only for observability, don't block the DDL.
+			log.Warn("failed to compute the digest of ddl query",
+				zap.String("namespace", p.changefeedID.Namespace),
+				zap.String("changefeed", p.changefeedID.ID),
+				zap.String("query", job.Query),
+				zap.Error(err))
+		}
+		jobEntry.QueryDigest = digest
+	}
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -156,24 +214,139 @@ func (p *ddlJobPullerImpl) handleRawKVEntry(ctx context.Context, ddlRawKV *model
 	return nil
 }
 
+// ddlQueryDigest returns the normalized digest of the DDL query. Literals and
+// table names are ignored, so that the same DDLs on different tables have the
+// same digest.
+func ddlQueryDigest(query string) (string, error) {
+	stmts, _, err := parser.New().Parse(query, "", "")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var sb strings.Builder
+	for i, stmt := range stmts {
+		stmt.Accept(&tableNameEraser{})
+		if i > 0 {
+			sb.WriteString(";")
+		}
+		if err := stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	_, digest := parser.NormalizeDigest(sb.String())
+	return digest.String(), nil
+}
+
+// tableNameEraser erases the schema and table names in an AST.
+type tableNameEraser struct{}
+
+func (e *tableNameEraser) Enter(in ast.Node) (ast.Node, bool) {
+	if t, ok := in.(*ast.TableName); ok {
+		t.Schema = timodel.CIStr{}
+		t.Name = timodel.NewCIStr("?")
+	}
+	return in, false
+}
+
+func (e *tableNameEraser) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
 func (p *ddlJobPullerImpl) run(ctx context.Context) error {
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.Go(func() error { return errors.Trace(p.puller.Run(ctx)) })
 	eg.Go(func() error {
 		rawDDLCh := memorysorter.SortOutput(ctx, p.changefeedID, p.puller.Output())
-		for {
-			var ddlRawKV *model.RawKVEntry
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case ddlRawKV = <-rawDDLCh:
+		return p.handleSortedRawKVEntries(ctx, rawDDLCh)
+	})
+	return eg.Wait()
+}
+
+// handleSortedRawKVEntries handles the sorted raw kv entries. If
+// resolvedTsMinInterval is set, the resolved ts entries arriving within the
+// interval after the last handled one are coalesced into the latest one, which
+// is handled when the interval elapses or before the next DDL job. DDL jobs
+// are never delayed.
+func (p *ddlJobPullerImpl) handleSortedRawKVEntries(
+	ctx context.Context, rawDDLCh <-chan *model.RawKVEntry,
+) error {
+	var (
+		pendingResolved *model.RawKVEntry
+		lastResolvedAt  time.Time
+		timer           *clock.Timer
+		timerCh         <-chan time.Time
+	)
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	handlePendingResolved := func() error {
+		if pendingResolved == nil {
+			return nil
+		}
+		ddlRawKV := pendingResolved
+		pendingResolved = nil
+		lastResolvedAt = p.clock.Now()
+		return p.handleRawKVEntry(ctx, ddlRawKV)
+	}
+
+	for {
+		var ddlRawKV *model.RawKVEntry
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timerCh:
+			timerCh = nil
+			// The timer may be stale if the pending resolved ts was handled
+			// before it fired.
+			if remaining := p.resolvedTsMinInterval - p.clock.Since(lastResolvedAt); pendingResolved != nil && remaining > 0 {
+				timer.Reset(remaining)
+				timerCh = timer.C
+				continue
+			}
+			if err := handlePendingResolved(); err != nil {
+				return errors.Trace(err)
 			}
+			continue
+		case ddlRawKV = <-rawDDLCh:
+		}
+
+		if p.resolvedTsMinInterval <= 0 || ddlRawKV == nil {
 			if err := p.handleRawKVEntry(ctx, ddlRawKV); err != nil {
 				return errors.Trace(err)
 			}
+			continue
 		}
-	})
-	return eg.Wait()
+		if ddlRawKV.OpType != model.OpTypeResolved {
+			// The pending resolved ts is older than the DDL job, handle it
+			// first to keep the order.
+			if err := handlePendingResolved(); err != nil {
+				return errors.Trace(err)
+			}
+			if err := p.handleRawKVEntry(ctx, ddlRawKV); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+
+		// The newer resolved ts supersedes the pending one.
+		pendingResolved = ddlRawKV
+		elapsed := p.clock.Since(lastResolvedAt)
+		if elapsed >= p.resolvedTsMinInterval {
+			if err := handlePendingResolved(); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+		if timerCh == nil {
+			if timer == nil {
+				timer = p.clock.Timer(p.resolvedTsMinInterval - elapsed)
+			} else {
+				timer.Reset(p.resolvedTsMinInterval - elapsed)
+			}
+			timerCh = timer.C
+		}
+	}
 }
 
 func (p *ddlJobPullerImpl) runMultiplexing(ctx context.Context) error {
@@ -200,6 +373,25 @@ func (p *ddlJobPullerImpl) Output() <-chan *model.DDLJobEntry {
 	return p.outputCh
 }
 
+// Stats implements DDLJobPuller.Stats.
+func (p *ddlJobPullerImpl) Stats() DDLJobPullerStats {
+	var kvStats Stats
+	if p.multiplexing {
+		var stats []Stats
+		p.multiplexingPuller.AllStats().Range(func(_ tablepb.Span, s Stats) bool {
+			stats = append(stats, s)
+			return true
+		})
+		kvStats = SumStats(stats)
+	} else {
+		kvStats = p.puller.Stats()
+	}
+	return DDLJobPullerStats{
+		KVResolvedTs: kvStats.ResolvedTsEgress,
+		ResolvedTs:   p.getResolvedTs(),
+	}
+}
+
 func (p *ddlJobPullerImpl) getResolvedTs() uint64 {
 	return atomic.LoadUint64(&p.resolvedTs)
 }
@@ -248,7 +440,7 @@ func (p *ddlJobPullerImpl) unmarshalDDL(rawKV *model.RawKVEntry) (*timodel.Job,
 	if rawKV.OpType != model.OpTypePut {
 		return nil, nil
 	}
-	if p.ddlJobsTable == nil && !entry.IsLegacyFormatJob(rawKV) {
+	if p.ddlJobsTable == nil && !p.isLegacyFormatJob(rawKV) {
 		err := p.initJobTableMeta()
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -291,6 +483,7 @@ func (p *ddlJobPullerImpl) handleRenameTables(job *timodel.Job) (skip bool, err
 	// 2. old table name does not match and new table name matches the filter rule, return error.
 	// 3. old table name and new table name do not match the filter rule, skip it.
 	remainTables := make([]*timodel.TableInfo, 0, len(multiTableInfos))
+	renamedTables := make([]model.RenamedTable, 0, len(multiTableInfos))
 	snap := p.schemaStorage.GetLastSnapshot()
 	for i, tableInfo := range multiTableInfos {
 		var shouldDiscardOldTable, shouldDiscardNewTable bool
@@ -323,6 +516,16 @@ func (p *ddlJobPullerImpl) handleRenameTables(job *timodel.Job) (skip bool, err
 		}
 		// old table name matches the filter rule, remain it.
 		remainTables = append(remainTables, tableInfo)
+		renamedTable := model.RenamedTable{
+			TableID:   tableInfo.ID,
+			OldSchema: oldSchemaNames[i].O,
+			OldTable:  oldTable.Name.O,
+			NewTable:  newTableNames[i].O,
+		}
+		if newSchemaName != nil {
+			renamedTable.NewSchema = newSchemaName.Name.O
+		}
+		renamedTables = append(renamedTables, renamedTable)
 		remainOldSchemaIDs = append(remainOldSchemaIDs, oldSchemaIDs[i])
 		remainNewSchemaIDs = append(remainNewSchemaIDs, newSchemaIDs[i])
 		remainOldTableIDs = append(remainOldTableIDs, oldTableIDs[i])
@@ -367,6 +570,7 @@ func (p *ddlJobPullerImpl) handleRenameTables(job *timodel.Job) (skip bool, err
 	}
 	job.RawArgs = newRawArgs
 	job.BinlogInfo.MultipleTableInfos = remainTables
+	p.renamedTables = renamedTables
 	return false, nil
 }
 
@@ -415,6 +619,20 @@ func (p *ddlJobPullerImpl) handleJob(job *timodel.Job) (skip bool, err error) {
 		return true, nil
 	}
 
+	if p.maxDDLCommitTs > 0 && job.BinlogInfo.FinishedTS > p.maxDDLCommitTs {
+		log.Info("ddl job finishedTs greater than the max ddl commit ts,"+
+			"discard the ddl job",
+			zap.Uint64("jobFinishedTS", job.BinlogInfo.FinishedTS),
+			zap.Uint64("maxDDLCommitTs", p.maxDDLCommitTs),
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.String("schema", job.SchemaName),
+			zap.String("table", job.TableName),
+			zap.String("query", job.Query),
+			zap.String("job", job.String()))
+		return true, nil
+	}
+
 	snap := p.schemaStorage.GetLastSnapshot()
 	if err := snap.FillSchemaName(job); err != nil {
 		log.Info("failed to fill schema name for ddl job", zap.Error(err))
@@ -463,6 +681,14 @@ func (p *ddlJobPullerImpl) handleJob(job *timodel.Job) (skip bool, err error) {
 				return true, nil
 			}
 		}
+	case timodel.ActionMultiSchemaChange:
+		if job.BinlogInfo.TableInfo != nil {
+			job.TableName = job.BinlogInfo.TableInfo.Name.O
+		}
+		skip, err = p.handleMultiSchemaChange(job)
+		if err != nil {
+			return true, errors.Trace(err)
+		}
 	default:
 		// nil means it is a schema ddl job, it's no need to fill the table name.
 		if job.BinlogInfo.TableInfo != nil {
@@ -475,6 +701,13 @@ func (p *ddlJobPullerImpl) handleJob(job *timodel.Job) (skip bool, err error) {
 		return true, nil
 	}
 
+	// The rejected create table jobs have been handled by others, e.g. the peer
+	// cluster in bidirectional replication. They are still applied to the schema
+	// storage, so that the DMLs of the tables can be decoded. Note that they skip
+	// checkIneligibleTableDDL, which only decides whether to output a job.
+	rejectCreateTable := job.Type == timodel.ActionCreateTable &&
+		p.shouldApplyCreateTable != nil && !p.shouldApplyCreateTable(job)
+
 	err = p.schemaStorage.HandleDDLJob(job)
 	if err != nil {
 		log.Error("handle ddl job failed",
@@ -490,9 +723,58 @@ func (p *ddlJobPullerImpl) handleJob(job *timodel.Job) (skip bool, err error) {
 
 	p.setResolvedTs(job.BinlogInfo.FinishedTS)
 
+	if rejectCreateTable {
+		log.Info("create table ddl job is rejected, discard it",
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.String("schema", job.SchemaName),
+			zap.String("table", job.TableName),
+			zap.String("query", job.Query))
+		return true, nil
+	}
 	return p.checkIneligibleTableDDL(snap, job)
 }
 
+// handleMultiSchemaChange decides whether to discard a multi-schema-change job
+// by its sub-jobs, since each of them can be discarded by its own type. The job
+// is discarded only if all the sub-jobs are discarded. An error is returned if
+// only some of them are, since the job can't be applied in part.
+// The sub-jobs of types not in the allow list of the filter, e.g. adding a
+// foreign key, follow the decision of the outer job, since they can't be
+// discarded by users.
+func (p *ddlJobPullerImpl) handleMultiSchemaChange(job *timodel.Job) (skip bool, err error) {
+	discardJob := p.filter.ShouldDiscardDDL(job.Type, job.SchemaName, job.TableName)
+	if job.MultiSchemaInfo == nil || len(job.MultiSchemaInfo.SubJobs) == 0 {
+		return discardJob, nil
+	}
+
+	discarded := 0
+	for _, subJob := range job.MultiSchemaInfo.SubJobs {
+		discardSubJob := discardJob
+		if filter.IsAllowedDDL(subJob.Type) {
+			discardSubJob = p.filter.ShouldDiscardDDL(subJob.Type, job.SchemaName, job.TableName)
+		}
+		if discardSubJob {
+			discarded++
+		}
+	}
+	switch discarded {
+	case 0:
+		return false, nil
+	case len(job.MultiSchemaInfo.SubJobs):
+		return true, nil
+	}
+	log.Warn("some sub-changes of the multi-schema-change ddl job are discarded",
+		zap.String("namespace", p.changefeedID.Namespace),
+		zap.String("changefeed", p.changefeedID.ID),
+		zap.String("schema", job.SchemaName),
+		zap.String("table", job.TableName),
+		zap.Int("discarded", discarded),
+		zap.Int("subJobs", len(job.MultiSchemaInfo.SubJobs)),
+		zap.String("query", job.Query))
+	return true, cerror.ErrSyncMultiSchemaChangeFailed.GenWithStackByArgs(job.TableID, job.Query)
+}
+
 // checkIneligibleTableDDL checks if the table is ineligible before and after the DDL.
 //  1. If it is not a table DDL, we shouldn't check it.
 //  2. If the table after the DDL is ineligible:
@@ -514,9 +796,30 @@ func (p *ddlJobPullerImpl) checkIneligibleTableDDL(snapBefore *schema.Snapshot,
 		}
 	}
 
-	// For create tables, we always apply the DDL here.
+	// For create tables, each created table is checked like create table. The
+	// DDL is ignored if all the tables are ineligible, otherwise it's applied
+	// as a whole, and the ineligible tables are logged since they won't be
+	// replicated. The tables are checked by their infos in the job, since the
+	// snapshot doesn't hold the tables created in batch.
 	if job.Type == timodel.ActionCreateTables {
-		return false, nil
+		var ineligibleTables []string
+		for _, tableInfo := range job.BinlogInfo.MultipleTableInfos {
+			wrapped := model.WrapTableInfo(job.SchemaID, job.SchemaName,
+				job.BinlogInfo.FinishedTS, tableInfo)
+			if !snapAfter.IsEligibleTable(wrapped) {
+				ineligibleTables = append(ineligibleTables, tableInfo.Name.O)
+			}
+		}
+		if len(ineligibleTables) == 0 {
+			return false, nil
+		}
+		log.Warn("Some tables created by the DDL are ineligible and won't be replicated",
+			zap.String("namespace", p.changefeedID.Namespace),
+			zap.String("changefeed", p.changefeedID.ID),
+			zap.String("schema", job.SchemaName),
+			zap.Strings("ineligibleTables", ineligibleTables),
+			zap.String("query", job.Query))
+		return len(ineligibleTables) == len(job.BinlogInfo.MultipleTableInfos), nil
 	}
 
 	oldTableID := job.TableID
@@ -590,15 +893,21 @@ func findColumnByName(cols []*timodel.ColumnInfo, name string) (*timodel.ColumnI
 
 // NewDDLJobPuller creates a new NewDDLJobPuller,
 // which fetches ddl events starting from checkpointTs.
+// DDL jobs finished after maxDDLCommitTs are discarded if it's not 0.
+// Create table jobs are discarded if shouldApplyCreateTable is not nil and
+// returns false for them.
 func NewDDLJobPuller(
 	ctx context.Context,
 	up *upstream.Upstream,
 	checkpointTs uint64,
+	maxDDLCommitTs uint64,
+	shouldApplyCreateTable func(job *timodel.Job) bool,
 	cfg *config.ServerConfig,
 	changefeed model.ChangeFeedID,
 	schemaStorage entry.SchemaStorage,
 	filter filter.Filter,
 	isOwner bool,
+	clk clock.Clock,
 ) (DDLJobPuller, error) {
 	pdCli := up.PDClient
 	regionCache := up.RegionCache
@@ -618,12 +927,18 @@ func NewDDLJobPuller(
 	}
 
 	jobPuller := &ddlJobPullerImpl{
-		changefeedID:  changefeed,
-		multiplexing:  cfg.KVClient.EnableMultiplexing,
-		schemaStorage: schemaStorage,
-		kvStorage:     kvStorage,
-		filter:        filter,
-		outputCh:      make(chan *model.DDLJobEntry, defaultPullerOutputChanSize),
+		changefeedID:           changefeed,
+		multiplexing:           cfg.KVClient.EnableMultiplexing,
+		schemaStorage:          schemaStorage,
+		kvStorage:              kvStorage,
+		maxDDLCommitTs:         maxDDLCommitTs,
+		shouldApplyCreateTable: shouldApplyCreateTable,
+		isLegacyFormatJob:      entry.IsLegacyFormatJob,
+		computeDDLDigest:       cfg.Debug.Puller.ComputeDDLDigest,
+		resolvedTsMinInterval:  time.Duration(cfg.Debug.Puller.DDLResolvedTsMinInterval),
+		clock:                  clk,
+		filter:                 filter,
+		outputCh:               make(chan *model.DDLJobEntry, defaultPullerOutputChanSize),
 	}
 	if jobPuller.multiplexing {
 		mp := &jobPuller.multiplexingPuller
@@ -651,11 +966,15 @@ func NewDDLJobPuller(
 
 		mp.Subscribe(spans, checkpointTs, memorysorter.DDLPullerTableName, func(_ *model.RawKVEntry) bool { return false })
 	} else {
-		jobPuller.puller.Puller = New(
+		p, err := New(
 			ctx, pdCli, up.GrpcPool, regionCache, kvStorage, pdClock,
 			checkpointTs, spans, cfg, changefeed, -1, memorysorter.DDLPullerTableName,
 			ddlPullerFilterLoop,
 		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		jobPuller.puller.Puller = p
 	}
 
 	return jobPuller, nil
@@ -666,26 +985,91 @@ type DDLPuller interface {
 	// Run runs the DDLPuller
 	Run(ctx context.Context) error
 	// PopFrontDDL returns and pops the first DDL job in the internal queue
-	PopFrontDDL() (uint64, *timodel.Job)
+	PopFrontDDL() (uint64, *timodel.Job, error)
 	// ResolvedTs returns the resolved ts of the DDLPuller
-	ResolvedTs() uint64
+	ResolvedTs() (uint64, error)
+	// DropPendingDDLs drops all pending DDL jobs matching the predicate and
+	// returns the number of dropped jobs. It's an admin operation used to
+	// discard DDLs that have already been applied out-of-band.
+	DropPendingDDLs(pred func(*timodel.Job) bool) int
+	// SetOnResolvedTsAdvanced sets a callback invoked with the new resolved ts
+	// whenever it advances. It must be called before Run.
+	SetOnResolvedTsAdvanced(fn func(ts uint64))
+	// SetMinResolvedTs sets the floor of the resolved ts, the resolved ts
+	// returned by ResolvedTs and PopFrontDDL is never below it. It must be
+	// called before Run.
+	SetMinResolvedTs(ts uint64)
 	// Close closes the DDLPuller
 	Close()
 }
 
+// recentDDLJobWindowSize is the number of recently received DDL jobs
+// remembered to detect duplicated jobs.
+const recentDDLJobWindowSize = 128
+
+type ddlJobKey struct {
+	jobID         int64
+	schemaVersion int64
+}
+
+// recentDDLJobs is a bounded window of recently received DDL jobs, the oldest
+// one is evicted when the window is full. The zero value is ready to use.
+type recentDDLJobs struct {
+	keys []ddlJobKey
+	next int
+	set  map[ddlJobKey]struct{}
+}
+
+func (r *recentDDLJobs) contains(key ddlJobKey) bool {
+	_, ok := r.set[key]
+	return ok
+}
+
+func (r *recentDDLJobs) add(key ddlJobKey) {
+	if r.set == nil {
+		r.set = make(map[ddlJobKey]struct{}, recentDDLJobWindowSize)
+	}
+	if len(r.keys) < recentDDLJobWindowSize {
+		r.keys = append(r.keys, key)
+	} else {
+		delete(r.set, r.keys[r.next])
+		r.keys[r.next] = key
+		r.next = (r.next + 1) % recentDDLJobWindowSize
+	}
+	r.set[key] = struct{}{}
+}
+
 type ddlPullerImpl struct {
 	ddlJobPuller DDLJobPuller
 
 	mu             sync.Mutex
 	resolvedTS     uint64
 	pendingDDLJobs []*timodel.Job
-	lastDDLJobID   int64
-	cancel         context.CancelFunc
+	// recentDDLJobs is used to ignore the DDL jobs sent more than once, which
+	// may be out of order, e.g. during region merges.
+	recentDDLJobs recentDDLJobs
+	cancel        context.CancelFunc
 
 	changefeedID model.ChangeFeedID
 
 	clock                      clock.Clock
 	lastResolvedTsAdvancedTime time.Time
+
+	// reportedResolvedTs is the max resolved ts that has been reported,
+	// it's used to detect resolved ts regression. It's protected by mu.
+	reportedResolvedTs uint64
+	// errorOnResolvedTsRegression indicates whether to return an error or
+	// only warn when the resolved ts is about to regress.
+	errorOnResolvedTsRegression bool
+
+	// onResolvedTsAdvanced is called without holding mu, so it's free to
+	// query the puller.
+	onResolvedTsAdvanced func(ts uint64)
+	// minResolvedTs is the floor of the reported resolved ts. Unlike startTs,
+	// which is where the puller starts pulling, it only prevents stale
+	// resolved events and pending jobs from lowering the resolved ts, e.g. in
+	// recovery flows. It's protected by mu.
+	minResolvedTs uint64
 }
 
 // NewDDLPuller return a puller for DDL Event
@@ -700,12 +1084,16 @@ func NewDDLPuller(ctx context.Context,
 	var puller DDLJobPuller
 	var err error
 
+	clk := clock.New()
 	// storage can be nil only in the test
 	if up.KVStorage != nil {
 		puller, err = NewDDLJobPuller(
-			ctx, up, startTs, config.GetGlobalServerConfig(),
+			ctx, up, startTs, 0, /* maxDDLCommitTs */
+			nil, /* shouldApplyCreateTable */
+			config.GetGlobalServerConfig(),
 			changefeed, schemaStorage, filter,
 			true, /* isOwner */
+			clk,
 		)
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -716,8 +1104,10 @@ func NewDDLPuller(ctx context.Context,
 		ddlJobPuller: puller,
 		resolvedTS:   startTs,
 		cancel:       func() {},
-		clock:        clock.New(),
+		clock:        clk,
 		changefeedID: changefeed,
+
+		errorOnResolvedTsRegression: config.GetGlobalServerConfig().Debug.Puller.ErrorOnDDLResolvedTsRegression,
 	}, nil
 }
 
@@ -726,6 +1116,9 @@ func (h *ddlPullerImpl) handleDDLJobEntry(jobEntry *model.DDLJobEntry) error {
 		if jobEntry.CRTs > atomic.LoadUint64(&h.resolvedTS) {
 			h.lastResolvedTsAdvancedTime = h.clock.Now()
 			atomic.StoreUint64(&h.resolvedTS, jobEntry.CRTs)
+			if h.onResolvedTsAdvanced != nil {
+				h.onResolvedTsAdvanced(jobEntry.CRTs)
+			}
 		}
 		return nil
 	}
@@ -737,12 +1130,17 @@ func (h *ddlPullerImpl) handleDDLJobEntry(jobEntry *model.DDLJobEntry) error {
 		return nil
 	}
 	log.Info("[ddl] handleDDLJobEntry", zap.String("job", job.String()))
-	if job.ID == h.lastDDLJobID {
+	key := ddlJobKey{jobID: job.ID}
+	if job.BinlogInfo != nil {
+		key.schemaVersion = job.BinlogInfo.SchemaVersion
+	}
+	if h.recentDDLJobs.contains(key) {
 		log.Warn("ignore duplicated DDL job",
 			zap.String("namespace", h.changefeedID.Namespace),
 			zap.String("changefeed", h.changefeedID.ID),
 			zap.String("query", job.Query),
 			zap.Int64("jobID", job.ID),
+			zap.Int64("schemaVersion", key.schemaVersion),
 			zap.Any("job", job))
 		return nil
 	}
@@ -756,10 +1154,41 @@ func (h *ddlPullerImpl) handleDDLJobEntry(jobEntry *model.DDLJobEntry) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.pendingDDLJobs = append(h.pendingDDLJobs, job)
-	h.lastDDLJobID = job.ID
+	h.recentDDLJobs.add(key)
 	return nil
 }
 
+// SetOnResolvedTsAdvanced implements DDLPuller.SetOnResolvedTsAdvanced.
+func (h *ddlPullerImpl) SetOnResolvedTsAdvanced(fn func(ts uint64)) {
+	h.onResolvedTsAdvanced = fn
+}
+
+// SetClock overrides the clock of the puller and its DDL job puller, it's
+// only used in tests and should be called before Run.
+func (h *ddlPullerImpl) SetClock(clk clock.Clock) {
+	h.clock = clk
+	if jobPuller, ok := h.ddlJobPuller.(*ddlJobPullerImpl); ok {
+		jobPuller.clock = clk
+	}
+}
+
+// SetMinResolvedTs implements DDLPuller.SetMinResolvedTs.
+func (h *ddlPullerImpl) SetMinResolvedTs(ts uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.minResolvedTs = ts
+	// The resolved events below the floor are ignored since they can't
+	// advance resolvedTS anymore.
+	if atomic.LoadUint64(&h.resolvedTS) < ts {
+		atomic.StoreUint64(&h.resolvedTS, ts)
+	}
+	// The reported resolved ts is raised too, so that pending jobs below the
+	// floor don't lower it.
+	if h.reportedResolvedTs < ts {
+		h.reportedResolvedTs = ts
+	}
+}
+
 // Run the ddl puller to receive DDL events
 func (h *ddlPullerImpl) Run(ctx context.Context) error {
 	g, ctx := errgroup.WithContext(ctx)
@@ -808,15 +1237,47 @@ func (h *ddlPullerImpl) Run(ctx context.Context) error {
 }
 
 // PopFrontDDL return the first pending DDL job and remove it from the pending list
-func (h *ddlPullerImpl) PopFrontDDL() (uint64, *timodel.Job) {
+func (h *ddlPullerImpl) PopFrontDDL() (uint64, *timodel.Job, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	if len(h.pendingDDLJobs) == 0 {
-		return atomic.LoadUint64(&h.resolvedTS), nil
+		return atomic.LoadUint64(&h.resolvedTS), nil, nil
 	}
 	job := h.pendingDDLJobs[0]
+	resolvedTs, err := h.checkResolvedTsRegression(job)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
 	h.pendingDDLJobs = h.pendingDDLJobs[1:]
-	return job.BinlogInfo.FinishedTS, job
+	return resolvedTs, job, nil
+}
+
+// DropPendingDDLs drops all pending DDL jobs matching the predicate.
+func (h *ddlPullerImpl) DropPendingDDLs(pred func(*timodel.Job) bool) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	dropped := 0
+	remained := make([]*timodel.Job, 0, len(h.pendingDDLJobs))
+	for _, job := range h.pendingDDLJobs {
+		if !pred(job) {
+			remained = append(remained, job)
+			continue
+		}
+		dropped++
+		log.Warn("drop pending DDL job by admin operation",
+			zap.String("namespace", h.changefeedID.Namespace),
+			zap.String("changefeed", h.changefeedID.ID),
+			zap.String("query", job.Query),
+			zap.Int64("jobID", job.ID),
+			zap.Uint64("finishedTs", job.BinlogInfo.FinishedTS))
+	}
+	h.pendingDDLJobs = remained
+	log.Warn("drop pending DDL jobs finished",
+		zap.String("namespace", h.changefeedID.Namespace),
+		zap.String("changefeed", h.changefeedID.ID),
+		zap.Int("dropped", dropped),
+		zap.Int("remained", len(remained)))
+	return dropped
 }
 
 // Close the ddl puller, release all resources.
@@ -830,12 +1291,55 @@ func (h *ddlPullerImpl) Close() {
 		zap.String("changefeed", h.changefeedID.ID))
 }
 
-func (h *ddlPullerImpl) ResolvedTs() uint64 {
+func (h *ddlPullerImpl) ResolvedTs() (uint64, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	if len(h.pendingDDLJobs) == 0 {
-		return atomic.LoadUint64(&h.resolvedTS)
+		resolvedTs := atomic.LoadUint64(&h.resolvedTS)
+		if resolvedTs > h.reportedResolvedTs {
+			h.reportedResolvedTs = resolvedTs
+		}
+		return resolvedTs, nil
 	}
-	job := h.pendingDDLJobs[0]
-	return job.BinlogInfo.FinishedTS
+	resolvedTs, err := h.checkResolvedTsRegression(h.pendingDDLJobs[0])
+	return resolvedTs, errors.Trace(err)
+}
+
+// checkResolvedTsRegression returns the resolved ts that can be reported for
+// the head job, it must be called with h.mu held. Pending jobs are assumed to
+// be ordered by FinishedTS, so if the head job's FinishedTS is less than the
+// reported resolved ts, the reported one is returned to prevent regression,
+// or an error is returned if errorOnResolvedTsRegression is set.
+func (h *ddlPullerImpl) checkResolvedTsRegression(job *timodel.Job) (uint64, error) {
+	finishedTs := job.BinlogInfo.FinishedTS
+	if finishedTs >= h.reportedResolvedTs {
+		h.reportedResolvedTs = finishedTs
+		return finishedTs, nil
+	}
+	if finishedTs < h.minResolvedTs {
+		// It's expected that jobs below the floor are still pending, the
+		// reported resolved ts is never lower than the floor.
+		log.Debug("ddl puller resolved ts is clamped to the min resolved ts",
+			zap.String("namespace", h.changefeedID.Namespace),
+			zap.String("changefeed", h.changefeedID.ID),
+			zap.Int64("jobID", job.ID),
+			zap.Uint64("finishedTs", finishedTs),
+			zap.Uint64("minResolvedTs", h.minResolvedTs))
+		return h.reportedResolvedTs, nil
+	}
+	fields := []zap.Field{
+		zap.String("namespace", h.changefeedID.Namespace),
+		zap.String("changefeed", h.changefeedID.ID),
+		zap.String("query", job.Query),
+		zap.Int64("jobID", job.ID),
+		zap.Uint64("finishedTs", finishedTs),
+		zap.Uint64("reportedResolvedTs", h.reportedResolvedTs),
+	}
+	if h.errorOnResolvedTsRegression {
+		log.Error("ddl puller resolved ts regressed, DDL jobs are out of order", fields...)
+		return 0, cerror.ErrDDLPullerResolvedTsRegressed.GenWithStackByArgs(
+			h.reportedResolvedTs, finishedTs)
+	}
+	log.Warn("ddl puller resolved ts regressed, DDL jobs are out of order", fields...)
+	return h.reportedResolvedTs, nil
 }
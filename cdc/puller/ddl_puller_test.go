@@ -33,10 +33,13 @@ import (
 	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tiflow/cdc/entry"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/puller/memorysorter"
 	"github.com/pingcap/tiflow/pkg/config"
 	cdcContext "github.com/pingcap/tiflow/pkg/context"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/filter"
 	"github.com/pingcap/tiflow/pkg/retry"
+	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/upstream"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
@@ -82,7 +85,7 @@ func (m *mockPuller) Output() <-chan *model.RawKVEntry {
 }
 
 func (m *mockPuller) Stats() Stats {
-	return Stats{}
+	return Stats{ResolvedTsEgress: atomic.LoadUint64(&m.resolvedTs)}
 }
 
 func (m *mockPuller) append(e *model.RawKVEntry) {
@@ -122,6 +125,8 @@ func newMockDDLJobPuller(
 		outputCh: make(
 			chan *model.DDLJobEntry,
 			defaultPullerOutputChanSize),
+		isLegacyFormatJob: entry.IsLegacyFormatJob,
+		clock:             clock.New(),
 	}
 	res.multiplexing = false
 	res.puller.Puller = puller
@@ -580,6 +585,229 @@ func TestHandleJob(t *testing.T) {
 	}
 }
 
+// discardDDLTypesFilter discards the DDLs of the given types, and the others
+// are decided by the embedded filter.
+type discardDDLTypesFilter struct {
+	filter.Filter
+	discardTypes map[timodel.ActionType]struct{}
+}
+
+func (f *discardDDLTypesFilter) ShouldDiscardDDL(
+	ddlType timodel.ActionType, schema, table string,
+) bool {
+	if _, ok := f.discardTypes[ddlType]; ok {
+		return true
+	}
+	return f.Filter.ShouldDiscardDDL(ddlType, schema, table)
+}
+
+func TestHandleMultiSchemaChangeJob(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, helper := newMockDDLJobPuller(t, mockPuller, true)
+	defer helper.Close()
+
+	ddlJobPullerImpl := ddlJobPuller.(*ddlJobPullerImpl)
+	f, err := filter.NewFilter(config.GetDefaultReplicaConfig(), "")
+	require.NoError(t, err)
+	mockFilter := &discardDDLTypesFilter{Filter: f}
+	ddlJobPullerImpl.filter = mockFilter
+
+	job := helper.DDL2Job("create database test1")
+	skip, err := ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.False(t, skip)
+	job = helper.DDL2Job("create table test1.t1(id int primary key, c1 int)")
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.False(t, skip)
+
+	job = helper.DDL2Job("alter table test1.t1 add column c2 int, add index idx_c1(c1)")
+	require.Equal(t, timodel.ActionMultiSchemaChange, job.Type)
+	require.Len(t, job.MultiSchemaInfo.SubJobs, 2)
+
+	// Only some of the sub-changes are discarded.
+	mockFilter.discardTypes = map[timodel.ActionType]struct{}{
+		timodel.ActionAddIndex: {},
+	}
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.True(t, cerror.ErrSyncMultiSchemaChangeFailed.Equal(err), err)
+	require.True(t, skip)
+
+	// All the sub-changes are discarded.
+	mockFilter.discardTypes = map[timodel.ActionType]struct{}{
+		timodel.ActionAddColumn: {},
+		timodel.ActionAddIndex:  {},
+	}
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.True(t, skip)
+
+	// None of the sub-changes is discarded, even if the outer job type is.
+	mockFilter.discardTypes = map[timodel.ActionType]struct{}{
+		timodel.ActionMultiSchemaChange: {},
+	}
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.False(t, skip)
+
+	// The sub-changes not in the allow list, e.g. adding a foreign key, follow
+	// the decision of the outer job.
+	mockFilter.discardTypes = nil
+	job = helper.DDL2Job("create table test1.t2(id int primary key)")
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.False(t, skip)
+	job = helper.DDL2Job("alter table test1.t1 add column c3 int, " +
+		"add constraint fk_c1 foreign key (c1) references test1.t2(id)")
+	require.Equal(t, timodel.ActionMultiSchemaChange, job.Type)
+	subJobTypes := make([]timodel.ActionType, 0, len(job.MultiSchemaInfo.SubJobs))
+	for _, subJob := range job.MultiSchemaInfo.SubJobs {
+		subJobTypes = append(subJobTypes, subJob.Type)
+	}
+	require.Contains(t, subJobTypes, timodel.ActionAddForeignKey)
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.False(t, skip)
+
+	mockFilter.discardTypes = map[timodel.ActionType]struct{}{
+		timodel.ActionMultiSchemaChange: {},
+		timodel.ActionAddColumn:         {},
+	}
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.True(t, skip)
+}
+
+func TestHandleJobWithMaxDDLCommitTs(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, helper := newMockDDLJobPuller(t, mockPuller, true)
+	defer helper.Close()
+
+	ddlJobPullerImpl := ddlJobPuller.(*ddlJobPullerImpl)
+	f, err := filter.NewFilter(config.GetDefaultReplicaConfig(), "")
+	require.NoError(t, err)
+	ddlJobPullerImpl.filter = f
+
+	job := helper.DDL2Job("create database test1")
+	ddlJobPullerImpl.maxDDLCommitTs = job.BinlogInfo.FinishedTS
+	// The job finished exactly at the bound is kept.
+	skip, err := ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.False(t, skip)
+
+	// The job finished after the bound is skipped without error.
+	job = helper.DDL2Job("create table test1.t1(id int primary key)")
+	require.Greater(t, job.BinlogInfo.FinishedTS, ddlJobPullerImpl.maxDDLCommitTs)
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.True(t, skip)
+
+	// The resolved ts advances up to the bound only.
+	ctx := context.Background()
+	maxDDLCommitTs := ddlJobPullerImpl.maxDDLCommitTs
+	for _, ts := range []uint64{maxDDLCommitTs - 1, maxDDLCommitTs + 1} {
+		require.NoError(t, ddlJobPullerImpl.handleRawKVEntry(ctx, &model.RawKVEntry{
+			OpType: model.OpTypeResolved, CRTs: ts, StartTs: ts,
+		}))
+	}
+	require.Equal(t, maxDDLCommitTs, ddlJobPullerImpl.getResolvedTs())
+	require.Equal(t, maxDDLCommitTs-1, (<-ddlJobPullerImpl.Output()).CRTs)
+	require.Equal(t, maxDDLCommitTs, (<-ddlJobPullerImpl.Output()).CRTs)
+}
+
+func TestUnmarshalDDLWithLegacyFormatJobDetector(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, helper := newMockDDLJobPuller(t, mockPuller, true)
+	defer helper.Close()
+	ddlJobPullerImpl := ddlJobPuller.(*ddlJobPullerImpl)
+
+	job := helper.DDL2Job("create database test1")
+	value, err := json.Marshal(job)
+	require.NoError(t, err)
+	// A job of the legacy DDL list.
+	rawKV := &model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     []byte("mDDLJobList"),
+		Value:   value,
+		StartTs: job.StartTS,
+		CRTs:    job.BinlogInfo.FinishedTS,
+	}
+
+	// The job table meta isn't initialized for legacy jobs.
+	detected := 0
+	ddlJobPullerImpl.isLegacyFormatJob = func(*model.RawKVEntry) bool {
+		detected++
+		return true
+	}
+	parsed, err := ddlJobPullerImpl.unmarshalDDL(rawKV)
+	require.NoError(t, err)
+	require.Equal(t, job.ID, parsed.ID)
+	require.Equal(t, 1, detected)
+	require.Nil(t, ddlJobPullerImpl.ddlJobsTable)
+
+	// The job table meta is initialized once the detector reports a
+	// non-legacy job, and the detector isn't consulted after that.
+	ddlJobPullerImpl.isLegacyFormatJob = func(*model.RawKVEntry) bool {
+		detected++
+		return false
+	}
+	parsed, err = ddlJobPullerImpl.unmarshalDDL(rawKV)
+	require.NoError(t, err)
+	require.Equal(t, job.ID, parsed.ID)
+	require.Equal(t, 2, detected)
+	require.NotNil(t, ddlJobPullerImpl.ddlJobsTable)
+	require.NotZero(t, ddlJobPullerImpl.jobMetaColumnID)
+
+	_, err = ddlJobPullerImpl.unmarshalDDL(rawKV)
+	require.NoError(t, err)
+	require.Equal(t, 2, detected)
+}
+
+func TestHandleJobWithShouldApplyCreateTable(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, helper := newMockDDLJobPuller(t, mockPuller, true)
+	defer helper.Close()
+
+	ddlJobPullerImpl := ddlJobPuller.(*ddlJobPullerImpl)
+	f, err := filter.NewFilter(config.GetDefaultReplicaConfig(), "")
+	require.NoError(t, err)
+	ddlJobPullerImpl.filter = f
+	ddlJobPullerImpl.shouldApplyCreateTable = func(job *timodel.Job) bool {
+		return job.TableName != "rejected"
+	}
+
+	job := helper.DDL2Job("create database test1")
+	skip, err := ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.False(t, skip)
+
+	job = helper.DDL2Job("create table test1.accepted(id int primary key)")
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.False(t, skip)
+
+	// The rejected job is skipped without error, but the table is still
+	// applied to the schema storage.
+	job = helper.DDL2Job("create table test1.rejected(id int primary key)")
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.True(t, skip)
+	require.Equal(t, job.BinlogInfo.FinishedTS, ddlJobPullerImpl.getResolvedTs())
+	snap := ddlJobPullerImpl.schemaStorage.GetLastSnapshot()
+	_, ok := snap.TableByName("test1", "rejected")
+	require.True(t, ok)
+
+	// Other DDLs of the rejected table are not affected by the hook.
+	job = helper.DDL2Job("alter table test1.rejected add column c int")
+	skip, err = ddlJobPullerImpl.handleJob(job)
+	require.NoError(t, err)
+	require.False(t, skip)
+}
+
 func waitResolvedTs(t *testing.T, p DDLJobPuller, targetTs model.Ts) {
 	err := retry.Do(context.Background(), func() error {
 		if p.(*ddlJobPullerImpl).getResolvedTs() < targetTs {
@@ -624,7 +852,7 @@ func TestDDLPuller(t *testing.T) {
 	defer wg.Wait()
 	defer p.Close()
 
-	resolvedTs, ddl := p.PopFrontDDL()
+	resolvedTs, ddl := mustPopFrontDDL(t, p)
 	require.Equal(t, resolvedTs, startTs)
 	require.Nil(t, ddl)
 
@@ -649,19 +877,19 @@ func TestDDLPuller(t *testing.T) {
 		BinlogInfo: &timodel.HistoryInfo{SchemaVersion: 1, FinishedTS: 16},
 		Query:      "create table t2(id int primary key)",
 	})
-	resolvedTs, ddl = p.PopFrontDDL()
+	resolvedTs, ddl = mustPopFrontDDL(t, p)
 	require.Equal(t, resolvedTs, uint64(15))
 	require.Nil(t, ddl)
 
 	mockPuller.appendResolvedTs(20)
 	waitResolvedTsGrowing(t, p, 16)
-	resolvedTs, ddl = p.PopFrontDDL()
+	resolvedTs, ddl = mustPopFrontDDL(t, p)
 	require.Equal(t, resolvedTs, uint64(16))
 	require.Equal(t, ddl.ID, int64(1))
 
 	// DDL could be processed with a delay, wait here for a pending DDL job is added
 	waitResolvedTsGrowing(t, p, 18)
-	resolvedTs, ddl = p.PopFrontDDL()
+	resolvedTs, ddl = mustPopFrontDDL(t, p)
 	require.Equal(t, resolvedTs, uint64(18))
 	require.Equal(t, ddl.ID, int64(2))
 
@@ -685,14 +913,14 @@ func TestDDLPuller(t *testing.T) {
 	mockPuller.appendResolvedTs(30)
 	waitResolvedTsGrowing(t, p, 25)
 
-	resolvedTs, ddl = p.PopFrontDDL()
+	resolvedTs, ddl = mustPopFrontDDL(t, p)
 	require.Equal(t, resolvedTs, uint64(25))
 	require.Equal(t, ddl.ID, int64(3))
-	_, ddl = p.PopFrontDDL()
+	_, ddl = mustPopFrontDDL(t, p)
 	require.Nil(t, ddl)
 
 	waitResolvedTsGrowing(t, p, 30)
-	resolvedTs, ddl = p.PopFrontDDL()
+	resolvedTs, ddl = mustPopFrontDDL(t, p)
 	require.Equal(t, resolvedTs, uint64(30))
 	require.Nil(t, ddl)
 
@@ -706,7 +934,7 @@ func TestDDLPuller(t *testing.T) {
 	})
 	mockPuller.appendResolvedTs(40)
 	waitResolvedTsGrowing(t, p, 40)
-	resolvedTs, ddl = p.PopFrontDDL()
+	resolvedTs, ddl = mustPopFrontDDL(t, p)
 	// no ddl should be received
 	require.Equal(t, resolvedTs, uint64(40))
 	require.Nil(t, ddl)
@@ -761,7 +989,7 @@ func TestResolvedTsStuck(t *testing.T) {
 	defer p.Close()
 
 	// test initialize state
-	resolvedTs, ddl := p.PopFrontDDL()
+	resolvedTs, ddl := mustPopFrontDDL(t, p)
 	require.Equal(t, resolvedTs, startTs)
 	require.Nil(t, ddl)
 
@@ -785,11 +1013,89 @@ func TestResolvedTsStuck(t *testing.T) {
 	waitResolvedTsGrowing(t, p, 40)
 }
 
+func TestResolvedTsStuckWarnOnce(t *testing.T) {
+	// For observing the logs
+	zapcore, logs := observer.New(zap.WarnLevel)
+	conf := &log.Config{Level: "warn", File: log.FileLogConfig{}}
+	_, r, _ := log.InitLogger(conf)
+	logger := zap.New(zapcore)
+	restoreFn := log.ReplaceGlobals(logger, r)
+	defer restoreFn()
+
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ctx := cdcContext.NewBackendContext4Test(true)
+	up := upstream.NewUpstream4Test(nil)
+	f, err := filter.NewFilter(config.GetDefaultReplicaConfig(), "")
+	require.Nil(t, err)
+	p, err := NewDDLPuller(
+		ctx, ctx.ChangefeedVars().Info.Config,
+		up, startTs,
+		ctx.ChangefeedVars().ID,
+		nil, /* schemaStorage */
+		f)
+	require.Nil(t, err)
+	p.(*ddlPullerImpl).ddlJobPuller, _ = newMockDDLJobPuller(t, mockPuller, false)
+	mockClock := clock.NewMock()
+	p.(*ddlPullerImpl).SetClock(mockClock)
+	require.Equal(t, mockClock, p.(*ddlPullerImpl).ddlJobPuller.(*ddlJobPullerImpl).clock)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := p.Run(ctx)
+		if errors.Cause(err) == context.Canceled {
+			err = nil
+		}
+		require.Nil(t, err)
+	}()
+	defer wg.Wait()
+	defer p.Close()
+
+	mockPuller.appendResolvedTs(30)
+	waitResolvedTsGrowing(t, p, 30)
+
+	// The resolved ts has not advanced for exactly the threshold, no warning.
+	mockClock.Add(ddlPullerStuckWarnDuration)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, 0, logs.Len())
+
+	// The threshold is exceeded.
+	mockClock.Add(ddlPullerStuckWarnDuration)
+	require.Eventually(t, func() bool {
+		return logs.Len() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// No more warnings after the resolved ts advances.
+	mockPuller.appendResolvedTs(40)
+	waitResolvedTsGrowing(t, p, 40)
+	mockClock.Add(ddlPullerStuckWarnDuration)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, 1, logs.FilterMessage("ddl puller resolved ts has not advanced").Len())
+	require.Equal(t, 1, logs.Len())
+}
+
+func mustPopFrontDDL(t *testing.T, p DDLPuller) (uint64, *timodel.Job) {
+	resolvedTs, job, err := p.PopFrontDDL()
+	require.NoError(t, err)
+	return resolvedTs, job
+}
+
+func mustResolvedTs(t *testing.T, p DDLPuller) uint64 {
+	resolvedTs, err := p.ResolvedTs()
+	require.NoError(t, err)
+	return resolvedTs
+}
+
 // waitResolvedTsGrowing can wait the first DDL reaches targetTs or if no pending
 // DDL, DDL resolved ts reaches targetTs.
 func waitResolvedTsGrowing(t *testing.T, p DDLPuller, targetTs model.Ts) {
 	err := retry.Do(context.Background(), func() error {
-		resolvedTs := p.ResolvedTs()
+		resolvedTs, err := p.ResolvedTs()
+		if err != nil {
+			return err
+		}
 		if resolvedTs < targetTs {
 			return errors.New("resolvedTs < targetTs")
 		}
@@ -890,4 +1196,473 @@ func TestCcheckIneligibleTableDDL(t *testing.T) {
 	skip, err = ddlJobPullerImpl.handleJob(ddl)
 	require.NoError(t, err)
 	require.False(t, skip)
+
+	// case 7: Batch create tables, only some of them are ineligible, expect
+	// to apply the DDL.
+	jobs := helper.DDL2Jobs(`CREATE TABLE test1.t7 (id INT);
+		CREATE TABLE test1.t8 (id INT PRIMARY KEY);
+		CREATE TABLE test1.t9 (id INT);`, 3)
+	newCreateTablesJob := func(jobs []*timodel.Job) *timodel.Job {
+		// The jobs are in the reverse order of execution.
+		last := jobs[0]
+		job := &timodel.Job{
+			ID:         last.ID,
+			Type:       timodel.ActionCreateTables,
+			State:      timodel.JobStateDone,
+			SchemaID:   last.SchemaID,
+			SchemaName: last.SchemaName,
+			BinlogInfo: &timodel.HistoryInfo{
+				SchemaVersion: last.BinlogInfo.SchemaVersion,
+				FinishedTS:    last.BinlogInfo.FinishedTS,
+			},
+		}
+		for i := len(jobs) - 1; i >= 0; i-- {
+			job.BinlogInfo.MultipleTableInfos = append(
+				job.BinlogInfo.MultipleTableInfos, jobs[i].BinlogInfo.TableInfo)
+			job.Query += jobs[i].Query
+		}
+		return job
+	}
+	skip, err = ddlJobPullerImpl.checkIneligibleTableDDL(
+		ddlJobPullerImpl.schemaStorage.GetLastSnapshot(), newCreateTablesJob(jobs))
+	require.NoError(t, err)
+	require.False(t, skip)
+
+	// case 8: Batch create tables, all of them are ineligible, expect to skip.
+	jobs = helper.DDL2Jobs(`CREATE TABLE test1.t10 (id INT);
+		CREATE TABLE test1.t11 (id INT);`, 2)
+	skip, err = ddlJobPullerImpl.checkIneligibleTableDDL(
+		ddlJobPullerImpl.schemaStorage.GetLastSnapshot(), newCreateTablesJob(jobs))
+	require.NoError(t, err)
+	require.True(t, skip)
+}
+
+func TestDDLQueryDigest(t *testing.T) {
+	d1, err := ddlQueryDigest("ALTER TABLE test.t1 ADD COLUMN c1 INT DEFAULT 1")
+	require.NoError(t, err)
+	d2, err := ddlQueryDigest("alter table `t2` add column c1 int default 100")
+	require.NoError(t, err)
+	require.NotEmpty(t, d1)
+	require.Equal(t, d1, d2)
+
+	// The structurally different DDLs have different digests.
+	d3, err := ddlQueryDigest("ALTER TABLE test.t1 DROP COLUMN c1")
+	require.NoError(t, err)
+	require.NotEqual(t, d1, d3)
+
+	_, err = ddlQueryDigest("ALTER TABLE")
+	require.Error(t, err)
+}
+
+func TestHandleRawKVEntryWithDDLDigest(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, helper := newMockDDLJobPuller(t, mockPuller, true)
+	defer helper.Close()
+	ddlJobPullerImpl := ddlJobPuller.(*ddlJobPullerImpl)
+	f, err := filter.NewFilter(config.GetDefaultReplicaConfig(), "")
+	require.NoError(t, err)
+	ddlJobPullerImpl.filter = f
+	ddlJobPullerImpl.computeDDLDigest = true
+
+	ctx := context.Background()
+	var digests []string
+	for _, ddl := range []string{
+		"create database test1",
+		"create table test1.t1(id int primary key)",
+		"create table test1.t2(id int primary key)",
+	} {
+		job := helper.DDL2Job(ddl)
+		value, err := json.Marshal(job)
+		require.NoError(t, err)
+		require.NoError(t, ddlJobPullerImpl.handleRawKVEntry(ctx, &model.RawKVEntry{
+			OpType:  model.OpTypePut,
+			Key:     []byte("mDDLJobList"),
+			Value:   value,
+			StartTs: job.StartTS,
+			CRTs:    job.BinlogInfo.FinishedTS,
+		}))
+		entry := <-ddlJobPullerImpl.Output()
+		require.NotEmpty(t, entry.QueryDigest)
+		digests = append(digests, entry.QueryDigest)
+	}
+	require.NotEqual(t, digests[0], digests[1])
+	require.Equal(t, digests[1], digests[2])
+}
+
+func TestDropPendingDDLs(t *testing.T) {
+	p := &ddlPullerImpl{
+		resolvedTS:   10,
+		cancel:       func() {},
+		clock:        clock.NewMock(),
+		changefeedID: model.DefaultChangeFeedID("test"),
+	}
+	for i := 1; i <= 5; i++ {
+		err := p.handleDDLJobEntry(&model.DDLJobEntry{
+			OpType: model.OpTypePut,
+			Job: &timodel.Job{
+				ID:         int64(i),
+				Type:       timodel.ActionCreateTable,
+				State:      timodel.JobStateDone,
+				BinlogInfo: &timodel.HistoryInfo{SchemaVersion: int64(i), FinishedTS: uint64(10 + i)},
+				Query:      fmt.Sprintf("create table t%d(id int primary key)", i),
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	// drop jobs with even IDs.
+	dropped := p.DropPendingDDLs(func(job *timodel.Job) bool {
+		return job.ID%2 == 0
+	})
+	require.Equal(t, 2, dropped)
+	require.Equal(t, uint64(11), mustResolvedTs(t, p))
+
+	// nothing matches.
+	dropped = p.DropPendingDDLs(func(job *timodel.Job) bool {
+		return job.ID > 100
+	})
+	require.Equal(t, 0, dropped)
+
+	var ids []int64
+	for {
+		_, job := mustPopFrontDDL(t, p)
+		if job == nil {
+			break
+		}
+		ids = append(ids, job.ID)
+	}
+	require.Equal(t, []int64{1, 3, 5}, ids)
+}
+
+func TestHandleDuplicatedDDLJobs(t *testing.T) {
+	p := &ddlPullerImpl{
+		resolvedTS:   10,
+		cancel:       func() {},
+		clock:        clock.NewMock(),
+		changefeedID: model.DefaultChangeFeedID("test"),
+	}
+	newEntry := func(id int64) *model.DDLJobEntry {
+		return &model.DDLJobEntry{
+			OpType: model.OpTypePut,
+			Job: &timodel.Job{
+				ID:         id,
+				Type:       timodel.ActionCreateTable,
+				State:      timodel.JobStateDone,
+				BinlogInfo: &timodel.HistoryInfo{SchemaVersion: id, FinishedTS: uint64(10 + id)},
+				Query:      fmt.Sprintf("create table t%d(id int primary key)", id),
+			},
+		}
+	}
+	// The duplicated job 1 isn't adjacent to its first occurrence.
+	for _, id := range []int64{1, 2, 1, 3, 2} {
+		require.NoError(t, p.handleDDLJobEntry(newEntry(id)))
+	}
+	var ids []int64
+	for {
+		_, job := mustPopFrontDDL(t, p)
+		if job == nil {
+			break
+		}
+		ids = append(ids, job.ID)
+	}
+	require.Equal(t, []int64{1, 2, 3}, ids)
+
+	// The window is bounded, the oldest jobs are forgotten.
+	for id := int64(4); id < 4+recentDDLJobWindowSize; id++ {
+		require.NoError(t, p.handleDDLJobEntry(newEntry(id)))
+	}
+	require.Len(t, p.recentDDLJobs.set, recentDDLJobWindowSize)
+	require.False(t, p.recentDDLJobs.contains(ddlJobKey{jobID: 3, schemaVersion: 3}))
+	require.True(t, p.recentDDLJobs.contains(ddlJobKey{jobID: 4, schemaVersion: 4}))
+}
+
+func TestOnResolvedTsAdvanced(t *testing.T) {
+	p := &ddlPullerImpl{
+		resolvedTS:   10,
+		cancel:       func() {},
+		clock:        clock.NewMock(),
+		changefeedID: model.DefaultChangeFeedID("test"),
+	}
+	var advanced []uint64
+	p.SetOnResolvedTsAdvanced(func(ts uint64) {
+		// The callback is free to query the puller.
+		require.Equal(t, ts, mustResolvedTs(t, p))
+		advanced = append(advanced, ts)
+	})
+	for _, ts := range []uint64{9, 10, 12, 11, 12, 15} {
+		err := p.handleDDLJobEntry(&model.DDLJobEntry{OpType: model.OpTypeResolved, CRTs: ts})
+		require.NoError(t, err)
+	}
+	require.Equal(t, []uint64{12, 15}, advanced)
+}
+
+func TestMinResolvedTs(t *testing.T) {
+	p := &ddlPullerImpl{
+		resolvedTS:   10,
+		cancel:       func() {},
+		clock:        clock.NewMock(),
+		changefeedID: model.DefaultChangeFeedID("test"),
+	}
+	p.SetMinResolvedTs(20)
+	require.Equal(t, uint64(20), mustResolvedTs(t, p))
+
+	// The stale resolved event is ignored.
+	err := p.handleDDLJobEntry(&model.DDLJobEntry{OpType: model.OpTypeResolved, CRTs: 15})
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), mustResolvedTs(t, p))
+
+	err = p.handleDDLJobEntry(&model.DDLJobEntry{OpType: model.OpTypeResolved, CRTs: 25})
+	require.NoError(t, err)
+	require.Equal(t, uint64(25), mustResolvedTs(t, p))
+
+	// The floor doesn't lower the resolved ts either.
+	p.SetMinResolvedTs(22)
+	require.Equal(t, uint64(25), mustResolvedTs(t, p))
+
+	// The pending jobs below the floor don't lower the resolved ts.
+	p.SetMinResolvedTs(30)
+	for i, finishedTs := range []uint64{26, 35} {
+		err = p.handleDDLJobEntry(&model.DDLJobEntry{
+			OpType: model.OpTypePut,
+			Job: &timodel.Job{
+				ID:         int64(i + 1),
+				Type:       timodel.ActionCreateTable,
+				State:      timodel.JobStateDone,
+				BinlogInfo: &timodel.HistoryInfo{SchemaVersion: int64(i + 1), FinishedTS: finishedTs},
+				Query:      fmt.Sprintf("create table t%d(id int primary key)", i+1),
+			},
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, uint64(30), mustResolvedTs(t, p))
+	ts, job := mustPopFrontDDL(t, p)
+	require.Equal(t, uint64(30), ts)
+	require.Equal(t, int64(1), job.ID)
+	ts, job = mustPopFrontDDL(t, p)
+	require.Equal(t, uint64(35), ts)
+	require.Equal(t, int64(2), job.ID)
+}
+
+func TestResolvedTsRegression(t *testing.T) {
+	newPuller := func(errorOnRegression bool) *ddlPullerImpl {
+		p := &ddlPullerImpl{
+			resolvedTS:   10,
+			cancel:       func() {},
+			clock:        clock.NewMock(),
+			changefeedID: model.DefaultChangeFeedID("test"),
+
+			errorOnResolvedTsRegression: errorOnRegression,
+		}
+		// The job with ID 2 is out of order.
+		for i, finishedTs := range []uint64{15, 12, 20} {
+			err := p.handleDDLJobEntry(&model.DDLJobEntry{
+				OpType: model.OpTypePut,
+				Job: &timodel.Job{
+					ID:         int64(i + 1),
+					Type:       timodel.ActionCreateTable,
+					State:      timodel.JobStateDone,
+					BinlogInfo: &timodel.HistoryInfo{SchemaVersion: int64(i + 1), FinishedTS: finishedTs},
+					Query:      fmt.Sprintf("create table t%d(id int primary key)", i+1),
+				},
+			})
+			require.NoError(t, err)
+		}
+		return p
+	}
+
+	p := newPuller(false)
+	require.Equal(t, uint64(15), mustResolvedTs(t, p))
+	ts, job := mustPopFrontDDL(t, p)
+	require.Equal(t, uint64(15), ts)
+	require.Equal(t, int64(1), job.ID)
+	// The resolved ts doesn't regress to the FinishedTS of the out-of-order job.
+	require.Equal(t, uint64(15), mustResolvedTs(t, p))
+	ts, job = mustPopFrontDDL(t, p)
+	require.Equal(t, uint64(15), ts)
+	require.Equal(t, int64(2), job.ID)
+	require.Equal(t, uint64(20), mustResolvedTs(t, p))
+
+	p = newPuller(true)
+	require.Equal(t, uint64(15), mustResolvedTs(t, p))
+	_, _ = mustPopFrontDDL(t, p)
+	_, err := p.ResolvedTs()
+	require.True(t, cerror.ErrDDLPullerResolvedTsRegressed.Equal(err))
+	_, job, err = p.PopFrontDDL()
+	require.True(t, cerror.ErrDDLPullerResolvedTsRegressed.Equal(err))
+	require.Nil(t, job)
+	// The out-of-order job is kept pending.
+	require.Len(t, p.pendingDDLJobs, 2)
+}
+
+func TestHandleSortedRawKVEntriesCoalesceResolvedTs(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, helper := newMockDDLJobPuller(t, mockPuller, true)
+	defer helper.Close()
+	ddlJobPullerImpl := ddlJobPuller.(*ddlJobPullerImpl)
+	f, err := filter.NewFilter(config.GetDefaultReplicaConfig(), "")
+	require.NoError(t, err)
+	ddlJobPullerImpl.filter = f
+	ddlJobPullerImpl.resolvedTsMinInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rawDDLCh := make(chan *model.RawKVEntry)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- ddlJobPullerImpl.handleSortedRawKVEntries(ctx, rawDDLCh)
+	}()
+
+	job := helper.DDL2Job("create database test1")
+	value, err := json.Marshal(job)
+	require.NoError(t, err)
+	ts := job.BinlogInfo.FinishedTS
+	resolved := func(ts uint64) *model.RawKVEntry {
+		return &model.RawKVEntry{OpType: model.OpTypeResolved, CRTs: ts, StartTs: ts}
+	}
+	nextEntry := func() *model.DDLJobEntry {
+		select {
+		case entry := <-ddlJobPullerImpl.Output():
+			return entry
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no ddl job entry is output")
+			return nil
+		}
+	}
+
+	// The first resolved ts is handled immediately, the following ones within
+	// the interval are coalesced.
+	rawDDLCh <- resolved(ts - 3)
+	entry := nextEntry()
+	require.Equal(t, model.OpTypeResolved, entry.OpType)
+	require.Equal(t, ts-3, entry.CRTs)
+	rawDDLCh <- resolved(ts - 2)
+	rawDDLCh <- resolved(ts - 1)
+	select {
+	case entry := <-ddlJobPullerImpl.Output():
+		require.FailNow(t, "resolved ts is not coalesced", "entry: %v", entry)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The DDL job is not delayed, the pending resolved ts is output before it.
+	rawDDLCh <- &model.RawKVEntry{
+		OpType:  model.OpTypePut,
+		Key:     []byte("mDDLJobList"),
+		Value:   value,
+		StartTs: job.StartTS,
+		CRTs:    ts,
+	}
+	entry = nextEntry()
+	require.Equal(t, model.OpTypeResolved, entry.OpType)
+	require.Equal(t, ts-1, entry.CRTs)
+	entry = nextEntry()
+	require.Equal(t, model.OpTypePut, entry.OpType)
+	require.Equal(t, job.ID, entry.Job.ID)
+
+	cancel()
+	require.ErrorIs(t, <-errCh, context.Canceled)
+}
+
+func TestHandleRawKVEntryWithRenamedTables(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, helper := newMockDDLJobPuller(t, mockPuller, true)
+	defer helper.Close()
+	ddlJobPullerImpl := ddlJobPuller.(*ddlJobPullerImpl)
+	cfg := config.GetDefaultReplicaConfig()
+	cfg.Filter.Rules = []string{"test1.t1", "test1.t11", "test1.t3", "test1.t33"}
+	f, err := filter.NewFilter(cfg, "")
+	require.NoError(t, err)
+	ddlJobPullerImpl.filter = f
+
+	ctx := context.Background()
+	handleDDL := func(ddl string) *model.DDLJobEntry {
+		job := helper.DDL2Job(ddl)
+		value, err := json.Marshal(job)
+		require.NoError(t, err)
+		require.NoError(t, ddlJobPullerImpl.handleRawKVEntry(ctx, &model.RawKVEntry{
+			OpType:  model.OpTypePut,
+			Key:     []byte("mDDLJobList"),
+			Value:   value,
+			StartTs: job.StartTS,
+			CRTs:    job.BinlogInfo.FinishedTS,
+		}))
+		select {
+		case entry := <-ddlJobPullerImpl.Output():
+			return entry
+		default:
+			return nil
+		}
+	}
+
+	entry := handleDDL("create database test1")
+	require.NotNil(t, entry)
+	require.Nil(t, entry.RenamedTables)
+	var tableIDs []int64
+	for _, ddl := range []string{
+		"create table test1.t1(id int primary key)",
+		"create table test1.t2(id int primary key)",
+		"create table test1.t3(id int primary key)",
+	} {
+		entry = handleDDL(ddl)
+		if entry != nil {
+			require.Nil(t, entry.RenamedTables)
+			tableIDs = append(tableIDs, entry.Job.TableID)
+		}
+	}
+	require.Len(t, tableIDs, 2)
+
+	// test1.t2 is filtered out.
+	entry = handleDDL("rename table test1.t1 to test1.t11, test1.t2 to test1.t22, test1.t3 to test1.t33")
+	require.NotNil(t, entry)
+	require.Equal(t, []model.RenamedTable{
+		{TableID: tableIDs[0], OldSchema: "test1", OldTable: "t1", NewSchema: "test1", NewTable: "t11"},
+		{TableID: tableIDs[1], OldSchema: "test1", OldTable: "t3", NewSchema: "test1", NewTable: "t33"},
+	}, entry.RenamedTables)
+	require.Len(t, entry.Job.BinlogInfo.MultipleTableInfos, 2)
+
+	entry = handleDDL("rename table test1.t11 to test1.t1")
+	require.NotNil(t, entry)
+	require.Nil(t, entry.RenamedTables)
+}
+
+func TestDDLJobPullerStats(t *testing.T) {
+	startTs := uint64(10)
+	mockPuller := newMockPuller(t, startTs)
+	ddlJobPuller, _ := newMockDDLJobPuller(t, mockPuller, false)
+	// The processed resolved ts is held back by the max DDL commit ts.
+	ddlJobPuller.(*ddlJobPullerImpl).maxDDLCommitTs = 20
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ddlJobPuller.Run(ctx)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ddlJobPuller.Output():
+			}
+		}
+	}()
+
+	mockPuller.appendResolvedTs(30)
+	waitResolvedTs(t, ddlJobPuller, 20)
+	require.Equal(t, DDLJobPullerStats{KVResolvedTs: 30, ResolvedTs: 20}, ddlJobPuller.Stats())
+
+	// multiplexing
+	outputCh := make(chan *model.RawKVEntry, 16)
+	mp := newMultiplexingPullerForTest(outputCh)
+	defer mp.client.Close()
+	multiplexingJobPuller := &ddlJobPullerImpl{multiplexing: true}
+	multiplexingJobPuller.multiplexingPuller.MultiplexingPuller = mp
+	spans := spanz.GetAllDDLSpan()
+	for i := range spans {
+		spans[i].TableID = int64(-1) - int64(i)
+	}
+	mp.Subscribe(spans, 100, memorysorter.DDLPullerTableName, func(*model.RawKVEntry) bool { return false })
+	mp.subscriptions.n.GetV(spans[0]).resolvedTs.Store(101)
+	multiplexingJobPuller.setResolvedTs(99)
+	require.Equal(t, DDLJobPullerStats{KVResolvedTs: 101, ResolvedTs: 99}, multiplexingJobPuller.Stats())
 }
@@ -232,6 +232,23 @@ func (p *MultiplexingPuller) unsubscribe(spans []tablepb.Span) {
 	}
 }
 
+// UnsubscribeAll unsubscribes all subscribed spans, and returns them.
+func (p *MultiplexingPuller) UnsubscribeAll() []tablepb.Span {
+	p.subscriptions.Lock()
+	defer p.subscriptions.Unlock()
+
+	progresses := make(map[*tableProgress]struct{}, len(p.subscriptions.m))
+	for _, progress := range p.subscriptions.m {
+		progresses[progress] = struct{}{}
+	}
+	spans := make([]tablepb.Span, 0, len(p.subscriptions.m))
+	for progress := range progresses {
+		spans = append(spans, progress.spans...)
+		p.unsubscribe(progress.spans)
+	}
+	return spans
+}
+
 // Run the puller.
 func (p *MultiplexingPuller) Run(ctx context.Context) (err error) {
 	return p.run(ctx, true)
@@ -466,11 +483,34 @@ func (p *MultiplexingPuller) Stats(span tablepb.Span) Stats {
 	if progress.tableProgress == nil {
 		return Stats{}
 	}
+	return p.stats(progress)
+}
+
+// AllStats returns the stats of all subscribed spans.
+func (p *MultiplexingPuller) AllStats() *spanz.HashMap[Stats] {
+	progresses := spanz.NewHashMap[tableProgressWithSubID]()
+	p.subscriptions.RLock()
+	p.subscriptions.n.Range(func(span tablepb.Span, progress tableProgressWithSubID) bool {
+		progresses.ReplaceOrInsert(span, progress)
+		return true
+	})
+	p.subscriptions.RUnlock()
+
+	result := spanz.NewHashMap[Stats]()
+	progresses.Range(func(span tablepb.Span, progress tableProgressWithSubID) bool {
+		result.ReplaceOrInsert(span, p.stats(progress))
+		return true
+	})
+	return result
+}
+
+func (p *MultiplexingPuller) stats(progress tableProgressWithSubID) Stats {
 	return Stats{
-		RegionCount:         p.client.RegionCount(progress.subID),
-		ResolvedTsIngress:   progress.maxIngressResolvedTs.Load(),
-		CheckpointTsIngress: progress.maxIngressResolvedTs.Load(),
-		ResolvedTsEgress:    progress.resolvedTs.Load(),
-		CheckpointTsEgress:  progress.resolvedTs.Load(),
+		RegionCount:            p.client.RegionCount(progress.subID),
+		InitializedRegionCount: p.client.InitializedRegionCount(progress.subID),
+		ResolvedTsIngress:      progress.maxIngressResolvedTs.Load(),
+		CheckpointTsIngress:    progress.maxIngressResolvedTs.Load(),
+		ResolvedTsEgress:       progress.resolvedTs.Load(),
+		CheckpointTsEgress:     progress.resolvedTs.Load(),
 	}
 }
@@ -99,3 +99,53 @@ func TestMultiplexingPullerResolvedForward(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+func TestMultiplexingPullerAllStats(t *testing.T) {
+	outputCh := make(chan *model.RawKVEntry, 16)
+	puller := newMultiplexingPullerForTest(outputCh)
+	defer puller.client.Close()
+
+	shouldSplitKVEntry := func(raw *model.RawKVEntry) bool {
+		return false
+	}
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.TableIDToComparableSpan(2)
+	puller.Subscribe([]tablepb.Span{span1}, 100, "t1", shouldSplitKVEntry)
+	puller.Subscribe([]tablepb.Span{span2}, 200, "t2", shouldSplitKVEntry)
+	puller.subscriptions.n.GetV(span1).resolvedTs.Store(101)
+	puller.subscriptions.n.GetV(span2).resolvedTs.Store(201)
+
+	stats := puller.AllStats()
+	require.Equal(t, 2, stats.Len())
+	require.Equal(t, puller.Stats(span1), stats.GetV(span1))
+	require.Equal(t, uint64(101), stats.GetV(span1).ResolvedTsEgress)
+	require.Equal(t, uint64(201), stats.GetV(span2).ResolvedTsEgress)
+
+	puller.Unsubscribe([]tablepb.Span{span1})
+	stats = puller.AllStats()
+	require.Equal(t, 1, stats.Len())
+	require.False(t, stats.Has(span1))
+}
+
+func TestMultiplexingPullerUnsubscribeAll(t *testing.T) {
+	outputCh := make(chan *model.RawKVEntry, 16)
+	puller := newMultiplexingPullerForTest(outputCh)
+	defer puller.client.Close()
+
+	shouldSplitKVEntry := func(raw *model.RawKVEntry) bool {
+		return false
+	}
+	span1 := spanz.TableIDToComparableSpan(1)
+	span2 := spanz.ToSpan([]byte("t_a"), []byte("t_c"))
+	span3 := spanz.ToSpan([]byte("t_c"), []byte("t_e"))
+	span2.TableID = 2
+	span3.TableID = 2
+	puller.Subscribe([]tablepb.Span{span1}, 100, "t1", shouldSplitKVEntry)
+	puller.Subscribe([]tablepb.Span{span2, span3}, 100, "t2", shouldSplitKVEntry)
+
+	spans := puller.UnsubscribeAll()
+	require.ElementsMatch(t, []tablepb.Span{span1, span2, span3}, spans)
+	require.Equal(t, 0, puller.AllStats().Len())
+	require.Empty(t, puller.subscriptions.m)
+	require.Empty(t, puller.UnsubscribeAll())
+}
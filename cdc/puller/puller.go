@@ -43,11 +43,36 @@ const (
 
 // Stats of a puller.
 type Stats struct {
-	RegionCount         uint64
-	CheckpointTsIngress model.Ts
-	ResolvedTsIngress   model.Ts
-	CheckpointTsEgress  model.Ts
-	ResolvedTsEgress    model.Ts
+	RegionCount            uint64
+	InitializedRegionCount uint64
+	CheckpointTsIngress    model.Ts
+	ResolvedTsIngress      model.Ts
+	CheckpointTsEgress     model.Ts
+	ResolvedTsEgress       model.Ts
+}
+
+// SumStats aggregates the stats of multiple tables. The region counts are
+// summed up, and the timestamps are the minimum ones, i.e. of the slowest table.
+func SumStats(stats []Stats) Stats {
+	var result Stats
+	first := true
+	for _, s := range stats {
+		result.RegionCount += s.RegionCount
+		result.InitializedRegionCount += s.InitializedRegionCount
+		if first {
+			result.CheckpointTsIngress = s.CheckpointTsIngress
+			result.ResolvedTsIngress = s.ResolvedTsIngress
+			result.CheckpointTsEgress = s.CheckpointTsEgress
+			result.ResolvedTsEgress = s.ResolvedTsEgress
+			first = false
+			continue
+		}
+		result.CheckpointTsIngress = min(result.CheckpointTsIngress, s.CheckpointTsIngress)
+		result.ResolvedTsIngress = min(result.ResolvedTsIngress, s.ResolvedTsIngress)
+		result.CheckpointTsEgress = min(result.CheckpointTsEgress, s.CheckpointTsEgress)
+		result.ResolvedTsEgress = min(result.ResolvedTsEgress, s.ResolvedTsEgress)
+	}
+	return result
 }
 
 // Puller pull data from tikv and push changes into a buffer.
@@ -94,10 +119,10 @@ func New(ctx context.Context,
 	tableID model.TableID,
 	tableName string,
 	filterLoop bool,
-) Puller {
+) (Puller, error) {
 	tikvStorage, ok := kvStorage.(tikv.Storage)
 	if !ok {
-		log.Panic("can't create puller for non-tikv storage")
+		return nil, errors.New("can't create puller for non-tikv storage")
 	}
 
 	// To make puller level resolved ts initialization distinguishable, we set
@@ -121,7 +146,7 @@ func New(ctx context.Context,
 
 		startResolvedTs: checkpointTs,
 	}
-	return p
+	return p, nil
 }
 
 // Run the puller, continually fetch event from TiKV and add event into buffer
@@ -315,10 +340,11 @@ func (p *pullerImpl) Output() <-chan *model.RawKVEntry {
 
 func (p *pullerImpl) Stats() Stats {
 	return Stats{
-		RegionCount:         p.kvCli.RegionCount(),
-		ResolvedTsIngress:   p.kvCli.ResolvedTs(),
-		CheckpointTsIngress: p.kvCli.CommitTs(),
-		ResolvedTsEgress:    atomic.LoadUint64(&p.resolvedTs),
-		CheckpointTsEgress:  atomic.LoadUint64(&p.checkpointTs),
+		RegionCount:            p.kvCli.RegionCount(),
+		InitializedRegionCount: p.kvCli.InitializedRegionCount(),
+		ResolvedTsIngress:      p.kvCli.ResolvedTs(),
+		CheckpointTsIngress:    p.kvCli.CommitTs(),
+		ResolvedTsEgress:       atomic.LoadUint64(&p.resolvedTs),
+		CheckpointTsEgress:     atomic.LoadUint64(&p.checkpointTs),
 	}
 }
@@ -130,11 +130,12 @@ func newPullerForTest(
 	defer grpcPool.Close()
 	regionCache := tikv.NewRegionCache(pdCli)
 	defer regionCache.Close()
-	plr := New(
+	plr, err := New(
 		ctx, pdCli, grpcPool, regionCache, store, pdutil.NewClock4Test(),
 		checkpointTs, spans, config.GetDefaultServerConfig(),
 		model.DefaultChangeFeedID("changefeed-id-test"), 0,
 		"table-test", false)
+	require.NoError(t, err)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -151,6 +152,16 @@ func newPullerForTest(
 	return mockPlr, cancel, &wg, store
 }
 
+func TestNewPullerWithNonTiKVStorage(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(
+		context.Background(), &mockPdClientForPullerTest{clusterID: uint64(1)}, nil, nil,
+		nil, pdutil.NewClock4Test(), 0, nil, config.GetDefaultServerConfig(),
+		model.DefaultChangeFeedID("changefeed-id-test"), 0, "table-test", false)
+	require.Error(t, err)
+}
+
 func TestPullerResolvedForward(t *testing.T) {
 	spans := []tablepb.Span{
 		{
@@ -238,3 +249,34 @@ func TestPullerRawKV(t *testing.T) {
 	cancel()
 	wg.Wait()
 }
+
+func TestSumStats(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, Stats{}, SumStats(nil))
+	require.Equal(t, Stats{
+		RegionCount:            5,
+		InitializedRegionCount: 3,
+		CheckpointTsIngress:    10,
+		ResolvedTsIngress:      11,
+		CheckpointTsEgress:     8,
+		ResolvedTsEgress:       9,
+	}, SumStats([]Stats{
+		{
+			RegionCount:            2,
+			InitializedRegionCount: 2,
+			CheckpointTsIngress:    10,
+			ResolvedTsIngress:      12,
+			CheckpointTsEgress:     8,
+			ResolvedTsEgress:       10,
+		},
+		{
+			RegionCount:            3,
+			InitializedRegionCount: 1,
+			CheckpointTsIngress:    11,
+			ResolvedTsIngress:      11,
+			CheckpointTsEgress:     9,
+			ResolvedTsEgress:       9,
+		},
+	}))
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/kv"
 	"github.com/pingcap/tiflow/cdc/owner"
 	"github.com/pingcap/tiflow/cdc/processor"
+	"github.com/pingcap/tiflow/cdc/processor/sourcemanager"
 	"github.com/pingcap/tiflow/cdc/processor/sourcemanager/engine"
 	"github.com/pingcap/tiflow/cdc/puller"
 	"github.com/pingcap/tiflow/cdc/puller/memorysorter"
@@ -51,6 +52,7 @@ func init() {
 	orchestrator.InitMetrics(registry)
 	p2p.InitMetrics(registry)
 	engine.InitMetrics(registry)
+	sourcemanager.InitMetrics(registry)
 	memorysorter.InitMetrics(registry)
 	redo.InitMetrics(registry)
 	scheduler.InitMetrics(registry)
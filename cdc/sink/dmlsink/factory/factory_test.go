@@ -17,12 +17,18 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink/mq/dmlproducer"
+	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/pdutil"
@@ -95,3 +101,60 @@ func TestSinkFactory(t *testing.T) {
 
 	sinkFactory.Close()
 }
+
+func TestSinkFactoryWithDMLFileDir(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	uri := fmt.Sprintf("mysql://127.0.0.1:3306/?worker-count=1&batch-dml-enable=false"+
+		"&dml-file-dir=%s&dml-file-max-size=1024", url.QueryEscape(dir))
+	replicaConfig := config.GetDefaultReplicaConfig()
+	errCh := make(chan error, 1)
+
+	// The downstream isn't connected, since the DMLs are written to the files.
+	sinkFactory, err := New(ctx, model.DefaultChangeFeedID("test"), uri,
+		replicaConfig, errCh, pdutil.NewClock4Test())
+	require.NoError(t, err)
+	require.Equal(t, CategoryTxn, sinkFactory.Category())
+
+	flushed := make(chan struct{})
+	sinkState := new(state.TableSinkState)
+	*sinkState = state.TableSinkSinking
+	table := &model.TableName{Schema: "s1", Table: "t1", TableID: 1}
+	err = sinkFactory.txnSink.WriteEvents(&dmlsink.CallbackableEvent[*model.SingleTableTxn]{
+		Event: &model.SingleTableTxn{
+			StartTs:  9,
+			CommitTs: 10,
+			Rows: []*model.RowChangedEvent{{
+				Table:         table,
+				StartTs:       9,
+				CommitTs:      10,
+				ReplicatingTs: 1,
+				Columns: []*model.Column{{
+					Name:  "id",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 1,
+				}},
+			}},
+		},
+		Callback:  func() { close(flushed) },
+		SinkState: sinkState,
+	})
+	require.NoError(t, err)
+	select {
+	case <-flushed:
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "the event isn't flushed")
+	}
+	sinkFactory.Close()
+
+	content, err := os.ReadFile(filepath.Join(dir, "dml-0-000000.sql"))
+	require.NoError(t, err)
+	require.Equal(t, "BEGIN;\n"+
+		"INSERT INTO `s1`.`t1` (`id`) VALUES (1);\n"+
+		"COMMIT;\n", string(content))
+}
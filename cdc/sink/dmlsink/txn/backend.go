@@ -33,4 +33,8 @@ type backend interface {
 
 	// Close the backend.
 	Close() error
+
+	// CloseAndFlush flushes pending events and closes the backend, the
+	// backend is closed even if the flush fails.
+	CloseAndFlush(ctx context.Context) error
 }
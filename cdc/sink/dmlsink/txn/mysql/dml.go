@@ -24,7 +24,9 @@ import (
 // prepareUpdate builds a parametrics UPDATE statement as following
 // sql: `UPDATE `test`.`t` SET {} = ?, {} = ? WHERE {} = ?, {} = {} LIMIT 1`
 // `WHERE` conditions come from `preCols` and SET clause targets come from `cols`.
-func prepareUpdate(quoteTable string, preCols, cols []*model.Column, forceReplicate bool) (string, []interface{}) {
+func prepareUpdate(
+	quoteTable string, preCols, cols []*model.Column, forceReplicate bool, quoteStyle quotes.QuoteStyle,
+) (string, []interface{}) {
 	var builder strings.Builder
 	builder.WriteString("UPDATE " + quoteTable + " SET ")
 
@@ -42,9 +44,9 @@ func prepareUpdate(quoteTable string, preCols, cols []*model.Column, forceReplic
 	}
 	for i, column := range columnNames {
 		if i == len(columnNames)-1 {
-			builder.WriteString("`" + quotes.EscapeName(column) + "` = ?")
+			builder.WriteString(quoteStyle.QuoteName(column) + " = ?")
 		} else {
-			builder.WriteString("`" + quotes.EscapeName(column) + "` = ?, ")
+			builder.WriteString(quoteStyle.QuoteName(column) + " = ?, ")
 		}
 	}
 
@@ -58,9 +60,9 @@ func prepareUpdate(quoteTable string, preCols, cols []*model.Column, forceReplic
 			builder.WriteString(" AND ")
 		}
 		if wargs[i] == nil {
-			builder.WriteString(quotes.QuoteName(colNames[i]) + " IS NULL")
+			builder.WriteString(quoteStyle.QuoteName(colNames[i]) + " IS NULL")
 		} else {
-			builder.WriteString(quotes.QuoteName(colNames[i]) + " = ?")
+			builder.WriteString(quoteStyle.QuoteName(colNames[i]) + " = ?")
 			args = append(args, wargs[i])
 		}
 	}
@@ -76,6 +78,7 @@ func prepareReplace(
 	cols []*model.Column,
 	appendPlaceHolder bool,
 	translateToInsert bool,
+	quoteStyle quotes.QuoteStyle,
 ) (string, []interface{}) {
 	var builder strings.Builder
 	columnNames := make([]string, 0, len(cols))
@@ -91,7 +94,7 @@ func prepareReplace(
 		return "", nil
 	}
 
-	colList := "(" + buildColumnList(columnNames) + ")"
+	colList := "(" + buildColumnList(columnNames, quoteStyle) + ")"
 	if translateToInsert {
 		builder.WriteString("INSERT INTO " + quoteTable + " " + colList + " VALUES ")
 	} else {
@@ -125,7 +128,9 @@ func appendQueryArgs(args []interface{}, col *model.Column) []interface{} {
 
 // prepareDelete builds a parametric DELETE statement as following
 // sql: `DELETE FROM `test`.`t` WHERE x = ? AND y >= ? LIMIT 1`
-func prepareDelete(quoteTable string, cols []*model.Column, forceReplicate bool) (string, []interface{}) {
+func prepareDelete(
+	quoteTable string, cols []*model.Column, forceReplicate bool, quoteStyle quotes.QuoteStyle,
+) (string, []interface{}) {
 	var builder strings.Builder
 	builder.WriteString("DELETE FROM " + quoteTable + " WHERE ")
 
@@ -139,9 +144,9 @@ func prepareDelete(quoteTable string, cols []*model.Column, forceReplicate bool)
 			builder.WriteString(" AND ")
 		}
 		if wargs[i] == nil {
-			builder.WriteString(quotes.QuoteName(colNames[i]) + " IS NULL")
+			builder.WriteString(quoteStyle.QuoteName(colNames[i]) + " IS NULL")
 		} else {
-			builder.WriteString(quotes.QuoteName(colNames[i]) + " = ?")
+			builder.WriteString(quoteStyle.QuoteName(colNames[i]) + " = ?")
 			args = append(args, wargs[i])
 		}
 	}
@@ -173,13 +178,13 @@ func whereSlice(cols []*model.Column, forceReplicate bool) (colNames []string, a
 	return
 }
 
-func buildColumnList(names []string) string {
+func buildColumnList(names []string, quoteStyle quotes.QuoteStyle) string {
 	var b strings.Builder
 	for i, name := range names {
 		if i > 0 {
 			b.WriteString(",")
 		}
-		b.WriteString(quotes.QuoteName(name))
+		b.WriteString(quoteStyle.QuoteName(name))
 
 	}
 
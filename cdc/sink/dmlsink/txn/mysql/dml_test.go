@@ -19,6 +19,7 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/charset"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/pkg/quotes"
 	"github.com/stretchr/testify/require"
 )
 
@@ -250,7 +251,7 @@ func TestPrepareUpdate(t *testing.T) {
 		},
 	}
 	for _, tc := range testCases {
-		query, args := prepareUpdate(tc.quoteTable, tc.preCols, tc.cols, false)
+		query, args := prepareUpdate(tc.quoteTable, tc.preCols, tc.cols, false, quotes.QuoteStyleBacktick)
 		require.Equal(t, tc.expectedSQL, query)
 		require.Equal(t, tc.expectedArgs, args)
 	}
@@ -392,7 +393,7 @@ func TestPrepareDelete(t *testing.T) {
 		},
 	}
 	for _, tc := range testCases {
-		query, args := prepareDelete(tc.quoteTable, tc.preCols, false)
+		query, args := prepareDelete(tc.quoteTable, tc.preCols, false, quotes.QuoteStyleBacktick)
 		require.Equal(t, tc.expectedSQL, query)
 		require.Equal(t, tc.expectedArgs, args)
 	}
@@ -713,9 +714,34 @@ func TestMapReplace(t *testing.T) {
 	for _, tc := range testCases {
 		// multiple times to verify the stability of column sequence in query string
 		for i := 0; i < 10; i++ {
-			query, args := prepareReplace(tc.quoteTable, tc.cols, false, false)
+			query, args := prepareReplace(tc.quoteTable, tc.cols, false, false, quotes.QuoteStyleBacktick)
 			require.Equal(t, tc.expectedQuery, query)
 			require.Equal(t, tc.expectedArgs, args)
 		}
 	}
 }
+
+func TestPrepareDMLWithDoubleQuote(t *testing.T) {
+	t.Parallel()
+	quoteTable := `"test"."t1"`
+	preCols := []*model.Column{
+		{Name: "a", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: 1},
+		{Name: "b", Type: mysql.TypeVarchar, Value: "你好"},
+	}
+	cols := []*model.Column{
+		{Name: "a", Type: mysql.TypeLong, Flag: model.HandleKeyFlag | model.PrimaryKeyFlag, Value: 1},
+		{Name: `b"c`, Type: mysql.TypeVarchar, Value: "世界"},
+	}
+
+	query, args := prepareUpdate(quoteTable, preCols, cols, false, quotes.QuoteStyleDoubleQuote)
+	require.Equal(t, `UPDATE "test"."t1" SET "a" = ?, "b""c" = ? WHERE "a" = ? LIMIT 1`, query)
+	require.Equal(t, []interface{}{1, "世界", 1}, args)
+
+	query, args = prepareDelete(quoteTable, preCols, false, quotes.QuoteStyleDoubleQuote)
+	require.Equal(t, `DELETE FROM "test"."t1" WHERE "a" = ? LIMIT 1`, query)
+	require.Equal(t, []interface{}{1}, args)
+
+	query, args = prepareReplace(quoteTable, cols, false, false, quotes.QuoteStyleDoubleQuote)
+	require.Equal(t, `REPLACE INTO "test"."t1" ("a","b""c") VALUES `, query)
+	require.Equal(t, []interface{}{1, "世界"}, args)
+}
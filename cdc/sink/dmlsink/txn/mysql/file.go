@@ -0,0 +1,370 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"go.uber.org/zap"
+)
+
+// fileBackend writes the DMLs which would be executed by the MySQL backend to
+// local SQL files instead, e.g. for auditing, or replaying them into another
+// downstream later. Each transaction is written as a `BEGIN; ...; COMMIT;`
+// block with the arguments interpolated, and a new file is started when the
+// current one reaches maxFileSize.
+type fileBackend struct {
+	// dmls buffers the events and prepares the DMLs exactly like the MySQL
+	// backend, it's never connected to a downstream.
+	dmls *mysqlBackend
+
+	dir         string
+	maxFileSize int64
+	file        *os.File
+	fileSize    int64
+	fileIndex   int
+}
+
+// NewFileBackends creates the backends writing DMLs to SQL files in the
+// `dml-file-dir` of the sink URI, which are used instead of the MySQL backends
+// if it's set. The downstream is never connected, so the DMLs are generated
+// for MySQL.
+func NewFileBackends(
+	changefeedID model.ChangeFeedID,
+	sinkURI *url.URL,
+	replicaConfig *config.ReplicaConfig,
+	statistics *metrics.Statistics,
+) ([]*fileBackend, error) {
+	cfg := pmysql.NewConfig()
+	err := cfg.Apply(config.GetGlobalServerConfig().TZ, changefeedID, sinkURI, replicaConfig)
+	if err != nil {
+		return nil, err
+	}
+	return newFileBackends(changefeedID, cfg, statistics, cfg.DMLFileDir, cfg.DMLFileMaxSize)
+}
+
+// newFileBackends creates the backends writing DMLs to SQL files in dir, one
+// for each worker. Files are rotated by maxFileSize, which is disabled if
+// it's not positive. A transaction is never split across files, so a file can
+// exceed maxFileSize if a single transaction is larger than it.
+func newFileBackends(
+	changefeedID model.ChangeFeedID,
+	cfg *pmysql.Config,
+	statistics *metrics.Statistics,
+	dir string,
+	maxFileSize int64,
+) ([]*fileBackend, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	backends := make([]*fileBackend, 0, cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		backend := &fileBackend{
+			dmls:        newBackendWithMetrics(i, changefeedID, cfg, statistics),
+			dir:         dir,
+			maxFileSize: maxFileSize,
+		}
+		// Continue with the last file written by the worker before restarting,
+		// instead of appending to the first one.
+		index, err := backend.lastFileIndex()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		backend.fileIndex = index
+		backends = append(backends, backend)
+	}
+
+	log.Info("file backends is created",
+		zap.String("changefeed", fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID)),
+		zap.Int("workerCount", cfg.WorkerCount),
+		zap.String("dir", dir),
+		zap.Int64("maxFileSize", maxFileSize))
+	return backends, nil
+}
+
+// OnTxnEvent implements interface backend.
+func (s *fileBackend) OnTxnEvent(event *dmlsink.TxnCallbackableEvent) (needFlush bool) {
+	return s.dmls.OnTxnEvent(event)
+}
+
+// Flush implements interface backend.
+func (s *fileBackend) Flush(ctx context.Context) error {
+	if s.dmls.rows == 0 {
+		return nil
+	}
+
+	if err := s.dmls.handleZeroAutoIncrementKeys(); err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, event := range s.dmls.events {
+		s.dmls.statistics.ObserveRows(event.Event.Rows...)
+	}
+
+	// The file is opened in advance, so that all the chunks written by this
+	// flush can be rolled back if any of them fails. Otherwise the retry
+	// would write the chunks written before the failure again.
+	if s.file == nil {
+		if err := s.openFile(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	startIndex, startSize := s.fileIndex, s.fileSize
+
+	var (
+		callbacks []dmlsink.CallbackFunc
+		batchSize int64
+	)
+	start := time.Now()
+	for _, events := range s.dmls.splitEventsBySize() {
+		dmls := s.dmls.prepareDMLsFor(events)
+		batchSize += dmls.approximateSize
+		err := s.dmls.statistics.RecordBatchExecution(func() (int, int64, error) {
+			if err := s.writeDMLs(dmls); err != nil {
+				return 0, 0, err
+			}
+			return dmls.rowCount, dmls.approximateSize, nil
+		})
+		if err != nil {
+			log.Error("write DMLs to file failed",
+				zap.String("changefeed", s.dmls.changefeed), zap.Error(err))
+			return errors.Trace(s.rollback(startIndex, startSize, err))
+		}
+		callbacks = append(callbacks, dmls.callbacks...)
+	}
+	if err := s.file.Sync(); err != nil {
+		return errors.Trace(s.rollback(startIndex, startSize, err))
+	}
+	s.dmls.metricTxnSinkDMLBatchSize.Observe(float64(batchSize))
+	startCallback := time.Now()
+	for _, callback := range callbacks {
+		callback()
+	}
+	s.dmls.metricTxnSinkDMLBatchCommit.Observe(startCallback.Sub(start).Seconds())
+	s.dmls.metricTxnSinkDMLBatchCallback.Observe(time.Since(startCallback).Seconds())
+
+	s.dmls.resetEvents()
+	return nil
+}
+
+// writeDMLs appends the DMLs as one transaction to the current file, which is
+// rotated first if the transaction makes it exceed maxFileSize.
+func (s *fileBackend) writeDMLs(dmls *preparedDMLs) error {
+	var buf strings.Builder
+	buf.WriteString("BEGIN;\n")
+	for i, query := range dmls.sqls {
+		stmt, err := interpolateSQL(query, dmls.values[i], s.dmls.cfg.QuoteStyle)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		buf.WriteString(stmt)
+		buf.WriteString(";\n")
+	}
+	buf.WriteString("COMMIT;\n")
+
+	if s.file != nil && s.maxFileSize > 0 && s.fileSize > 0 &&
+		s.fileSize+int64(buf.Len()) > s.maxFileSize {
+		err := s.closeFile()
+		s.fileIndex++
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if s.file == nil {
+		if err := s.openFile(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	n, err := s.file.WriteString(buf.String())
+	s.fileSize += int64(n)
+	return errors.Trace(err)
+}
+
+// rollback truncates the files back to the position before the flush
+// started, and removes the files created by the flush. err is returned, along
+// with the error of the rollback if there is one.
+func (s *fileBackend) rollback(startIndex int, startSize int64, err error) error {
+	// The file of the current index isn't created if it failed to be opened.
+	last := s.fileIndex
+	if s.file == nil {
+		last--
+	}
+	_ = s.closeFile()
+	for index := last; index > startIndex; index-- {
+		if rmErr := os.Remove(s.fileName(index)); rmErr != nil && !os.IsNotExist(rmErr) {
+			return errors.Annotatef(err, "rollback failed: %s", rmErr)
+		}
+	}
+	s.fileIndex = startIndex
+	if truncErr := os.Truncate(s.fileName(startIndex), startSize); truncErr != nil {
+		return errors.Annotatef(err, "rollback failed: %s", truncErr)
+	}
+	return err
+}
+
+// lastFileIndex returns the highest index of the existing files of the
+// worker, or 0 if there is none.
+func (s *fileBackend) lastFileIndex() (int, error) {
+	pattern := filepath.Join(s.dir, fmt.Sprintf("dml-%d-*.sql", s.dmls.workerID))
+	names, err := filepath.Glob(pattern)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	last := 0
+	for _, name := range names {
+		var workerID, index int
+		if _, err := fmt.Sscanf(filepath.Base(name), "dml-%d-%d.sql", &workerID, &index); err != nil ||
+			workerID != s.dmls.workerID {
+			continue
+		}
+		if index > last {
+			last = index
+		}
+	}
+	return last, nil
+}
+
+// fileName returns the name of the file with the index of the worker.
+func (s *fileBackend) fileName(index int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("dml-%d-%06d.sql", s.dmls.workerID, index))
+}
+
+func (s *fileBackend) openFile() error {
+	name := s.fileName(s.fileIndex)
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return errors.Trace(err)
+	}
+	s.file = file
+	s.fileSize = info.Size()
+	log.Info("open SQL file for DMLs",
+		zap.String("changefeed", s.dmls.changefeed),
+		zap.Int("workerID", s.dmls.workerID),
+		zap.String("file", name))
+	return nil
+}
+
+func (s *fileBackend) closeFile() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Sync()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	s.file = nil
+	s.fileSize = 0
+	return errors.Trace(err)
+}
+
+// Close implements interface backend.
+func (s *fileBackend) Close() error {
+	return s.closeFile()
+}
+
+// CloseAndFlush implements interface backend.
+func (s *fileBackend) CloseAndFlush(ctx context.Context) error {
+	flushErr := s.Flush(ctx)
+	if err := s.closeFile(); err != nil && flushErr == nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(flushErr)
+}
+
+// MaxFlushInterval implements interface backend.
+func (s *fileBackend) MaxFlushInterval() time.Duration {
+	return maxFlushInterval
+}
+
+// interpolateSQL replaces the placeholders in query with the escaped args.
+// Question marks in quoted strings, identifiers and comments are not
+// placeholders. Identifiers are quoted by quoteStyle, and backslashes are
+// only escapes in string literals.
+func interpolateSQL(query string, args []interface{}, quoteStyle quotes.QuoteStyle) (string, error) {
+	identifierQuote := byte('`')
+	if quoteStyle == quotes.QuoteStyleDoubleQuote {
+		identifierQuote = '"'
+	}
+	var buf strings.Builder
+	argPos := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch c {
+		case '\'', '"', '`':
+			end := i + 1
+			for end < len(query) && query[end] != c {
+				if c != identifierQuote && query[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(query) {
+				return "", errors.Errorf("unterminated quote %c in %s", c, query)
+			}
+			buf.WriteString(query[i : end+1])
+			i = end
+		case '/':
+			if i+1 < len(query) && query[i+1] == '*' {
+				end := strings.Index(query[i+2:], "*/")
+				if end < 0 {
+					return "", errors.Errorf("unterminated comment in %s", query)
+				}
+				end += i + 4
+				buf.WriteString(query[i:end])
+				i = end - 1
+				continue
+			}
+			buf.WriteByte(c)
+		case '?':
+			if argPos >= len(args) {
+				return "", errors.Errorf("missing arguments, need %d-th arg, but only got %d args",
+					argPos+1, len(args))
+			}
+			value, err := sqlexec.EscapeSQL("%?", args[argPos])
+			if err != nil {
+				return "", errors.Trace(err)
+			}
+			buf.WriteString(value)
+			argPos++
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	if argPos != len(args) {
+		return "", errors.Errorf("too many arguments, need %d args, but got %d args",
+			argPos, len(args))
+	}
+	return buf.String(), nil
+}
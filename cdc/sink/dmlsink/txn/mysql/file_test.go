@@ -0,0 +1,263 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tiflow/cdc/model"
+	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
+	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/pkg/quotes"
+	"github.com/pingcap/tiflow/pkg/sink"
+	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func newFileBackend(t *testing.T, dir string, maxFileSize int64) *fileBackend {
+	ctx, cancel := context.WithCancel(context.Background())
+	changefeedID := model.DefaultChangeFeedID("test")
+	statistics := metrics.NewStatistics(ctx, changefeedID, sink.TxnSink)
+	cancel() // Cancel background goroutines in returned metrics.Statistics.
+	cfg := pmysql.NewConfig()
+	cfg.WorkerCount = 1
+	cfg.BatchDMLEnable = false
+	backends, err := newFileBackends(changefeedID, cfg, statistics, dir, maxFileSize)
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	return backends[0]
+}
+
+func newFileTestEvent(
+	commitTs uint64, flushed *int, rows ...*model.RowChangedEvent,
+) *dmlsink.TxnCallbackableEvent {
+	table := &model.TableName{Schema: "s1", Table: "t1", TableID: 1}
+	for _, row := range rows {
+		row.Table = table
+		row.StartTs = commitTs - 1
+		row.CommitTs = commitTs
+		row.ReplicatingTs = 1
+	}
+	return &dmlsink.TxnCallbackableEvent{
+		Event:    &model.SingleTableTxn{StartTs: commitTs - 1, CommitTs: commitTs, Rows: rows},
+		Callback: func() { *flushed++ },
+	}
+}
+
+func fileTestColumns(id int, value string) []*model.Column {
+	return []*model.Column{{
+		Name:  "id",
+		Type:  mysql.TypeLong,
+		Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+		Value: id,
+	}, {
+		Name:  "v",
+		Type:  mysql.TypeVarchar,
+		Value: value,
+	}}
+}
+
+func TestFileBackendFlush(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := newFileBackend(t, dir, 0)
+	flushed := 0
+	s.OnTxnEvent(newFileTestEvent(10, &flushed,
+		&model.RowChangedEvent{Columns: fileTestColumns(1, "a")},
+		&model.RowChangedEvent{PreColumns: fileTestColumns(2, "b"), Columns: fileTestColumns(2, "it's")},
+		&model.RowChangedEvent{PreColumns: fileTestColumns(3, "c")},
+	))
+	s.OnTxnEvent(newFileTestEvent(20, &flushed,
+		&model.RowChangedEvent{Columns: fileTestColumns(4, "d?")},
+	))
+	require.NoError(t, s.Flush(context.Background()))
+	require.Equal(t, 2, flushed)
+	require.Equal(t, 0, s.dmls.rows)
+	require.NoError(t, s.Close())
+
+	content, err := os.ReadFile(filepath.Join(dir, "dml-0-000000.sql"))
+	require.NoError(t, err)
+	require.Equal(t, "BEGIN;\n"+
+		"INSERT INTO `s1`.`t1` (`id`,`v`) VALUES (1,'a');\n"+
+		"UPDATE `s1`.`t1` SET `id` = 2, `v` = 'it\\'s' WHERE `id` = 2 LIMIT 1;\n"+
+		"DELETE FROM `s1`.`t1` WHERE `id` = 3 LIMIT 1;\n"+
+		"INSERT INTO `s1`.`t1` (`id`,`v`) VALUES (4,'d?');\n"+
+		"COMMIT;\n", string(content))
+}
+
+func TestFileBackendRotate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := newFileBackend(t, dir, 64)
+	// Split the events into transactions, so that each of them is written
+	// to a new file.
+	s.dmls.cfg.MaxTxnSizeBytes = 1
+	flushed := 0
+	for i := 1; i <= 3; i++ {
+		row := &model.RowChangedEvent{Columns: fileTestColumns(i, "a"), ApproximateDataSize: 1}
+		s.OnTxnEvent(newFileTestEvent(uint64(i*10), &flushed, row))
+	}
+	require.NoError(t, s.Flush(context.Background()))
+	require.Equal(t, 3, flushed)
+	require.NoError(t, s.Close())
+
+	for i := 1; i <= 3; i++ {
+		content, err := os.ReadFile(s.fileName(i - 1))
+		require.NoError(t, err)
+		require.Equal(t, "BEGIN;\n"+
+			"INSERT INTO `s1`.`t1` (`id`,`v`) VALUES ("+string(rune('0'+i))+",'a');\n"+
+			"COMMIT;\n", string(content))
+	}
+	_, err := os.Stat(s.fileName(3))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestInterpolateSQL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		query      string
+		args       []interface{}
+		quoteStyle quotes.QuoteStyle
+		expected   string
+		hasErr     bool
+	}{
+		{
+			query:    "INSERT INTO `s`.`t?` (`a`,`b`,`c`) VALUES (?,?,?)",
+			args:     []interface{}{1, nil, []byte("x")},
+			expected: "INSERT INTO `s`.`t?` (`a`,`b`,`c`) VALUES (1,NULL,_binary'x')",
+		},
+		{
+			query:    "/* ticdc commitTs=1 ? */ UPDATE \"s\".\"t\" SET \"a\" = ? WHERE \"a\" = ?",
+			args:     []interface{}{"x'y", 1.5},
+			expected: "/* ticdc commitTs=1 ? */ UPDATE \"s\".\"t\" SET \"a\" = 'x\\'y' WHERE \"a\" = 1.5",
+		},
+		{
+			// backslashes are not escapes in identifiers.
+			query:      "UPDATE \"s\".\"t\\\" SET \"a\" = ? WHERE \"b\\\" = '\\'?'",
+			args:       []interface{}{1},
+			quoteStyle: quotes.QuoteStyleDoubleQuote,
+			expected:   "UPDATE \"s\".\"t\\\" SET \"a\" = 1 WHERE \"b\\\" = '\\'?'",
+		},
+		{
+			query:    "UPDATE `s`.`t\\` SET `a` = ? WHERE `b` = \"\\\"?\"",
+			args:     []interface{}{1},
+			expected: "UPDATE `s`.`t\\` SET `a` = 1 WHERE `b` = \"\\\"?\"",
+		},
+		{
+			query:  "DELETE FROM `s`.`t` WHERE `a` = ?",
+			hasErr: true,
+		},
+		{
+			query:  "DELETE FROM `s`.`t` WHERE `a` = 1",
+			args:   []interface{}{1},
+			hasErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		query, err := interpolateSQL(tc.query, tc.args, tc.quoteStyle)
+		if tc.hasErr {
+			require.Error(t, err, tc.query)
+			continue
+		}
+		require.NoError(t, err, tc.query)
+		require.Equal(t, tc.expected, query)
+	}
+}
+
+func TestFileBackendMetrics(t *testing.T) {
+	t.Parallel()
+
+	s := newFileBackend(t, t.TempDir(), 0)
+	s.dmls.cfg.StrictStartTsGrouping = true
+	// All the metrics of the inner backend must be set, e.g. the one of
+	// interleaved transactions.
+	require.False(t, s.dmls.checkStartTsGrouping([]model.Ts{1, 2, 1}))
+	require.NoError(t, s.Close())
+}
+
+func TestFileBackendResume(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "sql")
+	s := newFileBackend(t, dir, 0)
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+
+	// Files of other workers and unrelated files are ignored.
+	for _, name := range []string{"dml-0-000003.sql", "dml-1-000007.sql", "dml-0-x.sql"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("-- old\n"), 0o600))
+	}
+	s = newFileBackend(t, dir, 0)
+	require.Equal(t, 3, s.fileIndex)
+
+	flushed := 0
+	s.OnTxnEvent(newFileTestEvent(10, &flushed,
+		&model.RowChangedEvent{Columns: fileTestColumns(1, "a")}))
+	require.NoError(t, s.Flush(context.Background()))
+	require.NoError(t, s.Close())
+
+	content, err := os.ReadFile(s.fileName(3))
+	require.NoError(t, err)
+	require.Equal(t, "-- old\nBEGIN;\n"+
+		"INSERT INTO `s1`.`t1` (`id`,`v`) VALUES (1,'a');\n"+
+		"COMMIT;\n", string(content))
+	info, err = os.Stat(s.fileName(3))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestFileBackendFlushRollback(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := newFileBackend(t, dir, 64)
+	s.dmls.cfg.MaxTxnSizeBytes = 1
+	flushed := 0
+	for i := 1; i <= 3; i++ {
+		row := &model.RowChangedEvent{Columns: fileTestColumns(i, "a"), ApproximateDataSize: 1}
+		s.OnTxnEvent(newFileTestEvent(uint64(i*10), &flushed, row))
+	}
+	// The third transaction fails to be written, since its file can't be
+	// created.
+	require.NoError(t, os.Mkdir(s.fileName(2), 0o700))
+	require.Error(t, s.Flush(context.Background()))
+	require.Equal(t, 0, flushed)
+	require.Equal(t, 0, s.fileIndex)
+	content, err := os.ReadFile(s.fileName(0))
+	require.NoError(t, err)
+	require.Empty(t, content)
+	_, err = os.Stat(s.fileName(1))
+	require.True(t, os.IsNotExist(err))
+
+	// The retry writes every transaction exactly once.
+	require.NoError(t, os.Remove(s.fileName(2)))
+	require.NoError(t, s.Flush(context.Background()))
+	require.Equal(t, 3, flushed)
+	require.NoError(t, s.Close())
+	for i := 1; i <= 3; i++ {
+		content, err := os.ReadFile(s.fileName(i - 1))
+		require.NoError(t, err)
+		require.Equal(t, "BEGIN;\n"+
+			"INSERT INTO `s1`.`t1` (`id`,`v`) VALUES ("+string(rune('0'+i))+",'a');\n"+
+			"COMMIT;\n", string(content))
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -38,6 +39,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
 	"github.com/pingcap/tiflow/pkg/retry"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/sqlmodel"
@@ -53,12 +55,22 @@ const (
 	// networkDriftDuration is used to construct a context timeout for database operations.
 	networkDriftDuration = 5 * time.Second
 
-	defaultDMLMaxRetry uint64 = 8
-
 	// To limit memory usage for prepared statements.
 	prepStmtCacheSize int = 16 * 1024
+
+	// pingBeforeFlushTimeout is the timeout of pinging the downstream before
+	// flushing, it's short to avoid blocking the flush for long.
+	pingBeforeFlushTimeout = time.Second
+
+	// commitTsCommentPrefix is the prefix of the comment prepended to the first
+	// statement of each transaction when `AnnotateCommitTs` is enabled.
+	commitTsCommentPrefix = "/* ticdc commitTs="
 )
 
+// dupEntryMsgRegexp matches the message of a MySQL duplicate entry error,
+// e.g. "Duplicate entry '1' for key 't.PRIMARY'".
+var dupEntryMsgRegexp = regexp.MustCompile(`(?s)^Duplicate entry '(.*)' for key '(.*)'$`)
+
 type mysqlBackend struct {
 	workerID    int
 	changefeed  string
@@ -68,17 +80,82 @@ type mysqlBackend struct {
 
 	events []*dmlsink.TxnCallbackableEvent
 	rows   int
+	// bufferedBytes is the approximate size of the rows of events.
+	bufferedBytes int64
 
 	statistics                      *metrics.Statistics
 	metricTxnSinkDMLBatchCommit     prometheus.Observer
 	metricTxnSinkDMLBatchCallback   prometheus.Observer
+	metricTxnSinkDMLBatchSize       prometheus.Observer
 	metricTxnPrepareStatementErrors prometheus.Counter
+	metricTxnPingFailures           prometheus.Counter
+	metricTxnInterleavedStartTs     prometheus.Counter
+	metricTxnInsertTranslated       prometheus.Counter
+	metricTxnReplace                prometheus.Counter
+	metricTxnRowsAffectedMismatch   prometheus.Counter
+	metricTxnRowsAffectedTolerated  prometheus.Counter
+	// metricTxnDMLStatements and metricTxnDMLStatementRows are curried with
+	// the changefeed, and labeled by the operation of statements.
+	metricTxnDMLStatements    *prometheus.CounterVec
+	metricTxnDMLStatementRows *prometheus.CounterVec
 
 	// implement stmtCache to improve performance, especially when the downstream is TiDB
 	stmtCache *lru.Cache
 	// Indicate if the CachePrepStmts should be enabled or not
 	cachePrepStmts   bool
 	maxAllowedPacket int64
+
+	// columnTransformers transforms column values before generating SQLs,
+	// it's keyed by the lower case table and then the lower case column name,
+	// so that no key needs to be built for each column of each row.
+	columnTransformers map[transformerTable]map[string]func(interface{}) interface{}
+	// deterministicTransform indicates that all column transformers always
+	// return the same output for the same input, so it's safe to apply them
+	// to handle key columns, which are used to match downstream rows.
+	deterministicTransform bool
+	// warnedHandleKeyTransform records the handle key columns that have been
+	// warned about not being transformed.
+	warnedHandleKeyTransform map[transformerColumn]struct{}
+
+	// dryRunHook is called with the prepared SQLs and values instead of
+	// executing them when `DryRun` is enabled.
+	dryRunHook func(sqls []string, values [][]interface{})
+
+	// sessionVars is the session variables of the downstream connections, it's
+	// empty if they can't be queried.
+	sessionVars pmysql.SessionVariables
+
+	// capabilityCache is invalidated for downstreamHost on connection errors.
+	capabilityCache *pmysql.CapabilityCache
+	downstreamHost  string
+}
+
+// BackendOption customizes the MySQL backends created by NewMySQLBackends.
+type BackendOption func(*backendOptions)
+
+type backendOptions struct {
+	dsnTransform    func(dsn string) (string, error)
+	capabilityCache *pmysql.CapabilityCache
+}
+
+// WithDSNTransform sets a function to modify the DSN generated from the sink URI
+// before connecting to the downstream, such as injecting the credentials fetched
+// from a secrets manager, or adding the driver parameters not supported by the
+// sink URI. It applies to every connection, including the one to probe the
+// downstream when generating the DSN.
+func WithDSNTransform(transform func(dsn string) (string, error)) BackendOption {
+	return func(o *backendOptions) {
+		o.dsnTransform = transform
+	}
+}
+
+// WithCapabilityCache sets the cache of the downstream capabilities, so that
+// the downstream is not probed repeatedly. The downstream is always probed if
+// it's not set.
+func WithCapabilityCache(cache *pmysql.CapabilityCache) BackendOption {
+	return func(o *backendOptions) {
+		o.capabilityCache = cache
+	}
 }
 
 // NewMySQLBackends creates a new MySQL sink using schema storage
@@ -89,9 +166,25 @@ func NewMySQLBackends(
 	replicaConfig *config.ReplicaConfig,
 	dbConnFactory pmysql.Factory,
 	statistics *metrics.Statistics,
+	opts ...BackendOption,
 ) ([]*mysqlBackend, error) {
 	changefeed := fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID)
 
+	options := &backendOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.dsnTransform != nil {
+		factory := dbConnFactory
+		dbConnFactory = func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+			dsnStr, err := options.dsnTransform(dsnStr)
+			if err != nil {
+				return nil, cerror.WrapError(cerror.ErrMySQLConnectionError, err)
+			}
+			return factory(ctx, dsnStr)
+		}
+	}
+
 	cfg := pmysql.NewConfig()
 	err := cfg.Apply(config.GetGlobalServerConfig().TZ, changefeedID, sinkURI, replicaConfig)
 	if err != nil {
@@ -108,15 +201,13 @@ func NewMySQLBackends(
 		return nil, err
 	}
 
-	cfg.IsTiDB, err = pmysql.CheckIsTiDB(ctx, db)
-	if err != nil {
-		return nil, err
-	}
-
-	cfg.IsWriteSourceExisted, err = pmysql.CheckIfBDRModeIsSupported(ctx, db)
+	// Skip the BDR mode probe if write source is disabled, since it will never be set.
+	caps, err := options.capabilityCache.Probe(ctx, db, sinkURI.Host, cfg.EnableWriteSource)
 	if err != nil {
 		return nil, err
 	}
+	cfg.IsTiDB = caps.IsTiDB
+	cfg.IsWriteSourceExisted = caps.IsBDRModeSupported
 
 	// By default, cache-prep-stmts=true, an LRU cache is used for prepared statements,
 	// two connections are required to process a transaction.
@@ -131,11 +222,30 @@ func NewMySQLBackends(
 	// This issue is less likely to occur when the connection pool is larger,
 	// as there are more connections available for use.
 	// Adding an extra connection to the connection pool solves the connection exhaustion issue.
-	db.SetMaxIdleConns(cfg.WorkerCount + 1)
-	db.SetMaxOpenConns(cfg.WorkerCount + 1)
+	// The limits can be lowered explicitly for the downstreams shared by many changefeeds.
+	maxOpenConns, maxIdleConns := cfg.ConnPoolLimits()
+	if maxOpenConns < cfg.WorkerCount+1 {
+		log.Warn("max open connections is less than worker count plus one, "+
+			"workers may wait for connections",
+			zap.String("changefeed", changefeed),
+			zap.Int("maxOpenConns", maxOpenConns),
+			zap.Int("workerCount", cfg.WorkerCount))
+	}
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetMaxOpenConns(maxOpenConns)
 
 	// Inherit the default value of the prepared statement cache from the SinkURI Options
 	cachePrepStmts := cfg.CachePrepStmts
+	if cachePrepStmts && maxOpenConns <= cfg.WorkerCount {
+		// Without the extra connection, all the connections can be held by the
+		// transactions of workers, and preparing statements hangs.
+		log.Warn("prepared statement cache is disabled, "+
+			"since max open connections is not greater than worker count",
+			zap.String("changefeed", changefeed),
+			zap.Int("maxOpenConns", maxOpenConns),
+			zap.Int("workerCount", cfg.WorkerCount))
+		cachePrepStmts = false
+	}
 	if cachePrepStmts {
 		// query the size of the prepared statement cache on serverside
 		maxPreparedStmtCount, err := pmysql.QueryMaxPreparedStmtCount(ctx, db)
@@ -148,10 +258,10 @@ func NewMySQLBackends(
 		}
 		// if maxPreparedStmtCount == 0,
 		// it means that the prepared statement cache is disabled on serverside.
-		// if maxPreparedStmtCount/(cfg.WorkerCount+1) == 0, for each single connection,
+		// if maxPreparedStmtCount/maxOpenConns == 0, for each single connection,
 		// it means that the prepared statement cache is disabled on clientsize.
 		// Because each connection can not hold at lease one prepared statement.
-		if maxPreparedStmtCount == 0 || maxPreparedStmtCount/(cfg.WorkerCount+1) == 0 {
+		if maxPreparedStmtCount == 0 || maxPreparedStmtCount/maxOpenConns == 0 {
 			cachePrepStmts = false
 		}
 	}
@@ -176,23 +286,33 @@ func NewMySQLBackends(
 		maxAllowedPacket = int64(variable.DefMaxAllowedPacket)
 	}
 
+	// The session variables are only logged to help debugging write failures,
+	// such as the ones caused by an unexpected charset.
+	sessionVars, err := pmysql.QuerySessionVariables(ctx, db)
+	if err != nil {
+		log.Warn("failed to query session variables",
+			zap.String("changefeed", changefeed),
+			zap.Error(err))
+	} else {
+		log.Info("MySQL sink session variables",
+			zap.String("changefeed", changefeed),
+			zap.String("sqlMode", sessionVars.SQLMode),
+			zap.String("characterSetClient", sessionVars.CharacterSetClient),
+			zap.String("collationConnection", sessionVars.CollationConnection))
+	}
+
 	backends := make([]*mysqlBackend, 0, cfg.WorkerCount)
 	for i := 0; i < cfg.WorkerCount; i++ {
-		backends = append(backends, &mysqlBackend{
-			workerID:    i,
-			changefeed:  changefeed,
-			db:          db,
-			cfg:         cfg,
-			dmlMaxRetry: defaultDMLMaxRetry,
-			statistics:  statistics,
-
-			metricTxnSinkDMLBatchCommit:     txn.SinkDMLBatchCommit.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
-			metricTxnSinkDMLBatchCallback:   txn.SinkDMLBatchCallback.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
-			metricTxnPrepareStatementErrors: txn.PrepareStatementErrors.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
-			stmtCache:                       stmtCache,
-			cachePrepStmts:                  cachePrepStmts,
-			maxAllowedPacket:                maxAllowedPacket,
-		})
+		backend := newBackendWithMetrics(i, changefeedID, cfg, statistics)
+		backend.db = db
+		backend.dmlMaxRetry = cfg.DMLMaxRetry
+		backend.stmtCache = stmtCache
+		backend.cachePrepStmts = cachePrepStmts
+		backend.maxAllowedPacket = maxAllowedPacket
+		backend.sessionVars = sessionVars
+		backend.capabilityCache = options.capabilityCache
+		backend.downstreamHost = sinkURI.Host
+		backends = append(backends, backend)
 	}
 
 	log.Info("MySQL backends is created",
@@ -202,12 +322,61 @@ func NewMySQLBackends(
 	return backends, nil
 }
 
+// newBackendWithMetrics creates a backend of the worker with all of its
+// metrics set, which isn't connected to a downstream yet.
+func newBackendWithMetrics(
+	workerID int,
+	changefeedID model.ChangeFeedID,
+	cfg *pmysql.Config,
+	statistics *metrics.Statistics,
+) *mysqlBackend {
+	changefeedLabels := prometheus.Labels{"namespace": changefeedID.Namespace, "changefeed": changefeedID.ID}
+	return &mysqlBackend{
+		workerID:   workerID,
+		changefeed: fmt.Sprintf("%s.%s", changefeedID.Namespace, changefeedID.ID),
+		cfg:        cfg,
+		statistics: statistics,
+
+		metricTxnSinkDMLBatchCommit:     txn.SinkDMLBatchCommit.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnSinkDMLBatchCallback:   txn.SinkDMLBatchCallback.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnSinkDMLBatchSize:       txn.SinkDMLBatchApproximateSize.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnPrepareStatementErrors: txn.PrepareStatementErrors.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnPingFailures:           txn.PingFailures.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnInterleavedStartTs:     txn.InterleavedStartTs.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnInsertTranslated:       txn.InsertTranslatedEvents.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnReplace:                txn.ReplaceEvents.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnRowsAffectedMismatch:   txn.RowsAffectedMismatches.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnRowsAffectedTolerated:  txn.RowsAffectedToleratedMismatches.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
+		metricTxnDMLStatements:          txn.DMLStatements.MustCurryWith(changefeedLabels),
+		metricTxnDMLStatementRows:       txn.DMLStatementRows.MustCurryWith(changefeedLabels),
+	}
+}
+
+// pingDownstream checks the connection with a short timeout, so that a dead
+// connection is detected and recycled by the driver before executing DMLs.
+// The failure is only recorded, the following execution retries on errors.
+func (s *mysqlBackend) pingDownstream(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, pingBeforeFlushTimeout)
+	defer cancel()
+	if err := s.db.PingContext(ctx); err != nil {
+		s.metricTxnPingFailures.Inc()
+		log.Warn("ping downstream before flush failed",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Error(err))
+	}
+}
+
 // OnTxnEvent implements interface backend.
 // It adds the event to the buffer, and return true if it needs flush immediately.
 func (s *mysqlBackend) OnTxnEvent(event *dmlsink.TxnCallbackableEvent) (needFlush bool) {
 	s.events = append(s.events, event)
 	s.rows += len(event.Event.Rows)
-	return event.Event.ToWaitFlush() || s.rows >= s.cfg.MaxTxnRow
+	for _, row := range event.Event.Rows {
+		s.bufferedBytes += row.ApproximateDataSize
+	}
+	return event.Event.ToWaitFlush() || s.rows >= s.cfg.MaxTxnRow ||
+		(s.cfg.MaxTxnBufferBytes > 0 && s.bufferedBytes >= s.cfg.MaxTxnBufferBytes)
 }
 
 // Flush implements interface backend.
@@ -223,28 +392,60 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 		failpoint.Return(errors.Trace(dmysql.ErrInvalidConn))
 	})
 
+	if s.cfg.PingBeforeFlush {
+		s.pingDownstream(ctx)
+	}
+
+	if err := s.handleZeroAutoIncrementKeys(); err != nil {
+		return errors.Trace(err)
+	}
+
 	for _, event := range s.events {
 		s.statistics.ObserveRows(event.Event.Rows...)
 	}
 
-	dmls := s.prepareDMLs()
-	log.Debug("prepare DMLs", zap.String("changefeed", s.changefeed), zap.Any("rows", s.rows),
-		zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
-
+	// Callbacks are only called after all transactions of the flush are
+	// committed. If a later transaction fails, the committed ones are written
+	// again after the changefeed restarts from its checkpoint, which doesn't
+	// cover them since their callbacks are not called.
+	var (
+		callbacks []dmlsink.CallbackFunc
+		batchSize int64
+	)
 	start := time.Now()
-	if err := s.execDMLWithMaxRetries(ctx, dmls); err != nil {
-		if errors.Cause(err) != context.Canceled {
-			log.Error("execute DMLs failed", zap.String("changefeed", s.changefeed), zap.Error(err))
+	for _, events := range s.splitEventsBySize() {
+		dmls := s.prepareDMLsFor(events)
+		log.Debug("prepare DMLs", zap.String("changefeed", s.changefeed), zap.Any("rows", dmls.rowCount),
+			zap.Strings("sqls", dmls.sqls), zap.Any("values", dmls.values))
+		batchSize += dmls.approximateSize
+
+		if err := s.execDMLWithMaxRetries(ctx, dmls); err != nil {
+			if errors.Cause(err) != context.Canceled {
+				log.Error("execute DMLs failed", zap.String("changefeed", s.changefeed), zap.Error(err))
+			}
+			if isBadConnErr(err) {
+				s.capabilityCache.Invalidate(s.downstreamHost)
+			}
+			return errors.Trace(err)
 		}
-		return errors.Trace(err)
+		callbacks = append(callbacks, dmls.callbacks...)
 	}
+	// Observe the size once per flush even if it's split into several
+	// transactions, retries in execDMLWithMaxRetries are not counted repeatedly.
+	s.metricTxnSinkDMLBatchSize.Observe(float64(batchSize))
 	startCallback := time.Now()
-	for _, callback := range dmls.callbacks {
+	for _, callback := range callbacks {
 		callback()
 	}
 	s.metricTxnSinkDMLBatchCommit.Observe(startCallback.Sub(start).Seconds())
 	s.metricTxnSinkDMLBatchCallback.Observe(time.Since(startCallback).Seconds())
 
+	s.resetEvents()
+	return
+}
+
+// resetEvents drops the buffered events after they are flushed.
+func (s *mysqlBackend) resetEvents() {
 	// Be friently to GC.
 	for i := 0; i < len(s.events); i++ {
 		s.events[i] = nil
@@ -254,7 +455,42 @@ func (s *mysqlBackend) Flush(ctx context.Context) (err error) {
 	}
 	s.events = s.events[:0]
 	s.rows = 0
-	return
+	s.bufferedBytes = 0
+}
+
+// FlushAndReportCommitTs flushes all buffered events synchronously, and returns
+// the max commitTs among the events written to downstream. 0 is returned if there
+// is no buffered event.
+func (s *mysqlBackend) FlushAndReportCommitTs(ctx context.Context) (model.Ts, error) {
+	var maxCommitTs model.Ts
+	for _, event := range s.events {
+		if event.Event.CommitTs > maxCommitTs {
+			maxCommitTs = event.Event.CommitTs
+		}
+	}
+	if err := s.Flush(ctx); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return maxCommitTs, nil
+}
+
+// CloseAndFlush implements interface backend. It flushes the buffered events
+// before closing the backend, so that their callbacks are called, e.g. in a
+// controlled stop. The backend is closed even if the flush fails, and the
+// error of the flush is returned.
+func (s *mysqlBackend) CloseAndFlush(ctx context.Context) error {
+	var flushErr error
+	if s.rows > 0 {
+		if s.db == nil {
+			flushErr = errors.New("mysql backend is closed with buffered events")
+		} else {
+			flushErr = s.Flush(ctx)
+		}
+	}
+	if err := s.Close(); err != nil && flushErr == nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(flushErr)
 }
 
 // Close implements interface backend.
@@ -274,12 +510,35 @@ func (s *mysqlBackend) MaxFlushInterval() time.Duration {
 	return maxFlushInterval
 }
 
+// The operations of the generated DML statements.
+const (
+	dmlOperationInsert  = "insert"
+	dmlOperationReplace = "replace"
+	dmlOperationUpdate  = "update"
+	dmlOperationDelete  = "delete"
+)
+
+// observeDMLStatements counts the generated statements of the operation, which
+// write rows rows in total.
+func (s *mysqlBackend) observeDMLStatements(operation string, statements, rows int) {
+	s.metricTxnDMLStatements.WithLabelValues(operation).Add(float64(statements))
+	s.metricTxnDMLStatementRows.WithLabelValues(operation).Add(float64(rows))
+}
+
 type preparedDMLs struct {
-	startTs         []model.Ts
-	sqls            []string
-	values          [][]interface{}
-	callbacks       []dmlsink.CallbackFunc
-	rowCount        int
+	startTs []model.Ts
+	// sqlStartTs is the startTs of the transaction each sql belongs to.
+	sqlStartTs []model.Ts
+	sqls       []string
+	values     [][]interface{}
+	callbacks  []dmlsink.CallbackFunc
+	rowCount   int
+	// deleteRowCount, updateRowCount and replaceRowCount are the number of
+	// deleted rows, updated rows and inserted rows written by REPLACE, which
+	// can make the rows affected in the downstream less or more than rowCount.
+	deleteRowCount  int
+	updateRowCount  int
+	replaceRowCount int
 	approximateSize int64
 }
 
@@ -287,8 +546,10 @@ type preparedDMLs struct {
 // of CDC into a general one.
 func convert2RowChanges(
 	row *model.RowChangedEvent,
+	targetTable *model.TableName,
 	tableInfo *timodel.TableInfo,
 	changeType sqlmodel.RowChangeType,
+	quoteStyle quotes.QuoteStyle,
 ) *sqlmodel.RowChange {
 	preValues := make([]interface{}, 0, len(row.PreColumns))
 	for _, col := range row.PreColumns {
@@ -315,7 +576,7 @@ func convert2RowChanges(
 	case sqlmodel.RowChangeInsert:
 		res = sqlmodel.NewRowChange(
 			row.Table,
-			nil,
+			targetTable,
 			nil,
 			postValues,
 			tableInfo,
@@ -323,7 +584,7 @@ func convert2RowChanges(
 	case sqlmodel.RowChangeUpdate:
 		res = sqlmodel.NewRowChange(
 			row.Table,
-			nil,
+			targetTable,
 			preValues,
 			postValues,
 			tableInfo,
@@ -331,13 +592,14 @@ func convert2RowChanges(
 	case sqlmodel.RowChangeDelete:
 		res = sqlmodel.NewRowChange(
 			row.Table,
-			nil,
+			targetTable,
 			preValues,
 			nil,
 			tableInfo,
 			nil, nil)
 	}
 	res.SetApproximateDataSize(row.ApproximateDataSize)
+	res.SetQuoteStyle(quoteStyle)
 	return res
 }
 
@@ -355,6 +617,199 @@ func convertBinaryToString(cols []*model.Column) {
 	}
 }
 
+// SetColumnTransformers sets the column transformers used to transform column
+// values before they are written downstream. Transformers are keyed by
+// `schema.table.column`. Handle key columns are only transformed when
+// deterministic is true, otherwise the transformed values of a row could not be
+// matched by later updates and deletes.
+func (s *mysqlBackend) SetColumnTransformers(
+	transformers map[string]func(interface{}) interface{},
+	deterministic bool,
+) {
+	s.columnTransformers = make(map[transformerTable]map[string]func(interface{}) interface{})
+	for key, transformer := range transformers {
+		parts := strings.SplitN(strings.ToLower(key), ".", 3)
+		if len(parts) != 3 {
+			log.Warn("column transformer is ignored, since its key is not `schema.table.column`",
+				zap.String("changefeed", s.changefeed),
+				zap.String("key", key))
+			continue
+		}
+		table := transformerTable{schema: parts[0], table: parts[1]}
+		columns, ok := s.columnTransformers[table]
+		if !ok {
+			columns = make(map[string]func(interface{}) interface{})
+			s.columnTransformers[table] = columns
+		}
+		columns[parts[2]] = transformer
+	}
+	s.deterministicTransform = deterministic
+	s.warnedHandleKeyTransform = make(map[transformerColumn]struct{})
+}
+
+// transformerTable is the lower case table name of column transformers.
+type transformerTable struct {
+	schema string
+	table  string
+}
+
+// transformerColumn is the lower case column name of a column transformer.
+type transformerColumn struct {
+	transformerTable
+	column string
+}
+
+// SetDryRunHook sets the hook which receives the prepared SQLs and values in
+// the dry-run mode.
+func (s *mysqlBackend) SetDryRunHook(hook func(sqls []string, values [][]interface{})) {
+	s.dryRunHook = hook
+}
+
+// transformRow returns a copy of the row with column transformers applied.
+// The original row is left untouched.
+func (s *mysqlBackend) transformRow(row *model.RowChangedEvent) *model.RowChangedEvent {
+	if len(s.columnTransformers) == 0 {
+		return row
+	}
+	table := transformerTable{
+		schema: strings.ToLower(row.Table.Schema),
+		table:  strings.ToLower(row.Table.Table),
+	}
+	transformers, ok := s.columnTransformers[table]
+	if !ok {
+		return row
+	}
+	newRow := *row
+	newRow.Columns = s.transformColumns(table, transformers, row.Columns)
+	newRow.PreColumns = s.transformColumns(table, transformers, row.PreColumns)
+	return &newRow
+}
+
+func (s *mysqlBackend) transformColumns(
+	table transformerTable,
+	transformers map[string]func(interface{}) interface{},
+	cols []*model.Column,
+) []*model.Column {
+	var res []*model.Column
+	for i, col := range cols {
+		if col == nil {
+			continue
+		}
+		name := strings.ToLower(col.Name)
+		transformer, ok := transformers[name]
+		if !ok {
+			continue
+		}
+		if col.Flag.IsHandleKey() && !s.deterministicTransform {
+			key := transformerColumn{transformerTable: table, column: name}
+			if _, warned := s.warnedHandleKeyTransform[key]; !warned {
+				s.warnedHandleKeyTransform[key] = struct{}{}
+				log.Warn("column transformer is ignored for handle key column, "+
+					"since the transformers are not declared as deterministic",
+					zap.String("changefeed", s.changefeed),
+					zap.String("schema", table.schema),
+					zap.String("table", table.table),
+					zap.String("column", name))
+			}
+			continue
+		}
+		if res == nil {
+			res = make([]*model.Column, len(cols))
+			copy(res, cols)
+		}
+		newCol := *col
+		newCol.Value = transformer(col.Value)
+		res[i] = &newCol
+	}
+	if res == nil {
+		return cols
+	}
+	return res
+}
+
+// handleZeroAutoIncrementKeys handles the inserted or deleted rows whose auto
+// increment handle key is zero according to the configured policy.
+func (s *mysqlBackend) handleZeroAutoIncrementKeys() error {
+	policy := s.cfg.ZeroAutoIncrementKeyPolicy
+	if policy == "" || policy == pmysql.ZeroAutoIncrementKeyPolicyPassThrough {
+		return nil
+	}
+	for idx, event := range s.events {
+		var rows []*model.RowChangedEvent
+		for i, row := range event.Event.Rows {
+			col := getZeroAutoIncrementKey(row)
+			if col == nil {
+				if rows != nil {
+					rows = append(rows, row)
+				}
+				continue
+			}
+			if policy == pmysql.ZeroAutoIncrementKeyPolicyError {
+				return cerror.ErrMySQLZeroAutoIncrementKey.GenWithStackByArgs(
+					col.Name, row.Table.String(), row.StartTs)
+			}
+			log.Warn("skip the row whose auto increment handle key is zero",
+				zap.String("changefeed", s.changefeed),
+				zap.Stringer("table", row.Table),
+				zap.String("column", col.Name),
+				zap.Uint64("startTs", row.StartTs),
+				zap.Uint64("commitTs", row.CommitTs))
+			if rows == nil {
+				rows = make([]*model.RowChangedEvent, i, len(event.Event.Rows))
+				copy(rows, event.Event.Rows[:i])
+			}
+			s.rows--
+			s.bufferedBytes -= row.ApproximateDataSize
+		}
+		if rows != nil {
+			// The transaction is shared with the caller, so the rows are set on
+			// copies of it instead of being modified in place.
+			txn := *event.Event
+			txn.Rows = rows
+			copied := *event
+			copied.Event = &txn
+			s.events[idx] = &copied
+		}
+	}
+	return nil
+}
+
+// getZeroAutoIncrementKey returns the auto increment handle key column of the
+// inserted or deleted row if its value is zero, otherwise nil is returned.
+func getZeroAutoIncrementKey(row *model.RowChangedEvent) *model.Column {
+	if row.TableInfo == nil || row.IsUpdate() {
+		return nil
+	}
+	cols := row.Columns
+	if row.IsDelete() {
+		cols = row.PreColumns
+	}
+	for _, col := range cols {
+		if col == nil || !col.Flag.IsHandleKey() {
+			continue
+		}
+		info := timodel.FindColumnInfo(row.TableInfo.Columns, strings.ToLower(col.Name))
+		if info == nil || !mysql.HasAutoIncrementFlag(info.GetFlag()) {
+			continue
+		}
+		switch v := col.Value.(type) {
+		case int64:
+			if v == 0 {
+				return col
+			}
+		case uint64:
+			if v == 0 {
+				return col
+			}
+		case int:
+			if v == 0 {
+				return col
+			}
+		}
+	}
+	return nil
+}
+
 func (s *mysqlBackend) groupRowsByType(
 	event *dmlsink.TxnCallbackableEvent,
 	tableInfo *timodel.TableInfo,
@@ -371,11 +826,12 @@ func (s *mysqlBackend) groupRowsByType(
 	for _, row := range event.Event.Rows {
 		convertBinaryToString(row.Columns)
 		convertBinaryToString(row.PreColumns)
+		row = s.transformRow(row)
 
 		if row.IsInsert() {
 			insertRow = append(
 				insertRow,
-				convert2RowChanges(row, tableInfo, sqlmodel.RowChangeInsert))
+				convert2RowChanges(row, s.targetTable(row.Table), tableInfo, sqlmodel.RowChangeInsert, s.cfg.QuoteStyle))
 			if len(insertRow) >= s.cfg.MaxTxnRow {
 				insertRows = append(insertRows, insertRow)
 				insertRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
@@ -385,7 +841,7 @@ func (s *mysqlBackend) groupRowsByType(
 		if row.IsDelete() {
 			deleteRow = append(
 				deleteRow,
-				convert2RowChanges(row, tableInfo, sqlmodel.RowChangeDelete))
+				convert2RowChanges(row, s.targetTable(row.Table), tableInfo, sqlmodel.RowChangeDelete, s.cfg.QuoteStyle))
 			if len(deleteRow) >= s.cfg.MaxTxnRow {
 				deleteRows = append(deleteRows, deleteRow)
 				deleteRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
@@ -395,7 +851,7 @@ func (s *mysqlBackend) groupRowsByType(
 		if row.IsUpdate() {
 			updateRow = append(
 				updateRow,
-				convert2RowChanges(row, tableInfo, sqlmodel.RowChangeUpdate))
+				convert2RowChanges(row, s.targetTable(row.Table), tableInfo, sqlmodel.RowChangeUpdate, s.cfg.QuoteStyle))
 			if len(updateRow) >= s.cfg.MaxMultiUpdateRowCount {
 				updateRows = append(updateRows, updateRow)
 				updateRow = make([]*sqlmodel.RowChange, 0, preAllocateSize)
@@ -416,11 +872,43 @@ func (s *mysqlBackend) groupRowsByType(
 	return
 }
 
+// hasConsistentColumnCount checks whether all rows of the event have the same
+// column count as the table info. The table info is derived from the first row,
+// so a malformed event can produce wrong placeholders in batch SQLs.
+func hasConsistentColumnCount(
+	event *dmlsink.TxnCallbackableEvent,
+	tableInfo *timodel.TableInfo,
+) bool {
+	columnCount := len(tableInfo.Columns)
+	for _, row := range event.Event.Rows {
+		if len(row.Columns) != 0 && len(row.Columns) != columnCount {
+			return false
+		}
+		if len(row.PreColumns) != 0 && len(row.PreColumns) != columnCount {
+			return false
+		}
+	}
+	return true
+}
+
+// batchSingleTxnDmls generates batch SQLs for the event, ok is false if the
+// rows are inconsistent with the table info and the caller should fall back to
+// generating SQLs row by row.
 func (s *mysqlBackend) batchSingleTxnDmls(
 	event *dmlsink.TxnCallbackableEvent,
 	tableInfo *timodel.TableInfo,
 	translateToInsert bool,
-) (sqls []string, values [][]interface{}) {
+) (sqls []string, values [][]interface{}, ok bool) {
+	if !hasConsistentColumnCount(event, tableInfo) {
+		log.Warn("rows have inconsistent column count with the table info, "+
+			"fallback to generate SQLs row by row",
+			zap.String("changefeed", s.changefeed),
+			zap.Stringer("table", event.Event.Rows[0].Table),
+			zap.Int("columnCount", len(tableInfo.Columns)),
+			zap.Uint64("startTs", event.Event.Rows[0].StartTs))
+		return nil, nil, false
+	}
+
 	insertRows, updateRows, deleteRows := s.groupRowsByType(event, tableInfo)
 
 	// handle delete
@@ -429,6 +917,7 @@ func (s *mysqlBackend) batchSingleTxnDmls(
 			sql, value := sqlmodel.GenDeleteSQL(rows...)
 			sqls = append(sqls, sql)
 			values = append(values, value)
+			s.observeDMLStatements(dmlOperationDelete, 1, len(rows))
 		}
 	}
 
@@ -436,9 +925,10 @@ func (s *mysqlBackend) batchSingleTxnDmls(
 	if len(updateRows) > 0 {
 		if s.cfg.IsTiDB {
 			for _, rows := range updateRows {
-				s, v := s.genUpdateSQL(rows...)
-				sqls = append(sqls, s...)
-				values = append(values, v...)
+				sql, value := s.genUpdateSQL(rows...)
+				sqls = append(sqls, sql...)
+				values = append(values, value...)
+				s.observeDMLStatements(dmlOperationUpdate, len(sql), len(rows))
 			}
 			// The behavior of update statement differs between TiDB and MySQL.
 			// So we don't use batch update statement when downstream is MySQL.
@@ -450,6 +940,7 @@ func (s *mysqlBackend) batchSingleTxnDmls(
 					sqls = append(sqls, sql)
 					values = append(values, value)
 				}
+				s.observeDMLStatements(dmlOperationUpdate, len(rows), len(rows))
 			}
 		}
 	}
@@ -461,15 +952,17 @@ func (s *mysqlBackend) batchSingleTxnDmls(
 				sql, value := sqlmodel.GenInsertSQL(sqlmodel.DMLInsert, rows...)
 				sqls = append(sqls, sql)
 				values = append(values, value)
+				s.observeDMLStatements(dmlOperationInsert, 1, len(rows))
 			} else {
 				sql, value := sqlmodel.GenInsertSQL(sqlmodel.DMLReplace, rows...)
 				sqls = append(sqls, sql)
 				values = append(values, value)
+				s.observeDMLStatements(dmlOperationReplace, 1, len(rows))
 			}
 		}
 	}
 
-	return
+	return sqls, values, true
 }
 
 func (s *mysqlBackend) genUpdateSQL(rows ...*sqlmodel.RowChange) ([]string, [][]interface{}) {
@@ -494,6 +987,16 @@ func (s *mysqlBackend) genUpdateSQL(rows ...*sqlmodel.RowChange) ([]string, [][]
 	return sqls, values
 }
 
+// targetTable returns the downstream table which the DMLs of the given table
+// are written to. It's the table itself if Config.TableRewriteFunc is not set.
+func (s *mysqlBackend) targetTable(table *model.TableName) *model.TableName {
+	if s.cfg.TableRewriteFunc == nil || table == nil {
+		return table
+	}
+	target := s.cfg.TableRewriteFunc(*table)
+	return &target
+}
+
 func hasHandleKey(cols []*model.Column) bool {
 	for _, col := range cols {
 		if col == nil {
@@ -506,27 +1009,183 @@ func hasHandleKey(cols []*model.Column) bool {
 	return false
 }
 
+// handleKeyOf returns the values of the handle key columns of the row, prefixed
+// by the table. false is returned if the row doesn't have a handle key. Every
+// part is length-prefixed, so that the keys of different rows never collide
+// even if the values contain separators.
+func handleKeyOf(table *model.TableName, cols []*model.Column) (string, bool) {
+	var builder strings.Builder
+	writePart := func(part string) {
+		fmt.Fprintf(&builder, "%d:%s", len(part), part)
+	}
+	writePart(table.Schema)
+	writePart(table.Table)
+	found := false
+	for _, col := range cols {
+		if col == nil || !col.Flag.IsHandleKey() {
+			continue
+		}
+		found = true
+		writePart(col.Name)
+		if col.Value == nil {
+			builder.WriteString("n")
+			continue
+		}
+		builder.WriteString("v")
+		writePart(fmt.Sprintf("%T:%v", col.Value, col.Value))
+	}
+	return builder.String(), found
+}
+
+// coalesceUpdates collapses the consecutive updates to the same row into the
+// last one, whose PreColumns are replaced by the ones of the first update. The
+// updates are no longer consecutive if an insert or a delete, or an update
+// which changes the handle key, intervenes. The returned events are copies if
+// any of their rows are changed, the given events are kept as is.
+func coalesceUpdates(events []*dmlsink.TxnCallbackableEvent) []*dmlsink.TxnCallbackableEvent {
+	type rowPos struct {
+		event, row int
+	}
+	type pendingUpdate struct {
+		pos        rowPos
+		preColumns []*model.Column
+	}
+	// pending is the last update of every row, keyed by the handle key.
+	pending := make(map[string]pendingUpdate)
+	skipped := make(map[rowPos]struct{})
+	coalesced := make(map[rowPos][]*model.Column)
+	for i, event := range events {
+		for j, row := range event.Event.Rows {
+			preKey, hasPreKey := handleKeyOf(row.Table, row.PreColumns)
+			key, hasKey := handleKeyOf(row.Table, row.Columns)
+			if !row.IsUpdate() || !hasPreKey || !hasKey || preKey != key {
+				delete(pending, preKey)
+				delete(pending, key)
+				continue
+			}
+			pos := rowPos{event: i, row: j}
+			preColumns := row.PreColumns
+			if last, ok := pending[key]; ok {
+				skipped[last.pos] = struct{}{}
+				delete(coalesced, last.pos)
+				preColumns = last.preColumns
+				coalesced[pos] = preColumns
+			}
+			pending[key] = pendingUpdate{pos: pos, preColumns: preColumns}
+		}
+	}
+	if len(skipped) == 0 {
+		return events
+	}
+
+	result := make([]*dmlsink.TxnCallbackableEvent, 0, len(events))
+	for i, event := range events {
+		var rows []*model.RowChangedEvent
+		changed := false
+		for j, row := range event.Event.Rows {
+			pos := rowPos{event: i, row: j}
+			if _, ok := skipped[pos]; ok {
+				changed = true
+				continue
+			}
+			if preColumns, ok := coalesced[pos]; ok {
+				changed = true
+				copied := *row
+				copied.PreColumns = preColumns
+				row = &copied
+			}
+			rows = append(rows, row)
+		}
+		if !changed {
+			result = append(result, event)
+			continue
+		}
+		txn := *event.Event
+		txn.Rows = rows
+		result = append(result, &dmlsink.TxnCallbackableEvent{
+			Event:     &txn,
+			Callback:  event.Callback,
+			SinkState: event.SinkState,
+		})
+	}
+	return result
+}
+
 // prepareDMLs converts model.RowChangedEvent list to query string list and args list
 func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
+	return s.prepareDMLsFor(s.events)
+}
+
+// splitEventsBySize splits the buffered events into groups, each of which is
+// written in one downstream transaction. Events are only split when their
+// startTs changes, so a group can exceed MaxTxnSizeBytes if a single upstream
+// transaction is larger than it.
+func (s *mysqlBackend) splitEventsBySize() [][]*dmlsink.TxnCallbackableEvent {
+	if s.cfg.MaxTxnSizeBytes <= 0 {
+		return [][]*dmlsink.TxnCallbackableEvent{s.events}
+	}
+
+	var groups [][]*dmlsink.TxnCallbackableEvent
+	first := 0
+	size := int64(0)
+	for i, event := range s.events {
+		eventSize := int64(0)
+		for _, row := range event.Event.Rows {
+			eventSize += row.ApproximateDataSize
+		}
+		if i > first && size+eventSize > s.cfg.MaxTxnSizeBytes &&
+			event.Event.StartTs != s.events[i-1].Event.StartTs {
+			groups = append(groups, s.events[first:i])
+			first = i
+			size = 0
+		}
+		size += eventSize
+	}
+	return append(groups, s.events[first:])
+}
+
+func (s *mysqlBackend) prepareDMLsFor(events []*dmlsink.TxnCallbackableEvent) *preparedDMLs {
+	rows := 0
+	for _, event := range events {
+		rows += len(event.Event.Rows)
+	}
 	// TODO: use a sync.Pool to reduce allocations.
-	startTs := make([]uint64, 0, s.rows)
-	sqls := make([]string, 0, s.rows)
-	values := make([][]interface{}, 0, s.rows)
-	callbacks := make([]dmlsink.CallbackFunc, 0, len(s.events))
+	startTs := make([]uint64, 0, rows)
+	sqlStartTs := make([]uint64, 0, rows)
+	sqls := make([]string, 0, rows)
+	values := make([][]interface{}, 0, rows)
+	callbacks := make([]dmlsink.CallbackFunc, 0, len(events))
 
 	// translateToInsert control the update and insert behavior.
 	translateToInsert := !s.cfg.SafeMode
 
+	// The coalesced events are only used to build the DMLs, the original ones
+	// still decide translateToInsert, so that an event whose rows are all
+	// coalesced away can still turn the following inserts into REPLACE.
+	var coalescedEvents []*dmlsink.TxnCallbackableEvent
+	if s.cfg.CoalesceUpdates {
+		coalescedEvents = coalesceUpdates(events)
+	}
+
 	rowCount := 0
+	deleteRowCount, updateRowCount, replaceRowCount := 0, 0, 0
 	approximateSize := int64(0)
-	for _, event := range s.events {
+	for i, event := range events {
+		// The callback of an event without rows should also be called, since
+		// all its rows may be skipped before preparing, see handleZeroAutoIncrementKeys.
+		if event.Callback != nil {
+			callbacks = append(callbacks, event.Callback)
+		}
 		if len(event.Event.Rows) == 0 {
 			continue
 		}
-		rowCount += len(event.Event.Rows)
 
 		firstRow := event.Event.Rows[0]
-		if len(startTs) == 0 || startTs[len(startTs)-1] != firstRow.StartTs {
+		if s.cfg.StrictStartTsGrouping {
+			// record the startTs of every event group, so that the events of
+			// interleaved transactions can be told apart.
+			startTs = append(startTs, firstRow.StartTs)
+		} else if len(startTs) == 0 || startTs[len(startTs)-1] != firstRow.StartTs {
 			startTs = append(startTs, firstRow.StartTs)
 		}
 
@@ -540,15 +1199,43 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 			zap.Uint64("firstRowCommitTs", firstRow.CommitTs),
 			zap.Uint64("firstRowReplicatingTs", firstRow.ReplicatingTs),
 			zap.Bool("safeMode", s.cfg.SafeMode))
+		if translateToInsert {
+			s.metricTxnInsertTranslated.Inc()
+		} else {
+			s.metricTxnReplace.Inc()
+		}
+		if coalescedEvents != nil {
+			event = coalescedEvents[i]
+			if len(event.Event.Rows) == 0 {
+				continue
+			}
+		}
+		rowCount += len(event.Event.Rows)
+		for _, row := range event.Event.Rows {
+			if row.IsDelete() {
+				deleteRowCount++
+			} else if row.IsUpdate() {
+				updateRowCount++
+			} else if row.IsInsert() && !translateToInsert {
+				replaceRowCount++
+			}
+		}
 
-		if event.Callback != nil {
-			callbacks = append(callbacks, event.Callback)
+		// firstSQL is the index of the first statement of the transaction.
+		firstSQL := len(sqls)
+		finishTxn := func() {
+			for i := firstSQL; i < len(sqls); i++ {
+				sqlStartTs = append(sqlStartTs, firstRow.StartTs)
+			}
+			if s.cfg.AnnotateCommitTs && len(sqls) > firstSQL {
+				comment := fmt.Sprintf("%s%d */ ", commitTsCommentPrefix, firstRow.CommitTs)
+				sqls[firstSQL] = comment + sqls[firstSQL]
+				approximateSize += int64(len(comment))
+			}
 		}
 
-		// TODO: find a better threshold
-		enableBatchModeThreshold := 1
 		// Determine whether to use batch dml feature here.
-		if s.cfg.BatchDMLEnable && len(event.Event.Rows) > enableBatchModeThreshold {
+		if s.cfg.BatchDMLEnable && len(event.Event.Rows) >= s.cfg.BatchDMLRowThreshold {
 			tableColumns := firstRow.Columns
 			if firstRow.IsDelete() {
 				tableColumns = firstRow.PreColumns
@@ -557,22 +1244,27 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 			if hasHandleKey(tableColumns) {
 				// TODO(dongmen): find a better way to get table info.
 				tableInfo := model.BuildTiDBTableInfo(tableColumns, firstRow.IndexColumns)
-				sql, value := s.batchSingleTxnDmls(event, tableInfo, translateToInsert)
-				sqls = append(sqls, sql...)
-				values = append(values, value...)
+				sql, value, ok := s.batchSingleTxnDmls(event, tableInfo, translateToInsert)
+				if ok {
+					sqls = append(sqls, sql...)
+					values = append(values, value...)
 
-				for _, stmt := range sql {
-					approximateSize += int64(len(stmt))
-				}
-				for _, row := range event.Event.Rows {
-					approximateSize += row.ApproximateDataSize
+					for _, stmt := range sql {
+						approximateSize += int64(len(stmt))
+					}
+					for _, row := range event.Event.Rows {
+						approximateSize += row.ApproximateDataSize
+					}
+					finishTxn()
+					continue
 				}
-				continue
 			}
 		}
 
-		quoteTable := firstRow.Table.QuoteString()
+		targetTable := s.targetTable(firstRow.Table)
+		quoteTable := s.cfg.QuoteStyle.QuoteSchema(targetTable.Schema, targetTable.Table)
 		for _, row := range event.Event.Rows {
+			row = s.transformRow(row)
 			var query string
 			var args []interface{}
 			// Update Event
@@ -581,10 +1273,12 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 					quoteTable,
 					row.PreColumns,
 					row.Columns,
-					s.cfg.ForceReplicate)
+					s.cfg.ForceReplicate,
+					s.cfg.QuoteStyle)
 				if query != "" {
 					sqls = append(sqls, query)
 					values = append(values, args)
+					s.observeDMLStatements(dmlOperationUpdate, 1, 1)
 				}
 				approximateSize += int64(len(query)) + row.ApproximateDataSize
 				continue
@@ -592,10 +1286,11 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 
 			// Delete Event
 			if len(row.PreColumns) != 0 {
-				query, args = prepareDelete(quoteTable, row.PreColumns, s.cfg.ForceReplicate)
+				query, args = prepareDelete(quoteTable, row.PreColumns, s.cfg.ForceReplicate, s.cfg.QuoteStyle)
 				if query != "" {
 					sqls = append(sqls, query)
 					values = append(values, args)
+					s.observeDMLStatements(dmlOperationDelete, 1, 1)
 				}
 			}
 
@@ -604,27 +1299,41 @@ func (s *mysqlBackend) prepareDMLs() *preparedDMLs {
 			// INSERT(not in safe mode)
 			// or REPLACE(in safe mode) SQL.
 			if len(row.Columns) != 0 {
-				query, args = prepareReplace(quoteTable, row.Columns, true /* appendPlaceHolder */, translateToInsert)
+				query, args = prepareReplace(
+					quoteTable, row.Columns, true /* appendPlaceHolder */, translateToInsert, s.cfg.QuoteStyle)
 				if query != "" {
 					sqls = append(sqls, query)
 					values = append(values, args)
+					if translateToInsert {
+						s.observeDMLStatements(dmlOperationInsert, 1, 1)
+					} else {
+						s.observeDMLStatements(dmlOperationReplace, 1, 1)
+					}
 				}
 			}
 
 			approximateSize += int64(len(query)) + row.ApproximateDataSize
 		}
+		finishTxn()
 	}
 
 	if len(callbacks) == 0 {
 		callbacks = nil
 	}
+	if s.cfg.StrictStartTsGrouping {
+		s.checkStartTsGrouping(startTs)
+	}
 
 	return &preparedDMLs{
 		startTs:         startTs,
+		sqlStartTs:      sqlStartTs,
 		sqls:            sqls,
 		values:          values,
 		callbacks:       callbacks,
 		rowCount:        rowCount,
+		deleteRowCount:  deleteRowCount,
+		updateRowCount:  updateRowCount,
+		replaceRowCount: replaceRowCount,
 		approximateSize: approximateSize,
 	}
 }
@@ -646,9 +1355,9 @@ func (s *mysqlBackend) multiStmtExecute(
 	start := time.Now()
 	_, execError := tx.ExecContext(ctx, multiStmtSQL, multiStmtArgs...)
 	if execError != nil {
-		err := logDMLTxnErr(
+		err := s.logDMLTxnErr(
 			wrapMysqlTxnError(execError),
-			start, s.changefeed, multiStmtSQL, dmls.rowCount, dmls.startTs)
+			start, multiStmtSQL, dmls.rowCount, dmls.startTs)
 		if rbErr := tx.Rollback(); rbErr != nil {
 			if errors.Cause(rbErr) != context.Canceled {
 				log.Warn("failed to rollback txn", zap.String("changefeed", s.changefeed), zap.Error(rbErr))
@@ -659,11 +1368,13 @@ func (s *mysqlBackend) multiStmtExecute(
 	return nil
 }
 
-// execute SQLs in each preparedDMLs one by one in the same transaction.
+// execute SQLs in each preparedDMLs one by one in the same transaction,
+// it returns the total rows affected by the SQLs.
 func (s *mysqlBackend) sequenceExecute(
 	ctx context.Context, dmls *preparedDMLs, tx *sql.Tx, writeTimeout time.Duration,
-) error {
+) (int64, error) {
 	start := time.Now()
+	rowsAffected := int64(0)
 	for i, query := range dmls.sqls {
 		args := dmls.values[i]
 		log.Debug("exec row", zap.String("changefeed", s.changefeed), zap.Int("workerID", s.workerID),
@@ -671,7 +1382,8 @@ func (s *mysqlBackend) sequenceExecute(
 		ctx, cancelFunc := context.WithTimeout(ctx, writeTimeout)
 
 		var prepStmt *sql.Stmt
-		if s.cachePrepStmts {
+		// Statements annotated with the commit ts are unique, so don't cache them.
+		if s.cachePrepStmts && !strings.HasPrefix(query, commitTsCommentPrefix) {
 			if stmt, ok := s.stmtCache.Get(query); ok {
 				prepStmt = stmt.(*sql.Stmt)
 			} else if stmt, err := s.db.Prepare(query); err == nil {
@@ -685,28 +1397,85 @@ func (s *mysqlBackend) sequenceExecute(
 			}
 		}
 
-		var execError error
+		var (
+			res       sql.Result
+			execError error
+		)
 		if prepStmt == nil {
-			_, execError = tx.ExecContext(ctx, query, args...)
+			res, execError = tx.ExecContext(ctx, query, args...)
 		} else {
 			//nolint:sqlclosecheck
-			_, execError = tx.Stmt(prepStmt).ExecContext(ctx, args...)
+			res, execError = tx.Stmt(prepStmt).ExecContext(ctx, args...)
 		}
 		if execError != nil {
-			err := logDMLTxnErr(
+			// The cached statements may be invalid after the connection is
+			// broken, so clear the cache to prepare them again.
+			if prepStmt != nil && isBadConnErr(execError) {
+				s.stmtCache.Purge()
+			}
+			// Only log the startTs of the failed statement.
+			startTs := dmls.startTs
+			if i < len(dmls.sqlStartTs) {
+				startTs = dmls.sqlStartTs[i : i+1]
+			}
+			err := s.logDMLTxnErr(
 				wrapMysqlTxnError(execError),
-				start, s.changefeed, query, dmls.rowCount, dmls.startTs)
+				start, query, dmls.rowCount, startTs,
+				zap.Int("statementIndex", i),
+				zap.Int("statementCount", len(dmls.sqls)))
 			if rbErr := tx.Rollback(); rbErr != nil {
 				if errors.Cause(rbErr) != context.Canceled {
 					log.Warn("failed to rollback txn", zap.String("changefeed", s.changefeed), zap.Error(rbErr))
 				}
 			}
 			cancelFunc()
-			return err
+			return 0, err
 		}
 		cancelFunc()
+		if s.cfg.VerifyRowsAffected {
+			// The driver never fails to report the rows affected.
+			affected, _ := res.RowsAffected()
+			rowsAffected += affected
+		}
 	}
-	return nil
+	return rowsAffected, nil
+}
+
+// verifyRowsAffected checks the rows affected by a committed flush.
+// Deleting rows absent in the downstream affects nothing, and MySQL reports the
+// changed rows rather than the matched rows of an UPDATE, so a shortfall no
+// more than the deleted and updated rows is tolerated. A REPLACE affects 2 rows if it
+// overwrites an existing row, so the excess of replaced rows is expected.
+// It returns false and logs a warning on an unexpected mismatch.
+func (s *mysqlBackend) verifyRowsAffected(dmls *preparedDMLs, rowsAffected int64) bool {
+	expected := int64(dmls.rowCount)
+	switch {
+	case rowsAffected >= expected && rowsAffected <= expected+int64(dmls.replaceRowCount):
+		return true
+	case rowsAffected < expected &&
+		expected-rowsAffected <= int64(dmls.deleteRowCount+dmls.updateRowCount):
+		s.metricTxnRowsAffectedTolerated.Inc()
+		log.Debug("rows affected is less than expected, "+
+			"which may be caused by deleting absent rows or updating rows without changes",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Int64("rowsAffected", rowsAffected),
+			zap.Int64("expected", expected),
+			zap.Int("deleteRowCount", dmls.deleteRowCount),
+			zap.Int("updateRowCount", dmls.updateRowCount))
+		return true
+	}
+	s.metricTxnRowsAffectedMismatch.Inc()
+	log.Warn("rows affected mismatches the flushed rows",
+		zap.String("changefeed", s.changefeed),
+		zap.Int("workerID", s.workerID),
+		zap.Int64("rowsAffected", rowsAffected),
+		zap.Int64("expected", expected),
+		zap.Int("deleteRowCount", dmls.deleteRowCount),
+		zap.Int("updateRowCount", dmls.updateRowCount),
+		zap.Int("replaceRowCount", dmls.replaceRowCount),
+		zap.Uint64s("startTs", dmls.startTs))
+	return false
 }
 
 func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *preparedDMLs) error {
@@ -717,6 +1486,10 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 			zap.Any("values", dmls.values))
 	}
 
+	if s.cfg.DryRun {
+		return s.dryRunDMLs(dmls)
+	}
+
 	start := time.Now()
 	// approximateSize is multiplied by 2 because in extreme circustumas, every
 	// byte in dmls can be escaped and adds one byte.
@@ -727,25 +1500,25 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 
 		failpoint.Inject("MySQLSinkTxnRandomError", func() {
 			log.Warn("inject MySQLSinkTxnRandomError")
-			err := logDMLTxnErr(errors.Trace(driver.ErrBadConn), start, s.changefeed, "failpoint", 0, nil)
+			err := s.logDMLTxnErr(errors.Trace(driver.ErrBadConn), start, "failpoint", 0, nil)
 			failpoint.Return(err)
 		})
 		failpoint.Inject("MySQLSinkHangLongTime", func() { _ = util.Hang(pctx, time.Hour) })
 		failpoint.Inject("MySQLDuplicateEntryError", func() {
 			log.Warn("inject MySQLDuplicateEntryError")
-			err := logDMLTxnErr(cerror.WrapError(cerror.ErrMySQLDuplicateEntry, &dmysql.MySQLError{
+			err := s.logDMLTxnErr(wrapMysqlTxnError(&dmysql.MySQLError{
 				Number:  uint16(mysql.ErrDupEntry),
 				Message: "Duplicate entry",
-			}), start, s.changefeed, "failpoint", 0, nil)
+			}), start, "failpoint", 0, nil)
 			failpoint.Return(err)
 		})
 
 		err := s.statistics.RecordBatchExecution(func() (int, int64, error) {
 			tx, err := s.db.BeginTx(pctx, nil)
 			if err != nil {
-				return 0, 0, logDMLTxnErr(
+				return 0, 0, s.logDMLTxnErr(
 					wrapMysqlTxnError(err),
-					start, s.changefeed, "BEGIN", dmls.rowCount, dmls.startTs)
+					start, "BEGIN", dmls.rowCount, dmls.startTs)
 			}
 
 			// If interplated SQL size exceeds maxAllowedPacket, mysql driver will
@@ -753,6 +1526,12 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 			// error can be ErrPrepareMulti, ErrBadConn etc.
 			// TODO: add a quick path to check whether we should fallback to
 			// the sequence way.
+			// The driver doesn't report the rows affected by every statement
+			// executed in the multi statements way, so only verify them in the
+			// sequence way, which is always used if VerifyRowsAffected is set
+			// by the sink URI.
+			verifyRowsAffected := false
+			var rowsAffected int64
 			if s.cfg.MultiStmtEnable && !fallbackToSeqWay {
 				err = s.multiStmtExecute(pctx, dmls, tx, writeTimeout)
 				if err != nil {
@@ -760,18 +1539,19 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 					return 0, 0, err
 				}
 			} else {
-				err = s.sequenceExecute(pctx, dmls, tx, writeTimeout)
+				rowsAffected, err = s.sequenceExecute(pctx, dmls, tx, writeTimeout)
 				if err != nil {
 					return 0, 0, err
 				}
+				verifyRowsAffected = s.cfg.VerifyRowsAffected
 			}
 
 			// we try to set write source for each txn,
 			// so we can use it to trace the data source
 			if err = s.setWriteSource(pctx, tx); err != nil {
-				err := logDMLTxnErr(
+				err := s.logDMLTxnErr(
 					cerror.WrapError(cerror.ErrMySQLTxnError, err),
-					start, s.changefeed,
+					start,
 					fmt.Sprintf("SET SESSION %s = %d", "tidb_cdc_write_source",
 						s.cfg.SourceID),
 					dmls.rowCount, dmls.startTs)
@@ -784,9 +1564,12 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 			}
 
 			if err = tx.Commit(); err != nil {
-				return 0, 0, logDMLTxnErr(
+				return 0, 0, s.logDMLTxnErr(
 					wrapMysqlTxnError(err),
-					start, s.changefeed, "COMMIT", dmls.rowCount, dmls.startTs)
+					start, "COMMIT", dmls.rowCount, dmls.startTs)
+			}
+			if verifyRowsAffected {
+				s.verifyRowsAffected(dmls, rowsAffected)
 			}
 			return dmls.rowCount, dmls.approximateSize, nil
 		})
@@ -800,8 +1583,54 @@ func (s *mysqlBackend) execDMLWithMaxRetries(pctx context.Context, dmls *prepare
 		return nil
 	}, retry.WithBackoffBaseDelay(pmysql.BackoffBaseDelay.Milliseconds()),
 		retry.WithBackoffMaxDelay(pmysql.BackoffMaxDelay.Milliseconds()),
+		retry.WithJitter(s.cfg.DMLRetryJitter),
 		retry.WithMaxTries(s.dmlMaxRetry),
-		retry.WithIsRetryableErr(isRetryableDMLError))
+		retry.WithIsRetryableErr(s.isRetryableDMLError))
+}
+
+// checkStartTsGrouping checks whether the events of the same transaction are
+// grouped together, i.e. a startTs never shows up again after another one.
+// It returns false and logs a warning if the transactions are interleaved.
+func (s *mysqlBackend) checkStartTsGrouping(startTs []model.Ts) bool {
+	seen := make(map[model.Ts]struct{}, len(startTs))
+	for i, ts := range startTs {
+		if i > 0 && startTs[i-1] == ts {
+			continue
+		}
+		if _, ok := seen[ts]; ok {
+			s.metricTxnInterleavedStartTs.Inc()
+			log.Warn("events of different transactions are interleaved in one flush",
+				zap.String("changefeed", s.changefeed),
+				zap.Int("workerID", s.workerID),
+				zap.Uint64("startTs", ts),
+				zap.Uint64s("startTsList", startTs))
+			return false
+		}
+		seen[ts] = struct{}{}
+	}
+	return true
+}
+
+// dryRunDMLs logs the DMLs and records the statistics as if they are
+// executed successfully, without touching the downstream.
+func (s *mysqlBackend) dryRunDMLs(dmls *preparedDMLs) error {
+	return s.statistics.RecordBatchExecution(func() (int, int64, error) {
+		log.Info("dry run DMLs",
+			zap.String("changefeed", s.changefeed),
+			zap.Int("workerID", s.workerID),
+			zap.Int("numOfRows", dmls.rowCount),
+			zap.Uint64s("startTs", dmls.startTs),
+			zap.Strings("sqls", dmls.sqls))
+		if s.dryRunHook != nil {
+			s.dryRunHook(dmls.sqls, dmls.values)
+		}
+		return dmls.rowCount, dmls.approximateSize, nil
+	})
+}
+
+func isBadConnErr(err error) bool {
+	cause := errors.Cause(err)
+	return cause == driver.ErrBadConn || cause == dmysql.ErrInvalidConn
 }
 
 func wrapMysqlTxnError(err error) error {
@@ -811,33 +1640,72 @@ func wrapMysqlTxnError(err error) error {
 	}
 	switch errCode {
 	case mysql.ErrDupEntry:
-		return cerror.WrapError(cerror.ErrMySQLDuplicateEntry, err)
+		// The original message with the conflicting entry is kept in the error.
+		key, _, _ := getDupEntryInfo(err)
+		return cerror.WrapError(cerror.ErrMySQLDuplicateEntry, err, key)
 	}
 	return cerror.WrapError(cerror.ErrMySQLTxnError, err)
 }
 
-func logDMLTxnErr(
-	err error, start time.Time, changefeed string,
+// getDupEntryInfo extracts the conflicting key and entry from a MySQL
+// duplicate entry error. ok is false if err is not a duplicate entry error
+// or its message can't be parsed.
+func getDupEntryInfo(err error) (key, entry string, ok bool) {
+	mysqlErr, isMySQLErr := errors.Cause(err).(*dmysql.MySQLError)
+	if !isMySQLErr || mysqlErr.Number != mysql.ErrDupEntry {
+		return "", "", false
+	}
+	matches := dupEntryMsgRegexp.FindStringSubmatch(mysqlErr.Message)
+	if matches == nil {
+		return "", "", false
+	}
+	return matches[2], matches[1], true
+}
+
+func (s *mysqlBackend) logDMLTxnErr(
+	err error, start time.Time,
 	query string, count int, startTs []model.Ts,
+	extraFields ...zap.Field,
 ) error {
 	if len(query) > 1024 {
 		query = query[:1024]
 	}
-	if isRetryableDMLError(err) {
-		log.Warn("execute DMLs with error, retry later",
+	if s.isRetryableDMLError(err) {
+		fields := []zap.Field{
 			zap.Error(err), zap.Duration("duration", time.Since(start)),
 			zap.String("query", query), zap.Int("count", count),
 			zap.Uint64s("startTs", startTs),
-			zap.String("changefeed", changefeed))
+			zap.String("changefeed", s.changefeed),
+		}
+		log.Warn("execute DMLs with error, retry later", append(fields, extraFields...)...)
 	} else {
-		log.Error("execute DMLs with error, can not retry",
+		fields := []zap.Field{
 			zap.Error(err), zap.Duration("duration", time.Since(start)),
 			zap.String("query", query), zap.Int("count", count),
-			zap.String("changefeed", changefeed))
+			// The query may contain multiple statements, so log all startTs
+			// to help locating the source transaction.
+			zap.Uint64s("startTs", startTs),
+			zap.String("changefeed", s.changefeed),
+		}
+		if key, entry, ok := getDupEntryInfo(err); ok {
+			fields = append(fields, zap.String("duplicateKey", key), zap.String("duplicateEntry", entry))
+		}
+		log.Error("execute DMLs with error, can not retry", append(fields, extraFields...)...)
 	}
 	return errors.WithMessage(err, fmt.Sprintf("Failed query info: %s; ", query))
 }
 
+// isRetryableDMLError checks whether the error is retryable, the retry
+// classification overrides in the config take precedence over the defaults.
+func (s *mysqlBackend) isRetryableDMLError(err error) bool {
+	if errCode, ok := getSQLErrCode(err); ok && cerror.IsRetryableError(err) {
+		if retryable, ok := s.cfg.DMLRetryOverrides[uint16(errCode)]; ok {
+			return retryable
+		}
+	}
+	return isRetryableDMLError(err)
+}
+
 func isRetryableDMLError(err error) bool {
 	if !cerror.IsRetryableError(err) {
 		return false
@@ -872,8 +1740,9 @@ func (s *mysqlBackend) setDMLMaxRetry(maxRetry uint64) {
 
 // setWriteSource sets write source for the transaction.
 func (s *mysqlBackend) setWriteSource(ctx context.Context, txn *sql.Tx) error {
-	// we only set write source when donwstream is TiDB and write source is existed.
-	if !s.cfg.IsWriteSourceExisted {
+	// we only set write source when donwstream is TiDB and write source is existed,
+	// and it's not disabled by the user.
+	if !s.cfg.EnableWriteSource || !s.cfg.IsWriteSourceExisted {
 		return nil
 	}
 	// downstream is TiDB, set system variables.
@@ -20,12 +20,15 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	dmysql "github.com/go-sql-driver/mysql"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/pkg/ddl"
@@ -33,14 +36,21 @@ import (
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/charset"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
+	parser_types "github.com/pingcap/tidb/pkg/parser/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/metrics"
+	"github.com/pingcap/tiflow/cdc/sink/metrics/txn"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink"
 	pmysql "github.com/pingcap/tiflow/pkg/sink/mysql"
 	"github.com/pingcap/tiflow/pkg/sqlmodel"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
@@ -60,6 +70,16 @@ func newMySQLBackendWithoutDB(ctx context.Context) *mysqlBackend {
 			model.DefaultChangeFeedID("test"),
 			sink.TxnSink),
 		cfg: cfg,
+
+		metricTxnInsertTranslated:      txn.InsertTranslatedEvents.WithLabelValues("default", "test"),
+		metricTxnReplace:               txn.ReplaceEvents.WithLabelValues("default", "test"),
+		metricTxnRowsAffectedMismatch:  txn.RowsAffectedMismatches.WithLabelValues("default", "test"),
+		metricTxnRowsAffectedTolerated: txn.RowsAffectedToleratedMismatches.WithLabelValues("default", "test"),
+		metricTxnSinkDMLBatchCommit:    txn.SinkDMLBatchCommit.WithLabelValues("default", "test"),
+		metricTxnSinkDMLBatchCallback:  txn.SinkDMLBatchCallback.WithLabelValues("default", "test"),
+		metricTxnSinkDMLBatchSize:      txn.SinkDMLBatchApproximateSize.WithLabelValues("default", "test"),
+		metricTxnDMLStatements:         txn.DMLStatements.MustCurryWith(prometheus.Labels{"namespace": "default", "changefeed": "test"}),
+		metricTxnDMLStatementRows:      txn.DMLStatementRows.MustCurryWith(prometheus.Labels{"namespace": "default", "changefeed": "test"}),
 	}
 }
 
@@ -69,6 +89,7 @@ func newMySQLBackend(
 	sinkURI *url.URL,
 	replicaConfig *config.ReplicaConfig,
 	dbConnFactory pmysql.Factory,
+	opts ...BackendOption,
 ) (*mysqlBackend, error) {
 	ctx1, cancel := context.WithCancel(ctx)
 	statistics := metrics.NewStatistics(ctx1, changefeedID, sink.TxnSink)
@@ -78,7 +99,7 @@ func newMySQLBackend(
 	sinkURI.RawQuery = raw.Encode()
 
 	backends, err := NewMySQLBackends(ctx, changefeedID,
-		sinkURI, replicaConfig, dbConnFactory, statistics)
+		sinkURI, replicaConfig, dbConnFactory, statistics, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -91,11 +112,6 @@ func newTestMockDB(t *testing.T) (db *sql.DB, mock sqlmock.Sqlmock) {
 		Number:  1305,
 		Message: "FUNCTION test.tidb_version does not exist",
 	})
-	// mock a different possible error for the second query
-	mock.ExpectQuery("select tidb_version()").WillReturnError(&dmysql.MySQLError{
-		Number:  1044,
-		Message: "Access denied for user 'cdc'@'%' to database 'information_schema'",
-	})
 	require.Nil(t, err)
 	return
 }
@@ -109,9 +125,10 @@ func TestPrepareDML(t *testing.T) {
 		{
 			input: []*model.RowChangedEvent{},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{},
-				sqls:    []string{},
-				values:  [][]interface{}{},
+				startTs:    []model.Ts{},
+				sqlStartTs: []model.Ts{},
+				sqls:       []string{},
+				values:     [][]interface{}{},
 			},
 		},
 		// delete event
@@ -141,9 +158,11 @@ func TestPrepareDML(t *testing.T) {
 			},
 			expected: &preparedDMLs{
 				startTs:         []model.Ts{418658114257813514},
+				sqlStartTs:      []model.Ts{418658114257813514},
 				sqls:            []string{"DELETE FROM `common_1`.`uk_without_pk` WHERE `a1` = ? AND `a3` = ? LIMIT 1"},
 				values:          [][]interface{}{{1, 1}},
 				rowCount:        1,
+				deleteRowCount:  1,
 				approximateSize: 74,
 			},
 		},
@@ -174,6 +193,7 @@ func TestPrepareDML(t *testing.T) {
 			},
 			expected: &preparedDMLs{
 				startTs:         []model.Ts{418658114257813516},
+				sqlStartTs:      []model.Ts{418658114257813516},
 				sqls:            []string{"INSERT INTO `common_1`.`uk_without_pk` (`a1`,`a3`) VALUES (?,?)"},
 				values:          [][]interface{}{{2, 2}},
 				rowCount:        1,
@@ -603,6 +623,128 @@ func TestExecDMLRollbackErrRetryable(t *testing.T) {
 	require.Nil(t, sink.Close())
 }
 
+func TestExecDMLWithConfiguredMaxRetry(t *testing.T) {
+	rows := []*model.RowChangedEvent{
+		{
+			Table: &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{
+				{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 1,
+				},
+			},
+		},
+	}
+
+	errLockDeadlock := &dmysql.MySQLError{
+		Number: mysql.ErrLockDeadlock,
+	}
+
+	maxRetry := 3
+	dbIndex := 0
+	var mock sqlmock.Sqlmock
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		var db *sql.DB
+		db, mock = newTestMockDB(t)
+		for i := 0; i < maxRetry; i++ {
+			mock.ExpectBegin()
+			mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?)").
+				WithArgs(1).
+				WillReturnError(errLockDeadlock)
+			mock.ExpectRollback()
+		}
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeed := "test-changefeed"
+	sinkURI, err := url.Parse(fmt.Sprintf(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false"+
+			"&dml-max-retry=%d", maxRetry))
+	require.Nil(t, err)
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID(changefeed), sinkURI,
+		config.GetDefaultReplicaConfig(), mockGetDBConn)
+	require.Nil(t, err)
+	require.Equal(t, uint64(maxRetry), sink.dmlMaxRetry)
+
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{Rows: rows},
+	})
+	err = sink.Flush(context.Background())
+	require.Equal(t, errLockDeadlock, errors.Cause(err))
+
+	require.Nil(t, sink.Close())
+	// The DMLs are tried exactly maxRetry times.
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsRetryableDMLErrorWithOverrides(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	dupEntry := wrapMysqlTxnError(&dmysql.MySQLError{Number: mysql.ErrDupEntry})
+	deadlock := wrapMysqlTxnError(&dmysql.MySQLError{Number: mysql.ErrLockDeadlock})
+	require.False(t, ms.isRetryableDMLError(dupEntry))
+	require.True(t, ms.isRetryableDMLError(deadlock))
+
+	ms.cfg.DMLRetryOverrides = map[uint16]bool{
+		mysql.ErrDupEntry:     true,
+		mysql.ErrLockDeadlock: false,
+	}
+	require.True(t, ms.isRetryableDMLError(dupEntry))
+	require.False(t, ms.isRetryableDMLError(deadlock))
+	// Codes not overridden keep the built-in classification.
+	require.False(t, ms.isRetryableDMLError(
+		wrapMysqlTxnError(&dmysql.MySQLError{Number: mysql.ErrNoSuchTable})))
+	// Non-retryable errors can't be overridden to be retryable.
+	require.False(t, ms.isRetryableDMLError(context.Canceled))
+}
+
+func TestWrapMysqlTxnErrorWithDupEntry(t *testing.T) {
+	t.Parallel()
+
+	err := wrapMysqlTxnError(&dmysql.MySQLError{
+		Number:  mysql.ErrDupEntry,
+		Message: "Duplicate entry '1-a' for key 't.PRIMARY'",
+	})
+	require.True(t, cerror.IsDupEntryError(err))
+	require.Contains(t, err.Error(), "MySQL duplicate entry error, key: t.PRIMARY")
+	require.Contains(t, err.Error(), "Duplicate entry '1-a' for key 't.PRIMARY'")
+	key, entry, ok := getDupEntryInfo(err)
+	require.True(t, ok)
+	require.Equal(t, "t.PRIMARY", key)
+	require.Equal(t, "1-a", entry)
+	errCode, ok := getSQLErrCode(err)
+	require.True(t, ok)
+	require.Equal(t, errors.ErrCode(mysql.ErrDupEntry), errCode)
+
+	// The message can't be parsed.
+	err = wrapMysqlTxnError(&dmysql.MySQLError{Number: mysql.ErrDupEntry, Message: "Duplicate entry"})
+	require.True(t, cerror.IsDupEntryError(err))
+	_, _, ok = getDupEntryInfo(err)
+	require.False(t, ok)
+
+	// Not a duplicate entry error.
+	_, _, ok = getDupEntryInfo(wrapMysqlTxnError(&dmysql.MySQLError{Number: mysql.ErrLockDeadlock}))
+	require.False(t, ok)
+}
+
 func TestMysqlSinkNotRetryErrDupEntry(t *testing.T) {
 	errDup := mysql.NewErr(mysql.ErrDupEntry)
 	rows := []*model.RowChangedEvent{
@@ -707,6 +849,195 @@ func TestNewMySQLBackend(t *testing.T) {
 	require.Nil(t, sink.Close())
 }
 
+func TestNewMySQLBackendWithDSNTransform(t *testing.T) {
+	var dsns []string
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+		dsns = append(dsns, dsnStr)
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1" +
+		"&cache-prep-stmts=false")
+	require.Nil(t, err)
+	transform := func(dsnStr string) (string, error) {
+		dsn, err := dmysql.ParseDSN(dsnStr)
+		if err != nil {
+			return "", err
+		}
+		dsn.User = "secret-user"
+		dsn.Params["custom_param"] = "1"
+		return dsn.FormatDSN(), nil
+	}
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+		config.GetDefaultReplicaConfig(), mockGetDBConn, WithDSNTransform(transform))
+	require.Nil(t, err)
+	require.Len(t, dsns, 2)
+	for _, dsnStr := range dsns {
+		dsn, err := dmysql.ParseDSN(dsnStr)
+		require.Nil(t, err)
+		require.Equal(t, "secret-user", dsn.User)
+		require.Equal(t, "1", dsn.Params["custom_param"])
+	}
+	require.Nil(t, sink.Close())
+
+	// The error of the transform is returned.
+	dbIndex = 0
+	_, err = newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+		config.GetDefaultReplicaConfig(), mockGetDBConn,
+		WithDSNTransform(func(string) (string, error) { return "", errors.New("no secret") }))
+	require.ErrorContains(t, err, "no secret")
+}
+
+func TestNewMySQLBackendWithCapabilityCache(t *testing.T) {
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex%2 == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db, only the first one is probed.
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.Nil(t, err)
+		if dbIndex == 1 {
+			mock.ExpectQuery("select tidb_version()").
+				WillReturnRows(sqlmock.NewRows([]string{"tidb_version()"}).AddRow("5.7.25-TiDB-v7.5.0"))
+			mock.ExpectExec("SET SESSION tidb_cdc_write_source = 1").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := pmysql.NewCapabilityCache(time.Minute)
+	for i := 0; i < 2; i++ {
+		sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1" +
+			"&cache-prep-stmts=false")
+		require.Nil(t, err)
+		sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID(fmt.Sprintf("test-%d", i)), sinkURI,
+			config.GetDefaultReplicaConfig(), mockGetDBConn, WithCapabilityCache(cache))
+		require.Nil(t, err)
+		require.True(t, sink.cfg.IsTiDB)
+		require.True(t, sink.cfg.IsWriteSourceExisted)
+		require.Nil(t, sink.Close())
+	}
+	require.Equal(t, 4, dbIndex)
+}
+
+func TestNewMySQLBackendConnPoolLimits(t *testing.T) {
+	var db *sql.DB
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex%2 == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		var mock sqlmock.Sqlmock
+		db, mock = newTestMockDB(t)
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, tc := range []struct {
+		query   string
+		maxOpen int
+	}{
+		{query: "worker-count=4&cache-prep-stmts=false", maxOpen: 5},
+		{query: "worker-count=4&cache-prep-stmts=false&max-open-conns=2&max-idle-conns=1", maxOpen: 2},
+		// The prepared statement cache is disabled without querying the
+		// downstream, since there is no extra connection to prepare statements.
+		{query: "worker-count=4&max-open-conns=4", maxOpen: 4},
+	} {
+		sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&" + tc.query)
+		require.Nil(t, err)
+		sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+			config.GetDefaultReplicaConfig(), mockGetDBConn)
+		require.Nil(t, err)
+		require.Equal(t, tc.maxOpen, db.Stats().MaxOpenConnections, tc.query)
+		require.False(t, sink.cachePrepStmts, tc.query)
+		require.Nil(t, sink.Close())
+	}
+}
+
+func TestNewMySQLBackendSessionVariables(t *testing.T) {
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		mock.ExpectQuery("select @@global.max_allowed_packet;").
+			WillReturnRows(sqlmock.NewRows([]string{"@@global.max_allowed_packet"}).AddRow(67108864))
+		mock.ExpectQuery("SELECT @@SESSION.sql_mode, @@SESSION.character_set_client, " +
+			"@@SESSION.collation_connection;").
+			WillReturnRows(sqlmock.NewRows([]string{
+				"@@SESSION.sql_mode", "@@SESSION.character_set_client", "@@SESSION.collation_connection",
+			}).AddRow("NO_ENGINE_SUBSTITUTION", "utf8mb4", "utf8mb4_bin"))
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sinkURI, err := url.Parse("mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=2" +
+		"&cache-prep-stmts=false")
+	require.Nil(t, err)
+	backends, err := NewMySQLBackends(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+		config.GetDefaultReplicaConfig(), mockGetDBConn, metrics.NewStatistics(ctx,
+			model.DefaultChangeFeedID("test-changefeed"), sink.TxnSink))
+	require.Nil(t, err)
+	require.Len(t, backends, 2)
+	for _, backend := range backends {
+		require.Equal(t, int64(67108864), backend.maxAllowedPacket)
+		require.Equal(t, pmysql.SessionVariables{
+			SQLMode:             "NO_ENGINE_SUBSTITUTION",
+			CharacterSetClient:  "utf8mb4",
+			CollationConnection: "utf8mb4_bin",
+		}, backend.sessionVars)
+	}
+	require.Nil(t, backends[0].Close())
+}
+
 func TestNewMySQLBackendWithIPv6Address(t *testing.T) {
 	dbIndex := 0
 	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
@@ -891,9 +1222,10 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 			name:  "empty",
 			input: []*model.RowChangedEvent{},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{},
-				sqls:    []string{},
-				values:  [][]interface{}{},
+				startTs:    []model.Ts{},
+				sqlStartTs: []model.Ts{},
+				sqls:       []string{},
+				values:     [][]interface{}{},
 			},
 		}, {
 			name: "insert without PK",
@@ -917,7 +1249,8 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{418658114257813514},
+				startTs:    []model.Ts{418658114257813514},
+				sqlStartTs: []model.Ts{418658114257813514},
 				sqls: []string{
 					"INSERT INTO `common_1`.`uk_without_pk` (`a1`,`a3`) VALUES (?,?)",
 				},
@@ -948,6 +1281,7 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 			},
 			expected: &preparedDMLs{
 				startTs:         []model.Ts{418658114257813514},
+				sqlStartTs:      []model.Ts{418658114257813514},
 				sqls:            []string{"INSERT INTO `common_1`.`pk` (`a1`,`a3`) VALUES (?,?)"},
 				values:          [][]interface{}{{1, 1}},
 				rowCount:        1,
@@ -986,13 +1320,15 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{418658114257813516},
+				startTs:    []model.Ts{418658114257813516},
+				sqlStartTs: []model.Ts{418658114257813516},
 				sqls: []string{
 					"UPDATE `common_1`.`uk_without_pk` SET `a1` = ?, `a3` = ? " +
 						"WHERE `a1` = ? AND `a3` = ? LIMIT 1",
 				},
 				values:          [][]interface{}{{3, 3, 2, 2}},
 				rowCount:        1,
+				updateRowCount:  1,
 				approximateSize: 92,
 			},
 		}, {
@@ -1028,11 +1364,13 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{418658114257813516},
+				startTs:    []model.Ts{418658114257813516},
+				sqlStartTs: []model.Ts{418658114257813516},
 				sqls: []string{"UPDATE `common_1`.`pk` SET `a1` = ?, `a3` = ? " +
 					"WHERE `a1` = ? AND `a3` = ? LIMIT 1"},
 				values:          [][]interface{}{{3, 3, 2, 2}},
 				rowCount:        1,
+				updateRowCount:  1,
 				approximateSize: 81,
 			},
 		}, {
@@ -1074,7 +1412,8 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{418658114257813516},
+				startTs:    []model.Ts{418658114257813516},
+				sqlStartTs: []model.Ts{418658114257813516, 418658114257813516},
 				sqls: []string{
 					"INSERT INTO `common_1`.`pk` (`a1`,`a3`) VALUES (?,?)",
 					"INSERT INTO `common_1`.`pk` (`a1`,`a3`) VALUES (?,?)",
@@ -1105,12 +1444,14 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{418658114257813516},
+				startTs:    []model.Ts{418658114257813516},
+				sqlStartTs: []model.Ts{418658114257813516},
 				sqls: []string{
 					"REPLACE INTO `common_1`.`pk` (`a1`,`a3`) VALUES (?,?)",
 				},
 				values:          [][]interface{}{{3, 3}},
 				rowCount:        1,
+				replaceRowCount: 1,
 				approximateSize: 53,
 			},
 		}, {
@@ -1152,13 +1493,15 @@ func TestMysqlSinkSafeModeOff(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{418658114257813516},
+				startTs:    []model.Ts{418658114257813516},
+				sqlStartTs: []model.Ts{418658114257813516, 418658114257813516},
 				sqls: []string{
 					"REPLACE INTO `common_1`.`pk` (`a1`,`a3`) VALUES (?,?)",
 					"REPLACE INTO `common_1`.`pk` (`a1`,`a3`) VALUES (?,?)",
 				},
 				values:          [][]interface{}{{3, 3}, {5, 5}},
 				rowCount:        2,
+				replaceRowCount: 2,
 				approximateSize: 106,
 			},
 		},
@@ -1190,9 +1533,10 @@ func TestPrepareBatchDMLs(t *testing.T) {
 			isTiDB: true,
 			input:  []*model.RowChangedEvent{},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{},
-				sqls:    []string{},
-				values:  [][]interface{}{},
+				startTs:    []model.Ts{},
+				sqlStartTs: []model.Ts{},
+				sqls:       []string{},
+				values:     [][]interface{}{},
 			},
 		},
 		{ // delete event
@@ -1241,9 +1585,11 @@ func TestPrepareBatchDMLs(t *testing.T) {
 			},
 			expected: &preparedDMLs{
 				startTs:         []model.Ts{418658114257813514},
+				sqlStartTs:      []model.Ts{418658114257813514},
 				sqls:            []string{"DELETE FROM `common_1`.`uk_without_pk` WHERE (`a1` = ? AND `a3` = ?) OR (`a1` = ? AND `a3` = ?)"},
 				values:          [][]interface{}{{1, "你好", 2, "世界"}},
 				rowCount:        2,
+				deleteRowCount:  2,
 				approximateSize: 115,
 			},
 		},
@@ -1292,6 +1638,7 @@ func TestPrepareBatchDMLs(t *testing.T) {
 			},
 			expected: &preparedDMLs{
 				startTs:         []model.Ts{418658114257813516},
+				sqlStartTs:      []model.Ts{418658114257813516},
 				sqls:            []string{"INSERT INTO `common_1`.`uk_without_pk` (`a1`,`a3`) VALUES (?,?),(?,?)"},
 				values:          [][]interface{}{{1, "你好", 2, "世界"}},
 				rowCount:        2,
@@ -1370,7 +1717,8 @@ func TestPrepareBatchDMLs(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{418658114257813516},
+				startTs:    []model.Ts{418658114257813516},
+				sqlStartTs: []model.Ts{418658114257813516},
 				sqls: []string{"UPDATE `common_1`.`uk_without_pk` " +
 					"SET `a1`=CASE WHEN `a1` = ? AND `a3` = ? THEN ? WHEN `a1` = ? AND `a3` = ? THEN ? END, " +
 					"`a3`=CASE WHEN `a1` = ? AND `a3` = ? THEN ? WHEN `a1` = ? AND `a3` = ? THEN ? END " +
@@ -1380,6 +1728,7 @@ func TestPrepareBatchDMLs(t *testing.T) {
 					"纽约", "北京", 1, "开发", 3, "纽约",
 				}},
 				rowCount:        2,
+				updateRowCount:  2,
 				approximateSize: 283,
 			},
 		},
@@ -1515,7 +1864,8 @@ func TestPrepareBatchDMLs(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{418658114257813514},
+				startTs:    []model.Ts{418658114257813514},
+				sqlStartTs: []model.Ts{418658114257813514, 418658114257813514, 418658114257813514},
 				sqls: []string{
 					"DELETE FROM `common_1`.`uk_without_pk` WHERE (`a1` = ? AND `a3` = ?) OR (`a1` = ? AND `a3` = ?)",
 					"UPDATE `common_1`.`uk_without_pk` " +
@@ -1533,6 +1883,8 @@ func TestPrepareBatchDMLs(t *testing.T) {
 					{2, "你好"},
 				},
 				rowCount:        5,
+				updateRowCount:  2,
+				deleteRowCount:  2,
 				approximateSize: 467,
 			},
 		},
@@ -1619,7 +1971,8 @@ func TestPrepareBatchDMLs(t *testing.T) {
 				},
 			},
 			expected: &preparedDMLs{
-				startTs: []model.Ts{418658114257813516},
+				startTs:    []model.Ts{418658114257813516},
+				sqlStartTs: []model.Ts{418658114257813516, 418658114257813516},
 				sqls: []string{
 					"UPDATE `common_1`.`uk_without_pk` SET `a1` = ?, " +
 						"`a3` = ? WHERE `a1` = ? AND `a3` = ? LIMIT 1",
@@ -1628,6 +1981,7 @@ func TestPrepareBatchDMLs(t *testing.T) {
 				},
 				values:          [][]interface{}{{2, "测试", 1, "开发"}, {4, "北京", 3, "纽约"}},
 				rowCount:        2,
+				updateRowCount:  2,
 				approximateSize: 204,
 			},
 		},
@@ -1650,6 +2004,114 @@ func TestPrepareBatchDMLs(t *testing.T) {
 	}
 }
 
+func TestPrepareBatchDMLsWithRowThreshold(t *testing.T) {
+	t.Parallel()
+	newRow := func(value int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			StartTs:  418658114257813514,
+			CommitTs: 418658114257813515,
+			Table:    &model.TableName{Schema: "common_1", Table: "uk_without_pk"},
+			PreColumns: []*model.Column{nil, {
+				Name:  "a1",
+				Type:  mysql.TypeLong,
+				Flag:  model.BinaryFlag | model.MultipleKeyFlag | model.HandleKeyFlag | model.UniqueKeyFlag,
+				Value: value,
+			}},
+			IndexColumns:        [][]int{{1}},
+			ApproximateDataSize: 10,
+		}
+	}
+	testCases := []struct {
+		threshold    int
+		expectedSQLs []string
+	}{
+		{
+			threshold: 2,
+			expectedSQLs: []string{
+				"DELETE FROM `common_1`.`uk_without_pk` WHERE (`a1` = ?) OR (`a1` = ?)",
+			},
+		},
+		{
+			threshold: 3,
+			expectedSQLs: []string{
+				"DELETE FROM `common_1`.`uk_without_pk` WHERE `a1` = ? LIMIT 1",
+				"DELETE FROM `common_1`.`uk_without_pk` WHERE `a1` = ? LIMIT 1",
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, tc := range testCases {
+		ms := newMySQLBackendWithoutDB(ctx)
+		ms.cfg.BatchDMLEnable = true
+		ms.cfg.BatchDMLRowThreshold = tc.threshold
+		rows := []*model.RowChangedEvent{newRow(1), newRow(2)}
+		ms.events = []*dmlsink.TxnCallbackableEvent{{
+			Event: &model.SingleTableTxn{Rows: rows},
+		}}
+		ms.rows = len(rows)
+		dmls := ms.prepareDMLs()
+		require.Equal(t, tc.expectedSQLs, dmls.sqls, "threshold %d", tc.threshold)
+		require.Equal(t, 2, dmls.rowCount)
+	}
+}
+
+func TestPrepareBatchDMLsWithInconsistentColumns(t *testing.T) {
+	t.Parallel()
+	newRow := func(value int, withExtraColumn bool) *model.RowChangedEvent {
+		row := &model.RowChangedEvent{
+			StartTs:  418658114257813514,
+			CommitTs: 418658114257813515,
+			Table:    &model.TableName{Schema: "common_1", Table: "pk"},
+			Columns: []*model.Column{{
+				Name:  "a1",
+				Type:  mysql.TypeLong,
+				Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+				Value: value,
+			}},
+			IndexColumns:        [][]int{{0}},
+			ApproximateDataSize: 10,
+		}
+		if withExtraColumn {
+			row.Columns = append(row.Columns, &model.Column{
+				Name:  "a2",
+				Type:  mysql.TypeLong,
+				Value: value,
+			})
+		}
+		return row
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.cfg.BatchDMLEnable = true
+	rows := []*model.RowChangedEvent{newRow(1, false), newRow(2, true)}
+	ms.events = []*dmlsink.TxnCallbackableEvent{{
+		Event: &model.SingleTableTxn{Rows: rows},
+	}}
+	ms.rows = len(rows)
+	dmls := ms.prepareDMLs()
+	require.Equal(t, []string{
+		"INSERT INTO `common_1`.`pk` (`a1`) VALUES (?)",
+		"INSERT INTO `common_1`.`pk` (`a1`,`a2`) VALUES (?,?)",
+	}, dmls.sqls)
+	require.Equal(t, [][]interface{}{{1}, {2, 2}}, dmls.values)
+	require.Equal(t, 2, dmls.rowCount)
+
+	// consistent rows still use batch dml
+	rows = []*model.RowChangedEvent{newRow(1, false), newRow(2, false)}
+	ms.events = []*dmlsink.TxnCallbackableEvent{{
+		Event: &model.SingleTableTxn{Rows: rows},
+	}}
+	ms.rows = len(rows)
+	dmls = ms.prepareDMLs()
+	require.Equal(t, []string{
+		"INSERT INTO `common_1`.`pk` (`a1`) VALUES (?),(?)",
+	}, dmls.sqls)
+}
+
 func TestGroupRowsByType(t *testing.T) {
 	ctx := context.Background()
 	ms := newMySQLBackendWithoutDB(ctx)
@@ -1941,3 +2403,1453 @@ func TestBackendGenUpdateSQL(t *testing.T) {
 		require.Equal(t, tc.expectedValues, values)
 	}
 }
+
+func TestPrepareDMLsDeleteBeforeReplaceAcrossTables(t *testing.T) {
+	t.Parallel()
+
+	newDelete := func(table string, id int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			StartTs:  418658114257813514,
+			CommitTs: 418658114257813515,
+			Table:    &model.TableName{Schema: "common_1", Table: table},
+			PreColumns: []*model.Column{{
+				Name:  "id",
+				Type:  mysql.TypeLong,
+				Flag:  model.BinaryFlag | model.PrimaryKeyFlag | model.HandleKeyFlag,
+				Value: id,
+			}, {
+				Name:  "v",
+				Type:  mysql.TypeLong,
+				Value: 1,
+			}},
+			IndexColumns: [][]int{{0}},
+		}
+	}
+	newInsert := func(table string, id int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			StartTs:  418658114257813514,
+			CommitTs: 418658114257813515,
+			Table:    &model.TableName{Schema: "common_1", Table: table},
+			Columns: []*model.Column{{
+				Name:  "id",
+				Type:  mysql.TypeLong,
+				Flag:  model.BinaryFlag | model.PrimaryKeyFlag | model.HandleKeyFlag,
+				Value: id,
+			}, {
+				Name:  "v",
+				Type:  mysql.TypeLong,
+				Value: 2,
+			}},
+			IndexColumns: [][]int{{0}},
+		}
+	}
+
+	tables := []string{"t1", "t2", "t3"}
+	// eventsPerTable puts the delete and the insert of the same primary key
+	// of one table in a single event.
+	eventsPerTable := make([][]*model.RowChangedEvent, 0, len(tables))
+	for _, table := range tables {
+		eventsPerTable = append(eventsPerTable, []*model.RowChangedEvent{
+			newDelete(table, 1), newDelete(table, 2),
+			newInsert(table, 1), newInsert(table, 2),
+		})
+	}
+	// interleavedEvents emits all deletes first, and then all inserts, with
+	// each row in its own event.
+	interleavedEvents := make([][]*model.RowChangedEvent, 0, 2*len(tables))
+	for _, table := range tables {
+		interleavedEvents = append(interleavedEvents,
+			[]*model.RowChangedEvent{newDelete(table, 1)})
+	}
+	for _, table := range tables {
+		interleavedEvents = append(interleavedEvents,
+			[]*model.RowChangedEvent{newInsert(table, 1)})
+	}
+
+	testCases := []struct {
+		name           string
+		batchDMLEnable bool
+		events         [][]*model.RowChangedEvent
+	}{
+		{name: "batch/per-table", batchDMLEnable: true, events: eventsPerTable},
+		{name: "batch/interleaved", batchDMLEnable: true, events: interleavedEvents},
+		{name: "no-batch/per-table", batchDMLEnable: false, events: eventsPerTable},
+		{name: "no-batch/interleaved", batchDMLEnable: false, events: interleavedEvents},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ms := newMySQLBackendWithoutDB(ctx)
+			ms.cfg.BatchDMLEnable = tc.batchDMLEnable
+			ms.cfg.SafeMode = true
+			ms.cfg.IsTiDB = true
+			ms.events = make([]*dmlsink.TxnCallbackableEvent, 0, len(tc.events))
+			for _, rows := range tc.events {
+				ms.events = append(ms.events, &dmlsink.TxnCallbackableEvent{
+					Event: &model.SingleTableTxn{Rows: rows},
+				})
+				ms.rows += len(rows)
+			}
+			dmls := ms.prepareDMLs()
+			require.Equal(t, []model.Ts{418658114257813514}, dmls.startTs)
+
+			for _, table := range tables {
+				quoteTable := fmt.Sprintf("`common_1`.`%s`", table)
+				deleteIdx, replaceIdx := -1, -1
+				for i, sql := range dmls.sqls {
+					if strings.HasPrefix(sql, "DELETE FROM "+quoteTable) && deleteIdx == -1 {
+						deleteIdx = i
+					}
+					if strings.HasPrefix(sql, "REPLACE INTO "+quoteTable) {
+						replaceIdx = i
+					}
+				}
+				require.NotEqual(t, -1, deleteIdx, dmls.sqls)
+				require.NotEqual(t, -1, replaceIdx, dmls.sqls)
+				require.Less(t, deleteIdx, replaceIdx, dmls.sqls)
+				for i := replaceIdx + 1; i < len(dmls.sqls); i++ {
+					require.False(t, strings.HasPrefix(dmls.sqls[i], "DELETE FROM "+quoteTable),
+						"delete of %s emitted after its replace: %v", table, dmls.sqls)
+				}
+			}
+		})
+	}
+}
+
+func TestPrepareDMLsWithColumnTransformers(t *testing.T) {
+	t.Parallel()
+
+	newRows := func() []*model.RowChangedEvent {
+		return []*model.RowChangedEvent{
+			{
+				StartTs:  418658114257813514,
+				CommitTs: 418658114257813515,
+				Table:    &model.TableName{Schema: "common_1", Table: "users"},
+				PreColumns: []*model.Column{{
+					Name:  "id",
+					Type:  mysql.TypeLong,
+					Flag:  model.BinaryFlag | model.PrimaryKeyFlag | model.HandleKeyFlag,
+					Value: 1,
+				}, {
+					Name:  "email",
+					Type:  mysql.TypeVarchar,
+					Value: "a@pingcap.com",
+				}},
+				IndexColumns: [][]int{{0}},
+			},
+			{
+				StartTs:  418658114257813514,
+				CommitTs: 418658114257813515,
+				Table:    &model.TableName{Schema: "common_1", Table: "users"},
+				Columns: []*model.Column{{
+					Name:  "id",
+					Type:  mysql.TypeLong,
+					Flag:  model.BinaryFlag | model.PrimaryKeyFlag | model.HandleKeyFlag,
+					Value: 2,
+				}, {
+					Name:  "email",
+					Type:  mysql.TypeVarchar,
+					Value: "b@pingcap.com",
+				}},
+				IndexColumns: [][]int{{0}},
+			},
+		}
+	}
+	mask := func(v interface{}) interface{} {
+		return fmt.Sprintf("masked(%v)", v)
+	}
+	double := func(v interface{}) interface{} {
+		return v.(int) * 2
+	}
+
+	testCases := []struct {
+		name           string
+		batchDMLEnable bool
+		deterministic  bool
+		expectedSQLs   []string
+		expectedValues [][]interface{}
+	}{
+		{
+			name: "non-deterministic",
+			expectedSQLs: []string{
+				"DELETE FROM `common_1`.`users` WHERE `id` = ? LIMIT 1",
+				"INSERT INTO `common_1`.`users` (`id`,`email`) VALUES (?,?)",
+			},
+			expectedValues: [][]interface{}{{1}, {2, "masked(b@pingcap.com)"}},
+		},
+		{
+			name:          "deterministic",
+			deterministic: true,
+			expectedSQLs: []string{
+				"DELETE FROM `common_1`.`users` WHERE `id` = ? LIMIT 1",
+				"INSERT INTO `common_1`.`users` (`id`,`email`) VALUES (?,?)",
+			},
+			expectedValues: [][]interface{}{{2}, {4, "masked(b@pingcap.com)"}},
+		},
+		{
+			name:           "batch-deterministic",
+			batchDMLEnable: true,
+			deterministic:  true,
+			expectedSQLs: []string{
+				"DELETE FROM `common_1`.`users` WHERE (`id` = ?)",
+				"INSERT INTO `common_1`.`users` (`id`,`email`) VALUES (?,?)",
+			},
+			expectedValues: [][]interface{}{{2}, {4, "masked(b@pingcap.com)"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ms := newMySQLBackendWithoutDB(ctx)
+			ms.cfg.BatchDMLEnable = tc.batchDMLEnable
+			ms.cfg.IsTiDB = true
+			ms.SetColumnTransformers(map[string]func(interface{}) interface{}{
+				"common_1.users.email": mask,
+				"Common_1.Users.ID":    double,
+			}, tc.deterministic)
+			rows := newRows()
+			ms.events = []*dmlsink.TxnCallbackableEvent{{
+				Event: &model.SingleTableTxn{Rows: rows},
+			}}
+			ms.rows = len(rows)
+			dmls := ms.prepareDMLs()
+			require.Equal(t, tc.expectedSQLs, dmls.sqls)
+			require.Equal(t, tc.expectedValues, dmls.values)
+			// The original rows must not be modified.
+			require.Equal(t, 2, rows[1].Columns[0].Value)
+			require.Equal(t, "b@pingcap.com", rows[1].Columns[1].Value)
+		})
+	}
+}
+
+func TestFlushObservesBatchSizeOnce(t *testing.T) {
+	rows := []*model.RowChangedEvent{
+		{
+			Table: &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{
+				{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 1,
+				},
+			},
+			ApproximateDataSize: 10,
+		},
+		{
+			Table: &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{
+				{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 2,
+				},
+			},
+			ApproximateDataSize: 10,
+		},
+	}
+
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		// the first try fails with a retryable error.
+		mock.ExpectBegin()
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?),(?)").
+			WithArgs(1, 2).
+			WillReturnError(&dmysql.MySQLError{Number: mysql.ErrLockDeadlock})
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("REPLACE INTO `s1`.`t1` (`a`) VALUES (?),(?)").
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(2, 2))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changefeed := "test-batch-size"
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false")
+	require.Nil(t, err)
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID(changefeed), sinkURI,
+		config.GetDefaultReplicaConfig(), mockGetDBConn)
+	require.Nil(t, err)
+
+	_ = sink.OnTxnEvent(&dmlsink.TxnCallbackableEvent{
+		Event: &model.SingleTableTxn{Rows: rows},
+	})
+	err = sink.Flush(context.Background())
+	require.Nil(t, err)
+
+	metric := &dto.Metric{}
+	err = sink.metricTxnSinkDMLBatchSize.(prometheus.Metric).Write(metric)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+	require.Greater(t, metric.GetHistogram().GetSampleSum(), float64(20))
+
+	require.Nil(t, sink.Close())
+}
+
+func TestExecDMLWithWriteSourceDisabled(t *testing.T) {
+	t.Parallel()
+
+	rows := []*model.RowChangedEvent{
+		{
+			StartTs:  418658114257813514,
+			CommitTs: 418658114257813515,
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{
+				{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 1,
+				},
+			},
+		},
+	}
+
+	for _, enableWriteSource := range []bool{true, false} {
+		ctx, cancel := context.WithCancel(context.Background())
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.Nil(t, err)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `s1`.`t1` (`a`) VALUES (?)").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		if enableWriteSource {
+			mock.ExpectExec("SET SESSION tidb_cdc_write_source = 1").
+				WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectCommit()
+		mock.ExpectClose()
+
+		ms := newMySQLBackendWithoutDB(ctx)
+		ms.db = db
+		ms.dmlMaxRetry = 1
+		ms.cfg.IsTiDB = true
+		ms.cfg.IsWriteSourceExisted = true
+		ms.cfg.EnableWriteSource = enableWriteSource
+		ms.events = []*dmlsink.TxnCallbackableEvent{{
+			Event: &model.SingleTableTxn{Rows: rows},
+		}}
+		ms.rows = len(rows)
+		err = ms.execDMLWithMaxRetries(ctx, ms.prepareDMLs())
+		require.Nil(t, err)
+		require.Nil(t, db.Close())
+		require.Nil(t, mock.ExpectationsWereMet())
+		cancel()
+	}
+}
+
+func TestPingDownstream(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.Nil(t, err)
+	mock.ExpectPing()
+	mock.ExpectPing().WillReturnError(driver.ErrBadConn)
+	mock.ExpectClose()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.db = db
+	ms.metricTxnPingFailures = txn.PingFailures.WithLabelValues("default", "test-ping")
+
+	getFailures := func() float64 {
+		metric := &dto.Metric{}
+		require.Nil(t, ms.metricTxnPingFailures.(prometheus.Metric).Write(metric))
+		return metric.GetCounter().GetValue()
+	}
+	ms.pingDownstream(ctx)
+	require.Equal(t, float64(0), getFailures())
+	ms.pingDownstream(ctx)
+	require.Equal(t, float64(1), getFailures())
+
+	require.Nil(t, db.Close())
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPrepareDMLsWithCommitTsAnnotation(t *testing.T) {
+	t.Parallel()
+
+	newRow := func(commitTs uint64, value int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			StartTs:  commitTs - 1,
+			CommitTs: commitTs,
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{{
+				Name:  "a",
+				Type:  mysql.TypeLong,
+				Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+				Value: value,
+			}},
+		}
+	}
+
+	testCases := []struct {
+		batchDMLEnable bool
+		expected       []string
+	}{
+		{
+			batchDMLEnable: false,
+			expected: []string{
+				"/* ticdc commitTs=5 */ REPLACE INTO `s1`.`t1` (`a`) VALUES (?)",
+				"REPLACE INTO `s1`.`t1` (`a`) VALUES (?)",
+				"/* ticdc commitTs=8 */ REPLACE INTO `s1`.`t1` (`a`) VALUES (?)",
+				"REPLACE INTO `s1`.`t1` (`a`) VALUES (?)",
+			},
+		},
+		{
+			batchDMLEnable: true,
+			expected: []string{
+				"/* ticdc commitTs=5 */ REPLACE INTO `s1`.`t1` (`a`) VALUES (?),(?)",
+				"/* ticdc commitTs=8 */ REPLACE INTO `s1`.`t1` (`a`) VALUES (?),(?)",
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, tc := range testCases {
+		ms := newMySQLBackendWithoutDB(ctx)
+		ms.cfg.SafeMode = true
+		ms.cfg.BatchDMLEnable = tc.batchDMLEnable
+		ms.cfg.AnnotateCommitTs = true
+		ms.events = []*dmlsink.TxnCallbackableEvent{
+			{Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{newRow(5, 1), newRow(5, 2)}}},
+			{Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{newRow(8, 3), newRow(8, 4)}}},
+		}
+		ms.rows = 4
+		dmls := ms.prepareDMLs()
+		require.Equal(t, tc.expected, dmls.sqls)
+		// The comments must not break joining statements in the multi statements way.
+		require.Equal(t, len(tc.expected), len(strings.Split(strings.Join(dmls.sqls, ";"), ";")))
+	}
+}
+
+func TestPrepareDMLsWithTableRewrite(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "s1", Table: "t1", TableID: 1}
+	newColumns := func(a, b int) []*model.Column {
+		return []*model.Column{{
+			Name:  "a",
+			Type:  mysql.TypeLong,
+			Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			Value: a,
+		}, {
+			Name:  "b",
+			Type:  mysql.TypeLong,
+			Value: b,
+		}}
+	}
+	rows := []*model.RowChangedEvent{
+		{StartTs: 1, CommitTs: 2, Table: table, Columns: newColumns(1, 1)},
+		{StartTs: 1, CommitTs: 2, Table: table, PreColumns: newColumns(2, 2), Columns: newColumns(2, 3)},
+		{StartTs: 1, CommitTs: 2, Table: table, PreColumns: newColumns(3, 3)},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, batchDMLEnable := range []bool{false, true} {
+		ms := newMySQLBackendWithoutDB(ctx)
+		ms.cfg.BatchDMLEnable = batchDMLEnable
+		ms.cfg.TableRewriteFunc = func(src model.TableName) model.TableName {
+			src.Table += "_shard3"
+			return src
+		}
+		ms.events = []*dmlsink.TxnCallbackableEvent{{
+			Event: &model.SingleTableTxn{Rows: rows},
+		}}
+		ms.rows = len(rows)
+		dmls := ms.prepareDMLs()
+		require.Len(t, dmls.sqls, 3, "batchDMLEnable %v", batchDMLEnable)
+		// All of insert, update and delete target the rewritten table.
+		for _, sql := range dmls.sqls {
+			require.Contains(t, sql, "`s1`.`t1_shard3`", "batchDMLEnable %v", batchDMLEnable)
+		}
+		// The upstream table isn't changed.
+		require.Equal(t, "t1", table.Table)
+	}
+}
+
+func TestPrepareDMLsSQLStartTs(t *testing.T) {
+	t.Parallel()
+
+	newRow := func(startTs uint64, value int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{
+			StartTs:  startTs,
+			CommitTs: startTs + 1,
+			Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			Columns: []*model.Column{{
+				Name:  "a",
+				Type:  mysql.TypeLong,
+				Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+				Value: value,
+			}},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.events = []*dmlsink.TxnCallbackableEvent{
+		{Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{newRow(1, 1), newRow(1, 2)}}},
+		{Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{newRow(3, 3)}}},
+	}
+	ms.rows = 3
+	dmls := ms.prepareDMLs()
+	require.Len(t, dmls.sqls, 3)
+	require.Equal(t, []model.Ts{1, 3}, dmls.startTs)
+	require.Equal(t, []model.Ts{1, 1, 3}, dmls.sqlStartTs)
+}
+
+func TestHandleZeroAutoIncrementKeys(t *testing.T) {
+	t.Parallel()
+
+	ft := parser_types.NewFieldType(mysql.TypeLonglong)
+	ft.SetFlag(mysql.PriKeyFlag | mysql.AutoIncrementFlag | mysql.NotNullFlag)
+	tableInfo := model.WrapTableInfo(1, "s1", 0, &timodel.TableInfo{
+		ID:         1,
+		Name:       timodel.NewCIStr("t1"),
+		PKIsHandle: true,
+		Columns: []*timodel.ColumnInfo{
+			{ID: 1, Name: timodel.NewCIStr("id"), FieldType: *ft, State: timodel.StatePublic},
+		},
+	})
+	newRow := func(value int64, isDelete bool) *model.RowChangedEvent {
+		cols := []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLonglong,
+			Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			Value: value,
+		}}
+		row := &model.RowChangedEvent{
+			StartTs:             1,
+			CommitTs:            2,
+			Table:               &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+			TableInfo:           tableInfo,
+			ApproximateDataSize: 10,
+		}
+		if isDelete {
+			row.PreColumns = cols
+		} else {
+			row.Columns = cols
+		}
+		return row
+	}
+	newEvents := func() []*dmlsink.TxnCallbackableEvent {
+		return []*dmlsink.TxnCallbackableEvent{
+			{Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{
+				newRow(1, false), newRow(0, false), newRow(2, true),
+			}}},
+			{Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{newRow(0, true)}}},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+
+	// pass-through
+	ms.events = newEvents()
+	ms.rows = 4
+	ms.bufferedBytes = 40
+	require.NoError(t, ms.handleZeroAutoIncrementKeys())
+	require.Equal(t, 4, ms.rows)
+	require.Equal(t, int64(40), ms.bufferedBytes)
+	require.Len(t, ms.events[0].Event.Rows, 3)
+
+	// error
+	ms.cfg.ZeroAutoIncrementKeyPolicy = pmysql.ZeroAutoIncrementKeyPolicyError
+	ms.events = newEvents()
+	err := ms.handleZeroAutoIncrementKeys()
+	require.True(t, cerror.ErrMySQLZeroAutoIncrementKey.Equal(err))
+	require.Contains(t, err.Error(), "auto increment handle key id of table s1.t1 is zero")
+
+	// skip
+	ms.cfg.ZeroAutoIncrementKeyPolicy = pmysql.ZeroAutoIncrementKeyPolicySkip
+	events := newEvents()
+	ms.events = append([]*dmlsink.TxnCallbackableEvent{}, events...)
+	ms.rows = 4
+	ms.bufferedBytes = 40
+	require.NoError(t, ms.handleZeroAutoIncrementKeys())
+	require.Equal(t, 2, ms.rows)
+	require.Equal(t, int64(20), ms.bufferedBytes)
+	require.Len(t, ms.events[0].Event.Rows, 2)
+	require.Equal(t, int64(2), ms.events[0].Event.Rows[1].PreColumns[0].Value)
+	require.Empty(t, ms.events[1].Event.Rows)
+	// The events of the caller are not modified.
+	require.Len(t, events[0].Event.Rows, 3)
+	require.Len(t, events[1].Event.Rows, 1)
+}
+
+func TestExecDMLWithDryRun(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	mock.ExpectClose()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.db = db
+	ms.cfg.DryRun = true
+
+	var (
+		gotSQLs   []string
+		gotValues [][]interface{}
+	)
+	ms.SetDryRunHook(func(sqls []string, values [][]interface{}) {
+		gotSQLs = append(gotSQLs, sqls...)
+		gotValues = append(gotValues, values...)
+	})
+
+	dmls := &preparedDMLs{
+		startTs:  []model.Ts{1},
+		sqls:     []string{"INSERT INTO `s1`.`t1` (`a`) VALUES (?)"},
+		values:   [][]interface{}{{1}},
+		rowCount: 1,
+	}
+	require.Nil(t, ms.execDMLWithMaxRetries(ctx, dmls))
+	require.Equal(t, dmls.sqls, gotSQLs)
+	require.Equal(t, dmls.values, gotValues)
+
+	require.Nil(t, db.Close())
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestPrepareDMLsWithStrictStartTsGrouping(t *testing.T) {
+	t.Parallel()
+
+	newEvent := func(startTs uint64, value int) *dmlsink.TxnCallbackableEvent {
+		return &dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{{
+				StartTs:  startTs,
+				CommitTs: startTs + 1,
+				Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+				Columns: []*model.Column{{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: value,
+				}},
+			}}},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.cfg.StrictStartTsGrouping = true
+	ms.metricTxnInterleavedStartTs = txn.InterleavedStartTs.WithLabelValues("default", "test-interleaved")
+	getInterleaved := func() float64 {
+		metric := &dto.Metric{}
+		require.Nil(t, ms.metricTxnInterleavedStartTs.(prometheus.Metric).Write(metric))
+		return metric.GetCounter().GetValue()
+	}
+
+	// grouped transactions
+	ms.events = []*dmlsink.TxnCallbackableEvent{newEvent(1, 1), newEvent(1, 2), newEvent(3, 3)}
+	ms.rows = 3
+	dmls := ms.prepareDMLs()
+	require.Equal(t, []model.Ts{1, 1, 3}, dmls.startTs)
+	require.Equal(t, float64(0), getInterleaved())
+
+	// interleaved transactions
+	ms.events = []*dmlsink.TxnCallbackableEvent{newEvent(1, 1), newEvent(3, 2), newEvent(1, 3)}
+	dmls = ms.prepareDMLs()
+	require.Equal(t, []model.Ts{1, 3, 1}, dmls.startTs)
+	require.Equal(t, float64(1), getInterleaved())
+
+	require.True(t, ms.checkStartTsGrouping([]model.Ts{1, 1, 2, 3}))
+	require.False(t, ms.checkStartTsGrouping([]model.Ts{1, 2, 1}))
+}
+
+func newMySQLBackendWithStmtCache(t testing.TB, db *sql.DB) *mysqlBackend {
+	ms := newMySQLBackendWithoutDB(context.Background())
+	ms.db = db
+	ms.cachePrepStmts = true
+	stmtCache, err := lru.NewWithEvict(prepStmtCacheSize, func(key, value interface{}) {
+		stmt := value.(*sql.Stmt)
+		stmt.Close()
+	})
+	require.Nil(t, err)
+	ms.stmtCache = stmtCache
+	return ms
+}
+
+func TestSequenceExecuteClearStmtCacheOnBadConn(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.Nil(t, err)
+	query := "INSERT INTO `s1`.`t1` (`a`) VALUES (?)"
+	mock.ExpectBegin()
+	mock.ExpectPrepare(regexp.QuoteMeta(query))
+	// the statement is prepared again on the connection of the transaction
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().WithArgs(1).WillReturnError(dmysql.ErrInvalidConn)
+	mock.ExpectRollback()
+	// both the connection of the txn and the one preparing the statement are closed
+	mock.ExpectClose()
+	mock.ExpectClose()
+
+	ms := newMySQLBackendWithStmtCache(t, db)
+	dmls := &preparedDMLs{
+		startTs:  []model.Ts{1},
+		sqls:     []string{query},
+		values:   [][]interface{}{{1}},
+		rowCount: 1,
+	}
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	require.Nil(t, err)
+	_, err = ms.sequenceExecute(ctx, dmls, tx, time.Minute)
+	require.Equal(t, dmysql.ErrInvalidConn, errors.Cause(err))
+	require.Equal(t, 0, ms.stmtCache.Len())
+
+	require.Nil(t, db.Close())
+	require.Nil(t, mock.ExpectationsWereMet())
+}
+
+// BenchmarkSequenceExecute compares executing DMLs with prepared statements
+// against executing them inline.
+func BenchmarkSequenceExecute(b *testing.B) {
+	query := "INSERT INTO `s1`.`t1` (`a`) VALUES (?)"
+	dmls := &preparedDMLs{
+		startTs:  []model.Ts{1},
+		sqls:     []string{query},
+		values:   [][]interface{}{{1}},
+		rowCount: 1,
+	}
+
+	for _, cachePrepStmts := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cachePrepStmts=%v", cachePrepStmts), func(b *testing.B) {
+			db, mock, err := sqlmock.New()
+			require.Nil(b, err)
+			defer db.Close()
+			// Keep only one idle connection, so that the transactions after the
+			// first flush reuse the connection on which the statement is prepared.
+			db.SetMaxIdleConns(1)
+
+			ms := newMySQLBackendWithoutDB(context.Background())
+			ms.db = db
+			if cachePrepStmts {
+				ms = newMySQLBackendWithStmtCache(b, db)
+			}
+			for i := 0; i < b.N; i++ {
+				mock.ExpectBegin()
+				if cachePrepStmts && i == 0 {
+					// The statement is prepared on a new connection in the first
+					// flush, and then prepared again on the connection of the txn.
+					mock.ExpectPrepare(regexp.QuoteMeta(query))
+					mock.ExpectPrepare(regexp.QuoteMeta(query))
+				}
+				mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			}
+
+			ctx := context.Background()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tx, err := db.BeginTx(ctx, nil)
+				require.Nil(b, err)
+				_, err = ms.sequenceExecute(ctx, dmls, tx, time.Minute)
+				require.Nil(b, err)
+				require.Nil(b, tx.Commit())
+			}
+		})
+	}
+}
+
+func TestFlushAndReportCommitTs(t *testing.T) {
+	dbIndex := 0
+	mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+		defer func() { dbIndex++ }()
+
+		if dbIndex == 0 {
+			// test db
+			db, err := pmysql.MockTestDB(true)
+			require.Nil(t, err)
+			return db, nil
+		}
+
+		// normal db
+		db, mock := newTestMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `s1`.`t1` (`a`) VALUES (?);INSERT INTO `s1`.`t1` (`a`) VALUES (?)").
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(2, 2))
+		mock.ExpectCommit()
+		mock.ExpectClose()
+		return db, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sinkURI, err := url.Parse(
+		"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false")
+	require.Nil(t, err)
+	sink, err := newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+		config.GetDefaultReplicaConfig(), mockGetDBConn)
+	require.Nil(t, err)
+
+	// no buffered events
+	commitTs, err := sink.FlushAndReportCommitTs(ctx)
+	require.Nil(t, err)
+	require.Equal(t, model.Ts(0), commitTs)
+
+	newEvent := func(commitTs uint64, value int) *dmlsink.TxnCallbackableEvent {
+		return &dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{
+				StartTs:  commitTs - 1,
+				CommitTs: commitTs,
+				Rows: []*model.RowChangedEvent{{
+					StartTs:  commitTs - 1,
+					CommitTs: commitTs,
+					Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+					Columns: []*model.Column{{
+						Name:  "a",
+						Type:  mysql.TypeLong,
+						Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+						Value: value,
+					}},
+				}},
+			},
+			Callback: func() {},
+		}
+	}
+	_ = sink.OnTxnEvent(newEvent(6, 1))
+	_ = sink.OnTxnEvent(newEvent(3, 2))
+	commitTs, err = sink.FlushAndReportCommitTs(ctx)
+	require.Nil(t, err)
+	require.Equal(t, model.Ts(6), commitTs)
+	require.Len(t, sink.events, 0)
+
+	require.Nil(t, sink.Close())
+}
+
+func TestPrepareDMLsInsertTranslateMetrics(t *testing.T) {
+	t.Parallel()
+
+	newEvent := func(commitTs, replicatingTs uint64) *dmlsink.TxnCallbackableEvent {
+		rows := make([]*model.RowChangedEvent, 0, 2)
+		for i := 0; i < 2; i++ {
+			rows = append(rows, &model.RowChangedEvent{
+				StartTs:       commitTs - 1,
+				CommitTs:      commitTs,
+				ReplicatingTs: replicatingTs,
+				Table:         &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+				Columns: []*model.Column{{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: i,
+				}},
+			})
+		}
+		return &dmlsink.TxnCallbackableEvent{Event: &model.SingleTableTxn{Rows: rows}}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.metricTxnInsertTranslated = txn.InsertTranslatedEvents.WithLabelValues("default", "test-translate")
+	ms.metricTxnReplace = txn.ReplaceEvents.WithLabelValues("default", "test-translate")
+	getValue := func(counter prometheus.Counter) float64 {
+		metric := &dto.Metric{}
+		require.Nil(t, counter.(prometheus.Metric).Write(metric))
+		return metric.GetCounter().GetValue()
+	}
+
+	// The second event is committed before the table is replicating.
+	ms.events = []*dmlsink.TxnCallbackableEvent{newEvent(10, 5), newEvent(20, 30)}
+	ms.rows = 4
+	dmls := ms.prepareDMLs()
+	require.Equal(t, []string{
+		"INSERT INTO `s1`.`t1` (`a`) VALUES (?)",
+		"INSERT INTO `s1`.`t1` (`a`) VALUES (?)",
+		"REPLACE INTO `s1`.`t1` (`a`) VALUES (?)",
+		"REPLACE INTO `s1`.`t1` (`a`) VALUES (?)",
+	}, dmls.sqls)
+	// Counted per event rather than per row.
+	require.Equal(t, float64(1), getValue(ms.metricTxnInsertTranslated))
+	require.Equal(t, float64(1), getValue(ms.metricTxnReplace))
+}
+
+func TestExecDMLWithVerifyRowsAffected(t *testing.T) {
+	t.Parallel()
+
+	insert := "INSERT INTO `s1`.`t1` (`a`) VALUES (?)"
+	del := "DELETE FROM `s1`.`t1` WHERE `a` = ? LIMIT 1"
+	testCases := []struct {
+		name     string
+		dmls     *preparedDMLs
+		affected []int64
+		mismatch float64
+		tolerate float64
+	}{
+		{
+			name: "matched",
+			dmls: &preparedDMLs{
+				sqls: []string{insert, del}, values: [][]interface{}{{1}, {2}},
+				rowCount: 2, deleteRowCount: 1,
+			},
+			affected: []int64{1, 1},
+		},
+		{
+			name: "delete absent rows",
+			dmls: &preparedDMLs{
+				sqls: []string{insert, del}, values: [][]interface{}{{1}, {2}},
+				rowCount: 2, deleteRowCount: 1,
+			},
+			affected: []int64{1, 0},
+			tolerate: 1,
+		},
+		{
+			name: "update rows without changes",
+			dmls: &preparedDMLs{
+				sqls:     []string{"UPDATE `s1`.`t1` SET `a` = 1 WHERE `a` = ? LIMIT 1"},
+				values:   [][]interface{}{{1}},
+				rowCount: 1, updateRowCount: 1,
+			},
+			affected: []int64{0},
+			tolerate: 1,
+		},
+		{
+			name: "missing inserted rows",
+			dmls: &preparedDMLs{
+				sqls: []string{insert, insert}, values: [][]interface{}{{1}, {2}},
+				rowCount: 2,
+			},
+			affected: []int64{1, 0},
+			mismatch: 1,
+		},
+		{
+			name: "replace existing rows",
+			dmls: &preparedDMLs{
+				sqls:     []string{"REPLACE INTO `s1`.`t1` (`a`) VALUES (?)"},
+				values:   [][]interface{}{{1}},
+				rowCount: 1, replaceRowCount: 1,
+			},
+			affected: []int64{2},
+		},
+		{
+			name: "too many rows affected",
+			dmls: &preparedDMLs{
+				sqls: []string{insert}, values: [][]interface{}{{1}},
+				rowCount: 1,
+			},
+			affected: []int64{2},
+			mismatch: 1,
+		},
+	}
+
+	getValue := func(counter prometheus.Counter) float64 {
+		metric := &dto.Metric{}
+		require.Nil(t, counter.(prometheus.Metric).Write(metric))
+		return metric.GetCounter().GetValue()
+	}
+	for i, tc := range testCases {
+		db, mock, err := sqlmock.New()
+		require.Nil(t, err)
+		mock.ExpectBegin()
+		for j, query := range tc.dmls.sqls {
+			mock.ExpectExec(regexp.QuoteMeta(query)).WithArgs(tc.dmls.values[j][0]).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected[j]))
+		}
+		mock.ExpectCommit()
+		mock.ExpectClose()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ms := newMySQLBackendWithoutDB(ctx)
+		ms.db = db
+		ms.dmlMaxRetry = 1
+		// The DMLs are executed one by one even if multi-stmt-enable is
+		// true by default.
+		sinkURI, err := url.Parse("mysql://127.0.0.1:3306/?verify-rows-affected=true")
+		require.Nil(t, err)
+		require.Nil(t, ms.cfg.Apply("UTC", model.DefaultChangeFeedID("test"),
+			sinkURI, config.GetDefaultReplicaConfig()))
+		label := fmt.Sprintf("test-verify-%d", i)
+		ms.metricTxnRowsAffectedMismatch = txn.RowsAffectedMismatches.WithLabelValues("default", label)
+		ms.metricTxnRowsAffectedTolerated = txn.RowsAffectedToleratedMismatches.WithLabelValues("default", label)
+
+		tc.dmls.startTs = []model.Ts{1}
+		require.Nil(t, ms.execDMLWithMaxRetries(ctx, tc.dmls), tc.name)
+		require.Equal(t, tc.mismatch, getValue(ms.metricTxnRowsAffectedMismatch), tc.name)
+		require.Equal(t, tc.tolerate, getValue(ms.metricTxnRowsAffectedTolerated), tc.name)
+
+		require.Nil(t, db.Close())
+		require.Nil(t, mock.ExpectationsWereMet())
+		cancel()
+	}
+}
+
+func TestFlushSplitByMaxTxnSize(t *testing.T) {
+	t.Parallel()
+
+	insert := "INSERT INTO `s1`.`t1` (`a`) VALUES (?)"
+	newEvent := func(startTs uint64, value int, called *int) *dmlsink.TxnCallbackableEvent {
+		return &dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{
+				StartTs:  startTs,
+				CommitTs: startTs + 1,
+				Rows: []*model.RowChangedEvent{{
+					StartTs:  startTs,
+					CommitTs: startTs + 1,
+					Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+					Columns: []*model.Column{{
+						Name:  "a",
+						Type:  mysql.TypeLong,
+						Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+						Value: value,
+					}},
+					ApproximateDataSize: 100,
+				}},
+			},
+			Callback: func() { *called++ },
+		}
+	}
+
+	for _, failLast := range []bool{false, true} {
+		db, mock, err := sqlmock.New()
+		require.Nil(t, err)
+		// The last two events belong to the same upstream transaction, so they
+		// are not split even if they exceed the limit together.
+		for _, values := range [][]int{{1}, {2}, {3, 4}} {
+			mock.ExpectBegin()
+			for _, v := range values {
+				mock.ExpectExec(regexp.QuoteMeta(insert)).WithArgs(v).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			if failLast && values[0] == 3 {
+				mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
+			} else {
+				mock.ExpectCommit()
+			}
+		}
+		mock.ExpectClose()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ms := newMySQLBackendWithoutDB(ctx)
+		ms.db = db
+		ms.dmlMaxRetry = 1
+		ms.cfg.MultiStmtEnable = false
+		ms.cfg.MaxTxnSizeBytes = 150
+		ms.metricTxnSinkDMLBatchSize = txn.SinkDMLBatchApproximateSize.
+			WithLabelValues("default", fmt.Sprintf("test-split-%v", failLast))
+
+		called := 0
+		for i, startTs := range []uint64{10, 20, 30, 30} {
+			_ = ms.OnTxnEvent(newEvent(startTs, i+1, &called))
+		}
+		err = ms.Flush(ctx)
+		metric := &dto.Metric{}
+		require.Nil(t, ms.metricTxnSinkDMLBatchSize.(prometheus.Metric).Write(metric))
+		if failLast {
+			require.Error(t, err)
+			require.Equal(t, 0, called)
+			require.Equal(t, uint64(0), metric.GetHistogram().GetSampleCount())
+		} else {
+			require.Nil(t, err)
+			require.Equal(t, 4, called)
+			require.Len(t, ms.events, 0)
+			// The size of the split transactions is observed once per flush.
+			require.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+			require.Equal(t, float64(4*(len(insert)+100)), metric.GetHistogram().GetSampleSum())
+		}
+
+		require.Nil(t, db.Close())
+		require.Nil(t, mock.ExpectationsWereMet())
+		cancel()
+	}
+}
+
+func TestPrepareDMLsCoalesceUpdates(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "s1", Table: "t1", TableID: 1}
+	cols := func(id, value int) []*model.Column {
+		return []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLong,
+			Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			Value: id,
+		}, {
+			Name:  "v",
+			Type:  mysql.TypeLong,
+			Value: value,
+		}}
+	}
+	update := func(id, from, to int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{Table: table, PreColumns: cols(id, from), Columns: cols(id, to)}
+	}
+	updateKey := func(from, to int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{Table: table, PreColumns: cols(from, 0), Columns: cols(to, 0)}
+	}
+	insert := func(id, value int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{Table: table, Columns: cols(id, value)}
+	}
+	del := func(id, value int) *model.RowChangedEvent {
+		return &model.RowChangedEvent{Table: table, PreColumns: cols(id, value)}
+	}
+	updateSQL := "UPDATE `s1`.`t1` SET `id` = ?, `v` = ? WHERE `id` = ? LIMIT 1"
+	insertSQL := "INSERT INTO `s1`.`t1` (`id`,`v`) VALUES (?,?)"
+	replaceSQL := "REPLACE INTO `s1`.`t1` (`id`,`v`) VALUES (?,?)"
+	deleteSQL := "DELETE FROM `s1`.`t1` WHERE `id` = ? LIMIT 1"
+
+	testCases := []struct {
+		name   string
+		events [][]*model.RowChangedEvent
+		sqls   []string
+		values [][]interface{}
+	}{
+		{
+			name:   "sequential updates",
+			events: [][]*model.RowChangedEvent{{update(1, 1, 2)}, {update(1, 2, 3)}, {update(1, 3, 4)}},
+			sqls:   []string{updateSQL},
+			values: [][]interface{}{{1, 4, 1}},
+		},
+		{
+			name: "updates of other rows in between",
+			events: [][]*model.RowChangedEvent{
+				{update(1, 1, 2), update(2, 1, 2)}, {update(1, 2, 3)}, {update(2, 2, 3), update(1, 3, 4)},
+			},
+			sqls:   []string{updateSQL, updateSQL},
+			values: [][]interface{}{{2, 3, 2}, {1, 4, 1}},
+		},
+		{
+			name: "delete and insert in between",
+			events: [][]*model.RowChangedEvent{
+				{update(1, 1, 2)}, {del(1, 2)}, {insert(1, 3)}, {update(1, 3, 4)}, {update(1, 4, 5)},
+			},
+			sqls:   []string{updateSQL, deleteSQL, insertSQL, updateSQL},
+			values: [][]interface{}{{1, 2, 1}, {1}, {1, 3}, {1, 5, 1}},
+		},
+		{
+			name:   "handle key changed in between",
+			events: [][]*model.RowChangedEvent{{update(1, 1, 2)}, {updateKey(1, 2)}, {update(2, 0, 3)}},
+			sqls:   []string{updateSQL, updateSQL, updateSQL},
+			values: [][]interface{}{{1, 2, 1}, {2, 0, 1}, {2, 3, 2}},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	newEvents := func(rows [][]*model.RowChangedEvent, called *int) []*dmlsink.TxnCallbackableEvent {
+		events := make([]*dmlsink.TxnCallbackableEvent, 0, len(rows))
+		for i, eventRows := range rows {
+			commitTs := uint64(i+1) * 10
+			for _, row := range eventRows {
+				row.StartTs = commitTs - 1
+				row.CommitTs = commitTs
+				row.ReplicatingTs = 1
+			}
+			events = append(events, &dmlsink.TxnCallbackableEvent{
+				Event:    &model.SingleTableTxn{StartTs: commitTs - 1, CommitTs: commitTs, Rows: eventRows},
+				Callback: func() { *called++ },
+			})
+		}
+		return events
+	}
+	for _, tc := range testCases {
+		ms := newMySQLBackendWithoutDB(ctx)
+		ms.cfg.CoalesceUpdates = true
+		called := 0
+		ms.events = newEvents(tc.events, &called)
+		original := make([]*model.RowChangedEvent, 0, len(ms.events))
+		for _, event := range ms.events {
+			for _, row := range event.Event.Rows {
+				copied := *row
+				original = append(original, &copied)
+			}
+		}
+		rows := len(original)
+
+		dmls := ms.prepareDMLs()
+		require.Equal(t, tc.sqls, dmls.sqls, tc.name)
+		require.Equal(t, tc.values, dmls.values, tc.name)
+		require.Equal(t, len(tc.sqls), dmls.rowCount, tc.name)
+		require.Len(t, dmls.callbacks, len(tc.events), tc.name)
+		// the buffered events are not modified.
+		for _, event := range ms.events {
+			for _, row := range event.Event.Rows {
+				require.Equal(t, original[0], row, tc.name)
+				original = original[1:]
+			}
+		}
+
+		ms.cfg.CoalesceUpdates = false
+		dmls = ms.prepareDMLs()
+		require.Len(t, dmls.sqls, rows, tc.name)
+	}
+
+	// An event whose rows are all coalesced away still decides whether the
+	// following inserts are translated to REPLACE.
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.cfg.CoalesceUpdates = true
+	called := 0
+	ms.events = newEvents([][]*model.RowChangedEvent{{update(1, 1, 2)}, {update(1, 2, 3)}, {insert(2, 1)}}, &called)
+	ms.events[0].Event.Rows[0].ReplicatingTs = 20
+	dmls := ms.prepareDMLs()
+	require.Equal(t, []string{updateSQL, replaceSQL}, dmls.sqls)
+	require.Equal(t, [][]interface{}{{1, 3, 1}, {2, 1}}, dmls.values)
+}
+
+func TestHandleKeyOfWithSeparators(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "s1", Table: "t1", TableID: 1}
+	cols := func(a, b interface{}) []*model.Column {
+		return []*model.Column{
+			{Name: "a", Type: mysql.TypeVarchar, Flag: model.HandleKeyFlag, Value: a},
+			{Name: "b", Type: mysql.TypeVarchar, Flag: model.HandleKeyFlag, Value: b},
+		}
+	}
+	key1, ok := handleKeyOf(table, cols("x,b=y", "z"))
+	require.True(t, ok)
+	key2, ok := handleKeyOf(table, cols("x", "y,b=z"))
+	require.True(t, ok)
+	require.NotEqual(t, key1, key2)
+
+	key1, _ = handleKeyOf(table, cols(nil, "z"))
+	key2, _ = handleKeyOf(table, cols("<nil>", "z"))
+	require.NotEqual(t, key1, key2)
+
+	key1, _ = handleKeyOf(&model.TableName{Schema: "s1.t", Table: "1"}, cols("x", "y"))
+	key2, _ = handleKeyOf(&model.TableName{Schema: "s1", Table: "t.1"}, cols("x", "y"))
+	require.NotEqual(t, key1, key2)
+
+	// The updates of the rows whose keys were ambiguous are not coalesced.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.cfg.CoalesceUpdates = true
+	update := func(commitTs uint64, a, b string, from, to string) *dmlsink.TxnCallbackableEvent {
+		pre := append(cols(a, b), &model.Column{Name: "v", Type: mysql.TypeVarchar, Value: from})
+		post := append(cols(a, b), &model.Column{Name: "v", Type: mysql.TypeVarchar, Value: to})
+		row := &model.RowChangedEvent{
+			Table: table, PreColumns: pre, Columns: post,
+			StartTs: commitTs - 1, CommitTs: commitTs, ReplicatingTs: 1,
+		}
+		return &dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{StartTs: commitTs - 1, CommitTs: commitTs, Rows: []*model.RowChangedEvent{row}},
+		}
+	}
+	ms.events = []*dmlsink.TxnCallbackableEvent{
+		update(10, "x,b=y", "z", "1", "2"),
+		update(20, "x", "y,b=z", "3", "4"),
+	}
+	dmls := ms.prepareDMLs()
+	require.Equal(t, [][]interface{}{
+		{"x,b=y", "z", "2", "x,b=y", "z"},
+		{"x", "y,b=z", "4", "x", "y,b=z"},
+	}, dmls.values)
+}
+
+func TestPrepareDMLsStatementMetrics(t *testing.T) {
+	t.Parallel()
+
+	table := &model.TableName{Schema: "s1", Table: "t1", TableID: 1}
+	cols := func(id, value int) []*model.Column {
+		return []*model.Column{{
+			Name:  "id",
+			Type:  mysql.TypeLong,
+			Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+			Value: id,
+		}, {
+			Name:  "v",
+			Type:  mysql.TypeLong,
+			Value: value,
+		}}
+	}
+	newEvent := func(commitTs uint64, rows ...*model.RowChangedEvent) *dmlsink.TxnCallbackableEvent {
+		for _, row := range rows {
+			row.Table = table
+			row.StartTs = commitTs - 1
+			row.CommitTs = commitTs
+			row.ReplicatingTs = 1
+		}
+		return &dmlsink.TxnCallbackableEvent{Event: &model.SingleTableTxn{Rows: rows}}
+	}
+
+	testCases := []struct {
+		name          string
+		batchDML      bool
+		safeMode      bool
+		statements    map[string]float64
+		statementRows map[string]float64
+	}{
+		{
+			name:          "row by row",
+			statements:    map[string]float64{"insert": 2, "update": 1, "delete": 1},
+			statementRows: map[string]float64{"insert": 2, "update": 1, "delete": 1},
+		},
+		{
+			name:          "row by row in safe mode",
+			safeMode:      true,
+			statements:    map[string]float64{"replace": 2, "update": 1, "delete": 1},
+			statementRows: map[string]float64{"replace": 2, "update": 1, "delete": 1},
+		},
+		{
+			name:          "batch dml",
+			batchDML:      true,
+			statements:    map[string]float64{"insert": 1, "update": 1, "delete": 1},
+			statementRows: map[string]float64{"insert": 2, "update": 1, "delete": 1},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i, tc := range testCases {
+		ms := newMySQLBackendWithoutDB(ctx)
+		labels := prometheus.Labels{"namespace": "default", "changefeed": fmt.Sprintf("dml-statements-%d", i)}
+		ms.metricTxnDMLStatements = txn.DMLStatements.MustCurryWith(labels)
+		ms.metricTxnDMLStatementRows = txn.DMLStatementRows.MustCurryWith(labels)
+		ms.cfg.BatchDMLEnable = tc.batchDML
+		ms.cfg.BatchDMLRowThreshold = 2
+		ms.cfg.SafeMode = tc.safeMode
+		ms.events = []*dmlsink.TxnCallbackableEvent{newEvent(10,
+			&model.RowChangedEvent{Columns: cols(1, 1)},
+			&model.RowChangedEvent{Columns: cols(2, 1)},
+			&model.RowChangedEvent{PreColumns: cols(3, 1), Columns: cols(3, 2)},
+			&model.RowChangedEvent{PreColumns: cols(4, 1)},
+		)}
+		ms.prepareDMLs()
+
+		for _, operation := range []string{"insert", "replace", "update", "delete"} {
+			require.Equal(t, tc.statements[operation],
+				testutil.ToFloat64(ms.metricTxnDMLStatements.WithLabelValues(operation)),
+				"%s: %s statements", tc.name, operation)
+			require.Equal(t, tc.statementRows[operation],
+				testutil.ToFloat64(ms.metricTxnDMLStatementRows.WithLabelValues(operation)),
+				"%s: %s statement rows", tc.name, operation)
+		}
+	}
+}
+
+func TestOnTxnEventMaxTxnBufferBytes(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := newMySQLBackendWithoutDB(ctx)
+	ms.cfg.MaxTxnRow = 1024
+	newEvent := func(rowCount int, rowSize int64) *dmlsink.TxnCallbackableEvent {
+		rows := make([]*model.RowChangedEvent, 0, rowCount)
+		for i := 0; i < rowCount; i++ {
+			rows = append(rows, &model.RowChangedEvent{ApproximateDataSize: rowSize})
+		}
+		return &dmlsink.TxnCallbackableEvent{Event: &model.SingleTableTxn{Rows: rows}}
+	}
+
+	// No limit by default.
+	require.False(t, ms.OnTxnEvent(newEvent(8, 1<<20)))
+
+	// A single large event requests a flush far below the row limit.
+	ms.events, ms.rows, ms.bufferedBytes = nil, 0, 0
+	ms.cfg.MaxTxnBufferBytes = 1 << 20
+	require.False(t, ms.OnTxnEvent(newEvent(2, 1024)))
+	require.True(t, ms.OnTxnEvent(newEvent(4, 1<<18)))
+	require.Equal(t, 6, ms.rows)
+	require.Equal(t, int64(2*1024+4*(1<<18)), ms.bufferedBytes)
+}
+
+func TestCloseAndFlush(t *testing.T) {
+	t.Parallel()
+
+	newBackend := func(execErr error) *mysqlBackend {
+		dbIndex := 0
+		mockGetDBConn := func(ctx context.Context, dsnStr string) (*sql.DB, error) {
+			defer func() { dbIndex++ }()
+			if dbIndex == 0 {
+				// test db
+				db, err := pmysql.MockTestDB(true)
+				require.Nil(t, err)
+				return db, nil
+			}
+			// normal db
+			db, mock := newTestMockDB(t)
+			mock.ExpectBegin()
+			exec := mock.ExpectExec("INSERT INTO `s1`.`t1` (`a`) VALUES (?)").WithArgs(1)
+			if execErr != nil {
+				exec.WillReturnError(execErr)
+				mock.ExpectRollback()
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			}
+			mock.ExpectClose()
+			return db, nil
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sinkURI, err := url.Parse(
+			"mysql://127.0.0.1:4000/?time-zone=UTC&worker-count=1&cache-prep-stmts=false&max-txn-row=1&dml-max-retry=1")
+		require.NoError(t, err)
+		backend, err := newMySQLBackend(ctx, model.DefaultChangeFeedID("test-changefeed"), sinkURI,
+			config.GetDefaultReplicaConfig(), mockGetDBConn)
+		require.NoError(t, err)
+		return backend
+	}
+	newEvent := func(called *bool) *dmlsink.TxnCallbackableEvent {
+		return &dmlsink.TxnCallbackableEvent{
+			Event: &model.SingleTableTxn{Rows: []*model.RowChangedEvent{{
+				StartTs:  1,
+				CommitTs: 2,
+				Table:    &model.TableName{Schema: "s1", Table: "t1", TableID: 1},
+				Columns: []*model.Column{{
+					Name:  "a",
+					Type:  mysql.TypeLong,
+					Flag:  model.HandleKeyFlag | model.PrimaryKeyFlag,
+					Value: 1,
+				}},
+			}}},
+			Callback: func() { *called = true },
+		}
+	}
+
+	// The buffered events are flushed and their callbacks are called.
+	backend := newBackend(nil)
+	called := false
+	_ = backend.OnTxnEvent(newEvent(&called))
+	require.NoError(t, backend.CloseAndFlush(context.Background()))
+	require.True(t, called)
+	require.Nil(t, backend.db)
+	require.NoError(t, backend.CloseAndFlush(context.Background()))
+
+	// The error of the flush is returned, and the backend is still closed.
+	backend = newBackend(&dmysql.MySQLError{Number: mysql.ErrNoSuchTable})
+	called = false
+	_ = backend.OnTxnEvent(newEvent(&called))
+	require.Error(t, backend.CloseAndFlush(context.Background()))
+	require.False(t, called)
+	require.Nil(t, backend.db)
+}
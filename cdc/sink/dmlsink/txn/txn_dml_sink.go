@@ -49,6 +49,9 @@ type dmlSink struct {
 
 	workers []*worker
 	cancel  func()
+	// closed is closed by Close to tell the workers to flush the pending
+	// events before exiting.
+	closed chan struct{}
 
 	wg   sync.WaitGroup
 	dead chan struct{}
@@ -72,19 +75,32 @@ func NewMySQLSink(
 	replicaConfig *config.ReplicaConfig,
 	errCh chan<- error,
 	conflictDetectorSlots uint64,
+	opts ...mysql.BackendOption,
 ) (*dmlSink, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	statistics := metrics.NewStatistics(ctx, changefeedID, sink.TxnSink)
 
-	backendImpls, err := mysql.NewMySQLBackends(ctx, changefeedID, sinkURI, replicaConfig, GetDBConnImpl, statistics)
-	if err != nil {
-		cancel()
-		return nil, err
-	}
-
-	backends := make([]backend, 0, len(backendImpls))
-	for _, impl := range backendImpls {
-		backends = append(backends, impl)
+	var backends []backend
+	if pmysql.IsDMLFileSink(sinkURI) {
+		backendImpls, err := mysql.NewFileBackends(changefeedID, sinkURI, replicaConfig, statistics)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		for _, impl := range backendImpls {
+			backends = append(backends, impl)
+		}
+	} else {
+		// The capability probes are shared by the changefeeds to the same downstream.
+		opts = append([]mysql.BackendOption{mysql.WithCapabilityCache(pmysql.DefaultCapabilityCache)}, opts...)
+		backendImpls, err := mysql.NewMySQLBackends(ctx, changefeedID, sinkURI, replicaConfig, GetDBConnImpl, statistics, opts...)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		for _, impl := range backendImpls {
+			backends = append(backends, impl)
+		}
 	}
 
 	s := newSink(ctx, changefeedID, backends, errCh, conflictDetectorSlots)
@@ -104,6 +120,7 @@ func newSink(ctx context.Context,
 	sink := &dmlSink{
 		workers: make([]*worker, 0, len(backends)),
 		cancel:  cancel,
+		closed:  make(chan struct{}),
 		dead:    make(chan struct{}),
 	}
 
@@ -115,7 +132,7 @@ func newSink(ctx context.Context,
 
 	g, ctx1 := errgroup.WithContext(ctx)
 	for i, backend := range backends {
-		w := newWorker(ctx1, changefeedID, i, backend, len(backends))
+		w := newWorker(ctx1, changefeedID, i, backend, len(backends), sink.closed)
 		txnCh := sink.alive.conflictDetector.GetOutChByCacheID(int64(i))
 		g.Go(func() error { return w.run(txnCh) })
 		sink.workers = append(sink.workers, w)
@@ -165,6 +182,11 @@ func (s *dmlSink) WriteEvents(txnEvents ...*dmlsink.TxnCallbackableEvent) error
 
 // Close closes the dmlSink. It won't wait for all pending items backend handled.
 func (s *dmlSink) Close() {
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
 	if s.cancel != nil {
 		s.cancel()
 	}
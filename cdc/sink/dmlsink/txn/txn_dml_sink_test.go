@@ -19,6 +19,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/cdc/sink/dmlsink"
 	"github.com/pingcap/tiflow/cdc/sink/tablesink/state"
@@ -28,6 +29,9 @@ import (
 type blackhole struct {
 	blockOnEvents int32
 	panicOnFlush  int32
+	errOnFlush    int32
+	closed        int32
+	flushOnClose  int32
 }
 
 func (b *blackhole) OnTxnEvent(e *dmlsink.TxnCallbackableEvent) bool {
@@ -46,6 +50,9 @@ func (b *blackhole) Flush(ctx context.Context) error {
 	if atomic.LoadInt32(&b.panicOnFlush) > 0 {
 		panic("blackhole panics")
 	}
+	if atomic.LoadInt32(&b.errOnFlush) > 0 {
+		return errors.New("blackhole flush fails")
+	}
 	return nil
 }
 
@@ -54,9 +61,17 @@ func (b *blackhole) MaxFlushInterval() time.Duration {
 }
 
 func (b *blackhole) Close() error {
+	atomic.AddInt32(&b.closed, 1)
 	return nil
 }
 
+func (b *blackhole) CloseAndFlush(ctx context.Context) error {
+	if ctx.Err() == nil {
+		atomic.AddInt32(&b.flushOnClose, 1)
+	}
+	return b.Close()
+}
+
 // TestTxnSinkNolocking checks TxnSink must be nonblocking even if the associated
 // backends can be blocked in OnTxnEvent.
 func TestTxnSinkNolocking(t *testing.T) {
@@ -103,3 +118,65 @@ func TestTxnSinkNolocking(t *testing.T) {
 	require.Equal(t, uint32(100), atomic.LoadUint32(&handled))
 	sink.Close()
 }
+
+// TestTxnSinkCloseFlushesBackends checks pending events of the backends are
+// flushed with a live context when TxnSink is closed.
+func TestTxnSinkCloseFlushesBackends(t *testing.T) {
+	t.Parallel()
+
+	bes := make([]backend, 0, 4)
+	for i := 0; i < 4; i++ {
+		bes = append(bes, &blackhole{})
+	}
+	errCh := make(chan error, 1)
+	sink := newSink(context.Background(),
+		model.DefaultChangeFeedID("test"), bes, errCh, DefaultConflictDetectorSlots)
+	sink.Close()
+
+	for _, be := range bes {
+		require.Equal(t, int32(1), atomic.LoadInt32(&be.(*blackhole).closed))
+		require.Equal(t, int32(1), atomic.LoadInt32(&be.(*blackhole).flushOnClose))
+	}
+}
+
+// TestTxnSinkNotFlushBackendsOnError checks the backends are closed without
+// flushing when the workers exit because of an error.
+func TestTxnSinkNotFlushBackendsOnError(t *testing.T) {
+	t.Parallel()
+
+	bes := make([]backend, 0, 4)
+	for i := 0; i < 4; i++ {
+		bes = append(bes, &blackhole{errOnFlush: 1})
+	}
+	errCh := make(chan error, 1)
+	sink := newSink(context.Background(),
+		model.DefaultChangeFeedID("test"), bes, errCh, DefaultConflictDetectorSlots)
+
+	sinkState := new(state.TableSinkState)
+	*sinkState = state.TableSinkSinking
+	require.NoError(t, sink.WriteEvents(&dmlsink.CallbackableEvent[*model.SingleTableTxn]{
+		Event: &model.SingleTableTxn{
+			Rows: []*model.RowChangedEvent{
+				{
+					Table:   &model.TableName{Schema: "test", Table: "t1"},
+					Columns: []*model.Column{{Name: "a", Value: 1}},
+				},
+			},
+		},
+		Callback:  func() {},
+		SinkState: sinkState,
+	}))
+	select {
+	case err := <-errCh:
+		require.ErrorContains(t, err, "blackhole flush fails")
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "no error is reported")
+	}
+	<-sink.Dead()
+	sink.Close()
+
+	for _, be := range bes {
+		require.Equal(t, int32(1), atomic.LoadInt32(&be.(*blackhole).closed))
+		require.Equal(t, int32(0), atomic.LoadInt32(&be.(*blackhole).flushOnClose))
+	}
+}
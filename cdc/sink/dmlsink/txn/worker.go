@@ -34,6 +34,9 @@ type worker struct {
 
 	ID      int
 	backend backend
+	// closed is closed when the sink is closed gracefully, the pending events
+	// are only flushed on exit in that case.
+	closed <-chan struct{}
 
 	// Metrics.
 	metricConflictDetectDuration prometheus.Observer
@@ -49,7 +52,7 @@ type worker struct {
 }
 
 func newWorker(ctx context.Context, changefeedID model.ChangeFeedID,
-	ID int, backend backend, workerCount int,
+	ID int, backend backend, workerCount int, closed <-chan struct{},
 ) *worker {
 	wid := fmt.Sprintf("%d", ID)
 
@@ -60,6 +63,7 @@ func newWorker(ctx context.Context, changefeedID model.ChangeFeedID,
 
 		ID:      ID,
 		backend: backend,
+		closed:  closed,
 
 		metricConflictDetectDuration: txn.ConflictDetectDuration.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
 		metricQueueDuration:          txn.QueueDuration.WithLabelValues(changefeedID.Namespace, changefeedID.ID),
@@ -73,14 +77,28 @@ func newWorker(ctx context.Context, changefeedID model.ChangeFeedID,
 	}
 }
 
+// closeFlushTimeout bounds the flush of the pending events when the worker
+// exits as the sink is closed.
+const closeFlushTimeout = 10 * time.Second
+
 // Continuously get events from txnCh and call backend flush based on conditions.
-func (w *worker) run(txnCh <-chan causality.TxnWithNotifier[*txnEvent]) error {
+func (w *worker) run(txnCh <-chan causality.TxnWithNotifier[*txnEvent]) (err error) {
 	defer func() {
-		if err := w.backend.Close(); err != nil {
+		var closeErr error
+		if err == nil && w.isClosed() {
+			// w.ctx is canceled already, so a new context is used to flush
+			// the events still pending in the backend.
+			ctx, cancel := context.WithTimeout(context.Background(), closeFlushTimeout)
+			closeErr = w.backend.CloseAndFlush(ctx)
+			cancel()
+		} else {
+			closeErr = w.backend.Close()
+		}
+		if closeErr != nil {
 			log.Info("Transaction dmlSink backend close fail",
 				zap.String("changefeedID", w.changefeed),
 				zap.Int("workerID", w.ID),
-				zap.Error(err))
+				zap.Error(closeErr))
 		}
 	}()
 	log.Info("Transaction dmlSink worker starts",
@@ -105,6 +123,12 @@ func (w *worker) run(txnCh <-chan causality.TxnWithNotifier[*txnEvent]) error {
 					delay := time.NewTimer(w.flushInterval)
 					for !needFlush {
 						select {
+						case <-w.ctx.Done():
+							delay.Stop()
+							log.Info("Transaction dmlSink worker exits as canceled",
+								zap.String("changefeedID", w.changefeed),
+								zap.Int("workerID", w.ID))
+							return nil
 						case txn := <-txnCh:
 							needFlush = w.onEvent(txn.TxnEvent, txn.PostTxnExecuted)
 						case <-delay.C:
@@ -137,6 +161,17 @@ func (w *worker) run(txnCh <-chan causality.TxnWithNotifier[*txnEvent]) error {
 	}
 }
 
+// isClosed returns true if the sink is closed gracefully, rather than canceled
+// because of an error of any worker.
+func (w *worker) isClosed() bool {
+	select {
+	case <-w.closed:
+		return true
+	default:
+		return false
+	}
+}
+
 // onEvent is called when a new event is received.
 // It returns true if the event is sent to backend.
 func (w *worker) onEvent(txn *txnEvent, postTxnExecuted func()) bool {
@@ -81,6 +81,15 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 18), // 10ms~1300s
 		}, []string{"namespace", "changefeed"})
 
+	SinkDMLBatchApproximateSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_sink_dml_batch_approximate_size",
+			Help:      "Bucketed histogram of the approximate size (bytes) of a DML batch",
+			Buckets:   prometheus.ExponentialBuckets(1024, 2, 16), // 1KB~32MB
+		}, []string{"namespace", "changefeed"})
+
 	PrepareStatementErrors = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "ticdc",
@@ -88,6 +97,70 @@ var (
 			Name:      "txn_prepare_statement_errors",
 			Help:      "Prepare statement errors",
 		}, []string{"namespace", "changefeed"})
+
+	PingFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_ping_failures",
+			Help:      "Failures of pinging the downstream before flushing DMLs",
+		}, []string{"namespace", "changefeed"})
+
+	InterleavedStartTs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_interleaved_start_ts",
+			Help:      "Flushes in which events of different transactions are interleaved",
+		}, []string{"namespace", "changefeed"})
+
+	InsertTranslatedEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_insert_translated_events",
+			Help:      "Events whose inserted rows are written by INSERT statements",
+		}, []string{"namespace", "changefeed"})
+
+	ReplaceEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_replace_events",
+			Help:      "Events whose inserted rows are written by REPLACE statements",
+		}, []string{"namespace", "changefeed"})
+
+	RowsAffectedMismatches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_rows_affected_mismatches",
+			Help:      "Flushes whose rows affected in the downstream mismatch the flushed rows",
+		}, []string{"namespace", "changefeed"})
+
+	RowsAffectedToleratedMismatches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_rows_affected_tolerated_mismatches",
+			Help:      "Flushes whose missing rows affected can be explained by deleting absent rows",
+		}, []string{"namespace", "changefeed"})
+
+	DMLStatements = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_dml_statements",
+			Help:      "Generated DML statements by operation, a multi-row statement counts as one",
+		}, []string{"namespace", "changefeed", "operation"})
+
+	DMLStatementRows = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ticdc",
+			Subsystem: "sink",
+			Name:      "txn_dml_statement_rows",
+			Help:      "Rows written by the generated DML statements by operation",
+		}, []string{"namespace", "changefeed", "operation"})
 )
 
 // InitMetrics registers all metrics in this file.
@@ -99,5 +172,14 @@ func InitMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(WorkerHandledRows)
 	registry.MustRegister(SinkDMLBatchCommit)
 	registry.MustRegister(SinkDMLBatchCallback)
+	registry.MustRegister(SinkDMLBatchApproximateSize)
 	registry.MustRegister(PrepareStatementErrors)
+	registry.MustRegister(PingFailures)
+	registry.MustRegister(InterleavedStartTs)
+	registry.MustRegister(InsertTranslatedEvents)
+	registry.MustRegister(ReplaceEvents)
+	registry.MustRegister(RowsAffectedMismatches)
+	registry.MustRegister(RowsAffectedToleratedMismatches)
+	registry.MustRegister(DMLStatements)
+	registry.MustRegister(DMLStatementRows)
 }
@@ -59,6 +59,8 @@ import (
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
 
@@ -277,6 +279,7 @@ func main() {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			http.Handle("/metrics", promhttp.Handler())
 			if err := http.ListenAndServe(":6060", nil); err != nil {
 				log.Panic("Error starting pprof", zap.Error(err))
 			}
@@ -562,7 +565,8 @@ func (c *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 	case config.ProtocolOpen, config.ProtocolDefault:
 		decoder, err = open.NewBatchDecoder(ctx, c.option.codecConfig, c.upstreamTiDB)
 	case config.ProtocolCanalJSON:
-		decoder, err = canal.NewBatchDecoder(ctx, c.option.codecConfig, c.upstreamTiDB)
+		decoder, err = canal.NewBatchDecoder(ctx, c.option.codecConfig, c.upstreamTiDB,
+			canal.WithMetricsRegisterer(prometheus.DefaultRegisterer))
 		if err != nil {
 			return err
 		}
@@ -49,6 +49,7 @@ import (
 	"github.com/pingcap/tiflow/pkg/spanz"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
@@ -412,7 +413,8 @@ func (c *Consumer) HandleMsg(msg pulsar.Message) error {
 
 	switch c.codecConfig.Protocol {
 	case config.ProtocolCanalJSON:
-		decoder, err = canal.NewBatchDecoder(ctx, c.codecConfig, nil)
+		decoder, err = canal.NewBatchDecoder(ctx, c.codecConfig, nil,
+			canal.WithMetricsRegisterer(prometheus.DefaultRegisterer))
 		if err != nil {
 			return err
 		}
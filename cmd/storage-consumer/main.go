@@ -50,6 +50,8 @@ import (
 	"github.com/pingcap/tiflow/pkg/spanz"
 	putil "github.com/pingcap/tiflow/pkg/util"
 	"github.com/pingcap/tiflow/pkg/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
 
@@ -314,7 +316,8 @@ func (c *consumer) emitDMLEvents(
 		// Always enable tidb extension for canal-json protocol
 		// because we need to get the commit ts from the extension field.
 		c.codecCfg.EnableTiDBExtension = true
-		decoder, err = canal.NewBatchDecoder(ctx, c.codecCfg, nil)
+		decoder, err = canal.NewBatchDecoder(ctx, c.codecCfg, nil,
+			canal.WithMetricsRegisterer(prometheus.DefaultRegisterer))
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -646,6 +649,7 @@ func main() {
 
 	if enableProfiling {
 		go func() {
+			http.Handle("/metrics", promhttp.Handler())
 			server := &http.Server{
 				Addr:              ":6060",
 				ReadHeaderTimeout: 5 * time.Second,
@@ -34,6 +34,7 @@ import (
 	regexprrouter "github.com/pingcap/tidb/pkg/util/regexpr-router"
 	router "github.com/pingcap/tidb/pkg/util/table-router"
 	"github.com/pingcap/tiflow/dm/config/dbconfig"
+	"github.com/pingcap/tiflow/dm/pkg/cputil"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/storage"
 	"github.com/pingcap/tiflow/dm/pkg/terror"
@@ -62,6 +63,8 @@ const (
 	TiDBLightningCheckpointPrefix = "tidb_lightning_checkpoint_"
 )
 
+var checkpointTableSuffixRegexp = regexp.MustCompile(`^[0-9A-Za-z_]*$`)
+
 // FetchTimeZoneSetting fetch target db global time_zone setting.
 // TODO: move GetTimeZoneOffset and FormatTimeZoneOffset from TiDB to tiflow.
 func FetchTimeZoneSetting(ctx context.Context, db *sql.DB) (string, error) {
@@ -115,6 +118,8 @@ type SubTaskConfig struct {
 	ServerID   uint32 `toml:"server-id" json:"server-id"`
 	Flavor     string `toml:"flavor" json:"flavor"`
 	MetaSchema string `toml:"meta-schema" json:"meta-schema"`
+	// appended to the syncer checkpoint table name, see SyncerCheckpointTableName
+	CheckpointTableSuffix string `toml:"checkpoint-table-suffix" json:"checkpoint-table-suffix"`
 	// deprecated
 	HeartbeatUpdateInterval int `toml:"heartbeat-update-interval" json:"heartbeat-update-interval"`
 	// deprecated
@@ -328,6 +333,9 @@ func (c *SubTaskConfig) Adjust(verifyDecryptPassword bool) error {
 	if c.MetaSchema == "" {
 		c.MetaSchema = defaultMetaSchema
 	}
+	if !checkpointTableSuffixRegexp.MatchString(c.CheckpointTableSuffix) {
+		return terror.ErrConfigInvalidCheckpointTableSuffix.Generate(c.CheckpointTableSuffix)
+	}
 
 	// adjust dir, no need to do for load&sync mode because it needs its own s3 repository
 	if HasLoad(c.Mode) && c.Mode != ModeLoadSync {
@@ -483,6 +491,12 @@ func (c *SubTaskConfig) DecryptPassword() (*SubTaskConfig, error) {
 	return clone, nil
 }
 
+// SyncerCheckpointTableName returns the name of syncer's checkpoint table, the
+// CheckpointTableSuffix is appended if set.
+func (c *SubTaskConfig) SyncerCheckpointTableName() string {
+	return cputil.SyncerCheckpoint(c.Name) + c.CheckpointTableSuffix
+}
+
 // Clone returns a replica of SubTaskConfig.
 func (c *SubTaskConfig) Clone() (*SubTaskConfig, error) {
 	content, err := c.Toml()
@@ -149,6 +149,14 @@ func TestSubTaskAdjustFail(t *testing.T) {
 			},
 			"Message: online scheme rtc not supported",
 		},
+		{
+			func() *SubTaskConfig {
+				cfg := newSubTaskConfig()
+				cfg.CheckpointTableSuffix = "a`; DROP TABLE t; --"
+				return cfg
+			},
+			"Message: invalid checkpoint-table-suffix",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -158,6 +166,13 @@ func TestSubTaskAdjustFail(t *testing.T) {
 	}
 }
 
+func TestSubTaskSyncerCheckpointTableName(t *testing.T) {
+	cfg := &SubTaskConfig{Name: "test-task"}
+	require.Equal(t, "test-task_syncer_checkpoint", cfg.SyncerCheckpointTableName())
+	cfg.CheckpointTableSuffix = "_pipeline_2"
+	require.Equal(t, "test-task_syncer_checkpoint_pipeline_2", cfg.SyncerCheckpointTableName())
+}
+
 func TestSubTaskBlockAllowList(t *testing.T) {
 	filterRules1 := &filter.Rules{
 		DoDBs: []string{"s1"},
@@ -399,6 +399,13 @@ type SyncerConfig struct {
 	QueueSize   int    `yaml:"queue-size" toml:"queue-size" json:"queue-size"`
 	// checkpoint flush interval in seconds.
 	CheckpointFlushInterval int `yaml:"checkpoint-flush-interval" toml:"checkpoint-flush-interval" json:"checkpoint-flush-interval"`
+	// external storage URL to mirror the checkpoints after each flush, such as s3://bucket/prefix.
+	// the downstream database is still the source of truth, mirroring is best-effort.
+	CheckpointMirrorStorage string `yaml:"checkpoint-mirror-storage,omitempty" toml:"checkpoint-mirror-storage,omitempty" json:"checkpoint-mirror-storage,omitempty"`
+	// max attempts to execute the checkpoint flush transaction when meeting retryable errors, 0 means using the default value.
+	CheckpointFlushRetryCount int `yaml:"checkpoint-flush-retry-count,omitempty" toml:"checkpoint-flush-retry-count,omitempty" json:"checkpoint-flush-retry-count,omitempty"`
+	// wait interval in seconds before retrying the checkpoint flush, it increases linearly with the retry times. 0 means using the default value.
+	CheckpointFlushRetryInterval int `yaml:"checkpoint-flush-retry-interval,omitempty" toml:"checkpoint-flush-retry-interval,omitempty" json:"checkpoint-flush-retry-interval,omitempty"`
 	// TODO: add this two new config items for openapi.
 	Compact      bool `yaml:"compact" toml:"compact" json:"compact"`
 	MultipleRows bool `yaml:"multiple-rows" toml:"multiple-rows" json:"multiple-rows"`
@@ -513,6 +520,8 @@ type TaskConfig struct {
 	// we store detail status in meta
 	// don't save configuration into it
 	MetaSchema string `yaml:"meta-schema" toml:"meta-schema" json:"meta-schema"`
+	// appended to the syncer checkpoint table name to isolate checkpoints of tasks with the same name
+	CheckpointTableSuffix string `yaml:"checkpoint-table-suffix" toml:"checkpoint-table-suffix" json:"checkpoint-table-suffix"`
 	// deprecated
 	EnableHeartbeat bool `yaml:"enable-heartbeat" toml:"enable-heartbeat" json:"enable-heartbeat"`
 	// deprecated
@@ -1249,6 +1258,7 @@ type TaskConfigForDowngrade struct {
 	ShadowTableRules          []string                     `yaml:"shadow-table-rules,omitempty"`
 	TrashTableRules           []string                     `yaml:"trash-table-rules,omitempty"`
 	StrictOptimisticShardMode bool                         `yaml:"strict-optimistic-shard-mode,omitempty"`
+	CheckpointTableSuffix     string                       `yaml:"checkpoint-table-suffix,omitempty"`
 }
 
 // NewTaskConfigForDowngrade create new TaskConfigForDowngrade.
@@ -1284,6 +1294,7 @@ func NewTaskConfigForDowngrade(taskConfig *TaskConfig) *TaskConfigForDowngrade {
 		OnlineDDL:                 taskConfig.OnlineDDL,
 		ShadowTableRules:          taskConfig.ShadowTableRules,
 		TrashTableRules:           taskConfig.TrashTableRules,
+		CheckpointTableSuffix:     taskConfig.CheckpointTableSuffix,
 	}
 }
 
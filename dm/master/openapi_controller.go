@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tiflow/dm/master/workerrpc"
 	"github.com/pingcap/tiflow/dm/openapi"
 	"github.com/pingcap/tiflow/dm/pb"
+	"github.com/pingcap/tiflow/dm/pkg/cputil"
 	"github.com/pingcap/tiflow/dm/pkg/ha"
 	"github.com/pingcap/tiflow/dm/pkg/terror"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -479,7 +480,12 @@ func (s *Server) deleteTask(ctx context.Context, taskName string, force bool) er
 		}
 	}
 	metaSchema := *task.MetaSchema
-	err = s.removeMetaData(ctx, taskName, metaSchema, toDBCfg)
+	syncerCheckpointTable := cputil.SyncerCheckpoint(taskName)
+	for _, stCfg := range s.scheduler.GetSubTaskCfgsByTask(taskName) {
+		syncerCheckpointTable = stCfg.SyncerCheckpointTableName()
+		break
+	}
+	err = s.removeMetaData(ctx, taskName, metaSchema, syncerCheckpointTable, toDBCfg)
 	if err != nil {
 		if !ignoreCannotConnectError(err) {
 			return terror.Annotate(err, "while removing metadata")
@@ -716,7 +722,7 @@ func (s *Server) startTask(ctx context.Context, taskName string, req openapi.Sta
 		defer release()
 		metaSchema := needStartSubTaskList[0].MetaSchema
 		targetDB := needStartSubTaskList[0].To
-		err = s.removeMetaData(ctx, taskName, metaSchema, &targetDB)
+		err = s.removeMetaData(ctx, taskName, metaSchema, needStartSubTaskList[0].SyncerCheckpointTableName(), &targetDB)
 		if err != nil {
 			return terror.Annotate(err, "while removing metadata")
 		}
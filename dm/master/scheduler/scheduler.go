@@ -1122,17 +1122,17 @@ func (s *Scheduler) getSubTaskCfgByTaskSource(task, source string) *config.SubTa
 	return &clone
 }
 
-// GetDownstreamMetaByTask gets downstream db config and meta config by task name.
-func (s *Scheduler) GetDownstreamMetaByTask(task string) (*dbconfig.DBConfig, string) {
+// GetDownstreamMetaByTask gets downstream db config, meta config and the name of syncer's checkpoint table by task name.
+func (s *Scheduler) GetDownstreamMetaByTask(task string) (*dbconfig.DBConfig, string, string) {
 	v, ok := s.subTaskCfgs.Load(task)
 	if !ok {
-		return nil, ""
+		return nil, "", ""
 	}
 	cfgM := v.(map[string]config.SubTaskConfig)
 	for _, cfg := range cfgM {
-		return cfg.To.Clone(), cfg.MetaSchema
+		return cfg.To.Clone(), cfg.MetaSchema, cfg.SyncerCheckpointTableName()
 	}
-	return nil, ""
+	return nil, "", ""
 }
 
 // GetSubTaskCfgsByTask gets subtask configs' map by task name.
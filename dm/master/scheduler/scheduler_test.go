@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tiflow/dm/config/security"
 	"github.com/pingcap/tiflow/dm/master/workerrpc"
 	"github.com/pingcap/tiflow/dm/pb"
+	"github.com/pingcap/tiflow/dm/pkg/cputil"
 	"github.com/pingcap/tiflow/dm/pkg/ha"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/terror"
@@ -645,17 +646,19 @@ func (t *testSchedulerSuite) subTaskCfgExist(s *Scheduler, expectCfg config.SubT
 
 func (t *testSchedulerSuite) downstreamMetaNotExist(s *Scheduler, task string) {
 	t.T().Helper()
-	dbConfig, metaConfig := s.GetDownstreamMetaByTask(task)
+	dbConfig, metaConfig, checkpointTable := s.GetDownstreamMetaByTask(task)
 	require.Nil(t.T(), dbConfig)
 	require.Equal(t.T(), "", metaConfig)
+	require.Equal(t.T(), "", checkpointTable)
 }
 
 func (t *testSchedulerSuite) downstreamMetaExist(s *Scheduler, task string, expectDBCfg dbconfig.DBConfig, expectMetaConfig string) {
 	t.T().Helper()
-	dbConfig, metaConfig := s.GetDownstreamMetaByTask(task)
+	dbConfig, metaConfig, checkpointTable := s.GetDownstreamMetaByTask(task)
 	require.NotNil(t.T(), dbConfig)
 	require.Equal(t.T(), expectDBCfg, *dbConfig)
 	require.Equal(t.T(), expectMetaConfig, metaConfig)
+	require.Equal(t.T(), cputil.SyncerCheckpoint(task), checkpointTable)
 }
 
 func (t *testSchedulerSuite) workerNotExist(s *Scheduler, worker string) {
@@ -569,7 +569,7 @@ func (s *Server) StartTask(ctx context.Context, req *pb.StartTaskRequest) (*pb.S
 				return respWithErr(terror.Annotate(terror.ErrSchedulerSubTaskExist.Generate(cfg.Name, sources),
 					"while remove-meta is true"))
 			}
-			err = s.removeMetaData(ctx, cfg.Name, cfg.MetaSchema, cfg.TargetDB)
+			err = s.removeMetaData(ctx, cfg.Name, cfg.MetaSchema, stCfgs[0].SyncerCheckpointTableName(), cfg.TargetDB)
 			if err != nil {
 				return respWithErr(terror.Annotate(err, "while removing metadata"))
 			}
@@ -1765,7 +1765,9 @@ func withHost(addr string) string {
 	return addr
 }
 
-func (s *Server) removeMetaData(ctx context.Context, taskName, metaSchema string, toDBCfg *dbconfig.DBConfig) error {
+// removeMetaData removes the meta data of the task in downstream, syncerCheckpointTable is the
+// name of syncer's checkpoint table, see SubTaskConfig.SyncerCheckpointTableName.
+func (s *Server) removeMetaData(ctx context.Context, taskName, metaSchema, syncerCheckpointTable string, toDBCfg *dbconfig.DBConfig) error {
 	failpoint.Inject("MockSkipRemoveMetaData", func() {
 		failpoint.Return(nil)
 	})
@@ -1811,7 +1813,7 @@ func (s *Server) removeMetaData(ctx context.Context, taskName, metaSchema string
 	sqls = append(sqls, fmt.Sprintf("DROP TABLE IF EXISTS %s",
 		dbutil.TableName(metaSchema, cputil.LightningCheckpoint(taskName))))
 	sqls = append(sqls, fmt.Sprintf("DROP TABLE IF EXISTS %s",
-		dbutil.TableName(metaSchema, cputil.SyncerCheckpoint(taskName))))
+		dbutil.TableName(metaSchema, syncerCheckpointTable)))
 	sqls = append(sqls, fmt.Sprintf("DROP TABLE IF EXISTS %s",
 		dbutil.TableName(metaSchema, cputil.SyncerShardMeta(taskName))))
 	sqls = append(sqls, fmt.Sprintf("DROP TABLE IF EXISTS %s",
@@ -52,7 +52,7 @@ type Optimist struct {
 }
 
 // NewOptimist creates a new Optimist instance.
-func NewOptimist(pLogger *log.Logger, getDownstreamMetaFunc func(string) (*dbconfig.DBConfig, string)) *Optimist {
+func NewOptimist(pLogger *log.Logger, getDownstreamMetaFunc func(string) (*dbconfig.DBConfig, string, string)) *Optimist {
 	return &Optimist{
 		logger: pLogger.WithFields(zap.String("component", "shard DDL optimist")),
 		closed: true,
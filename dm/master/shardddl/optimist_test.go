@@ -1228,6 +1228,6 @@ func (t *testOptimistSuite) TestBuildLockWithInitSchema() {
 	o.tk.Init(stm)
 }
 
-func getDownstreamMeta(string) (*dbconfig.DBConfig, string) {
-	return nil, ""
+func getDownstreamMeta(string) (*dbconfig.DBConfig, string, string) {
+	return nil, "", ""
 }
@@ -16,6 +16,7 @@ package dumpling
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -176,6 +177,68 @@ func parseMetaDataByReader(filename, flavor string, rd io.Reader) (*binlog.Locat
 	return locPtr, locPtr2, nil
 }
 
+// MetaDataJSONLocation is a binlog location in the JSON metadata file.
+type MetaDataJSONLocation struct {
+	BinLogName string `json:"binlog-name"`
+	BinLogPos  uint32 `json:"binlog-pos"`
+	BinLogGTID string `json:"binlog-gtid"`
+}
+
+// MetaDataJSON is the content of the JSON metadata file, which is an alternative
+// of mydumper's output meta file for checkpoints generated by other tools.
+type MetaDataJSON struct {
+	MetaDataJSONLocation
+	ExitSafeModeLocation *MetaDataJSONLocation `json:"exit-safe-mode-location,omitempty"`
+}
+
+// ParseMetaDataJSON parses the JSON metadata file and returns binlog location
+// and the location to exit safe mode, the latter may be nil.
+func ParseMetaDataJSON(
+	ctx context.Context,
+	dir string,
+	filename string,
+	flavor string,
+	extStorage brstorage.ExternalStorage,
+) (*binlog.Location, *binlog.Location, error) {
+	data, err := storage.ReadFile(ctx, dir, filename, extStorage)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseMetaDataJSON(filename, flavor, data)
+}
+
+func parseMetaDataJSON(filename, flavor string, data []byte) (*binlog.Location, *binlog.Location, error) {
+	var meta MetaDataJSON
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, nil, fmt.Errorf("file %s invalid format: %w", filename, err)
+	}
+
+	toLocation := func(l *MetaDataJSONLocation) (*binlog.Location, error) {
+		if len(l.BinLogName) == 0 || l.BinLogPos == uint32(0) {
+			return nil, terror.ErrMetadataNoBinlogLoc.Generate(filename)
+		}
+		gset, err := gtid.ParserGTID(flavor, l.BinLogGTID)
+		if err != nil {
+			return nil, fmt.Errorf("file %s invalid GTID set %s: %w", filename, l.BinLogGTID, err)
+		}
+		loc := binlog.NewLocation(mysql.Position{Name: l.BinLogName, Pos: l.BinLogPos}, gset)
+		return &loc, nil
+	}
+
+	loc, err := toLocation(&meta.MetaDataJSONLocation)
+	if err != nil {
+		return nil, nil, err
+	}
+	if meta.ExitSafeModeLocation == nil {
+		return loc, nil, nil
+	}
+	loc2, err := toLocation(meta.ExitSafeModeLocation)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loc, loc2, nil
+}
+
 func readFollowingGTIDs(br *bufio.Reader, flavor string) (string, error) {
 	var following strings.Builder
 	for {
@@ -255,6 +255,102 @@ Finished dump at: 2020-12-02 17:13:56
 	require.True(t, terror.ErrMetadataNoBinlogLoc.Equal(err))
 }
 
+func TestParseMetaDataJSON(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	fname := "metadata.json"
+
+	testCases := []struct {
+		flavor   string
+		source   string
+		pos      mysql.Position
+		gsetStr  string
+		loc2     bool
+		pos2     mysql.Position
+		gsetStr2 string
+	}{
+		{
+			mysql.MySQLFlavor,
+			`{"binlog-name": "bin.000001", "binlog-pos": 2479, "binlog-gtid": "97b5142f-e19c-11e8-808c-0242ac110005:1-13"}`,
+			mysql.Position{Name: "bin.000001", Pos: 2479},
+			"97b5142f-e19c-11e8-808c-0242ac110005:1-13",
+			false,
+			mysql.Position{},
+			"",
+		},
+		{
+			mysql.MySQLFlavor,
+			`{
+	"binlog-name": "bin.000001",
+	"binlog-pos": 2479,
+	"binlog-gtid": "97b5142f-e19c-11e8-808c-0242ac110005:1-13",
+	"exit-safe-mode-location": {
+		"binlog-name": "bin.000002",
+		"binlog-pos": 4,
+		"binlog-gtid": "97b5142f-e19c-11e8-808c-0242ac110005:1-20"
+	}
+}`,
+			mysql.Position{Name: "bin.000001", Pos: 2479},
+			"97b5142f-e19c-11e8-808c-0242ac110005:1-13",
+			true,
+			mysql.Position{Name: "bin.000002", Pos: 4},
+			"97b5142f-e19c-11e8-808c-0242ac110005:1-20",
+		},
+		{
+			mysql.MariaDBFlavor,
+			`{
+	"binlog-name": "mariadb-bin.000016",
+	"binlog-pos": 475,
+	"binlog-gtid": "0-1-2",
+	"exit-safe-mode-location": {
+		"binlog-name": "mariadb-bin.000016",
+		"binlog-pos": 1024,
+		"binlog-gtid": "0-1-5"
+	}
+}`,
+			mysql.Position{Name: "mariadb-bin.000016", Pos: 475},
+			"0-1-2",
+			true,
+			mysql.Position{Name: "mariadb-bin.000016", Pos: 1024},
+			"0-1-5",
+		},
+	}
+	ctx := context.Background()
+	for _, tc := range testCases {
+		err := os.WriteFile(path.Join(dir, fname), []byte(tc.source), 0o644)
+		require.NoError(t, err)
+		loc, loc2, err := ParseMetaDataJSON(ctx, dir, fname, tc.flavor, nil)
+		require.NoError(t, err)
+		require.Equal(t, tc.pos, loc.Position)
+		gs, _ := gtid.ParserGTID(tc.flavor, tc.gsetStr)
+		require.Equal(t, gs, loc.GetGTID())
+		if tc.loc2 {
+			require.Equal(t, tc.pos2, loc2.Position)
+			gs2, _ := gtid.ParserGTID(tc.flavor, tc.gsetStr2)
+			require.Equal(t, gs2, loc2.GetGTID())
+		} else {
+			require.Nil(t, loc2)
+		}
+	}
+
+	// invalid GTID for the flavor
+	err := os.WriteFile(path.Join(dir, fname), []byte(
+		`{"binlog-name": "bin.000001", "binlog-pos": 2479, "binlog-gtid": "97b5142f-e19c-11e8-808c-0242ac110005:1-13"}`), 0o644)
+	require.NoError(t, err)
+	_, _, err = ParseMetaDataJSON(ctx, dir, fname, mysql.MariaDBFlavor, nil)
+	require.ErrorContains(t, err, "invalid GTID set")
+
+	// no binlog location
+	err = os.WriteFile(path.Join(dir, fname), []byte(`{"binlog-gtid": ""}`), 0o644)
+	require.NoError(t, err)
+	_, _, err = ParseMetaDataJSON(ctx, dir, fname, mysql.MySQLFlavor, nil)
+	require.True(t, terror.ErrMetadataNoBinlogLoc.Equal(err))
+
+	// not exist
+	_, _, err = ParseMetaDataJSON(ctx, dir, "not-exist.json", mysql.MySQLFlavor, nil)
+	require.Error(t, err)
+}
+
 func TestParseArgs(t *testing.T) {
 	t.Parallel()
 	logger := log.L()
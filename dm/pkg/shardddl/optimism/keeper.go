@@ -30,6 +30,8 @@ import (
 type DownstreamMeta struct {
 	dbConfig *dbconfig.DBConfig
 	meta     string
+	// checkpointTable is the name of syncer's checkpoint table in meta schema.
+	checkpointTable string
 }
 
 // LockKeeper used to keep and handle DDL lock conveniently.
@@ -40,13 +42,15 @@ type LockKeeper struct {
 	locks map[string]*Lock // lockID -> Lock
 
 	downstreamMetaMap     map[string]*DownstreamMeta
-	getDownstreamMetaFunc func(string) (*dbconfig.DBConfig, string)
+	getDownstreamMetaFunc func(string) (*dbconfig.DBConfig, string, string)
 	// lockID -> column name -> source -> upSchema -> upTable -> int
 	dropColumns map[string]map[string]map[string]map[string]map[string]DropColumnStage
 }
 
 // NewLockKeeper creates a new LockKeeper instance.
-func NewLockKeeper(getDownstreamMetaFunc func(string) (*dbconfig.DBConfig, string)) *LockKeeper {
+// getDownstreamMetaFunc returns the downstream db config, the meta schema and
+// the name of syncer's checkpoint table of the task.
+func NewLockKeeper(getDownstreamMetaFunc func(string) (*dbconfig.DBConfig, string, string)) *LockKeeper {
 	return &LockKeeper{
 		locks:                 make(map[string]*Lock),
 		downstreamMetaMap:     make(map[string]*DownstreamMeta),
@@ -65,11 +69,11 @@ func (lk *LockKeeper) getDownstreamMeta(task string) (*DownstreamMeta, error) {
 		return downstreamMeta, nil
 	}
 
-	dbConfig, meta := lk.getDownstreamMetaFunc(task)
+	dbConfig, meta, checkpointTable := lk.getDownstreamMetaFunc(task)
 	if dbConfig == nil {
 		return nil, terror.ErrMasterOptimisticDownstreamMetaNotFound.Generate(task)
 	}
-	downstreamMeta := &DownstreamMeta{dbConfig: dbConfig, meta: meta}
+	downstreamMeta := &DownstreamMeta{dbConfig: dbConfig, meta: meta, checkpointTable: checkpointTable}
 	lk.downstreamMetaMap[task] = downstreamMeta
 	return downstreamMeta, nil
 }
@@ -478,8 +478,8 @@ func (t *testKeeper) TestTargetTablesForTask(c *C) {
 	})
 }
 
-func getDownstreamMeta(string) (*dbconfig.DBConfig, string) {
-	return nil, ""
+func getDownstreamMeta(string) (*dbconfig.DBConfig, string, string) {
+	return nil, "", ""
 }
 
 func (t *testKeeper) TestGetDownstreamMeta(c *C) {
@@ -488,12 +488,12 @@ func (t *testKeeper) TestGetDownstreamMeta(c *C) {
 		task2 = "hihihi"
 		task3 = "hehehe"
 	)
-	getDownstreamMetaFunc := func(task string) (*dbconfig.DBConfig, string) {
+	getDownstreamMetaFunc := func(task string) (*dbconfig.DBConfig, string, string) {
 		switch task {
 		case task1, task2:
-			return &dbconfig.DBConfig{}, "meta"
+			return &dbconfig.DBConfig{}, "meta", "checkpoint"
 		default:
-			return nil, ""
+			return nil, "", ""
 		}
 	}
 
@@ -28,7 +28,6 @@ import (
 	"github.com/pingcap/tidb/pkg/util/schemacmp"
 	"github.com/pingcap/tiflow/dm/master/metrics"
 	"github.com/pingcap/tiflow/dm/pkg/conn"
-	"github.com/pingcap/tiflow/dm/pkg/cputil"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/terror"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -144,7 +143,7 @@ func (l *Lock) FetchTableInfos(task, source, schema, table string) (*model.Table
 	ctx, cancel := context.WithTimeout(context.Background(), dbutil.DefaultTimeout)
 	defer cancel()
 
-	query := `SELECT table_info FROM ` + dbutil.TableName(l.downstreamMeta.meta, cputil.SyncerCheckpoint(task)) + ` WHERE id = ? AND cp_schema = ? AND cp_table = ?`
+	query := `SELECT table_info FROM ` + dbutil.TableName(l.downstreamMeta.meta, l.downstreamMeta.checkpointTable) + ` WHERE id = ? AND cp_schema = ? AND cp_table = ?`
 	row := db.DB.QueryRowContext(ctx, query, source, schema, table)
 	if row.Err() != nil {
 		return nil, terror.ErrDBExecuteFailed.Delegate(row.Err(), query)
@@ -2084,7 +2084,7 @@ func (t *testLock) TestFetchTableInfo(c *C) {
 		tts = []TargetTable{
 			newTargetTable(task, source, downSchema, downTable, tables),
 		}
-		query = fmt.Sprintf("SELECT table_info FROM `%s`.`%s` WHERE id = \\? AND cp_schema = \\? AND cp_table = \\?", meta, cputil.SyncerCheckpoint(task))
+		query = fmt.Sprintf("SELECT table_info FROM `%s`.`%s` WHERE id = \\? AND cp_schema = \\? AND cp_table = \\?", meta, cputil.SyncerCheckpoint(task)+"_suffix")
 	)
 
 	// nil downstream meta
@@ -2094,7 +2094,7 @@ func (t *testLock) TestFetchTableInfo(c *C) {
 	c.Assert(ti, IsNil)
 
 	// table info not exist
-	l = NewLock(etcdTestCli, ID, task, downSchema, downTable, schemacmp.Encode(ti0), tts, &DownstreamMeta{dbConfig: &dbconfig.DBConfig{}, meta: meta})
+	l = NewLock(etcdTestCli, ID, task, downSchema, downTable, schemacmp.Encode(ti0), tts, &DownstreamMeta{dbConfig: &dbconfig.DBConfig{}, meta: meta, checkpointTable: cputil.SyncerCheckpoint(task) + "_suffix"})
 	conn.DefaultDBProvider = &conn.DefaultDBProviderImpl{}
 	mock := conn.InitMockDB(c)
 	mock.ExpectQuery(query).WithArgs(source, schema, tbls[0]).WillReturnRows(sqlmock.NewRows([]string{"table_info"}))
@@ -2103,7 +2103,7 @@ func (t *testLock) TestFetchTableInfo(c *C) {
 	c.Assert(ti, IsNil)
 
 	// null table info
-	l = NewLock(etcdTestCli, ID, task, downSchema, downTable, schemacmp.Encode(ti0), tts, &DownstreamMeta{dbConfig: &dbconfig.DBConfig{}, meta: meta})
+	l = NewLock(etcdTestCli, ID, task, downSchema, downTable, schemacmp.Encode(ti0), tts, &DownstreamMeta{dbConfig: &dbconfig.DBConfig{}, meta: meta, checkpointTable: cputil.SyncerCheckpoint(task) + "_suffix"})
 	conn.DefaultDBProvider = &conn.DefaultDBProviderImpl{}
 	mock = conn.InitMockDB(c)
 	mock.ExpectQuery(query).WithArgs(source, schema, tbls[0]).WillReturnRows(sqlmock.NewRows([]string{"table_info"}).AddRow("null"))
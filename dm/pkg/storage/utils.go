@@ -159,6 +159,17 @@ func ReadFile(ctx context.Context, dir, fileName string, storage bstorage.Extern
 	return storage.ReadFile(ctx, fileName)
 }
 
+func FileExists(ctx context.Context, dir, fileName string, storage bstorage.ExternalStorage) (bool, error) {
+	var err error
+	if storage == nil {
+		storage, err = CreateStorage(ctx, dir)
+		if err != nil {
+			return false, err
+		}
+	}
+	return storage.FileExists(ctx, fileName)
+}
+
 func OpenFile(ctx context.Context, dir, fileName string, storage bstorage.ExternalStorage) (bstorage.ExternalFileReader, error) {
 	var err error
 	if storage == nil {
@@ -210,6 +210,7 @@ func _() {
 	_ = x[codeConfigColumnMappingDeprecated-20064]
 	_ = x[codeConfigInvalidLoadAnalyze-20065]
 	_ = x[codeConfigStrictOptimisticShardMode-20066]
+	_ = x[codeConfigInvalidCheckpointTableSuffix-20067]
 	_ = x[codeBinlogExtractPosition-22001]
 	_ = x[codeBinlogInvalidFilename-22002]
 	_ = x[codeBinlogParsePosFromStr-22003]
@@ -598,7 +599,7 @@ func _() {
 	_ = x[codeNotSet-50000]
 }
 
-const _ErrCode_name = "DBDriverErrorDBBadConnDBInvalidConnDBUnExpectDBQueryFailedDBExecuteFailedParseMydumperMetaGetFileSizeDropMultipleTablesRenameMultipleTablesAlterMultipleTablesParseSQLUnknownTypeDDLRestoreASTNodeParseGTIDNotSupportedFlavorNotMySQLGTIDNotMariaDBGTIDNotUUIDStringMariaDBDomainIDInvalidServerIDGetSQLModeFromStrVerifySQLOperateArgsStatFileSizeReaderAlreadyRunningReaderAlreadyStartedReaderStateCannotCloseReaderShouldStartSyncEmptyRelayDirReadDirBaseFileNotFoundBinFileCmpCondNotSupportBinlogFileNotValidBinlogFilesNotFoundGetRelayLogStatAddWatchForRelayLogDirWatcherStartWatcherChanClosedWatcherChanRecvErrorRelayLogFileSizeSmallerBinlogFileNotSpecifiedNoRelayLogMatchPosFirstRelayLogNotMatchPosParserParseRelayLogNoSubdirToSwitchNeedSyncAgainSyncClosedSchemaTableNameNotValidGenTableRouterEncryptSecretKeyNotValidEncryptGenCipherEncryptGenIVCiphertextLenNotValidCiphertextContextNotValidInvalidBinlogPosStrEncCipherTextBase64DecodeBinlogWriteBinaryDataBinlogWriteDataToBufferBinlogHeaderLengthNotValidBinlogEventDecodeBinlogEmptyNextBinNameBinlogParseSIDBinlogEmptyGTIDBinlogGTIDSetNotValidBinlogGTIDMySQLNotValidBinlogGTIDMariaDBNotValidBinlogMariaDBServerIDMismatchBinlogOnlyOneGTIDSupportBinlogOnlyOneIntervalInUUIDBinlogIntervalValueNotValidBinlogEmptyQueryBinlogTableMapEvNotValidBinlogExpectFormatDescEvBinlogExpectTableMapEvBinlogExpectRowsEvBinlogUnexpectedEvBinlogParseSingleEvBinlogEventTypeNotValidBinlogEventNoRowsBinlogEventNoColumnsBinlogEventRowLengthNotEqBinlogColumnTypeNotSupportBinlogGoMySQLTypeNotSupportBinlogColumnTypeMisMatchBinlogDummyEvSizeTooSmallBinlogFlavorNotSupportBinlogDMLEmptyDataBinlogLatestGTIDNotInPrevBinlogReadFileByGTIDBinlogWriterNotStateNewBinlogWriterStateCannotCloseBinlogWriterNeedStartBinlogWriterOpenFileBinlogWriterGetFileStatBinlogWriterWriteDataLenBinlogWriterFileNotOpenedBinlogWriterFileSyncBinlogPrevGTIDEvNotValidBinlogDecodeMySQLGTIDSetBinlogNeedMariaDBGTIDSetBinlogParseMariaDBGTIDSetBinlogMariaDBAddGTIDSetTracingEventDataNotValidTracingUploadDataTracingEventTypeNotValidTracingGetTraceCodeTracingDataChecksumTracingGetTSOBackoffArgsNotValidInitLoggerFailGTIDTruncateInvalidRelayLogGivenPosTooBigElectionCampaignFailElectionGetLeaderIDFailBinlogInvalidFilenameWithUUIDSuffixDecodeEtcdKeyFailShardDDLOptimismTrySyncFailConnInvalidTLSConfigConnRegistryTLSConfigUpgradeVersionEtcdFailInvalidV1WorkerMetaPathFailUpdateV1DBSchemaBinlogStatusVarsParseVerifyHandleErrorArgsRewriteSQLNoUUIDDirMatchGTIDNoRelayPosMatchGTIDReaderReachEndOfFileMetadataNoBinlogLocPreviousGTIDNotExistNoMasterStatusBinlogNotLogColumnShardDDLOptimismNeedSkipAndRedirectShardDDLOptimismAddNotFullyDroppedColumnSyncerCancelledDDLIncorrectReturnColumnsNumConfigCheckItemNotSupportConfigTomlTransformConfigYamlTransformConfigTaskNameEmptyConfigEmptySourceIDConfigTooLongSourceIDConfigOnlineSchemeNotSupportConfigInvalidTimezoneConfigParseFlagSetConfigDecryptDBPasswordConfigMetaInvalidConfigMySQLInstNotFoundConfigMySQLInstsAtLeastOneConfigMySQLInstSameSourceIDConfigMydumperCfgConflictConfigLoaderCfgConflictConfigSyncerCfgConflictConfigReadCfgFromFileConfigNeedUniqueTaskNameConfigInvalidTaskModeConfigNeedTargetDBConfigMetadataNotSetConfigRouteRuleNotFoundConfigFilterRuleNotFoundConfigColumnMappingNotFoundConfigBAListNotFoundConfigMydumperCfgNotFoundConfigMydumperPathNotValidConfigLoaderCfgNotFoundConfigSyncerCfgNotFoundConfigSourceIDNotFoundConfigDuplicateCfgItemConfigShardModeNotSupportConfigMoreThanOneConfigEtcdParseConfigMissingForBoundConfigBinlogEventFilterConfigGlobalConfigsUnusedConfigExprFilterManyExprConfigExprFilterNotFoundConfigExprFilterWrongGrammarConfigExprFilterEmptyNameConfigCheckerMaxTooSmallConfigGenBAListConfigGenTableRouterConfigGenColumnMappingConfigInvalidChunkFileSizeConfigOnlineDDLInvalidRegexConfigOnlineDDLMistakeRegexConfigOpenAPITaskConfigExistConfigOpenAPITaskConfigNotExistCollationCompatibleNotSupportConfigInvalidLoadModeConfigInvalidLoadDuplicateResolutionConfigValidationModeContinuousValidatorCfgNotFoundConfigStartTimeTooLateConfigLoaderDirInvalidConfigLoaderS3NotSupportConfigInvalidSafeModeDurationConfigConfictSafeModeDurationAndSafeModeConfigInvalidLoadPhysicalDuplicateResolutionConfigInvalidLoadPhysicalChecksumConfigColumnMappingDeprecatedConfigInvalidLoadAnalyzeConfigStrictOptimisticShardModeBinlogExtractPositionBinlogInvalidFilenameBinlogParsePosFromStrCheckpointInvalidTaskModeCheckpointSaveInvalidPosCheckpointInvalidTableFileCheckpointDBNotExistInFileCheckpointTableNotExistInFileCheckpointRestoreCountGreaterTaskCheckSameTableNameTaskCheckFailedOpenDBTaskCheckGenTableRouterTaskCheckGenColumnMappingTaskCheckSyncConfigErrorTaskCheckGenBAListSourceCheckGTIDRelayParseUUIDIndexRelayParseUUIDSuffixRelayUUIDWithSuffixNotFoundRelayGenFakeRotateEventRelayNoValidRelaySubDirRelayUUIDSuffixNotValidRelayUUIDSuffixLessThanPrevRelayLoadMetaDataRelayBinlogNameNotValidRelayNoCurrentUUIDRelayFlushLocalMetaRelayUpdateIndexFileRelayLogDirpathEmptyRelayReaderNotStateNewRelayReaderStateCannotCloseRelayReaderNeedStartRelayTCPReaderStartSyncRelayTCPReaderNilGTIDRelayTCPReaderStartSyncGTIDRelayTCPReaderGetEventRelayWriterNotStateNewRelayWriterStateCannotCloseRelayWriterNeedStartRelayWriterNotOpenedRelayWriterExpectRotateEvRelayWriterRotateEvWithNoWriterRelayWriterStatusNotValidRelayWriterGetFileStatRelayWriterLatestPosGTFileSizeRelayWriterFileOperateRelayCheckBinlogFileHeaderExistRelayCheckFormatDescEventExistRelayCheckFormatDescEventParseEvRelayCheckIsDuplicateEventRelayUpdateGTIDRelayNeedPrevGTIDEvBeforeGTIDEvRelayNeedMaGTIDListEvBeforeGTIDEvRelayMkdirRelaySwitchMasterNeedGTIDRelayThisStrategyIsPurgingRelayOtherStrategyIsPurgingRelayPurgeIsForbiddenRelayNoActiveRelayLogRelayPurgeRequestNotValidRelayTrimUUIDNotFoundRelayRemoveFileFailRelayPurgeArgsNotValidPreviousGTIDsNotValidRotateEventWithDifferentServerIDDumpUnitRuntimeDumpUnitGenTableRouterDumpUnitGenBAListDumpUnitGlobalLockLoadUnitCreateSchemaFileLoadUnitInvalidFileEndingLoadUnitParseQuoteValuesLoadUnitDoColumnMappingLoadUnitReadSchemaFileLoadUnitParseStatementLoadUnitNotCreateTableLoadUnitDispatchSQLFromFileLoadUnitInvalidInsertSQLLoadUnitGenTableRouterLoadUnitGenColumnMappingLoadUnitNoDBFileLoadUnitNoTableFileLoadUnitDumpDirNotFoundLoadUnitDuplicateTableFileLoadUnitGenBAListLoadTaskWorkerNotMatchLoadCheckPointNotMatchLoadLightningRuntimeLoadLightningHasDupLoadLightningChecksumSyncerUnitPanicSyncUnitInvalidTableNameSyncUnitTableNameQuerySyncUnitNotSupportedDMLSyncUnitAddTableInShardingSyncUnitDropSchemaTableInShardingSyncUnitInvalidShardMetaSyncUnitDDLWrongSequenceSyncUnitDDLActiveIndexLargerSyncUnitDupTableGroupSyncUnitShardingGroupNotFoundSyncUnitSafeModeSetCountSyncUnitCausalityConflictSyncUnitDMLStatementFoundSyncerUnitBinlogEventFilterSyncerUnitInvalidReplicaEventSyncerUnitParseStmtSyncerUnitUUIDNotLatestSyncerUnitDDLExecChanCloseOrBusySyncerUnitDDLChanDoneSyncerUnitDDLChanCanceledSyncerUnitDDLOnMultipleTableSyncerUnitInjectDDLOnlySyncerUnitInjectDDLWithoutSchemaSyncerUnitNotSupportedOperateSyncerUnitNilOperatorReqSyncerUnitDMLColumnNotMatchSyncerUnitDMLOldNewValueMismatchSyncerUnitDMLPruneColumnMismatchSyncerUnitGenBinlogEventFilterSyncerUnitGenTableRouterSyncerUnitGenColumnMappingSyncerUnitDoColumnMappingSyncerUnitCacheKeyNotFoundSyncerUnitHeartbeatCheckConfigSyncerUnitHeartbeatRecordExistsSyncerUnitHeartbeatRecordNotFoundSyncerUnitHeartbeatRecordNotValidSyncerUnitOnlineDDLInvalidMetaSyncerUnitOnlineDDLSchemeNotSupportSyncerUnitOnlineDDLOnMultipleTableSyncerUnitGhostApplyEmptyTableSyncerUnitGhostRenameTableNotValidSyncerUnitGhostRenameToGhostTableSyncerUnitGhostRenameGhostTblToOtherSyncerUnitGhostOnlineDDLOnGhostTblSyncerUnitPTApplyEmptyTableSyncerUnitPTRenameTableNotValidSyncerUnitPTRenameToPTTableSyncerUnitPTRenamePTTblToOtherSyncerUnitPTOnlineDDLOnPTTblSyncerUnitRemoteSteamerWithGTIDSyncerUnitRemoteSteamerStartSyncSyncerUnitGetTableFromDBSyncerUnitFirstEndPosNotFoundSyncerUnitResolveCasualityFailSyncerUnitReopenStreamNotSupportSyncerUnitUpdateConfigInShardingSyncerUnitExecWithNoBlockingDDLSyncerUnitGenBAListSyncerUnitHandleDDLFailedSyncerShardDDLConflictSyncerFailpointSyncerEventSyncerOperatorNotExistSyncerEventNotExistSyncerParseDDLSyncerUnsupportedStmtSyncerGetEventSyncerDownstreamTableNotFoundSyncerReprocessWithSafeModeFailMasterSQLOpNilRequestMasterSQLOpNotSupportMasterSQLOpWithoutShardingMasterGRPCCreateConnMasterGRPCSendOnCloseConnMasterGRPCClientCloseMasterGRPCInvalidReqTypeMasterGRPCRequestErrorMasterDeployMapperVerifyMasterConfigParseFlagSetMasterConfigUnknownItemMasterConfigInvalidFlagMasterConfigTomlTransformMasterConfigTimeoutParseMasterConfigUpdateCfgFileMasterShardingDDLDiffMasterStartServiceMasterNoEmitTokenMasterLockNotFoundMasterLockIsResolvingMasterWorkerCliNotFoundMasterWorkerNotWaitLockMasterHandleSQLReqFailMasterOwnerExecDDLMasterPartWorkerExecDDLFailMasterWorkerExistDDLLockMasterGetWorkerCfgExtractorMasterTaskConfigExtractorMasterWorkerArgsExtractorMasterQueryWorkerConfigMasterOperNotFoundMasterOperRespNotSuccessMasterOperRequestTimeoutMasterHandleHTTPApisMasterHostPortNotValidMasterGetHostnameFailMasterGenEmbedEtcdConfigFailMasterStartEmbedEtcdFailMasterParseURLFailMasterJoinEmbedEtcdFailMasterInvalidOperateOpMasterAdvertiseAddrNotValidMasterRequestIsNotForwardToLeaderMasterIsNotAsyncRequestMasterFailToGetExpectResultMasterPessimistNotStartedMasterOptimistNotStartedMasterMasterNameNotExistMasterInvalidOfflineTypeMasterAdvertisePeerURLsNotValidMasterTLSConfigNotValidMasterBoundChangingMasterFailToImportFromV10xMasterInconsistentOptimistDDLsAndInfoMasterOptimisticTableInfobeforeNotExistMasterOptimisticDownstreamMetaNotFoundMasterInvalidClusterIDMasterStartTaskWorkerParseFlagSetWorkerInvalidFlagWorkerDecodeConfigFromFileWorkerUndecodedItemFromFileWorkerNeedSourceIDWorkerTooLongSourceIDWorkerRelayBinlogNameWorkerWriteConfigFileWorkerLogInvalidHandlerWorkerLogPointerInvalidWorkerLogFetchPointerWorkerLogUnmarshalPointerWorkerLogClearPointerWorkerLogTaskKeyNotValidWorkerLogUnmarshalTaskKeyWorkerLogFetchLogIterWorkerLogGetTaskLogWorkerLogUnmarshalBinaryWorkerLogForwardPointerWorkerLogMarshalTaskWorkerLogSaveTaskWorkerLogDeleteKVWorkerLogDeleteKVIterWorkerLogUnmarshalTaskMetaWorkerLogFetchTaskFromMetaWorkerLogVerifyTaskMetaWorkerLogSaveTaskMetaWorkerLogGetTaskMetaWorkerLogDeleteTaskMetaWorkerMetaTomlTransformWorkerMetaOldFileStatWorkerMetaOldReadFileWorkerMetaEncodeTaskWorkerMetaRemoveOldDirWorkerMetaTaskLogNotFoundWorkerMetaHandleTaskOrderWorkerMetaOpenTxnWorkerMetaCommitTxnWorkerRelayStageNotValidWorkerRelayOperNotSupportWorkerOpenKVDBFileWorkerUpgradeCheckKVDirWorkerMarshalVerBinaryWorkerUnmarshalVerBinaryWorkerGetVersionFromKVWorkerSaveVersionToKVWorkerVerAutoDowngradeWorkerStartServiceWorkerAlreadyClosedWorkerNotRunningStageWorkerNotPausedStageWorkerUpdateTaskStageWorkerMigrateStopRelayWorkerSubTaskNotFoundWorkerSubTaskExistsWorkerOperSyncUnitOnlyWorkerRelayUnitStageWorkerNoSyncerRunningWorkerCannotUpdateSourceIDWorkerNoAvailUnitsWorkerDDLLockInfoNotFoundWorkerDDLLockInfoExistsWorkerCacheDDLInfoExistsWorkerExecSkipDDLConflictWorkerExecDDLSyncerOnlyWorkerExecDDLTimeoutWorkerWaitRelayCatchupTimeoutWorkerRelayIsPurgingWorkerHostPortNotValidWorkerNoStartWorkerAlreadyStartedWorkerSourceNotMatchWorkerFailToGetSubtaskConfigFromEtcdWorkerFailToGetSourceConfigFromEtcdWorkerDDLLockOpNotFoundWorkerTLSConfigNotValidWorkerFailConnectMasterWorkerWaitRelayCatchupGTIDWorkerRelayConfigChangingWorkerRouteTableDupMatchWorkerUpdateSubTaskConfigWorkerValidatorNotPausedWorkerServerClosedTracerParseFlagSetTracerConfigTomlTransformTracerConfigInvalidFlagTracerTraceEventNotFoundTracerTraceIDNotProvidedTracerParamNotValidTracerPostMethodOnlyTracerEventAssertionFailTracerEventTypeNotValidTracerStartServiceHAFailTxnOperationHAInvalidItemHAFailWatchEtcdHAFailLeaseOperationHAFailKeepaliveValidatorLoadPersistedDataValidatorPersistDataValidatorGetEventValidatorProcessRowEventValidatorValidateChangeValidatorNotFoundValidatorPanicValidatorTooMuchPendingSchemaTrackerInvalidJSONSchemaTrackerCannotCreateSchemaSchemaTrackerCannotCreateTableSchemaTrackerCannotSerializeSchemaTrackerCannotGetTableSchemaTrackerCannotExecDDLSchemaTrackerCannotFetchDownstreamTableSchemaTrackerCannotParseDownstreamTableSchemaTrackerInvalidCreateTableStmtSchemaTrackerRestoreStmtFailSchemaTrackerCannotDropTableSchemaTrackerInitSchemaTrackerMarshalJSONSchemaTrackerUnMarshalJSONSchemaTrackerUnSchemaNotExistSchemaTrackerCannotSetDownstreamSQLModeSchemaTrackerCannotInitDownstreamParserSchemaTrackerCannotMockDownstreamTableSchemaTrackerCannotFetchDownstreamCreateTableStmtSchemaTrackerIsClosedSchedulerNotStartedSchedulerStartedSchedulerWorkerExistSchedulerWorkerNotExistSchedulerWorkerOnlineSchedulerWorkerInvalidTransSchedulerSourceCfgExistSchedulerSourceCfgNotExistSchedulerSourcesUnboundSchedulerSourceOpTaskExistSchedulerRelayStageInvalidUpdateSchedulerRelayStageSourceNotExistSchedulerMultiTaskSchedulerSubTaskExistSchedulerSubTaskStageInvalidUpdateSchedulerSubTaskOpTaskNotExistSchedulerSubTaskOpSourceNotExistSchedulerTaskNotExistSchedulerRequireRunningTaskInSyncUnitSchedulerRelayWorkersBusySchedulerRelayWorkersBoundSchedulerRelayWorkersWrongRelaySchedulerSourceOpRelayExistSchedulerLatchInUseSchedulerSourceCfgUpdateSchedulerWrongWorkerInputSchedulerCantTransferToRelayWorkerSchedulerStartRelayOnSpecifiedSchedulerStopRelayOnSpecifiedSchedulerStartRelayOnBoundSchedulerStopRelayOnBoundSchedulerPauseTaskForTransferSourceSchedulerWorkerNotFreeSchedulerSubTaskNotExistSchedulerSubTaskCfgUpdateCtlGRPCCreateConnCtlInvalidTLSCfgCtlLoadTLSCfgOpenAPICommonOpenAPITaskSourceNotFoundNotSet"
+const _ErrCode_name = "DBDriverErrorDBBadConnDBInvalidConnDBUnExpectDBQueryFailedDBExecuteFailedParseMydumperMetaGetFileSizeDropMultipleTablesRenameMultipleTablesAlterMultipleTablesParseSQLUnknownTypeDDLRestoreASTNodeParseGTIDNotSupportedFlavorNotMySQLGTIDNotMariaDBGTIDNotUUIDStringMariaDBDomainIDInvalidServerIDGetSQLModeFromStrVerifySQLOperateArgsStatFileSizeReaderAlreadyRunningReaderAlreadyStartedReaderStateCannotCloseReaderShouldStartSyncEmptyRelayDirReadDirBaseFileNotFoundBinFileCmpCondNotSupportBinlogFileNotValidBinlogFilesNotFoundGetRelayLogStatAddWatchForRelayLogDirWatcherStartWatcherChanClosedWatcherChanRecvErrorRelayLogFileSizeSmallerBinlogFileNotSpecifiedNoRelayLogMatchPosFirstRelayLogNotMatchPosParserParseRelayLogNoSubdirToSwitchNeedSyncAgainSyncClosedSchemaTableNameNotValidGenTableRouterEncryptSecretKeyNotValidEncryptGenCipherEncryptGenIVCiphertextLenNotValidCiphertextContextNotValidInvalidBinlogPosStrEncCipherTextBase64DecodeBinlogWriteBinaryDataBinlogWriteDataToBufferBinlogHeaderLengthNotValidBinlogEventDecodeBinlogEmptyNextBinNameBinlogParseSIDBinlogEmptyGTIDBinlogGTIDSetNotValidBinlogGTIDMySQLNotValidBinlogGTIDMariaDBNotValidBinlogMariaDBServerIDMismatchBinlogOnlyOneGTIDSupportBinlogOnlyOneIntervalInUUIDBinlogIntervalValueNotValidBinlogEmptyQueryBinlogTableMapEvNotValidBinlogExpectFormatDescEvBinlogExpectTableMapEvBinlogExpectRowsEvBinlogUnexpectedEvBinlogParseSingleEvBinlogEventTypeNotValidBinlogEventNoRowsBinlogEventNoColumnsBinlogEventRowLengthNotEqBinlogColumnTypeNotSupportBinlogGoMySQLTypeNotSupportBinlogColumnTypeMisMatchBinlogDummyEvSizeTooSmallBinlogFlavorNotSupportBinlogDMLEmptyDataBinlogLatestGTIDNotInPrevBinlogReadFileByGTIDBinlogWriterNotStateNewBinlogWriterStateCannotCloseBinlogWriterNeedStartBinlogWriterOpenFileBinlogWriterGetFileStatBinlogWriterWriteDataLenBinlogWriterFileNotOpenedBinlogWriterFileSyncBinlogPrevGTIDEvNotValidBinlogDecodeMySQLGTIDSetBinlogNeedMariaDBGTIDSetBinlogParseMariaDBGTIDSetBinlogMariaDBAddGTIDSetTracingEventDataNotValidTracingUploadDataTracingEventTypeNotValidTracingGetTraceCodeTracingDataChecksumTracingGetTSOBackoffArgsNotValidInitLoggerFailGTIDTruncateInvalidRelayLogGivenPosTooBigElectionCampaignFailElectionGetLeaderIDFailBinlogInvalidFilenameWithUUIDSuffixDecodeEtcdKeyFailShardDDLOptimismTrySyncFailConnInvalidTLSConfigConnRegistryTLSConfigUpgradeVersionEtcdFailInvalidV1WorkerMetaPathFailUpdateV1DBSchemaBinlogStatusVarsParseVerifyHandleErrorArgsRewriteSQLNoUUIDDirMatchGTIDNoRelayPosMatchGTIDReaderReachEndOfFileMetadataNoBinlogLocPreviousGTIDNotExistNoMasterStatusBinlogNotLogColumnShardDDLOptimismNeedSkipAndRedirectShardDDLOptimismAddNotFullyDroppedColumnSyncerCancelledDDLIncorrectReturnColumnsNumConfigCheckItemNotSupportConfigTomlTransformConfigYamlTransformConfigTaskNameEmptyConfigEmptySourceIDConfigTooLongSourceIDConfigOnlineSchemeNotSupportConfigInvalidTimezoneConfigParseFlagSetConfigDecryptDBPasswordConfigMetaInvalidConfigMySQLInstNotFoundConfigMySQLInstsAtLeastOneConfigMySQLInstSameSourceIDConfigMydumperCfgConflictConfigLoaderCfgConflictConfigSyncerCfgConflictConfigReadCfgFromFileConfigNeedUniqueTaskNameConfigInvalidTaskModeConfigNeedTargetDBConfigMetadataNotSetConfigRouteRuleNotFoundConfigFilterRuleNotFoundConfigColumnMappingNotFoundConfigBAListNotFoundConfigMydumperCfgNotFoundConfigMydumperPathNotValidConfigLoaderCfgNotFoundConfigSyncerCfgNotFoundConfigSourceIDNotFoundConfigDuplicateCfgItemConfigShardModeNotSupportConfigMoreThanOneConfigEtcdParseConfigMissingForBoundConfigBinlogEventFilterConfigGlobalConfigsUnusedConfigExprFilterManyExprConfigExprFilterNotFoundConfigExprFilterWrongGrammarConfigExprFilterEmptyNameConfigCheckerMaxTooSmallConfigGenBAListConfigGenTableRouterConfigGenColumnMappingConfigInvalidChunkFileSizeConfigOnlineDDLInvalidRegexConfigOnlineDDLMistakeRegexConfigOpenAPITaskConfigExistConfigOpenAPITaskConfigNotExistCollationCompatibleNotSupportConfigInvalidLoadModeConfigInvalidLoadDuplicateResolutionConfigValidationModeContinuousValidatorCfgNotFoundConfigStartTimeTooLateConfigLoaderDirInvalidConfigLoaderS3NotSupportConfigInvalidSafeModeDurationConfigConfictSafeModeDurationAndSafeModeConfigInvalidLoadPhysicalDuplicateResolutionConfigInvalidLoadPhysicalChecksumConfigColumnMappingDeprecatedConfigInvalidLoadAnalyzeConfigStrictOptimisticShardModeConfigInvalidCheckpointTableSuffixBinlogExtractPositionBinlogInvalidFilenameBinlogParsePosFromStrCheckpointInvalidTaskModeCheckpointSaveInvalidPosCheckpointInvalidTableFileCheckpointDBNotExistInFileCheckpointTableNotExistInFileCheckpointRestoreCountGreaterTaskCheckSameTableNameTaskCheckFailedOpenDBTaskCheckGenTableRouterTaskCheckGenColumnMappingTaskCheckSyncConfigErrorTaskCheckGenBAListSourceCheckGTIDRelayParseUUIDIndexRelayParseUUIDSuffixRelayUUIDWithSuffixNotFoundRelayGenFakeRotateEventRelayNoValidRelaySubDirRelayUUIDSuffixNotValidRelayUUIDSuffixLessThanPrevRelayLoadMetaDataRelayBinlogNameNotValidRelayNoCurrentUUIDRelayFlushLocalMetaRelayUpdateIndexFileRelayLogDirpathEmptyRelayReaderNotStateNewRelayReaderStateCannotCloseRelayReaderNeedStartRelayTCPReaderStartSyncRelayTCPReaderNilGTIDRelayTCPReaderStartSyncGTIDRelayTCPReaderGetEventRelayWriterNotStateNewRelayWriterStateCannotCloseRelayWriterNeedStartRelayWriterNotOpenedRelayWriterExpectRotateEvRelayWriterRotateEvWithNoWriterRelayWriterStatusNotValidRelayWriterGetFileStatRelayWriterLatestPosGTFileSizeRelayWriterFileOperateRelayCheckBinlogFileHeaderExistRelayCheckFormatDescEventExistRelayCheckFormatDescEventParseEvRelayCheckIsDuplicateEventRelayUpdateGTIDRelayNeedPrevGTIDEvBeforeGTIDEvRelayNeedMaGTIDListEvBeforeGTIDEvRelayMkdirRelaySwitchMasterNeedGTIDRelayThisStrategyIsPurgingRelayOtherStrategyIsPurgingRelayPurgeIsForbiddenRelayNoActiveRelayLogRelayPurgeRequestNotValidRelayTrimUUIDNotFoundRelayRemoveFileFailRelayPurgeArgsNotValidPreviousGTIDsNotValidRotateEventWithDifferentServerIDDumpUnitRuntimeDumpUnitGenTableRouterDumpUnitGenBAListDumpUnitGlobalLockLoadUnitCreateSchemaFileLoadUnitInvalidFileEndingLoadUnitParseQuoteValuesLoadUnitDoColumnMappingLoadUnitReadSchemaFileLoadUnitParseStatementLoadUnitNotCreateTableLoadUnitDispatchSQLFromFileLoadUnitInvalidInsertSQLLoadUnitGenTableRouterLoadUnitGenColumnMappingLoadUnitNoDBFileLoadUnitNoTableFileLoadUnitDumpDirNotFoundLoadUnitDuplicateTableFileLoadUnitGenBAListLoadTaskWorkerNotMatchLoadCheckPointNotMatchLoadLightningRuntimeLoadLightningHasDupLoadLightningChecksumSyncerUnitPanicSyncUnitInvalidTableNameSyncUnitTableNameQuerySyncUnitNotSupportedDMLSyncUnitAddTableInShardingSyncUnitDropSchemaTableInShardingSyncUnitInvalidShardMetaSyncUnitDDLWrongSequenceSyncUnitDDLActiveIndexLargerSyncUnitDupTableGroupSyncUnitShardingGroupNotFoundSyncUnitSafeModeSetCountSyncUnitCausalityConflictSyncUnitDMLStatementFoundSyncerUnitBinlogEventFilterSyncerUnitInvalidReplicaEventSyncerUnitParseStmtSyncerUnitUUIDNotLatestSyncerUnitDDLExecChanCloseOrBusySyncerUnitDDLChanDoneSyncerUnitDDLChanCanceledSyncerUnitDDLOnMultipleTableSyncerUnitInjectDDLOnlySyncerUnitInjectDDLWithoutSchemaSyncerUnitNotSupportedOperateSyncerUnitNilOperatorReqSyncerUnitDMLColumnNotMatchSyncerUnitDMLOldNewValueMismatchSyncerUnitDMLPruneColumnMismatchSyncerUnitGenBinlogEventFilterSyncerUnitGenTableRouterSyncerUnitGenColumnMappingSyncerUnitDoColumnMappingSyncerUnitCacheKeyNotFoundSyncerUnitHeartbeatCheckConfigSyncerUnitHeartbeatRecordExistsSyncerUnitHeartbeatRecordNotFoundSyncerUnitHeartbeatRecordNotValidSyncerUnitOnlineDDLInvalidMetaSyncerUnitOnlineDDLSchemeNotSupportSyncerUnitOnlineDDLOnMultipleTableSyncerUnitGhostApplyEmptyTableSyncerUnitGhostRenameTableNotValidSyncerUnitGhostRenameToGhostTableSyncerUnitGhostRenameGhostTblToOtherSyncerUnitGhostOnlineDDLOnGhostTblSyncerUnitPTApplyEmptyTableSyncerUnitPTRenameTableNotValidSyncerUnitPTRenameToPTTableSyncerUnitPTRenamePTTblToOtherSyncerUnitPTOnlineDDLOnPTTblSyncerUnitRemoteSteamerWithGTIDSyncerUnitRemoteSteamerStartSyncSyncerUnitGetTableFromDBSyncerUnitFirstEndPosNotFoundSyncerUnitResolveCasualityFailSyncerUnitReopenStreamNotSupportSyncerUnitUpdateConfigInShardingSyncerUnitExecWithNoBlockingDDLSyncerUnitGenBAListSyncerUnitHandleDDLFailedSyncerShardDDLConflictSyncerFailpointSyncerEventSyncerOperatorNotExistSyncerEventNotExistSyncerParseDDLSyncerUnsupportedStmtSyncerGetEventSyncerDownstreamTableNotFoundSyncerReprocessWithSafeModeFailMasterSQLOpNilRequestMasterSQLOpNotSupportMasterSQLOpWithoutShardingMasterGRPCCreateConnMasterGRPCSendOnCloseConnMasterGRPCClientCloseMasterGRPCInvalidReqTypeMasterGRPCRequestErrorMasterDeployMapperVerifyMasterConfigParseFlagSetMasterConfigUnknownItemMasterConfigInvalidFlagMasterConfigTomlTransformMasterConfigTimeoutParseMasterConfigUpdateCfgFileMasterShardingDDLDiffMasterStartServiceMasterNoEmitTokenMasterLockNotFoundMasterLockIsResolvingMasterWorkerCliNotFoundMasterWorkerNotWaitLockMasterHandleSQLReqFailMasterOwnerExecDDLMasterPartWorkerExecDDLFailMasterWorkerExistDDLLockMasterGetWorkerCfgExtractorMasterTaskConfigExtractorMasterWorkerArgsExtractorMasterQueryWorkerConfigMasterOperNotFoundMasterOperRespNotSuccessMasterOperRequestTimeoutMasterHandleHTTPApisMasterHostPortNotValidMasterGetHostnameFailMasterGenEmbedEtcdConfigFailMasterStartEmbedEtcdFailMasterParseURLFailMasterJoinEmbedEtcdFailMasterInvalidOperateOpMasterAdvertiseAddrNotValidMasterRequestIsNotForwardToLeaderMasterIsNotAsyncRequestMasterFailToGetExpectResultMasterPessimistNotStartedMasterOptimistNotStartedMasterMasterNameNotExistMasterInvalidOfflineTypeMasterAdvertisePeerURLsNotValidMasterTLSConfigNotValidMasterBoundChangingMasterFailToImportFromV10xMasterInconsistentOptimistDDLsAndInfoMasterOptimisticTableInfobeforeNotExistMasterOptimisticDownstreamMetaNotFoundMasterInvalidClusterIDMasterStartTaskWorkerParseFlagSetWorkerInvalidFlagWorkerDecodeConfigFromFileWorkerUndecodedItemFromFileWorkerNeedSourceIDWorkerTooLongSourceIDWorkerRelayBinlogNameWorkerWriteConfigFileWorkerLogInvalidHandlerWorkerLogPointerInvalidWorkerLogFetchPointerWorkerLogUnmarshalPointerWorkerLogClearPointerWorkerLogTaskKeyNotValidWorkerLogUnmarshalTaskKeyWorkerLogFetchLogIterWorkerLogGetTaskLogWorkerLogUnmarshalBinaryWorkerLogForwardPointerWorkerLogMarshalTaskWorkerLogSaveTaskWorkerLogDeleteKVWorkerLogDeleteKVIterWorkerLogUnmarshalTaskMetaWorkerLogFetchTaskFromMetaWorkerLogVerifyTaskMetaWorkerLogSaveTaskMetaWorkerLogGetTaskMetaWorkerLogDeleteTaskMetaWorkerMetaTomlTransformWorkerMetaOldFileStatWorkerMetaOldReadFileWorkerMetaEncodeTaskWorkerMetaRemoveOldDirWorkerMetaTaskLogNotFoundWorkerMetaHandleTaskOrderWorkerMetaOpenTxnWorkerMetaCommitTxnWorkerRelayStageNotValidWorkerRelayOperNotSupportWorkerOpenKVDBFileWorkerUpgradeCheckKVDirWorkerMarshalVerBinaryWorkerUnmarshalVerBinaryWorkerGetVersionFromKVWorkerSaveVersionToKVWorkerVerAutoDowngradeWorkerStartServiceWorkerAlreadyClosedWorkerNotRunningStageWorkerNotPausedStageWorkerUpdateTaskStageWorkerMigrateStopRelayWorkerSubTaskNotFoundWorkerSubTaskExistsWorkerOperSyncUnitOnlyWorkerRelayUnitStageWorkerNoSyncerRunningWorkerCannotUpdateSourceIDWorkerNoAvailUnitsWorkerDDLLockInfoNotFoundWorkerDDLLockInfoExistsWorkerCacheDDLInfoExistsWorkerExecSkipDDLConflictWorkerExecDDLSyncerOnlyWorkerExecDDLTimeoutWorkerWaitRelayCatchupTimeoutWorkerRelayIsPurgingWorkerHostPortNotValidWorkerNoStartWorkerAlreadyStartedWorkerSourceNotMatchWorkerFailToGetSubtaskConfigFromEtcdWorkerFailToGetSourceConfigFromEtcdWorkerDDLLockOpNotFoundWorkerTLSConfigNotValidWorkerFailConnectMasterWorkerWaitRelayCatchupGTIDWorkerRelayConfigChangingWorkerRouteTableDupMatchWorkerUpdateSubTaskConfigWorkerValidatorNotPausedWorkerServerClosedTracerParseFlagSetTracerConfigTomlTransformTracerConfigInvalidFlagTracerTraceEventNotFoundTracerTraceIDNotProvidedTracerParamNotValidTracerPostMethodOnlyTracerEventAssertionFailTracerEventTypeNotValidTracerStartServiceHAFailTxnOperationHAInvalidItemHAFailWatchEtcdHAFailLeaseOperationHAFailKeepaliveValidatorLoadPersistedDataValidatorPersistDataValidatorGetEventValidatorProcessRowEventValidatorValidateChangeValidatorNotFoundValidatorPanicValidatorTooMuchPendingSchemaTrackerInvalidJSONSchemaTrackerCannotCreateSchemaSchemaTrackerCannotCreateTableSchemaTrackerCannotSerializeSchemaTrackerCannotGetTableSchemaTrackerCannotExecDDLSchemaTrackerCannotFetchDownstreamTableSchemaTrackerCannotParseDownstreamTableSchemaTrackerInvalidCreateTableStmtSchemaTrackerRestoreStmtFailSchemaTrackerCannotDropTableSchemaTrackerInitSchemaTrackerMarshalJSONSchemaTrackerUnMarshalJSONSchemaTrackerUnSchemaNotExistSchemaTrackerCannotSetDownstreamSQLModeSchemaTrackerCannotInitDownstreamParserSchemaTrackerCannotMockDownstreamTableSchemaTrackerCannotFetchDownstreamCreateTableStmtSchemaTrackerIsClosedSchedulerNotStartedSchedulerStartedSchedulerWorkerExistSchedulerWorkerNotExistSchedulerWorkerOnlineSchedulerWorkerInvalidTransSchedulerSourceCfgExistSchedulerSourceCfgNotExistSchedulerSourcesUnboundSchedulerSourceOpTaskExistSchedulerRelayStageInvalidUpdateSchedulerRelayStageSourceNotExistSchedulerMultiTaskSchedulerSubTaskExistSchedulerSubTaskStageInvalidUpdateSchedulerSubTaskOpTaskNotExistSchedulerSubTaskOpSourceNotExistSchedulerTaskNotExistSchedulerRequireRunningTaskInSyncUnitSchedulerRelayWorkersBusySchedulerRelayWorkersBoundSchedulerRelayWorkersWrongRelaySchedulerSourceOpRelayExistSchedulerLatchInUseSchedulerSourceCfgUpdateSchedulerWrongWorkerInputSchedulerCantTransferToRelayWorkerSchedulerStartRelayOnSpecifiedSchedulerStopRelayOnSpecifiedSchedulerStartRelayOnBoundSchedulerStopRelayOnBoundSchedulerPauseTaskForTransferSourceSchedulerWorkerNotFreeSchedulerSubTaskNotExistSchedulerSubTaskCfgUpdateCtlGRPCCreateConnCtlInvalidTLSCfgCtlLoadTLSCfgOpenAPICommonOpenAPITaskSourceNotFoundNotSet"
 
 var _ErrCode_map = map[ErrCode]string{
 	10001: _ErrCode_name[0:13],
@@ -803,392 +804,393 @@ var _ErrCode_map = map[ErrCode]string{
 	20064: _ErrCode_name[4188:4217],
 	20065: _ErrCode_name[4217:4241],
 	20066: _ErrCode_name[4241:4272],
-	22001: _ErrCode_name[4272:4293],
-	22002: _ErrCode_name[4293:4314],
-	22003: _ErrCode_name[4314:4335],
-	24001: _ErrCode_name[4335:4360],
-	24002: _ErrCode_name[4360:4384],
-	24003: _ErrCode_name[4384:4410],
-	24004: _ErrCode_name[4410:4436],
-	24005: _ErrCode_name[4436:4465],
-	24006: _ErrCode_name[4465:4494],
-	26001: _ErrCode_name[4494:4516],
-	26002: _ErrCode_name[4516:4537],
-	26003: _ErrCode_name[4537:4560],
-	26004: _ErrCode_name[4560:4585],
-	26005: _ErrCode_name[4585:4609],
-	26006: _ErrCode_name[4609:4627],
-	26007: _ErrCode_name[4627:4642],
-	28001: _ErrCode_name[4642:4661],
-	28002: _ErrCode_name[4661:4681],
-	28003: _ErrCode_name[4681:4708],
-	28004: _ErrCode_name[4708:4731],
-	28005: _ErrCode_name[4731:4754],
-	30001: _ErrCode_name[4754:4777],
-	30002: _ErrCode_name[4777:4804],
-	30003: _ErrCode_name[4804:4821],
-	30004: _ErrCode_name[4821:4844],
-	30005: _ErrCode_name[4844:4862],
-	30006: _ErrCode_name[4862:4881],
-	30007: _ErrCode_name[4881:4901],
-	30008: _ErrCode_name[4901:4921],
-	30009: _ErrCode_name[4921:4943],
-	30010: _ErrCode_name[4943:4970],
-	30011: _ErrCode_name[4970:4990],
-	30012: _ErrCode_name[4990:5013],
-	30013: _ErrCode_name[5013:5034],
-	30014: _ErrCode_name[5034:5061],
-	30015: _ErrCode_name[5061:5083],
-	30016: _ErrCode_name[5083:5105],
-	30017: _ErrCode_name[5105:5132],
-	30018: _ErrCode_name[5132:5152],
-	30019: _ErrCode_name[5152:5172],
-	30020: _ErrCode_name[5172:5197],
-	30021: _ErrCode_name[5197:5228],
-	30022: _ErrCode_name[5228:5253],
-	30023: _ErrCode_name[5253:5275],
-	30024: _ErrCode_name[5275:5305],
-	30025: _ErrCode_name[5305:5327],
-	30026: _ErrCode_name[5327:5358],
-	30027: _ErrCode_name[5358:5388],
-	30028: _ErrCode_name[5388:5420],
-	30029: _ErrCode_name[5420:5446],
-	30030: _ErrCode_name[5446:5461],
-	30031: _ErrCode_name[5461:5492],
-	30032: _ErrCode_name[5492:5525],
-	30033: _ErrCode_name[5525:5535],
-	30034: _ErrCode_name[5535:5560],
-	30035: _ErrCode_name[5560:5586],
-	30036: _ErrCode_name[5586:5613],
-	30037: _ErrCode_name[5613:5634],
-	30038: _ErrCode_name[5634:5655],
-	30039: _ErrCode_name[5655:5680],
-	30040: _ErrCode_name[5680:5701],
-	30041: _ErrCode_name[5701:5720],
-	30042: _ErrCode_name[5720:5742],
-	30043: _ErrCode_name[5742:5763],
-	30044: _ErrCode_name[5763:5795],
-	32001: _ErrCode_name[5795:5810],
-	32002: _ErrCode_name[5810:5832],
-	32003: _ErrCode_name[5832:5849],
-	32004: _ErrCode_name[5849:5867],
-	34001: _ErrCode_name[5867:5891],
-	34002: _ErrCode_name[5891:5916],
-	34003: _ErrCode_name[5916:5940],
-	34004: _ErrCode_name[5940:5963],
-	34005: _ErrCode_name[5963:5985],
-	34006: _ErrCode_name[5985:6007],
-	34007: _ErrCode_name[6007:6029],
-	34008: _ErrCode_name[6029:6056],
-	34009: _ErrCode_name[6056:6080],
-	34010: _ErrCode_name[6080:6102],
-	34011: _ErrCode_name[6102:6126],
-	34012: _ErrCode_name[6126:6142],
-	34013: _ErrCode_name[6142:6161],
-	34014: _ErrCode_name[6161:6184],
-	34015: _ErrCode_name[6184:6210],
-	34016: _ErrCode_name[6210:6227],
-	34017: _ErrCode_name[6227:6249],
-	34018: _ErrCode_name[6249:6271],
-	34019: _ErrCode_name[6271:6291],
-	34020: _ErrCode_name[6291:6310],
-	34021: _ErrCode_name[6310:6331],
-	36001: _ErrCode_name[6331:6346],
-	36002: _ErrCode_name[6346:6370],
-	36003: _ErrCode_name[6370:6392],
-	36004: _ErrCode_name[6392:6415],
-	36005: _ErrCode_name[6415:6441],
-	36006: _ErrCode_name[6441:6474],
-	36007: _ErrCode_name[6474:6498],
-	36008: _ErrCode_name[6498:6522],
-	36009: _ErrCode_name[6522:6550],
-	36010: _ErrCode_name[6550:6571],
-	36011: _ErrCode_name[6571:6600],
-	36012: _ErrCode_name[6600:6624],
-	36013: _ErrCode_name[6624:6649],
-	36014: _ErrCode_name[6649:6674],
-	36015: _ErrCode_name[6674:6701],
-	36016: _ErrCode_name[6701:6730],
-	36017: _ErrCode_name[6730:6749],
-	36018: _ErrCode_name[6749:6772],
-	36019: _ErrCode_name[6772:6804],
-	36020: _ErrCode_name[6804:6825],
-	36021: _ErrCode_name[6825:6850],
-	36022: _ErrCode_name[6850:6878],
-	36023: _ErrCode_name[6878:6901],
-	36024: _ErrCode_name[6901:6933],
-	36025: _ErrCode_name[6933:6962],
-	36026: _ErrCode_name[6962:6986],
-	36027: _ErrCode_name[6986:7013],
-	36028: _ErrCode_name[7013:7045],
-	36029: _ErrCode_name[7045:7077],
-	36030: _ErrCode_name[7077:7107],
-	36031: _ErrCode_name[7107:7131],
-	36032: _ErrCode_name[7131:7157],
-	36033: _ErrCode_name[7157:7182],
-	36034: _ErrCode_name[7182:7208],
-	36035: _ErrCode_name[7208:7238],
-	36036: _ErrCode_name[7238:7269],
-	36037: _ErrCode_name[7269:7302],
-	36038: _ErrCode_name[7302:7335],
-	36039: _ErrCode_name[7335:7365],
-	36040: _ErrCode_name[7365:7400],
-	36041: _ErrCode_name[7400:7434],
-	36042: _ErrCode_name[7434:7464],
-	36043: _ErrCode_name[7464:7498],
-	36044: _ErrCode_name[7498:7531],
-	36045: _ErrCode_name[7531:7567],
-	36046: _ErrCode_name[7567:7601],
-	36047: _ErrCode_name[7601:7628],
-	36048: _ErrCode_name[7628:7659],
-	36049: _ErrCode_name[7659:7686],
-	36050: _ErrCode_name[7686:7716],
-	36051: _ErrCode_name[7716:7744],
-	36052: _ErrCode_name[7744:7775],
-	36053: _ErrCode_name[7775:7807],
-	36054: _ErrCode_name[7807:7831],
-	36055: _ErrCode_name[7831:7860],
-	36056: _ErrCode_name[7860:7890],
-	36057: _ErrCode_name[7890:7922],
-	36058: _ErrCode_name[7922:7954],
-	36059: _ErrCode_name[7954:7985],
-	36060: _ErrCode_name[7985:8004],
-	36061: _ErrCode_name[8004:8029],
-	36062: _ErrCode_name[8029:8051],
-	36063: _ErrCode_name[8051:8066],
-	36064: _ErrCode_name[8066:8077],
-	36065: _ErrCode_name[8077:8099],
-	36066: _ErrCode_name[8099:8118],
-	36067: _ErrCode_name[8118:8132],
-	36068: _ErrCode_name[8132:8153],
-	36069: _ErrCode_name[8153:8167],
-	36070: _ErrCode_name[8167:8196],
-	36071: _ErrCode_name[8196:8227],
-	38001: _ErrCode_name[8227:8248],
-	38002: _ErrCode_name[8248:8269],
-	38003: _ErrCode_name[8269:8295],
-	38004: _ErrCode_name[8295:8315],
-	38005: _ErrCode_name[8315:8340],
-	38006: _ErrCode_name[8340:8361],
-	38007: _ErrCode_name[8361:8385],
-	38008: _ErrCode_name[8385:8407],
-	38009: _ErrCode_name[8407:8431],
-	38010: _ErrCode_name[8431:8455],
-	38011: _ErrCode_name[8455:8478],
-	38012: _ErrCode_name[8478:8501],
-	38013: _ErrCode_name[8501:8526],
-	38014: _ErrCode_name[8526:8550],
-	38015: _ErrCode_name[8550:8575],
-	38016: _ErrCode_name[8575:8596],
-	38017: _ErrCode_name[8596:8614],
-	38018: _ErrCode_name[8614:8631],
-	38019: _ErrCode_name[8631:8649],
-	38020: _ErrCode_name[8649:8670],
-	38021: _ErrCode_name[8670:8693],
-	38022: _ErrCode_name[8693:8716],
-	38023: _ErrCode_name[8716:8738],
-	38024: _ErrCode_name[8738:8756],
-	38025: _ErrCode_name[8756:8783],
-	38026: _ErrCode_name[8783:8807],
-	38027: _ErrCode_name[8807:8834],
-	38028: _ErrCode_name[8834:8859],
-	38029: _ErrCode_name[8859:8884],
-	38030: _ErrCode_name[8884:8907],
-	38031: _ErrCode_name[8907:8925],
-	38032: _ErrCode_name[8925:8949],
-	38033: _ErrCode_name[8949:8973],
-	38034: _ErrCode_name[8973:8993],
-	38035: _ErrCode_name[8993:9015],
-	38036: _ErrCode_name[9015:9036],
-	38037: _ErrCode_name[9036:9064],
-	38038: _ErrCode_name[9064:9088],
-	38039: _ErrCode_name[9088:9106],
-	38040: _ErrCode_name[9106:9129],
-	38041: _ErrCode_name[9129:9151],
-	38042: _ErrCode_name[9151:9178],
-	38043: _ErrCode_name[9178:9211],
-	38044: _ErrCode_name[9211:9234],
-	38045: _ErrCode_name[9234:9261],
-	38046: _ErrCode_name[9261:9286],
-	38047: _ErrCode_name[9286:9310],
-	38048: _ErrCode_name[9310:9334],
-	38049: _ErrCode_name[9334:9358],
-	38050: _ErrCode_name[9358:9389],
-	38051: _ErrCode_name[9389:9412],
-	38052: _ErrCode_name[9412:9431],
-	38053: _ErrCode_name[9431:9457],
-	38054: _ErrCode_name[9457:9494],
-	38055: _ErrCode_name[9494:9533],
-	38056: _ErrCode_name[9533:9571],
-	38057: _ErrCode_name[9571:9593],
-	38058: _ErrCode_name[9593:9608],
-	40001: _ErrCode_name[9608:9626],
-	40002: _ErrCode_name[9626:9643],
-	40003: _ErrCode_name[9643:9669],
-	40004: _ErrCode_name[9669:9696],
-	40005: _ErrCode_name[9696:9714],
-	40006: _ErrCode_name[9714:9735],
-	40007: _ErrCode_name[9735:9756],
-	40008: _ErrCode_name[9756:9777],
-	40009: _ErrCode_name[9777:9800],
-	40010: _ErrCode_name[9800:9823],
-	40011: _ErrCode_name[9823:9844],
-	40012: _ErrCode_name[9844:9869],
-	40013: _ErrCode_name[9869:9890],
-	40014: _ErrCode_name[9890:9914],
-	40015: _ErrCode_name[9914:9939],
-	40016: _ErrCode_name[9939:9960],
-	40017: _ErrCode_name[9960:9979],
-	40018: _ErrCode_name[9979:10003],
-	40019: _ErrCode_name[10003:10026],
-	40020: _ErrCode_name[10026:10046],
-	40021: _ErrCode_name[10046:10063],
-	40022: _ErrCode_name[10063:10080],
-	40023: _ErrCode_name[10080:10101],
-	40024: _ErrCode_name[10101:10127],
-	40025: _ErrCode_name[10127:10153],
-	40026: _ErrCode_name[10153:10176],
-	40027: _ErrCode_name[10176:10197],
-	40028: _ErrCode_name[10197:10217],
-	40029: _ErrCode_name[10217:10240],
-	40030: _ErrCode_name[10240:10263],
-	40031: _ErrCode_name[10263:10284],
-	40032: _ErrCode_name[10284:10305],
-	40033: _ErrCode_name[10305:10325],
-	40034: _ErrCode_name[10325:10347],
-	40035: _ErrCode_name[10347:10372],
-	40036: _ErrCode_name[10372:10397],
-	40037: _ErrCode_name[10397:10414],
-	40038: _ErrCode_name[10414:10433],
-	40039: _ErrCode_name[10433:10457],
-	40040: _ErrCode_name[10457:10482],
-	40041: _ErrCode_name[10482:10500],
-	40042: _ErrCode_name[10500:10523],
-	40043: _ErrCode_name[10523:10545],
-	40044: _ErrCode_name[10545:10569],
-	40045: _ErrCode_name[10569:10591],
-	40046: _ErrCode_name[10591:10612],
-	40047: _ErrCode_name[10612:10634],
-	40048: _ErrCode_name[10634:10652],
-	40049: _ErrCode_name[10652:10671],
-	40050: _ErrCode_name[10671:10692],
-	40051: _ErrCode_name[10692:10712],
-	40052: _ErrCode_name[10712:10733],
-	40053: _ErrCode_name[10733:10755],
-	40054: _ErrCode_name[10755:10776],
-	40055: _ErrCode_name[10776:10795],
-	40056: _ErrCode_name[10795:10817],
-	40057: _ErrCode_name[10817:10837],
-	40058: _ErrCode_name[10837:10858],
-	40059: _ErrCode_name[10858:10884],
-	40060: _ErrCode_name[10884:10902],
-	40061: _ErrCode_name[10902:10927],
-	40062: _ErrCode_name[10927:10950],
-	40063: _ErrCode_name[10950:10974],
-	40064: _ErrCode_name[10974:10999],
-	40065: _ErrCode_name[10999:11022],
-	40066: _ErrCode_name[11022:11042],
-	40067: _ErrCode_name[11042:11071],
-	40068: _ErrCode_name[11071:11091],
-	40069: _ErrCode_name[11091:11113],
-	40070: _ErrCode_name[11113:11126],
-	40071: _ErrCode_name[11126:11146],
-	40072: _ErrCode_name[11146:11166],
-	40073: _ErrCode_name[11166:11202],
-	40074: _ErrCode_name[11202:11237],
-	40075: _ErrCode_name[11237:11260],
-	40076: _ErrCode_name[11260:11283],
-	40077: _ErrCode_name[11283:11306],
-	40078: _ErrCode_name[11306:11332],
-	40079: _ErrCode_name[11332:11357],
-	40080: _ErrCode_name[11357:11381],
-	40081: _ErrCode_name[11381:11406],
-	40082: _ErrCode_name[11406:11430],
-	40083: _ErrCode_name[11430:11448],
-	42001: _ErrCode_name[11448:11466],
-	42002: _ErrCode_name[11466:11491],
-	42003: _ErrCode_name[11491:11514],
-	42004: _ErrCode_name[11514:11538],
-	42005: _ErrCode_name[11538:11562],
-	42006: _ErrCode_name[11562:11581],
-	42007: _ErrCode_name[11581:11601],
-	42008: _ErrCode_name[11601:11625],
-	42009: _ErrCode_name[11625:11648],
-	42010: _ErrCode_name[11648:11666],
-	42501: _ErrCode_name[11666:11684],
-	42502: _ErrCode_name[11684:11697],
-	42503: _ErrCode_name[11697:11712],
-	42504: _ErrCode_name[11712:11732],
-	42505: _ErrCode_name[11732:11747],
-	43001: _ErrCode_name[11747:11773],
-	43002: _ErrCode_name[11773:11793],
-	43003: _ErrCode_name[11793:11810],
-	43004: _ErrCode_name[11810:11834],
-	43005: _ErrCode_name[11834:11857],
-	43006: _ErrCode_name[11857:11874],
-	43007: _ErrCode_name[11874:11888],
-	43008: _ErrCode_name[11888:11911],
-	44001: _ErrCode_name[11911:11935],
-	44002: _ErrCode_name[11935:11966],
-	44003: _ErrCode_name[11966:11996],
-	44004: _ErrCode_name[11996:12024],
-	44005: _ErrCode_name[12024:12051],
-	44006: _ErrCode_name[12051:12077],
-	44007: _ErrCode_name[12077:12116],
-	44008: _ErrCode_name[12116:12155],
-	44009: _ErrCode_name[12155:12190],
-	44010: _ErrCode_name[12190:12218],
-	44011: _ErrCode_name[12218:12246],
-	44012: _ErrCode_name[12246:12263],
-	44013: _ErrCode_name[12263:12287],
-	44014: _ErrCode_name[12287:12313],
-	44015: _ErrCode_name[12313:12342],
-	44016: _ErrCode_name[12342:12381],
-	44017: _ErrCode_name[12381:12420],
-	44018: _ErrCode_name[12420:12458],
-	44019: _ErrCode_name[12458:12507],
-	44020: _ErrCode_name[12507:12528],
-	46001: _ErrCode_name[12528:12547],
-	46002: _ErrCode_name[12547:12563],
-	46003: _ErrCode_name[12563:12583],
-	46004: _ErrCode_name[12583:12606],
-	46005: _ErrCode_name[12606:12627],
-	46006: _ErrCode_name[12627:12654],
-	46007: _ErrCode_name[12654:12677],
-	46008: _ErrCode_name[12677:12703],
-	46009: _ErrCode_name[12703:12726],
-	46010: _ErrCode_name[12726:12752],
-	46011: _ErrCode_name[12752:12784],
-	46012: _ErrCode_name[12784:12817],
-	46013: _ErrCode_name[12817:12835],
-	46014: _ErrCode_name[12835:12856],
-	46015: _ErrCode_name[12856:12890],
-	46016: _ErrCode_name[12890:12920],
-	46017: _ErrCode_name[12920:12952],
-	46018: _ErrCode_name[12952:12973],
-	46019: _ErrCode_name[12973:13010],
-	46020: _ErrCode_name[13010:13035],
-	46021: _ErrCode_name[13035:13061],
-	46022: _ErrCode_name[13061:13092],
-	46023: _ErrCode_name[13092:13119],
-	46024: _ErrCode_name[13119:13138],
-	46025: _ErrCode_name[13138:13162],
-	46026: _ErrCode_name[13162:13187],
-	46027: _ErrCode_name[13187:13221],
-	46028: _ErrCode_name[13221:13251],
-	46029: _ErrCode_name[13251:13280],
-	46030: _ErrCode_name[13280:13306],
-	46031: _ErrCode_name[13306:13331],
-	46032: _ErrCode_name[13331:13366],
-	46033: _ErrCode_name[13366:13388],
-	46034: _ErrCode_name[13388:13412],
-	46035: _ErrCode_name[13412:13437],
-	48001: _ErrCode_name[13437:13454],
-	48002: _ErrCode_name[13454:13470],
-	48003: _ErrCode_name[13470:13483],
-	49001: _ErrCode_name[13483:13496],
-	49002: _ErrCode_name[13496:13521],
-	50000: _ErrCode_name[13521:13527],
+	20067: _ErrCode_name[4272:4306],
+	22001: _ErrCode_name[4306:4327],
+	22002: _ErrCode_name[4327:4348],
+	22003: _ErrCode_name[4348:4369],
+	24001: _ErrCode_name[4369:4394],
+	24002: _ErrCode_name[4394:4418],
+	24003: _ErrCode_name[4418:4444],
+	24004: _ErrCode_name[4444:4470],
+	24005: _ErrCode_name[4470:4499],
+	24006: _ErrCode_name[4499:4528],
+	26001: _ErrCode_name[4528:4550],
+	26002: _ErrCode_name[4550:4571],
+	26003: _ErrCode_name[4571:4594],
+	26004: _ErrCode_name[4594:4619],
+	26005: _ErrCode_name[4619:4643],
+	26006: _ErrCode_name[4643:4661],
+	26007: _ErrCode_name[4661:4676],
+	28001: _ErrCode_name[4676:4695],
+	28002: _ErrCode_name[4695:4715],
+	28003: _ErrCode_name[4715:4742],
+	28004: _ErrCode_name[4742:4765],
+	28005: _ErrCode_name[4765:4788],
+	30001: _ErrCode_name[4788:4811],
+	30002: _ErrCode_name[4811:4838],
+	30003: _ErrCode_name[4838:4855],
+	30004: _ErrCode_name[4855:4878],
+	30005: _ErrCode_name[4878:4896],
+	30006: _ErrCode_name[4896:4915],
+	30007: _ErrCode_name[4915:4935],
+	30008: _ErrCode_name[4935:4955],
+	30009: _ErrCode_name[4955:4977],
+	30010: _ErrCode_name[4977:5004],
+	30011: _ErrCode_name[5004:5024],
+	30012: _ErrCode_name[5024:5047],
+	30013: _ErrCode_name[5047:5068],
+	30014: _ErrCode_name[5068:5095],
+	30015: _ErrCode_name[5095:5117],
+	30016: _ErrCode_name[5117:5139],
+	30017: _ErrCode_name[5139:5166],
+	30018: _ErrCode_name[5166:5186],
+	30019: _ErrCode_name[5186:5206],
+	30020: _ErrCode_name[5206:5231],
+	30021: _ErrCode_name[5231:5262],
+	30022: _ErrCode_name[5262:5287],
+	30023: _ErrCode_name[5287:5309],
+	30024: _ErrCode_name[5309:5339],
+	30025: _ErrCode_name[5339:5361],
+	30026: _ErrCode_name[5361:5392],
+	30027: _ErrCode_name[5392:5422],
+	30028: _ErrCode_name[5422:5454],
+	30029: _ErrCode_name[5454:5480],
+	30030: _ErrCode_name[5480:5495],
+	30031: _ErrCode_name[5495:5526],
+	30032: _ErrCode_name[5526:5559],
+	30033: _ErrCode_name[5559:5569],
+	30034: _ErrCode_name[5569:5594],
+	30035: _ErrCode_name[5594:5620],
+	30036: _ErrCode_name[5620:5647],
+	30037: _ErrCode_name[5647:5668],
+	30038: _ErrCode_name[5668:5689],
+	30039: _ErrCode_name[5689:5714],
+	30040: _ErrCode_name[5714:5735],
+	30041: _ErrCode_name[5735:5754],
+	30042: _ErrCode_name[5754:5776],
+	30043: _ErrCode_name[5776:5797],
+	30044: _ErrCode_name[5797:5829],
+	32001: _ErrCode_name[5829:5844],
+	32002: _ErrCode_name[5844:5866],
+	32003: _ErrCode_name[5866:5883],
+	32004: _ErrCode_name[5883:5901],
+	34001: _ErrCode_name[5901:5925],
+	34002: _ErrCode_name[5925:5950],
+	34003: _ErrCode_name[5950:5974],
+	34004: _ErrCode_name[5974:5997],
+	34005: _ErrCode_name[5997:6019],
+	34006: _ErrCode_name[6019:6041],
+	34007: _ErrCode_name[6041:6063],
+	34008: _ErrCode_name[6063:6090],
+	34009: _ErrCode_name[6090:6114],
+	34010: _ErrCode_name[6114:6136],
+	34011: _ErrCode_name[6136:6160],
+	34012: _ErrCode_name[6160:6176],
+	34013: _ErrCode_name[6176:6195],
+	34014: _ErrCode_name[6195:6218],
+	34015: _ErrCode_name[6218:6244],
+	34016: _ErrCode_name[6244:6261],
+	34017: _ErrCode_name[6261:6283],
+	34018: _ErrCode_name[6283:6305],
+	34019: _ErrCode_name[6305:6325],
+	34020: _ErrCode_name[6325:6344],
+	34021: _ErrCode_name[6344:6365],
+	36001: _ErrCode_name[6365:6380],
+	36002: _ErrCode_name[6380:6404],
+	36003: _ErrCode_name[6404:6426],
+	36004: _ErrCode_name[6426:6449],
+	36005: _ErrCode_name[6449:6475],
+	36006: _ErrCode_name[6475:6508],
+	36007: _ErrCode_name[6508:6532],
+	36008: _ErrCode_name[6532:6556],
+	36009: _ErrCode_name[6556:6584],
+	36010: _ErrCode_name[6584:6605],
+	36011: _ErrCode_name[6605:6634],
+	36012: _ErrCode_name[6634:6658],
+	36013: _ErrCode_name[6658:6683],
+	36014: _ErrCode_name[6683:6708],
+	36015: _ErrCode_name[6708:6735],
+	36016: _ErrCode_name[6735:6764],
+	36017: _ErrCode_name[6764:6783],
+	36018: _ErrCode_name[6783:6806],
+	36019: _ErrCode_name[6806:6838],
+	36020: _ErrCode_name[6838:6859],
+	36021: _ErrCode_name[6859:6884],
+	36022: _ErrCode_name[6884:6912],
+	36023: _ErrCode_name[6912:6935],
+	36024: _ErrCode_name[6935:6967],
+	36025: _ErrCode_name[6967:6996],
+	36026: _ErrCode_name[6996:7020],
+	36027: _ErrCode_name[7020:7047],
+	36028: _ErrCode_name[7047:7079],
+	36029: _ErrCode_name[7079:7111],
+	36030: _ErrCode_name[7111:7141],
+	36031: _ErrCode_name[7141:7165],
+	36032: _ErrCode_name[7165:7191],
+	36033: _ErrCode_name[7191:7216],
+	36034: _ErrCode_name[7216:7242],
+	36035: _ErrCode_name[7242:7272],
+	36036: _ErrCode_name[7272:7303],
+	36037: _ErrCode_name[7303:7336],
+	36038: _ErrCode_name[7336:7369],
+	36039: _ErrCode_name[7369:7399],
+	36040: _ErrCode_name[7399:7434],
+	36041: _ErrCode_name[7434:7468],
+	36042: _ErrCode_name[7468:7498],
+	36043: _ErrCode_name[7498:7532],
+	36044: _ErrCode_name[7532:7565],
+	36045: _ErrCode_name[7565:7601],
+	36046: _ErrCode_name[7601:7635],
+	36047: _ErrCode_name[7635:7662],
+	36048: _ErrCode_name[7662:7693],
+	36049: _ErrCode_name[7693:7720],
+	36050: _ErrCode_name[7720:7750],
+	36051: _ErrCode_name[7750:7778],
+	36052: _ErrCode_name[7778:7809],
+	36053: _ErrCode_name[7809:7841],
+	36054: _ErrCode_name[7841:7865],
+	36055: _ErrCode_name[7865:7894],
+	36056: _ErrCode_name[7894:7924],
+	36057: _ErrCode_name[7924:7956],
+	36058: _ErrCode_name[7956:7988],
+	36059: _ErrCode_name[7988:8019],
+	36060: _ErrCode_name[8019:8038],
+	36061: _ErrCode_name[8038:8063],
+	36062: _ErrCode_name[8063:8085],
+	36063: _ErrCode_name[8085:8100],
+	36064: _ErrCode_name[8100:8111],
+	36065: _ErrCode_name[8111:8133],
+	36066: _ErrCode_name[8133:8152],
+	36067: _ErrCode_name[8152:8166],
+	36068: _ErrCode_name[8166:8187],
+	36069: _ErrCode_name[8187:8201],
+	36070: _ErrCode_name[8201:8230],
+	36071: _ErrCode_name[8230:8261],
+	38001: _ErrCode_name[8261:8282],
+	38002: _ErrCode_name[8282:8303],
+	38003: _ErrCode_name[8303:8329],
+	38004: _ErrCode_name[8329:8349],
+	38005: _ErrCode_name[8349:8374],
+	38006: _ErrCode_name[8374:8395],
+	38007: _ErrCode_name[8395:8419],
+	38008: _ErrCode_name[8419:8441],
+	38009: _ErrCode_name[8441:8465],
+	38010: _ErrCode_name[8465:8489],
+	38011: _ErrCode_name[8489:8512],
+	38012: _ErrCode_name[8512:8535],
+	38013: _ErrCode_name[8535:8560],
+	38014: _ErrCode_name[8560:8584],
+	38015: _ErrCode_name[8584:8609],
+	38016: _ErrCode_name[8609:8630],
+	38017: _ErrCode_name[8630:8648],
+	38018: _ErrCode_name[8648:8665],
+	38019: _ErrCode_name[8665:8683],
+	38020: _ErrCode_name[8683:8704],
+	38021: _ErrCode_name[8704:8727],
+	38022: _ErrCode_name[8727:8750],
+	38023: _ErrCode_name[8750:8772],
+	38024: _ErrCode_name[8772:8790],
+	38025: _ErrCode_name[8790:8817],
+	38026: _ErrCode_name[8817:8841],
+	38027: _ErrCode_name[8841:8868],
+	38028: _ErrCode_name[8868:8893],
+	38029: _ErrCode_name[8893:8918],
+	38030: _ErrCode_name[8918:8941],
+	38031: _ErrCode_name[8941:8959],
+	38032: _ErrCode_name[8959:8983],
+	38033: _ErrCode_name[8983:9007],
+	38034: _ErrCode_name[9007:9027],
+	38035: _ErrCode_name[9027:9049],
+	38036: _ErrCode_name[9049:9070],
+	38037: _ErrCode_name[9070:9098],
+	38038: _ErrCode_name[9098:9122],
+	38039: _ErrCode_name[9122:9140],
+	38040: _ErrCode_name[9140:9163],
+	38041: _ErrCode_name[9163:9185],
+	38042: _ErrCode_name[9185:9212],
+	38043: _ErrCode_name[9212:9245],
+	38044: _ErrCode_name[9245:9268],
+	38045: _ErrCode_name[9268:9295],
+	38046: _ErrCode_name[9295:9320],
+	38047: _ErrCode_name[9320:9344],
+	38048: _ErrCode_name[9344:9368],
+	38049: _ErrCode_name[9368:9392],
+	38050: _ErrCode_name[9392:9423],
+	38051: _ErrCode_name[9423:9446],
+	38052: _ErrCode_name[9446:9465],
+	38053: _ErrCode_name[9465:9491],
+	38054: _ErrCode_name[9491:9528],
+	38055: _ErrCode_name[9528:9567],
+	38056: _ErrCode_name[9567:9605],
+	38057: _ErrCode_name[9605:9627],
+	38058: _ErrCode_name[9627:9642],
+	40001: _ErrCode_name[9642:9660],
+	40002: _ErrCode_name[9660:9677],
+	40003: _ErrCode_name[9677:9703],
+	40004: _ErrCode_name[9703:9730],
+	40005: _ErrCode_name[9730:9748],
+	40006: _ErrCode_name[9748:9769],
+	40007: _ErrCode_name[9769:9790],
+	40008: _ErrCode_name[9790:9811],
+	40009: _ErrCode_name[9811:9834],
+	40010: _ErrCode_name[9834:9857],
+	40011: _ErrCode_name[9857:9878],
+	40012: _ErrCode_name[9878:9903],
+	40013: _ErrCode_name[9903:9924],
+	40014: _ErrCode_name[9924:9948],
+	40015: _ErrCode_name[9948:9973],
+	40016: _ErrCode_name[9973:9994],
+	40017: _ErrCode_name[9994:10013],
+	40018: _ErrCode_name[10013:10037],
+	40019: _ErrCode_name[10037:10060],
+	40020: _ErrCode_name[10060:10080],
+	40021: _ErrCode_name[10080:10097],
+	40022: _ErrCode_name[10097:10114],
+	40023: _ErrCode_name[10114:10135],
+	40024: _ErrCode_name[10135:10161],
+	40025: _ErrCode_name[10161:10187],
+	40026: _ErrCode_name[10187:10210],
+	40027: _ErrCode_name[10210:10231],
+	40028: _ErrCode_name[10231:10251],
+	40029: _ErrCode_name[10251:10274],
+	40030: _ErrCode_name[10274:10297],
+	40031: _ErrCode_name[10297:10318],
+	40032: _ErrCode_name[10318:10339],
+	40033: _ErrCode_name[10339:10359],
+	40034: _ErrCode_name[10359:10381],
+	40035: _ErrCode_name[10381:10406],
+	40036: _ErrCode_name[10406:10431],
+	40037: _ErrCode_name[10431:10448],
+	40038: _ErrCode_name[10448:10467],
+	40039: _ErrCode_name[10467:10491],
+	40040: _ErrCode_name[10491:10516],
+	40041: _ErrCode_name[10516:10534],
+	40042: _ErrCode_name[10534:10557],
+	40043: _ErrCode_name[10557:10579],
+	40044: _ErrCode_name[10579:10603],
+	40045: _ErrCode_name[10603:10625],
+	40046: _ErrCode_name[10625:10646],
+	40047: _ErrCode_name[10646:10668],
+	40048: _ErrCode_name[10668:10686],
+	40049: _ErrCode_name[10686:10705],
+	40050: _ErrCode_name[10705:10726],
+	40051: _ErrCode_name[10726:10746],
+	40052: _ErrCode_name[10746:10767],
+	40053: _ErrCode_name[10767:10789],
+	40054: _ErrCode_name[10789:10810],
+	40055: _ErrCode_name[10810:10829],
+	40056: _ErrCode_name[10829:10851],
+	40057: _ErrCode_name[10851:10871],
+	40058: _ErrCode_name[10871:10892],
+	40059: _ErrCode_name[10892:10918],
+	40060: _ErrCode_name[10918:10936],
+	40061: _ErrCode_name[10936:10961],
+	40062: _ErrCode_name[10961:10984],
+	40063: _ErrCode_name[10984:11008],
+	40064: _ErrCode_name[11008:11033],
+	40065: _ErrCode_name[11033:11056],
+	40066: _ErrCode_name[11056:11076],
+	40067: _ErrCode_name[11076:11105],
+	40068: _ErrCode_name[11105:11125],
+	40069: _ErrCode_name[11125:11147],
+	40070: _ErrCode_name[11147:11160],
+	40071: _ErrCode_name[11160:11180],
+	40072: _ErrCode_name[11180:11200],
+	40073: _ErrCode_name[11200:11236],
+	40074: _ErrCode_name[11236:11271],
+	40075: _ErrCode_name[11271:11294],
+	40076: _ErrCode_name[11294:11317],
+	40077: _ErrCode_name[11317:11340],
+	40078: _ErrCode_name[11340:11366],
+	40079: _ErrCode_name[11366:11391],
+	40080: _ErrCode_name[11391:11415],
+	40081: _ErrCode_name[11415:11440],
+	40082: _ErrCode_name[11440:11464],
+	40083: _ErrCode_name[11464:11482],
+	42001: _ErrCode_name[11482:11500],
+	42002: _ErrCode_name[11500:11525],
+	42003: _ErrCode_name[11525:11548],
+	42004: _ErrCode_name[11548:11572],
+	42005: _ErrCode_name[11572:11596],
+	42006: _ErrCode_name[11596:11615],
+	42007: _ErrCode_name[11615:11635],
+	42008: _ErrCode_name[11635:11659],
+	42009: _ErrCode_name[11659:11682],
+	42010: _ErrCode_name[11682:11700],
+	42501: _ErrCode_name[11700:11718],
+	42502: _ErrCode_name[11718:11731],
+	42503: _ErrCode_name[11731:11746],
+	42504: _ErrCode_name[11746:11766],
+	42505: _ErrCode_name[11766:11781],
+	43001: _ErrCode_name[11781:11807],
+	43002: _ErrCode_name[11807:11827],
+	43003: _ErrCode_name[11827:11844],
+	43004: _ErrCode_name[11844:11868],
+	43005: _ErrCode_name[11868:11891],
+	43006: _ErrCode_name[11891:11908],
+	43007: _ErrCode_name[11908:11922],
+	43008: _ErrCode_name[11922:11945],
+	44001: _ErrCode_name[11945:11969],
+	44002: _ErrCode_name[11969:12000],
+	44003: _ErrCode_name[12000:12030],
+	44004: _ErrCode_name[12030:12058],
+	44005: _ErrCode_name[12058:12085],
+	44006: _ErrCode_name[12085:12111],
+	44007: _ErrCode_name[12111:12150],
+	44008: _ErrCode_name[12150:12189],
+	44009: _ErrCode_name[12189:12224],
+	44010: _ErrCode_name[12224:12252],
+	44011: _ErrCode_name[12252:12280],
+	44012: _ErrCode_name[12280:12297],
+	44013: _ErrCode_name[12297:12321],
+	44014: _ErrCode_name[12321:12347],
+	44015: _ErrCode_name[12347:12376],
+	44016: _ErrCode_name[12376:12415],
+	44017: _ErrCode_name[12415:12454],
+	44018: _ErrCode_name[12454:12492],
+	44019: _ErrCode_name[12492:12541],
+	44020: _ErrCode_name[12541:12562],
+	46001: _ErrCode_name[12562:12581],
+	46002: _ErrCode_name[12581:12597],
+	46003: _ErrCode_name[12597:12617],
+	46004: _ErrCode_name[12617:12640],
+	46005: _ErrCode_name[12640:12661],
+	46006: _ErrCode_name[12661:12688],
+	46007: _ErrCode_name[12688:12711],
+	46008: _ErrCode_name[12711:12737],
+	46009: _ErrCode_name[12737:12760],
+	46010: _ErrCode_name[12760:12786],
+	46011: _ErrCode_name[12786:12818],
+	46012: _ErrCode_name[12818:12851],
+	46013: _ErrCode_name[12851:12869],
+	46014: _ErrCode_name[12869:12890],
+	46015: _ErrCode_name[12890:12924],
+	46016: _ErrCode_name[12924:12954],
+	46017: _ErrCode_name[12954:12986],
+	46018: _ErrCode_name[12986:13007],
+	46019: _ErrCode_name[13007:13044],
+	46020: _ErrCode_name[13044:13069],
+	46021: _ErrCode_name[13069:13095],
+	46022: _ErrCode_name[13095:13126],
+	46023: _ErrCode_name[13126:13153],
+	46024: _ErrCode_name[13153:13172],
+	46025: _ErrCode_name[13172:13196],
+	46026: _ErrCode_name[13196:13221],
+	46027: _ErrCode_name[13221:13255],
+	46028: _ErrCode_name[13255:13285],
+	46029: _ErrCode_name[13285:13314],
+	46030: _ErrCode_name[13314:13340],
+	46031: _ErrCode_name[13340:13365],
+	46032: _ErrCode_name[13365:13400],
+	46033: _ErrCode_name[13400:13422],
+	46034: _ErrCode_name[13422:13446],
+	46035: _ErrCode_name[13446:13471],
+	48001: _ErrCode_name[13471:13488],
+	48002: _ErrCode_name[13488:13504],
+	48003: _ErrCode_name[13504:13517],
+	49001: _ErrCode_name[13517:13530],
+	49002: _ErrCode_name[13530:13555],
+	50000: _ErrCode_name[13555:13561],
 }
 
 func (i ErrCode) String() string {
@@ -201,6 +201,9 @@ const (
 
 	// pkg/utils.
 	codeIncorrectReturnColumnsNum
+
+	// pkg/dumpling.
+	codeParseMetadataJSON
 )
 
 // Config related error code list.
@@ -271,6 +274,7 @@ const (
 	codeConfigColumnMappingDeprecated
 	codeConfigInvalidLoadAnalyze
 	codeConfigStrictOptimisticShardMode
+	codeConfigInvalidCheckpointTableSuffix
 )
 
 // Binlog operation error code list.
@@ -900,6 +904,7 @@ var (
 
 	// pkg/dumplling.
 	ErrMetadataNoBinlogLoc = New(codeMetadataNoBinlogLoc, ClassFunctional, ScopeUpstream, LevelLow, "didn't found binlog location in dumped metadata file %s", "Please check log of dump unit, there maybe errors when read upstream binlog status")
+	ErrParseMetadataJSON   = New(codeParseMetadataJSON, ClassFunctional, ScopeInternal, LevelHigh, "parse metadata file %s error", "Please check the content of the metadata file in the dump folder.")
 
 	ErrPreviousGTIDNotExist = New(codePreviousGTIDNotExist, ClassFunctional, ScopeInternal, LevelHigh, "no previous gtid event from binlog %s", "")
 
@@ -983,6 +988,7 @@ var (
 	ErrConfigColumnMappingDeprecated            = New(codeConfigColumnMappingDeprecated, ClassConfig, ScopeInternal, LevelHigh, "column-mapping is not supported since v6.6.0", "Please use extract-table/extract-schema/extract-source to handle data conflict when merge tables. See https://docs.pingcap.com/tidb/v6.4/task-configuration-file-full#task-configuration-file-template-advanced")
 	ErrConfigInvalidLoadAnalyze                 = New(codeConfigInvalidLoadAnalyze, ClassConfig, ScopeInternal, LevelMedium, "invalid load analyze option '%s'", "Please choose a valid value in ['required', 'optional', 'off'] or leave it empty.")
 	ErrConfigStrictOptimisticShardMode          = New(codeConfigStrictOptimisticShardMode, ClassConfig, ScopeInternal, LevelMedium, "cannot enable `strict-optimistic-shard-mode` while `shard-mode` is not `optimistic`", "Please set `shard-mode` to `optimistic` if you want to enable `strict-optimistic-shard-mode`.")
+	ErrConfigInvalidCheckpointTableSuffix       = New(codeConfigInvalidCheckpointTableSuffix, ClassConfig, ScopeInternal, LevelMedium, "invalid checkpoint-table-suffix '%s', only letters, digits and underscores are allowed", "Please check the `checkpoint-table-suffix` config in task configuration file.")
 
 	// Binlog operation error.
 	ErrBinlogExtractPosition = New(codeBinlogExtractPosition, ClassBinlogOp, ScopeInternal, LevelHigh, "", "")
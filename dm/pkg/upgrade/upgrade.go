@@ -24,7 +24,6 @@ import (
 	"github.com/pingcap/tiflow/dm/config/dbconfig"
 	"github.com/pingcap/tiflow/dm/pkg/conn"
 	tcontext "github.com/pingcap/tiflow/dm/pkg/context"
-	"github.com/pingcap/tiflow/dm/pkg/cputil"
 	"github.com/pingcap/tiflow/dm/pkg/etcdutil"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/utils"
@@ -172,7 +171,7 @@ func upgradeToVer2(cli *clientv3.Client, uctx Context) error {
 	dbConfigs := map[string]dbconfig.DBConfig{}
 	for task, m := range uctx.SubTaskConfigs {
 		for sourceID, subCfg := range m {
-			tableName := dbutil.TableName(subCfg.MetaSchema, cputil.SyncerCheckpoint(subCfg.Name))
+			tableName := dbutil.TableName(subCfg.MetaSchema, subCfg.SyncerCheckpointTableName())
 			subCfg2, err := subCfg.DecryptPassword()
 			if err != nil {
 				log.L().Error("subconfig error when upgrading", zap.String("task", task),
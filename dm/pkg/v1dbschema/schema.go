@@ -67,7 +67,7 @@ func UpdateSchema(tctx *tcontext.Context, db *conn.BaseDB, cfg *config.SubTaskCo
 	tcpReader := reader.NewTCPReader(syncCfg)
 
 	// update checkpoint.
-	err = updateSyncerCheckpoint(tctx, dbConn, cfg.Name, dbutil.TableName(cfg.MetaSchema, cputil.SyncerCheckpoint(cfg.Name)), cfg.SourceID, cfg.EnableGTID, tcpReader)
+	err = updateSyncerCheckpoint(tctx, dbConn, cfg.Name, dbutil.TableName(cfg.MetaSchema, cfg.SyncerCheckpointTableName()), cfg.SourceID, cfg.EnableGTID, tcpReader)
 	if err != nil {
 		return terror.ErrFailUpdateV1DBSchema.Delegate(err)
 	}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -36,7 +37,6 @@ import (
 	"github.com/pingcap/tiflow/dm/pkg/binlog"
 	"github.com/pingcap/tiflow/dm/pkg/conn"
 	tcontext "github.com/pingcap/tiflow/dm/pkg/context"
-	"github.com/pingcap/tiflow/dm/pkg/cputil"
 	"github.com/pingcap/tiflow/dm/pkg/dumpling"
 	fr "github.com/pingcap/tiflow/dm/pkg/func-rollback"
 	"github.com/pingcap/tiflow/dm/pkg/gtid"
@@ -64,6 +64,13 @@ var (
 	globalCpTable        = "" // global checkpoint's cp_table
 	maxCheckPointTimeout = "1m"
 	batchFlushPoints     = 100
+
+	defaultFlushRetryCount    = 3
+	defaultFlushRetryInterval = time.Second
+
+	// mirrorCheckpointTimeout bounds each write of the checkpoints to the mirror
+	// backend, so that a slow external storage doesn't delay the later writes long.
+	mirrorCheckpointTimeout = 10 * time.Second
 )
 
 type tablePoint struct {
@@ -81,6 +88,52 @@ func (t *tablePoint) writeString(buf io.Writer) {
 	}
 }
 
+// estimatedMemoryBytes returns an approximate size of the table point, the
+// table info is estimated by its names and comments instead of serializing it.
+func (t *tablePoint) estimatedMemoryBytes() int64 {
+	if t == nil {
+		return 0
+	}
+	size := estimateLocationBytes(t.location)
+	if t.ti != nil {
+		size += estimateTableInfoBytes(t.ti)
+	}
+	return size
+}
+
+func estimateLocationBytes(location binlog.Location) int64 {
+	// binlog position is a name and a uint32, suffix is an int
+	size := int64(len(location.Position.Name)) + 4 + 8
+	if gset := location.GetGTID(); gset != nil {
+		size += int64(len(gset.String()))
+	}
+	return size
+}
+
+const (
+	estimatedTableInfoOverhead   = 512
+	estimatedColumnInfoOverhead  = 256
+	estimatedIndexInfoOverhead   = 128
+	estimatedIndexColumnOverhead = 32
+)
+
+func estimateTableInfoBytes(ti *model.TableInfo) int64 {
+	size := int64(estimatedTableInfoOverhead + len(ti.Name.O) + len(ti.Comment))
+	for _, col := range ti.Columns {
+		size += int64(estimatedColumnInfoOverhead + len(col.Name.O) + len(col.Comment))
+		for _, elem := range col.GetElems() {
+			size += int64(len(elem))
+		}
+	}
+	for _, idx := range ti.Indices {
+		size += int64(estimatedIndexInfoOverhead + len(idx.Name.O) + len(idx.Comment))
+		for _, idxCol := range idx.Columns {
+			size += int64(estimatedIndexColumnOverhead + len(idxCol.Name.O))
+		}
+	}
+	return size
+}
+
 func (t *tablePoint) String() string {
 	if t == nil {
 		return ""
@@ -174,6 +227,21 @@ func (b *binlogPoint) outOfDateBy(pos binlog.Location) bool {
 	return binlog.CompareLocation(pos, b.flushedPoint.location, b.enableGTID) > 0
 }
 
+// estimatedMemoryBytes returns an approximate size of the saved and flushed
+// points, a table info shared by both is only counted once.
+func (b *binlogPoint) estimatedMemoryBytes() int64 {
+	b.RLock()
+	defer b.RUnlock()
+
+	size := b.savedPoint.estimatedMemoryBytes()
+	if b.flushedPoint.ti == b.savedPoint.ti {
+		size += estimateLocationBytes(b.flushedPoint.location)
+	} else {
+		size += b.flushedPoint.estimatedMemoryBytes()
+	}
+	return size
+}
+
 // MySQLLocation returns point as binlog.Location.
 func (b *binlogPoint) MySQLLocation() binlog.Location {
 	b.RLock()
@@ -249,12 +317,19 @@ type CheckPoint interface {
 	// DeleteTablePoint deletes checkpoint for specified table in memory and storage
 	DeleteTablePoint(tctx *tcontext.Context, table *filter.Table) error
 
+	// DeleteTablePoints deletes checkpoints for specified tables in memory and storage in one transaction
+	DeleteTablePoints(tctx *tcontext.Context, tables []*filter.Table) error
+
 	// DeleteAllTablePoint deletes all checkpoints for table in memory and storage
 	DeleteAllTablePoint(tctx *tcontext.Context) error
 
 	// DeleteSchemaPoint deletes checkpoint for specified schema
 	DeleteSchemaPoint(tctx *tcontext.Context, sourceSchema string) error
 
+	// CompactOutdatedPoints deletes table checkpoints whose tables no longer exist in the schema tracker
+	// and are confirmed dropped by upstreamTables, which returns the tables of the schema in upstream
+	CompactOutdatedPoints(tctx *tcontext.Context, schemaTracker *schema.Tracker, upstreamTables UpstreamTablesFunc) error
+
 	// IsOlderThanTablePoint checks whether job's checkpoint is older than previous saved checkpoint
 	IsOlderThanTablePoint(table *filter.Table, point binlog.Location) bool
 
@@ -289,7 +364,7 @@ type CheckPoint interface {
 	// corresponding to Meta.Pos and Meta.GTID
 	GlobalPoint() binlog.Location
 
-	// GlobalPointSaveTime return the global point saved time, used for test only
+	// GlobalPointSaveTime return the global point saved time
 	GlobalPointSaveTime() time.Time
 
 	// SaveSafeModeExitPoint saves the pointer to location which indicates safe mode exit
@@ -376,6 +451,14 @@ type RemoteCheckPoint struct {
 
 	snapshots   []*remoteCheckpointSnapshot
 	snapshotSeq int
+
+	// mirrorBackend mirrors the flushed checkpoints in background in a best-effort
+	// way, it's nil if cfg.CheckpointMirrorStorage is not set.
+	mirrorBackend *asyncCheckpointBackend
+
+	// flushRetryCount and flushRetryInterval control retrying the flush transaction on retryable errors.
+	flushRetryCount    int
+	flushRetryInterval time.Duration
 }
 
 // NewRemoteCheckPoint creates a new RemoteCheckPoint.
@@ -388,7 +471,7 @@ func NewRemoteCheckPoint(
 	cp := &RemoteCheckPoint{
 		cfg:           cfg,
 		metricProxies: metricProxies,
-		tableName:     dbutil.TableName(cfg.MetaSchema, cputil.SyncerCheckpoint(cfg.Name)),
+		tableName:     dbutil.TableName(cfg.MetaSchema, cfg.SyncerCheckpointTableName()),
 		id:            id,
 		points:        make(map[string]map[string]*binlogPoint),
 		globalPoint:   newBinlogPoint(binlog.MustZeroLocation(cfg.Flavor), binlog.MustZeroLocation(cfg.Flavor), nil, nil, cfg.EnableGTID),
@@ -396,6 +479,14 @@ func NewRemoteCheckPoint(
 		snapshots:     make([]*remoteCheckpointSnapshot, 0),
 		snapshotSeq:   0,
 	}
+	cp.flushRetryCount = defaultFlushRetryCount
+	if cfg.CheckpointFlushRetryCount > 0 {
+		cp.flushRetryCount = cfg.CheckpointFlushRetryCount
+	}
+	cp.flushRetryInterval = defaultFlushRetryInterval
+	if cfg.CheckpointFlushRetryInterval > 0 {
+		cp.flushRetryInterval = time.Duration(cfg.CheckpointFlushRetryInterval) * time.Second
+	}
 
 	return cp
 }
@@ -490,6 +581,17 @@ func (cp *RemoteCheckPoint) Init(tctx *tcontext.Context) (err error) {
 	cp.dbConn = dbConns[0]
 	rollbackHolder.Add(fr.FuncRollback{Name: "CloseRemoteCheckPoint", Fn: cp.Close})
 
+	if cp.cfg.CheckpointMirrorStorage != "" {
+		var backend CheckpointBackend
+		backend, err = newExternalStorageCheckpointBackend(
+			tctx.Ctx, cp.cfg.CheckpointMirrorStorage, cp.cfg.Name, cp.cfg.CheckpointTableSuffix, cp.id)
+		if err != nil {
+			return
+		}
+		cp.mirrorBackend = newAsyncCheckpointBackend(backend, cp.logCtx.L())
+		rollbackHolder.Add(fr.FuncRollback{Name: "CloseCheckpointMirror", Fn: cp.closeMirrorBackend})
+	}
+
 	err = cp.prepare(tctx)
 
 	return
@@ -497,9 +599,17 @@ func (cp *RemoteCheckPoint) Init(tctx *tcontext.Context) (err error) {
 
 // Close implements CheckPoint.Close.
 func (cp *RemoteCheckPoint) Close() {
+	cp.closeMirrorBackend()
 	dbconn.CloseBaseDB(cp.logCtx, cp.db)
 }
 
+func (cp *RemoteCheckPoint) closeMirrorBackend() {
+	if cp.mirrorBackend != nil {
+		cp.mirrorBackend.Close()
+		cp.mirrorBackend = nil
+	}
+}
+
 // ResetConn implements CheckPoint.ResetConn.
 func (cp *RemoteCheckPoint) ResetConn(tctx *tcontext.Context) error {
 	return cp.dbConn.ResetConn(tctx)
@@ -654,6 +764,153 @@ func (cp *RemoteCheckPoint) DeleteSchemaPoint(tctx *tcontext.Context, sourceSche
 	return nil
 }
 
+// UpstreamTablesFunc returns the names of the tables in the schema of upstream.
+type UpstreamTablesFunc func(ctx context.Context, schema string) ([]string, error)
+
+// CompactOutdatedPoints implements CheckPoint.CompactOutdatedPoints.
+// the table checkpoints are deleted in batches, and the global checkpoint is never deleted.
+// The schema tracker is populated lazily, so a table missing in it is only treated as dropped
+// if it doesn't exist in upstream either. The upstream is queried without holding the lock,
+// so the candidates are checked again before deleted.
+func (cp *RemoteCheckPoint) CompactOutdatedPoints(
+	tctx *tcontext.Context, schemaTracker *schema.Tracker, upstreamTables UpstreamTablesFunc,
+) error {
+	candidates, err := cp.tablesMissingInTracker(schemaTracker)
+	if err != nil || len(candidates) == 0 {
+		return err
+	}
+
+	outdated := make([]*filter.Table, 0)
+	for sourceSchema, names := range candidates {
+		tables, err := upstreamTables(tctx.Ctx, sourceSchema)
+		if err != nil {
+			return err
+		}
+		upstream := make(map[string]struct{}, len(tables))
+		for _, name := range tables {
+			upstream[name] = struct{}{}
+		}
+		for _, name := range names {
+			if _, ok := upstream[name]; !ok {
+				outdated = append(outdated, &filter.Table{Schema: sourceSchema, Name: name})
+			}
+		}
+	}
+
+	cp.Lock()
+	defer cp.Unlock()
+
+	// the table may be saved or created in schema tracker while querying upstream.
+	stale := outdated[:0]
+	for _, table := range outdated {
+		if _, ok := cp.points[table.Schema][table.Name]; !ok {
+			continue
+		}
+		if _, err := schemaTracker.GetTableInfo(table); err == nil {
+			continue
+		} else if !schema.IsTableNotExists(err) {
+			return err
+		}
+		stale = append(stale, table)
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Schema != stale[j].Schema {
+			return stale[i].Schema < stale[j].Schema
+		}
+		return stale[i].Name < stale[j].Name
+	})
+
+	cp.logCtx.L().Info("compact outdated table checkpoints", zap.Stringers("tables", stale))
+	return cp.deleteTablePoints(tctx, stale)
+}
+
+// tablesMissingInTracker returns the tables which have checkpoints but are not
+// in the schema tracker, grouped by schema.
+func (cp *RemoteCheckPoint) tablesMissingInTracker(schemaTracker *schema.Tracker) (map[string][]string, error) {
+	cp.RLock()
+	defer cp.RUnlock()
+
+	missing := make(map[string][]string)
+	for sourceSchema, mSchema := range cp.points {
+		for sourceTable := range mSchema {
+			_, err := schemaTracker.GetTableInfo(&filter.Table{Schema: sourceSchema, Name: sourceTable})
+			if err == nil {
+				continue
+			}
+			if !schema.IsTableNotExists(err) {
+				return nil, err
+			}
+			missing[sourceSchema] = append(missing[sourceSchema], sourceTable)
+		}
+	}
+	return missing, nil
+}
+
+// DeleteTablePoints implements CheckPoint.DeleteTablePoints.
+func (cp *RemoteCheckPoint) DeleteTablePoints(tctx *tcontext.Context, tables []*filter.Table) error {
+	cp.Lock()
+	defer cp.Unlock()
+
+	existing := make([]*filter.Table, 0, len(tables))
+	for _, table := range tables {
+		if _, ok := cp.points[table.Schema][table.Name]; ok {
+			existing = append(existing, table)
+		}
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	cp.logCtx.L().Info("delete table checkpoints", zap.Stringers("tables", existing))
+	return cp.deleteTablePoints(tctx, existing)
+}
+
+// deleteTablePoints deletes the table checkpoints in one transaction, and removes
+// them from memory after success. The global checkpoint is never deleted.
+// Caller should hold the lock.
+func (cp *RemoteCheckPoint) deleteTablePoints(tctx *tcontext.Context, tables []*filter.Table) error {
+	sqls := make([]string, 0, (len(tables)+batchFlushPoints-1)/batchFlushPoints)
+	args := make([][]interface{}, 0, cap(sqls))
+	for start := 0; start < len(tables); start += batchFlushPoints {
+		end := start + batchFlushPoints
+		if end > len(tables) {
+			end = len(tables)
+		}
+		var buf strings.Builder
+		buf.WriteString(`DELETE FROM ` + cp.tableName + ` WHERE id = ? AND is_global = ? AND (`)
+		arg := make([]interface{}, 0, 2+2*(end-start))
+		arg = append(arg, cp.id, false)
+		for i, table := range tables[start:end] {
+			if i > 0 {
+				buf.WriteString(" OR ")
+			}
+			buf.WriteString("(cp_schema = ? AND cp_table = ?)")
+			arg = append(arg, table.Schema, table.Name)
+		}
+		buf.WriteString(")")
+		sqls = append(sqls, buf.String())
+		args = append(args, arg)
+	}
+
+	// use a new context apart from syncer, to make sure when syncer call `cancel` checkpoint could update
+	tctx2, cancel := tctx.WithContext(context.Background()).WithTimeout(maxDMLConnectionDuration)
+	defer cancel()
+	_, err := cp.dbConn.ExecuteSQL(tctx2, cp.metricProxies, sqls, args...)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		delete(cp.points[table.Schema], table.Name)
+		if len(cp.points[table.Schema]) == 0 {
+			delete(cp.points, table.Schema)
+		}
+	}
+	return nil
+}
+
 // IsOlderThanTablePoint implements CheckPoint.IsOlderThanTablePoint.
 // This function is used to skip old binlog events. Table checkpoint is saved after dispatching a binlog event.
 //   - For GTID based and position based replication, DML handling is a bit different but comparison is same here.
@@ -784,7 +1041,8 @@ func (cp *RemoteCheckPoint) FlushPointsExcept(
 	// use a new context apart from syncer, to make sure when syncer call `cancel` checkpoint could update
 	tctx2, cancel := tctx.WithContext(context.Background()).WithTimeout(maxDMLConnectionDuration)
 	defer cancel()
-	err := cp.dbConn.ExecuteSQLAutoSplit(tctx2, cp.metricProxies, sqls, args...)
+	// the snapshot is kept until the flush succeeds or retries are exhausted.
+	err := cp.dbConn.ExecuteSQLAutoSplitWithRetry(tctx2, cp.metricProxies, cp.flushRetryCount, cp.flushRetryInterval, sqls, args...)
 	if err != nil {
 		return err
 	}
@@ -800,9 +1058,49 @@ func (cp *RemoteCheckPoint) FlushPointsExcept(
 		point.tableCp.flushBy(point.snapshotTableCP)
 	}
 	cp.needFlushSafeModeExitPoint.Store(false)
+	cp.mirrorFlushedPoints()
 	return nil
 }
 
+// mirrorFlushedPoints sends the flushed checkpoints to the mirror backend, which
+// writes them in background.
+func (cp *RemoteCheckPoint) mirrorFlushedPoints() {
+	if cp.mirrorBackend == nil {
+		return
+	}
+
+	cp.RLock()
+	mirror := &checkpointMirror{
+		GlobalPoint: newCheckpointMirrorLocation(cp.globalPoint.FlushedMySQLLocation()),
+		TablePoints: make(map[string]map[string]checkpointMirrorTablePoint, len(cp.points)),
+	}
+	if cp.safeModeExitPoint != nil {
+		loc := newCheckpointMirrorLocation(*cp.safeModeExitPoint)
+		mirror.SafeModeExitPoint = &loc
+	}
+	for schema, tables := range cp.points {
+		mSchema := make(map[string]checkpointMirrorTablePoint, len(tables))
+		for table, point := range tables {
+			point.RLock()
+			mSchema[table] = checkpointMirrorTablePoint{
+				Location:  newCheckpointMirrorLocation(point.flushedPoint.location),
+				TableInfo: point.flushedPoint.ti,
+			}
+			point.RUnlock()
+		}
+		mirror.TablePoints[schema] = mSchema
+	}
+	cp.RUnlock()
+
+	data, err := mirror.marshal()
+	if err != nil {
+		cp.logCtx.L().Warn("failed to mirror checkpoints", zap.Error(err))
+		return
+	}
+	// the async backend never blocks, so the context is unused.
+	_ = cp.mirrorBackend.Write(context.Background(), data)
+}
+
 // FlushPointsWithTableInfos implements CheckPoint.FlushPointsWithTableInfos.
 func (cp *RemoteCheckPoint) FlushPointsWithTableInfos(tctx *tcontext.Context, tables []*filter.Table, tis []*model.TableInfo) error {
 	cp.Lock()
@@ -921,6 +1219,63 @@ func (cp *RemoteCheckPoint) TablePoint() map[string]map[string]binlog.Location {
 	return tablePoint
 }
 
+// EstimatedMemoryBytes returns an approximate size of the in-memory points,
+// including their table infos. It's cheap and doesn't serialize table infos.
+func (cp *RemoteCheckPoint) EstimatedMemoryBytes() int64 {
+	cp.RLock()
+	defer cp.RUnlock()
+
+	var size int64
+	for schema, tables := range cp.points {
+		size += int64(len(schema))
+		for table, point := range tables {
+			size += int64(len(table)) + point.estimatedMemoryBytes()
+		}
+	}
+	if cp.globalPoint != nil {
+		size += cp.globalPoint.estimatedMemoryBytes()
+	}
+	return size
+}
+
+// ExportState serializes all the in-memory points to JSON for diagnostics,
+// including the ones not flushed yet.
+func (cp *RemoteCheckPoint) ExportState() ([]byte, error) {
+	cp.RLock()
+	state := &checkpointState{
+		Version:     checkpointStateVersion,
+		TablePoints: make([]checkpointStateTablePoint, 0, len(cp.points)),
+	}
+	if cp.globalPoint != nil {
+		state.GlobalPoint = newCheckpointMirrorLocation(cp.globalPoint.MySQLLocation())
+		state.FlushedGlobalPoint = newCheckpointMirrorLocation(cp.globalPoint.FlushedMySQLLocation())
+	}
+	if cp.safeModeExitPoint != nil {
+		loc := newCheckpointMirrorLocation(*cp.safeModeExitPoint)
+		state.SafeModeExitPoint = &loc
+	}
+	for schema, tables := range cp.points {
+		for table, point := range tables {
+			state.TablePoints = append(state.TablePoints, checkpointStateTablePoint{
+				Schema:          schema,
+				Table:           table,
+				Location:        newCheckpointMirrorLocation(point.MySQLLocation()),
+				FlushedLocation: newCheckpointMirrorLocation(point.FlushedMySQLLocation()),
+			})
+		}
+	}
+	cp.RUnlock()
+
+	sort.Slice(state.TablePoints, func(i, j int) bool {
+		if state.TablePoints[i].Schema != state.TablePoints[j].Schema {
+			return state.TablePoints[i].Schema < state.TablePoints[j].Schema
+		}
+		return state.TablePoints[i].Table < state.TablePoints[j].Table
+	})
+	data, err := json.Marshal(state)
+	return data, errors.Trace(err)
+}
+
 func (cp *RemoteCheckPoint) GetTableInfo(schema string, table string) *model.TableInfo {
 	cp.RLock()
 	defer cp.RUnlock()
@@ -1060,6 +1415,9 @@ func (cp *RemoteCheckPoint) Load(tctx *tcontext.Context) error {
 		exitSafeBinlogGTIDSet sql.NullString
 		tiBytes               []byte
 		isGlobal              bool
+
+		globalLocation *binlog.Location
+		tableLocations = make(map[filter.Table]binlog.Location)
 	)
 	for rows.Next() {
 		err := rows.Scan(&cpSchema, &cpTable, &binlogName, &binlogPos, &binlogGTIDSet, &exitSafeBinlogName, &exitSafeBinlogPos, &exitSafeBinlogGTIDSet, &tiBytes, &isGlobal)
@@ -1080,6 +1438,7 @@ func (cp *RemoteCheckPoint) Load(tctx *tcontext.Context) error {
 			gset,
 		)
 		if isGlobal {
+			globalLocation = &location
 			// Use IsFreshPosition here to make sure checkpoint can be updated if gset is empty
 			if !binlog.IsFreshPosition(location, cp.cfg.Flavor, cp.cfg.EnableGTID) {
 				cp.globalPoint = newBinlogPoint(location, location, nil, nil, cp.cfg.EnableGTID)
@@ -1130,9 +1489,43 @@ func (cp *RemoteCheckPoint) Load(tctx *tcontext.Context) error {
 			cp.points[cpSchema] = mSchema
 		}
 		mSchema[cpTable] = newBinlogPoint(location, location, ti, ti, cp.cfg.EnableGTID)
+		tableLocations[filter.Table{Schema: cpSchema, Name: cpTable}] = location
+	}
+
+	if err = rows.Err(); err != nil {
+		return terror.DBErrorAdapt(err, cp.dbConn.Scope(), terror.ErrDBDriverError)
 	}
 
-	return terror.DBErrorAdapt(rows.Err(), cp.dbConn.Scope(), terror.ErrDBDriverError)
+	// the global point may be loaded after table points, so check them at last.
+	if globalLocation != nil {
+		for table, location := range tableLocations {
+			cp.checkTablePointRegression(table, location, *globalLocation)
+		}
+	}
+	return nil
+}
+
+// checkTablePointRegression warns if the loaded location of a table is ahead
+// of the loaded global location. The global point is flushed along with or
+// after the table points, so the table point can't be ahead of it normally,
+// and the global point has regressed then, data of the table may be replayed
+// or skipped. A table point behind the global point is normal, since it stays
+// at the last event of the table.
+func (cp *RemoteCheckPoint) checkTablePointRegression(table filter.Table, location, globalLocation binlog.Location) {
+	if binlog.CompareLocation(location, globalLocation, cp.cfg.EnableGTID) <= 0 {
+		cp.logCtx.L().Debug("loaded table checkpoint is not ahead of the global checkpoint",
+			zap.Stringer("table", &table),
+			zap.Stringer("table checkpoint", location),
+			zap.Stringer("global checkpoint", globalLocation))
+		return
+	}
+	cp.logCtx.L().Warn("loaded table checkpoint is ahead of the global checkpoint, data of the table may be replayed or skipped",
+		zap.Stringer("table", &table),
+		zap.Stringer("table checkpoint", location),
+		zap.Stringer("global checkpoint", globalLocation))
+	if cp.metricProxies != nil && cp.metricProxies.Metrics != nil {
+		cp.metricProxies.Metrics.TablePointRegressionTotal.Inc()
+	}
 }
 
 // LoadIntoSchemaTracker loads table infos of all points into schema tracker.
@@ -1300,6 +1693,22 @@ func (cp *RemoteCheckPoint) genUpdateSQL(cpSchema, cpTable string, location binl
 }
 
 func (cp *RemoteCheckPoint) parseMetaData(ctx context.Context) (*binlog.Location, *binlog.Location, error) {
+	// `metadata.json` is written by custom tools rather than mydumper, it takes precedence if exists
+	// existence is checked first, since not every external storage returns
+	// an error recognized by IsNotExistError when reading a missing file
+	jsonFilename := "metadata.json"
+	exists, err := storage.FileExists(ctx, cp.cfg.LoaderConfig.Dir, jsonFilename, cp.cfg.ExtStorage)
+	if err != nil {
+		return nil, nil, terror.ErrParseMetadataJSON.Delegate(err, jsonFilename)
+	}
+	if exists {
+		loc, loc2, err := dumpling.ParseMetaDataJSON(ctx, cp.cfg.LoaderConfig.Dir, jsonFilename, cp.cfg.Flavor, cp.cfg.ExtStorage)
+		if err != nil {
+			return nil, nil, terror.ErrParseMetadataJSON.Delegate(err, jsonFilename)
+		}
+		return loc, loc2, nil
+	}
+
 	// `metadata` is mydumper's output meta file name
 	filename := "metadata"
 	loc, loc2, err := dumpling.ParseMetaData(ctx, cp.cfg.LoaderConfig.Dir, filename, cp.cfg.Flavor, cp.cfg.ExtStorage)
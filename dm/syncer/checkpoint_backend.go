@@ -0,0 +1,194 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/pingcap/errors"
+	bstorage "github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tiflow/dm/pkg/binlog"
+	"github.com/pingcap/tiflow/dm/pkg/log"
+	"github.com/pingcap/tiflow/dm/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// CheckpointBackend mirrors the serialized checkpoints to somewhere other than
+// the downstream database, such as external object storage.
+// The downstream database is still the source of truth of checkpoints.
+type CheckpointBackend interface {
+	// Write writes the serialized checkpoints, the previous ones are overwritten.
+	Write(ctx context.Context, data []byte) error
+}
+
+// externalStorageCheckpointBackend writes checkpoints to a file of external storage.
+type externalStorageCheckpointBackend struct {
+	storage  bstorage.ExternalStorage
+	fileName string
+}
+
+// newExternalStorageCheckpointBackend creates a backend writing to
+// `<task><checkpoint table suffix>.<source ID>.checkpoint.json`, so that tasks
+// with the same name but different suffixes don't overwrite each other.
+func newExternalStorageCheckpointBackend(
+	ctx context.Context, rawURL, task, suffix, sourceID string,
+) (CheckpointBackend, error) {
+	s, err := storage.CreateStorage(ctx, rawURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &externalStorageCheckpointBackend{
+		storage:  s,
+		fileName: fmt.Sprintf("%s%s.%s.checkpoint.json", task, suffix, sourceID),
+	}, nil
+}
+
+// Write implements CheckpointBackend.Write.
+func (b *externalStorageCheckpointBackend) Write(ctx context.Context, data []byte) error {
+	return errors.Trace(b.storage.WriteFile(ctx, b.fileName, data))
+}
+
+// asyncCheckpointBackend writes the checkpoints to the underlying backend in
+// background, so that a slow backend doesn't block the flush of checkpoints.
+// Only the latest data is written if the backend falls behind.
+type asyncCheckpointBackend struct {
+	backend CheckpointBackend
+	logger  log.Logger
+
+	mu      sync.Mutex
+	pending []byte
+
+	notifyCh chan struct{}
+	closeCh  chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newAsyncCheckpointBackend(backend CheckpointBackend, logger log.Logger) *asyncCheckpointBackend {
+	b := &asyncCheckpointBackend{
+		backend:  backend,
+		logger:   logger,
+		notifyCh: make(chan struct{}, 1),
+		closeCh:  make(chan struct{}),
+	}
+	b.wg.Add(1)
+	go b.run()
+	return b
+}
+
+// Write implements CheckpointBackend.Write. It never blocks, and the errors of
+// the underlying backend are only logged.
+func (b *asyncCheckpointBackend) Write(_ context.Context, data []byte) error {
+	b.mu.Lock()
+	b.pending = data
+	b.mu.Unlock()
+	select {
+	case b.notifyCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (b *asyncCheckpointBackend) run() {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-b.notifyCh:
+			b.writePending()
+		case <-b.closeCh:
+			// write the last checkpoints before exiting.
+			b.writePending()
+			return
+		}
+	}
+}
+
+func (b *asyncCheckpointBackend) writePending() {
+	b.mu.Lock()
+	data := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+	if data == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorCheckpointTimeout)
+	defer cancel()
+	if err := b.backend.Write(ctx, data); err != nil {
+		b.logger.Warn("failed to mirror checkpoints", zap.Error(err))
+	}
+}
+
+// Close stops the background writing after the pending data is written.
+func (b *asyncCheckpointBackend) Close() {
+	close(b.closeCh)
+	b.wg.Wait()
+}
+
+type checkpointMirrorLocation struct {
+	BinlogName string `json:"binlog-name"`
+	BinlogPos  uint32 `json:"binlog-pos"`
+	BinlogGTID string `json:"binlog-gtid"`
+	Suffix     int    `json:"suffix"`
+}
+
+func newCheckpointMirrorLocation(location binlog.Location) checkpointMirrorLocation {
+	return checkpointMirrorLocation{
+		BinlogName: location.Position.Name,
+		BinlogPos:  location.Position.Pos,
+		BinlogGTID: location.GTIDSetStr(),
+		Suffix:     location.Suffix,
+	}
+}
+
+type checkpointMirrorTablePoint struct {
+	Location  checkpointMirrorLocation `json:"location"`
+	TableInfo *model.TableInfo         `json:"table-info"`
+}
+
+// checkpointMirror is the serialized flushed checkpoints written to CheckpointBackend.
+type checkpointMirror struct {
+	GlobalPoint       checkpointMirrorLocation                         `json:"global-point"`
+	SafeModeExitPoint *checkpointMirrorLocation                        `json:"safe-mode-exit-point,omitempty"`
+	TablePoints       map[string]map[string]checkpointMirrorTablePoint `json:"table-points"`
+}
+
+func (m *checkpointMirror) marshal() ([]byte, error) {
+	data, err := json.Marshal(m)
+	return data, errors.Trace(err)
+}
+
+// checkpointStateVersion is the format version of checkpointState, bump it
+// when the format changes incompatibly.
+const checkpointStateVersion = 1
+
+type checkpointStateTablePoint struct {
+	Schema          string                   `json:"schema"`
+	Table           string                   `json:"table"`
+	Location        checkpointMirrorLocation `json:"location"`
+	FlushedLocation checkpointMirrorLocation `json:"flushed-location"`
+}
+
+// checkpointState is the serialized in-memory checkpoints for diagnostics.
+// Table points are sorted by schema and table to keep the output stable.
+type checkpointState struct {
+	Version            int                         `json:"version"`
+	GlobalPoint        checkpointMirrorLocation    `json:"global-point"`
+	FlushedGlobalPoint checkpointMirrorLocation    `json:"flushed-global-point"`
+	SafeModeExitPoint  *checkpointMirrorLocation   `json:"safe-mode-exit-point,omitempty"`
+	TablePoints        []checkpointStateTablePoint `json:"table-points"`
+}
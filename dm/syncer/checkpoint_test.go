@@ -19,12 +19,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-mysql-org/go-mysql/mysql"
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	tidbddl "github.com/pingcap/tidb/pkg/ddl"
 	"github.com/pingcap/tidb/pkg/parser/ast"
@@ -39,7 +43,10 @@ import (
 	dlog "github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/retry"
 	"github.com/pingcap/tiflow/dm/pkg/schema"
+	"github.com/pingcap/tiflow/dm/pkg/terror"
 	"github.com/pingcap/tiflow/dm/syncer/dbconn"
+	"github.com/pingcap/tiflow/dm/syncer/metrics"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
 )
@@ -567,3 +574,588 @@ func TestLastFlushOutdated(t *testing.T) {
 	// though snapshot is nil, checkpoint is not outdated
 	require.False(t, checkpoint.LastFlushOutdated())
 }
+
+func TestRemoteCheckPointEstimatedMemoryBytes(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+
+	cp := NewRemoteCheckPoint(tcontext.Background(), cfg, nil, "1")
+	checkpoint := cp.(*RemoteCheckPoint)
+	emptySize := checkpoint.EstimatedMemoryBytes()
+	require.Greater(t, emptySize, int64(0))
+
+	parser, err := conn.GetParserFromSQLModeStr("")
+	require.NoError(t, err)
+	createNode, err := parser.ParseOneStmt("create table tbl1(id int primary key, name varchar(20))", "", "")
+	require.NoError(t, err)
+	ti, err := tidbddl.BuildTableInfoFromAST(createNode.(*ast.CreateTableStmt))
+	require.NoError(t, err)
+
+	location := binlog.MustZeroLocation(cfg.Flavor)
+	checkpoint.SaveTablePoint(&filter.Table{Schema: "test", Name: "tbl1"}, location, ti)
+	smallSize := checkpoint.EstimatedMemoryBytes()
+	require.Greater(t, smallSize, emptySize)
+
+	// a table info with a large comment
+	largeTI := ti.Clone()
+	largeTI.Comment = strings.Repeat("A", 100000)
+	checkpoint.SaveTablePoint(&filter.Table{Schema: "test", Name: "tbl2"}, location, largeTI)
+	largeSize := checkpoint.EstimatedMemoryBytes()
+	require.Greater(t, largeSize-smallSize, int64(100000))
+
+	// the estimate scales with the number of points
+	for i := 0; i < 10; i++ {
+		checkpoint.SaveTablePoint(&filter.Table{Schema: "test", Name: fmt.Sprintf("tbl_%d", i)}, location, largeTI)
+	}
+	require.Greater(t, checkpoint.EstimatedMemoryBytes()-largeSize, int64(10*100000))
+}
+
+type memoryCheckpointBackend struct {
+	sync.Mutex
+	data []byte
+	err  error
+	// release makes Write block until it's closed or the context is done.
+	release chan struct{}
+}
+
+func (b *memoryCheckpointBackend) Write(ctx context.Context, data []byte) error {
+	b.Lock()
+	release, err := b.release, b.err
+	b.Unlock()
+	if release != nil {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	b.Lock()
+	b.data = data
+	b.Unlock()
+	return nil
+}
+
+func (b *memoryCheckpointBackend) getData() []byte {
+	b.Lock()
+	defer b.Unlock()
+	return b.data
+}
+
+func (b *memoryCheckpointBackend) set(err error, release chan struct{}) {
+	b.Lock()
+	defer b.Unlock()
+	b.err, b.release = err, release
+}
+
+func TestRemoteCheckPointMirror(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+	tctx := tcontext.Background()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	dbConn, err := db.Conn(tctx.Ctx)
+	require.NoError(t, err)
+
+	cp := NewRemoteCheckPoint(tctx, cfg, nil, "1")
+	checkpoint := cp.(*RemoteCheckPoint)
+	checkpoint.dbConn = dbconn.NewDBConn(cfg, conn.NewBaseConnForTest(dbConn, &retry.FiniteRetryStrategy{}))
+	backend := &memoryCheckpointBackend{}
+	checkpoint.mirrorBackend = newAsyncCheckpointBackend(backend, dlog.L())
+	defer checkpoint.closeMirrorBackend()
+
+	parser, err := conn.GetParserFromSQLModeStr("")
+	require.NoError(t, err)
+	createNode, err := parser.ParseOneStmt("create table tbl1(id int)", "", "")
+	require.NoError(t, err)
+	ti, err := tidbddl.BuildTableInfoFromAST(createNode.(*ast.CreateTableStmt))
+	require.NoError(t, err)
+
+	location := binlog.MustZeroLocation(cfg.Flavor)
+	location.Position = mysql.Position{Name: "mysql-bin.000003", Pos: 1943}
+	checkpoint.SaveTablePoint(&filter.Table{Schema: "test", Name: "tbl1"}, location, ti)
+	checkpoint.SaveGlobalPoint(location)
+
+	flushSQL := "INSERT INTO .* VALUES.* ON DUPLICATE KEY UPDATE .*"
+	mock.ExpectBegin()
+	mock.ExpectExec(flushSQL).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(flushSQL).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	snap := checkpoint.Snapshot(true)
+	require.NoError(t, checkpoint.FlushPointsExcept(tctx, snap.id, nil, nil, nil))
+
+	require.Eventually(t, func() bool { return backend.getData() != nil }, 5*time.Second, 10*time.Millisecond)
+	var mirror checkpointMirror
+	require.NoError(t, json.Unmarshal(backend.getData(), &mirror))
+	require.Equal(t, "mysql-bin.000003", mirror.GlobalPoint.BinlogName)
+	require.Equal(t, uint32(1943), mirror.GlobalPoint.BinlogPos)
+	tablePoint := mirror.TablePoints["test"]["tbl1"]
+	require.Equal(t, uint32(1943), tablePoint.Location.BinlogPos)
+	require.Len(t, tablePoint.TableInfo.Columns, 1)
+
+	// failing to mirror doesn't fail the flush
+	backend.set(errors.New("mock mirror error"), nil)
+	location.Position.Pos = 2044
+	checkpoint.SaveGlobalPoint(location)
+	mock.ExpectBegin()
+	mock.ExpectExec(flushSQL).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	snap = checkpoint.Snapshot(true)
+	require.NoError(t, checkpoint.FlushPointsExcept(tctx, snap.id, nil, nil, nil))
+	require.Equal(t, uint32(2044), checkpoint.FlushedGlobalPoint().Position.Pos)
+
+	// a slow mirror doesn't block the flush
+	release := make(chan struct{})
+	backend.set(nil, release)
+	location.Position.Pos = 2145
+	checkpoint.SaveGlobalPoint(location)
+	mock.ExpectBegin()
+	mock.ExpectExec(flushSQL).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	snap = checkpoint.Snapshot(true)
+	start := time.Now()
+	require.NoError(t, checkpoint.FlushPointsExcept(tctx, snap.id, nil, nil, nil))
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.Equal(t, uint32(2145), checkpoint.FlushedGlobalPoint().Position.Pos)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the pending checkpoints are written after the slow write finishes
+	close(release)
+	require.Eventually(t, func() bool {
+		var mirror checkpointMirror
+		require.NoError(t, json.Unmarshal(backend.getData(), &mirror))
+		return mirror.GlobalPoint.BinlogPos == 2145
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestExternalStorageCheckpointBackendFileName(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	for _, suffix := range []string{"", "_2"} {
+		backend, err := newExternalStorageCheckpointBackend(ctx, dir, "task", suffix, "source")
+		require.NoError(t, err)
+		require.NoError(t, backend.Write(ctx, []byte(suffix)))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "task.source.checkpoint.json"))
+	require.NoError(t, err)
+	require.Empty(t, data)
+	data, err = os.ReadFile(filepath.Join(dir, "task_2.source.checkpoint.json"))
+	require.NoError(t, err)
+	require.Equal(t, "_2", string(data))
+}
+
+func TestRemoteCheckPointCompactOutdatedPoints(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+	tctx := tcontext.Background()
+
+	trackerDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	trackerConn, err := trackerDB.Conn(tctx.Ctx)
+	require.NoError(t, err)
+	downstreamTrackConn := dbconn.NewDBConn(cfg, conn.NewBaseConnForTest(trackerConn, &retry.FiniteRetryStrategy{}))
+	schemaTracker, err := schema.NewTestTracker(tctx.Ctx, cfg.Name, downstreamTrackConn, dlog.L())
+	require.NoError(t, err)
+	defer schemaTracker.Close() //nolint
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	dbConn, err := db.Conn(tctx.Ctx)
+	require.NoError(t, err)
+	cp := NewRemoteCheckPoint(tctx, cfg, nil, cpid)
+	checkpoint := cp.(*RemoteCheckPoint)
+	checkpoint.dbConn = dbconn.NewDBConn(cfg, conn.NewBaseConnForTest(dbConn, &retry.FiniteRetryStrategy{}))
+
+	require.NoError(t, schemaTracker.CreateSchemaIfNotExists("test"))
+	parser, err := conn.GetParserFromSQLModeStr("")
+	require.NoError(t, err)
+	createNode, err := parser.ParseOneStmt("create table tbl1(id int)", "", "")
+	require.NoError(t, err)
+	require.NoError(t, schemaTracker.Exec(tctx.Ctx, "test", createNode))
+
+	location := binlog.MustZeroLocation(cfg.Flavor)
+	for _, table := range []*filter.Table{
+		{Schema: "test", Name: "tbl1"},
+		{Schema: "test", Name: "tbl2"},
+		{Schema: "test", Name: "tbl3"},
+		{Schema: "dropped", Name: "tbl1"},
+	} {
+		checkpoint.SaveTablePoint(table, location, nil)
+	}
+	// test.tbl3 is not loaded into schema tracker yet, but still exists in upstream
+	upstreamTables := func(_ context.Context, schema string) ([]string, error) {
+		// the checkpoint isn't locked while querying upstream
+		checkpoint.SaveTablePoint(&filter.Table{Schema: "test", Name: "tbl1"}, location, nil)
+		if schema == "test" {
+			return []string{"tbl1", "tbl3"}, nil
+		}
+		return nil, nil
+	}
+
+	require.Len(t, checkpoint.points, 2)
+
+	// only the table checkpoints of tables neither in schema tracker nor upstream are deleted
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM .* WHERE id = \\? AND is_global = \\? AND \\(\\(cp_schema = \\? AND cp_table = \\?\\) OR \\(cp_schema = \\? AND cp_table = \\?\\)\\)").
+		WithArgs(cpid, false, "dropped", "tbl1", "test", "tbl2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	require.NoError(t, checkpoint.CompactOutdatedPoints(tctx, schemaTracker, upstreamTables))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Len(t, checkpoint.points, 1)
+	require.Len(t, checkpoint.points["test"], 2)
+	require.Contains(t, checkpoint.points["test"], "tbl1")
+	require.Contains(t, checkpoint.points["test"], "tbl3")
+
+	// the table checkpoints are kept if upstream can't be queried
+	checkpoint.SaveTablePoint(&filter.Table{Schema: "test", Name: "tbl2"}, location, nil)
+	require.Error(t, checkpoint.CompactOutdatedPoints(tctx, schemaTracker,
+		func(context.Context, string) ([]string, error) { return nil, errors.New("upstream error") }))
+	require.Contains(t, checkpoint.points["test"], "tbl2")
+	delete(checkpoint.points["test"], "tbl2")
+
+	// the table created in schema tracker while querying upstream is not deleted
+	checkpoint.SaveTablePoint(&filter.Table{Schema: "test", Name: "tbl2"}, location, nil)
+	require.NoError(t, checkpoint.CompactOutdatedPoints(tctx, schemaTracker,
+		func(context.Context, string) ([]string, error) {
+			createNode, err := parser.ParseOneStmt("create table tbl2(id int)", "", "")
+			require.NoError(t, err)
+			require.NoError(t, schemaTracker.Exec(tctx.Ctx, "test", createNode))
+			return []string{"tbl1", "tbl3"}, nil
+		}))
+	require.Contains(t, checkpoint.points["test"], "tbl2")
+	delete(checkpoint.points["test"], "tbl2")
+
+	// no outdated table checkpoints, no SQL executed
+	require.NoError(t, checkpoint.CompactOutdatedPoints(tctx, schemaTracker, upstreamTables))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoteCheckPointFlushRetry(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+	cfg.CheckpointFlushRetryCount = 2
+	tctx := tcontext.Background()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	dbConn, err := db.Conn(tctx.Ctx)
+	require.NoError(t, err)
+
+	cp := NewRemoteCheckPoint(tctx, cfg, nil, "1")
+	checkpoint := cp.(*RemoteCheckPoint)
+	checkpoint.dbConn = dbconn.NewDBConn(cfg, conn.NewBaseConnForTest(dbConn, &retry.FiniteRetryStrategy{}))
+	require.Equal(t, 2, checkpoint.flushRetryCount)
+	require.Equal(t, defaultFlushRetryInterval, checkpoint.flushRetryInterval)
+	checkpoint.flushRetryInterval = time.Millisecond
+
+	location := binlog.MustZeroLocation(cfg.Flavor)
+	location.Position = mysql.Position{Name: "mysql-bin.000003", Pos: 1943}
+	checkpoint.SaveGlobalPoint(location)
+
+	flushSQL := "INSERT INTO .* VALUES.* ON DUPLICATE KEY UPDATE .*"
+	// the first attempt meets a retryable error
+	mock.ExpectBegin()
+	mock.ExpectExec(flushSQL).WillReturnError(newMysqlErr(mysql.ER_LOCK_DEADLOCK, "Deadlock found when trying to get lock"))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec(flushSQL).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	snap := checkpoint.Snapshot(true)
+	require.NoError(t, checkpoint.FlushPointsExcept(tctx, snap.id, nil, nil, nil))
+	require.Equal(t, uint32(1943), checkpoint.FlushedGlobalPoint().Position.Pos)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// non-retryable errors are returned directly
+	location.Position.Pos = 2044
+	checkpoint.SaveGlobalPoint(location)
+	mock.ExpectBegin()
+	mock.ExpectExec(flushSQL).WillReturnError(errors.New("mock non-retryable error"))
+	mock.ExpectRollback()
+	snap = checkpoint.Snapshot(true)
+	require.Error(t, checkpoint.FlushPointsExcept(tctx, snap.id, nil, nil, nil))
+	require.Equal(t, uint32(1943), checkpoint.FlushedGlobalPoint().Position.Pos)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFlushedCheckpointAgeMetric(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+	tctx := tcontext.Background()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	dbConn, err := db.Conn(tctx.Ctx)
+	require.NoError(t, err)
+
+	metricProxies := metrics.DefaultMetricsProxies.CacheForOneTask(cfg.Name, "worker", cfg.SourceID)
+	ageGauge := metricProxies.Metrics.FlushedCheckpointAgeGauge
+	cp := NewRemoteCheckPoint(tctx, cfg, metricProxies, "1")
+	checkpoint := cp.(*RemoteCheckPoint)
+	checkpoint.dbConn = dbconn.NewDBConn(cfg, conn.NewBaseConnForTest(dbConn, &retry.FiniteRetryStrategy{}))
+	syncer := &Syncer{checkpoint: cp, metricsProxies: metricProxies}
+
+	// not started
+	ageGauge.Set(-1)
+	syncer.updateFlushedCheckpointAgeMetric()
+	require.Equal(t, float64(-1), promtestutil.ToFloat64(ageGauge))
+
+	// never flushed, the age is counted from the start of the syncer
+	syncer.start.Store(time.Now().Add(-time.Hour))
+	syncer.updateFlushedCheckpointAgeMetric()
+	require.GreaterOrEqual(t, promtestutil.ToFloat64(ageGauge), float64(3600))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO .* VALUES.* ON DUPLICATE KEY UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	snap := checkpoint.Snapshot(true)
+	require.NoError(t, checkpoint.FlushPointsExcept(tctx, snap.id, nil, nil, nil))
+	syncer.updateFlushedCheckpointAgeMetric()
+	require.Less(t, promtestutil.ToFloat64(ageGauge), float64(60))
+
+	// the age keeps growing without flushing
+	checkpoint.globalPointSaveTime = time.Now().Add(-time.Minute)
+	syncer.updateFlushedCheckpointAgeMetric()
+	require.GreaterOrEqual(t, promtestutil.ToFloat64(ageGauge), float64(60))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM .* WHERE id = \\?").WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, checkpoint.Clear(tctx))
+	syncer.updateFlushedCheckpointAgeMetric()
+	require.GreaterOrEqual(t, promtestutil.ToFloat64(ageGauge), float64(3600))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoteCheckPointDeleteTablePoints(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+	tctx := tcontext.Background()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	dbConn, err := db.Conn(tctx.Ctx)
+	require.NoError(t, err)
+	cp := NewRemoteCheckPoint(tctx, cfg, nil, cpid)
+	checkpoint := cp.(*RemoteCheckPoint)
+	checkpoint.dbConn = dbconn.NewDBConn(cfg, conn.NewBaseConnForTest(dbConn, &retry.FiniteRetryStrategy{}))
+
+	location := binlog.MustZeroLocation(cfg.Flavor)
+	tables := make([]*filter.Table, 0, batchFlushPoints+1)
+	for i := 0; i < batchFlushPoints+1; i++ {
+		table := &filter.Table{Schema: "test", Name: fmt.Sprintf("tbl_%d", i)}
+		tables = append(tables, table)
+		checkpoint.SaveTablePoint(table, location, nil)
+	}
+	kept := &filter.Table{Schema: "kept", Name: "tbl"}
+	checkpoint.SaveTablePoint(kept, location, nil)
+
+	// all the table points are deleted in one transaction, the not existing table is ignored
+	deleteSQL := "DELETE FROM .* WHERE id = \\? AND is_global = \\? AND \\(\\(cp_schema = \\? AND cp_table = \\?\\).*\\)"
+	mock.ExpectBegin()
+	mock.ExpectExec(deleteSQL).WillReturnResult(sqlmock.NewResult(0, int64(batchFlushPoints)))
+	mock.ExpectExec(deleteSQL).WithArgs(cpid, false, "test", fmt.Sprintf("tbl_%d", batchFlushPoints)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	notExisting := &filter.Table{Schema: "test", Name: "not_existing"}
+	require.NoError(t, checkpoint.DeleteTablePoints(tctx, append(tables, notExisting)))
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Len(t, checkpoint.points, 1)
+	require.Contains(t, checkpoint.points[kept.Schema], kept.Name)
+
+	// nothing to delete
+	require.NoError(t, checkpoint.DeleteTablePoints(tctx, tables))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoteCheckPointLoadMetaFromJSON(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+	cfg.Mode = config.ModeAll
+	cfg.Dir = t.TempDir()
+	tctx := tcontext.Background()
+
+	cases := []struct {
+		flavor   string
+		gtidStr  string
+		gtidStr2 string
+	}{
+		{mysql.MySQLFlavor, "97b5142f-e19c-11e8-808c-0242ac110005:1-13", "97b5142f-e19c-11e8-808c-0242ac110005:1-20"},
+		{mysql.MariaDBFlavor, "0-1-2", "0-1-5"},
+	}
+	for _, c := range cases {
+		cfg.Flavor = c.flavor
+		cfg.EnableGTID = true
+		// the mydumper metadata should be ignored when metadata.json exists
+		require.NoError(t, os.WriteFile(filepath.Join(cfg.Dir, "metadata"), []byte(
+			"SHOW MASTER STATUS:\n\tLog: mysql-bin.000001\n\tPos: 4\n\tGTID:\n\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(cfg.Dir, "metadata.json"), []byte(fmt.Sprintf(
+			`{"binlog-name": "mysql-bin.000003", "binlog-pos": 1943, "binlog-gtid": %q,
+			"exit-safe-mode-location": {"binlog-name": "mysql-bin.000004", "binlog-pos": 2052, "binlog-gtid": %q}}`,
+			c.gtidStr, c.gtidStr2)), 0o644))
+
+		cp := NewRemoteCheckPoint(tctx, cfg, nil, cpid)
+		require.NoError(t, cp.LoadMeta(tctx.Ctx))
+		require.Equal(t, mysql.Position{Name: "mysql-bin.000003", Pos: 1943}, cp.GlobalPoint().Position)
+		require.Equal(t, c.gtidStr, cp.GlobalPoint().GTIDSetStr())
+		require.NotNil(t, cp.SafeModeExitPoint())
+		require.Equal(t, mysql.Position{Name: "mysql-bin.000004", Pos: 2052}, cp.SafeModeExitPoint().Position)
+		require.Equal(t, c.gtidStr2, cp.SafeModeExitPoint().GTIDSetStr())
+	}
+
+	// invalid GTID set in metadata.json
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.Dir, "metadata.json"), []byte(
+		`{"binlog-name": "mysql-bin.000003", "binlog-pos": 1943, "binlog-gtid": "invalid"}`), 0o644))
+	cp := NewRemoteCheckPoint(tctx, cfg, nil, cpid)
+	require.True(t, terror.ErrParseMetadataJSON.Equal(cp.LoadMeta(tctx.Ctx)))
+
+	// invalid format of metadata.json
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.Dir, "metadata.json"), []byte(`{"binlog-name"`), 0o644))
+	cp = NewRemoteCheckPoint(tctx, cfg, nil, cpid)
+	require.True(t, terror.ErrParseMetadataJSON.Equal(cp.LoadMeta(tctx.Ctx)))
+
+	// fall back to mydumper metadata
+	require.NoError(t, os.Remove(filepath.Join(cfg.Dir, "metadata.json")))
+	cfg.Flavor = mysql.MySQLFlavor
+	cp = NewRemoteCheckPoint(tctx, cfg, nil, cpid)
+	require.NoError(t, cp.LoadMeta(tctx.Ctx))
+	require.Equal(t, mysql.Position{Name: "mysql-bin.000001", Pos: 4}, cp.GlobalPoint().Position)
+	require.Nil(t, cp.SafeModeExitPoint())
+}
+
+func TestRemoteCheckPointExportState(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+	cfg.EnableGTID = true
+	tctx := tcontext.Background()
+
+	cp := NewRemoteCheckPoint(tctx, cfg, nil, cpid)
+	checkpoint := cp.(*RemoteCheckPoint)
+	gset, err := gtid.ParserGTID(mysql.MySQLFlavor, "97b5142f-e19c-11e8-808c-0242ac110005:1-13")
+	require.NoError(t, err)
+	global := binlog.NewLocation(mysql.Position{Name: "mysql-bin.000003", Pos: 1943}, gset)
+	checkpoint.globalPoint = newBinlogPoint(global, global, nil, nil, true)
+	cp.SaveGlobalPoint(binlog.NewLocation(mysql.Position{Name: "mysql-bin.000003", Pos: 2000}, gset))
+	cp.SaveTablePoint(&filter.Table{Schema: "db2", Name: "tb1"}, binlog.NewLocation(mysql.Position{Name: "mysql-bin.000003", Pos: 1990}, gset), nil)
+	cp.SaveTablePoint(&filter.Table{Schema: "db1", Name: "tb2"}, binlog.NewLocation(mysql.Position{Name: "mysql-bin.000003", Pos: 1980}, gset), nil)
+	cp.SaveTablePoint(&filter.Table{Schema: "db1", Name: "tb1"}, binlog.NewLocation(mysql.Position{Name: "mysql-bin.000003", Pos: 1970}, gset), nil)
+	safeModeExit := binlog.NewLocation(mysql.Position{Name: "mysql-bin.000004", Pos: 4}, gset)
+	cp.SaveSafeModeExitPoint(&safeModeExit)
+
+	data, err := checkpoint.ExportState()
+	require.NoError(t, err)
+	data2, err := checkpoint.ExportState()
+	require.NoError(t, err)
+	require.Equal(t, data, data2)
+
+	var state checkpointState
+	require.NoError(t, json.Unmarshal(data, &state))
+	require.Equal(t, checkpointStateVersion, state.Version)
+	require.Equal(t, uint32(2000), state.GlobalPoint.BinlogPos)
+	require.Equal(t, uint32(1943), state.FlushedGlobalPoint.BinlogPos)
+	require.Equal(t, "97b5142f-e19c-11e8-808c-0242ac110005:1-13", state.GlobalPoint.BinlogGTID)
+	require.NotNil(t, state.SafeModeExitPoint)
+	require.Equal(t, "mysql-bin.000004", state.SafeModeExitPoint.BinlogName)
+	require.Len(t, state.TablePoints, 3)
+	expected := []struct {
+		schema, table string
+		pos           uint32
+	}{{"db1", "tb1", 1970}, {"db1", "tb2", 1980}, {"db2", "tb1", 1990}}
+	for i, e := range expected {
+		require.Equal(t, e.schema, state.TablePoints[i].Schema)
+		require.Equal(t, e.table, state.TablePoints[i].Table)
+		require.Equal(t, e.pos, state.TablePoints[i].Location.BinlogPos)
+		require.Equal(t, "", state.TablePoints[i].FlushedLocation.BinlogName)
+	}
+
+	// the exported state should be round-trippable
+	roundTrip, err := json.Marshal(&state)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(roundTrip))
+}
+
+func TestRemoteCheckPointTableSuffix(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+	cfg.CheckpointTableSuffix = "_p2"
+	tctx := tcontext.Background()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	dbConn, err := db.Conn(tctx.Ctx)
+	require.NoError(t, err)
+
+	cp := NewRemoteCheckPoint(tctx, cfg, nil, cpid)
+	checkpoint := cp.(*RemoteCheckPoint)
+	checkpoint.dbConn = dbconn.NewDBConn(cfg, conn.NewBaseConnForTest(dbConn, &retry.FiniteRetryStrategy{}))
+	tableName := "`" + cfg.MetaSchema + "`.`" + cfg.Name + "_syncer_checkpoint_p2`"
+	require.Equal(t, tableName, checkpoint.tableName)
+
+	mock.ExpectQuery("SELECT .* FROM " + regexp.QuoteMeta(tableName) + " WHERE id = ?").WithArgs(cpid).WillReturnRows(sqlmock.NewRows(nil))
+	require.NoError(t, cp.Load(tctx))
+
+	cp.SaveTablePoint(&filter.Table{Schema: "db", Name: "tb"}, binlog.MustZeroLocation(cfg.Flavor), nil)
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM "+regexp.QuoteMeta(tableName)+" WHERE id = \\? AND cp_schema = \\? AND cp_table = \\?").
+		WithArgs(cpid, "db", "tb").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, cp.DeleteTablePoint(tctx, &filter.Table{Schema: "db", Name: "tb"}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM " + regexp.QuoteMeta(tableName) + " WHERE id = \\?").WithArgs(cpid).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, cp.Clear(tctx))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoteCheckPointLoadTablePointRegression(t *testing.T) {
+	cfg := genDefaultSubTaskConfig4Test()
+	cfg.WorkerCount = 0
+	tctx := tcontext.Background()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	dbConn, err := db.Conn(tctx.Ctx)
+	require.NoError(t, err)
+
+	metricProxies := metrics.DefaultMetricsProxies.CacheForOneTask(cfg.Name, "worker", cfg.SourceID)
+	counter := metricProxies.Metrics.TablePointRegressionTotal
+	before := promtestutil.ToFloat64(counter)
+	cp := NewRemoteCheckPoint(tctx, cfg, metricProxies, cpid)
+	checkpoint := cp.(*RemoteCheckPoint)
+	checkpoint.dbConn = dbconn.NewDBConn(cfg, conn.NewBaseConnForTest(dbConn, &retry.FiniteRetryStrategy{}))
+
+	columns := []string{"cp_schema", "cp_table", "binlog_name", "binlog_pos", "binlog_gtid", "exit_safe_binlog_name", "exit_safe_binlog_pos", "exit_safe_binlog_gtid", "table_info", "is_global"}
+	// table points not ahead of the global point are consistent, the tables
+	// without recent writes are behind the global point.
+	mock.ExpectQuery("SELECT .* FROM .* WHERE id = ?").WithArgs(cpid).WillReturnRows(
+		sqlmock.NewRows(columns).
+			AddRow("db", "idle", "mysql-bin.000003", 1000, "", "", 0, "", []byte("null"), false).
+			AddRow("", "", "mysql-bin.000003", 2000, "", "", 0, "", []byte("null"), true).
+			AddRow("db", "same", "mysql-bin.000003", 2000, "", "", 0, "", []byte("null"), false))
+	require.NoError(t, cp.Load(tctx))
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, before, promtestutil.ToFloat64(counter))
+
+	// a table point ahead of the global point was persisted before the restart,
+	// reload it by a new checkpoint. The table point is loaded before the global
+	// point to make sure the check is not order dependent.
+	cp = NewRemoteCheckPoint(tctx, cfg, metricProxies, cpid)
+	checkpoint = cp.(*RemoteCheckPoint)
+	checkpoint.dbConn = dbconn.NewDBConn(cfg, conn.NewBaseConnForTest(dbConn, &retry.FiniteRetryStrategy{}))
+	mock.ExpectQuery("SELECT .* FROM .* WHERE id = ?").WithArgs(cpid).WillReturnRows(
+		sqlmock.NewRows(columns).
+			AddRow("db", "ahead", "mysql-bin.000003", 3000, "", "", 0, "", []byte("null"), false).
+			AddRow("", "", "mysql-bin.000003", 2000, "", "", 0, "", []byte("null"), true).
+			AddRow("db", "idle", "mysql-bin.000003", 1000, "", "", 0, "", []byte("null"), false))
+	require.NoError(t, cp.Load(tctx))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Equal(t, uint32(2000), cp.GlobalPoint().Position.Pos)
+	require.Equal(t, uint32(3000), checkpoint.points["db"]["ahead"].MySQLLocation().Position.Pos)
+	require.Equal(t, before+1, promtestutil.ToFloat64(counter))
+}
@@ -239,6 +239,36 @@ func (conn *DBConn) ExecuteSQLAutoSplit(
 	return conn.baseConn.ExecuteSQLsAutoSplit(tctx, m, conn.cfg.Name, queries, args...)
 }
 
+// ExecuteSQLAutoSplitWithRetry wraps ExecuteSQLAutoSplit and retries it at most
+// `retryCount` times when meeting retryable errors, the wait interval before each
+// retry increases linearly from `firstRetryDuration`.
+func (conn *DBConn) ExecuteSQLAutoSplitWithRetry(
+	tctx *tcontext.Context,
+	metricProxies *metrics.Proxies,
+	retryCount int,
+	firstRetryDuration time.Duration,
+	queries []string,
+	args ...[]interface{},
+) error {
+	if conn == nil {
+		// only happens in test
+		return nil
+	}
+	params := retry.Params{
+		RetryCount:         retryCount,
+		FirstRetryDuration: firstRetryDuration,
+		BackoffStrategy:    retry.LinearIncrease,
+		IsRetryableFn:      conn.retryableFn(tctx, queries, args),
+	}
+	_, _, err := conn.baseConn.ApplyRetryStrategy(
+		tctx,
+		params,
+		func(ctx *tcontext.Context) (interface{}, error) {
+			return nil, conn.ExecuteSQLAutoSplit(ctx, metricProxies, queries, args...)
+		})
+	return err
+}
+
 func (conn *DBConn) retryableFn(tctx *tcontext.Context, queries, args any) func(int, error) bool {
 	return func(retryTime int, err error) bool {
 		if retry.IsConnectionError(err) {
@@ -1357,14 +1357,14 @@ func (ddl *Pessimist) dropSchemaInSharding(tctx *tcontext.Context, sourceSchema
 		}
 	}
 	// delete from checkpoint
+	allTables := make([]*filter.Table, 0, len(sources))
 	for _, tables := range sources {
-		for _, table := range tables {
-			// refine clear them later if failed
-			// now it doesn't have problems
-			if err1 := ddl.checkpoint.DeleteTablePoint(tctx, table); err1 != nil {
-				ddl.logger.Error("fail to delete checkpoint", zap.Stringer("table", table))
-			}
-		}
+		allTables = append(allTables, tables...)
+	}
+	// refine clear them later if failed
+	// now it doesn't have problems
+	if err1 := ddl.checkpoint.DeleteTablePoints(tctx, allTables); err1 != nil {
+		ddl.logger.Error("fail to delete checkpoint", zap.Stringers("tables", allTables))
 	}
 	return nil
 }
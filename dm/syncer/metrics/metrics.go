@@ -50,6 +50,8 @@ type Metrics struct {
 	ShardLockResolving               prometheus.Gauge
 	FinishedTransactionTotal         prometheus.Counter
 	FlushCheckPointsTimeInterval     prometheus.Observer
+	FlushedCheckpointAgeGauge        prometheus.Gauge
+	TablePointRegressionTotal        prometheus.Counter
 }
 
 // Proxies provides the ability to clean Metrics values when syncer is closed.
@@ -84,6 +86,8 @@ type Proxies struct {
 	finishedTransactionTotal        *prometheus.CounterVec
 	ReplicationTransactionBatch     *prometheus.HistogramVec
 	flushCheckPointsTimeInterval    *prometheus.HistogramVec
+	flushedCheckpointAgeGauge       *prometheus.GaugeVec
+	tablePointRegressionTotal       *prometheus.CounterVec
 }
 
 var DefaultMetricsProxies *Proxies
@@ -291,6 +295,20 @@ func (m *Proxies) Init(f promutil.Factory) {
 			Help:      "checkpoint flushed time interval in seconds",
 			Buckets:   prometheus.LinearBuckets(1, 50, 21), // linear from 1 to 1001, i think this is enough
 		}, []string{"worker", "task", "source_id"})
+	m.flushedCheckpointAgeGauge = f.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "dm",
+			Subsystem: "syncer",
+			Name:      "flushed_checkpoint_age",
+			Help:      "seconds since the global checkpoint was flushed successfully",
+		}, []string{"task", "source_id"})
+	m.tablePointRegressionTotal = f.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dm",
+			Subsystem: "syncer",
+			Name:      "table_point_regression_total",
+			Help:      "total number of loaded table checkpoints which are ahead of the global checkpoint",
+		}, []string{"task", "source_id"})
 }
 
 // CacheForOneTask returns a new Proxies with m.Metrics filled. It is used
@@ -317,6 +335,8 @@ func (m *Proxies) CacheForOneTask(taskName, workerName, sourceID string) *Proxie
 	ret.Metrics.ShardLockResolving = m.shardLockResolving.WithLabelValues(taskName, sourceID)
 	ret.Metrics.FinishedTransactionTotal = m.finishedTransactionTotal.WithLabelValues(taskName, workerName, sourceID)
 	ret.Metrics.FlushCheckPointsTimeInterval = m.flushCheckPointsTimeInterval.WithLabelValues(workerName, taskName, sourceID)
+	ret.Metrics.FlushedCheckpointAgeGauge = m.flushedCheckpointAgeGauge.WithLabelValues(taskName, sourceID)
+	ret.Metrics.TablePointRegressionTotal = m.tablePointRegressionTotal.WithLabelValues(taskName, sourceID)
 	return &ret
 }
 
@@ -348,6 +368,8 @@ func (m *Proxies) RegisterMetrics(registry *prometheus.Registry) {
 	registry.MustRegister(m.finishedTransactionTotal)
 	registry.MustRegister(m.ReplicationTransactionBatch)
 	registry.MustRegister(m.flushCheckPointsTimeInterval)
+	registry.MustRegister(m.flushedCheckpointAgeGauge)
+	registry.MustRegister(m.tablePointRegressionTotal)
 }
 
 // RemoveLabelValuesWithTaskInMetrics cleans all Metrics related to the task.
@@ -378,4 +400,6 @@ func (m *Proxies) RemoveLabelValuesWithTaskInMetrics(task string) {
 	m.finishedTransactionTotal.DeletePartialMatch(prometheus.Labels{"task": task})
 	m.ReplicationTransactionBatch.DeletePartialMatch(prometheus.Labels{"task": task})
 	m.flushCheckPointsTimeInterval.DeletePartialMatch(prometheus.Labels{"task": task})
+	m.flushedCheckpointAgeGauge.DeletePartialMatch(prometheus.Labels{"task": task})
+	m.tablePointRegressionTotal.DeletePartialMatch(prometheus.Labels{"task": task})
 }
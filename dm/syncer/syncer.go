@@ -107,6 +107,10 @@ const (
 	waitComplete
 )
 
+// checkpointCompactInterval is the interval to delete the table checkpoints of
+// dropped tables from the downstream checkpoint table.
+const checkpointCompactInterval = time.Hour
+
 // Syncer can sync your MySQL data to another MySQL database.
 type Syncer struct {
 	sync.RWMutex
@@ -243,6 +247,7 @@ type Syncer struct {
 	// 	- 2+ is for DML worker job idx=(queue id + 2)
 	workerJobTSArray          []*atomic.Int64
 	lastCheckpointFlushedTime time.Time
+	lastCheckpointCompactTime time.Time
 
 	firstMeetBinlogTS *int64
 	exitSafeModeTS    *int64 // TS(in binlog header) need to exit safe mode.
@@ -1379,6 +1384,7 @@ func (s *Syncer) afterFlushCheckpoint(task *checkpointFlushTask) error {
 	s.lastCheckpointFlushedTime = now
 
 	s.logAndClearFilteredStatistics()
+	s.compactCheckpointIfNeeded(now)
 
 	if s.cliArgs != nil && s.cliArgs.StartTime != "" && s.cli != nil {
 		clone := *s.cliArgs
@@ -1395,6 +1401,29 @@ func (s *Syncer) afterFlushCheckpoint(task *checkpointFlushTask) error {
 	return nil
 }
 
+// compactCheckpointIfNeeded deletes the table checkpoints of tables no longer in schema
+// tracker nor upstream every checkpointCompactInterval. Failures are only logged and retried next time.
+func (s *Syncer) compactCheckpointIfNeeded(now time.Time) {
+	if s.lastCheckpointCompactTime.IsZero() {
+		s.lastCheckpointCompactTime = now
+		return
+	}
+	if now.Sub(s.lastCheckpointCompactTime) < checkpointCompactInterval {
+		return
+	}
+	s.lastCheckpointCompactTime = now
+	upstreamTables := func(ctx context.Context, schema string) ([]string, error) {
+		tables, err := dbutil.GetTables(ctx, s.fromDB.BaseDB.DB, schema)
+		if err != nil {
+			return nil, terror.DBErrorAdapt(err, s.fromDB.BaseDB.Scope, terror.ErrDBDriverError)
+		}
+		return tables, nil
+	}
+	if err := s.checkpoint.CompactOutdatedPoints(s.tctx, s.schemaTracker, upstreamTables); err != nil {
+		s.tctx.L().Warn("failed to compact outdated table checkpoints", zap.Error(err))
+	}
+}
+
 func (s *Syncer) logAndClearFilteredStatistics() {
 	filteredInsert := s.filteredInsert.Swap(0)
 	filteredUpdate := s.filteredUpdate.Swap(0)
@@ -1714,12 +1743,27 @@ func (s *Syncer) updateLagCronJob(ctx context.Context) {
 		select {
 		case <-ticker.C:
 			s.updateReplicationLagMetric()
+			s.updateFlushedCheckpointAgeMetric()
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// updateFlushedCheckpointAgeMetric updates the seconds since the global
+// checkpoint was flushed. Before the first flush, it's the seconds since the
+// syncer started, so that it isn't read as just flushed.
+func (s *Syncer) updateFlushedCheckpointAgeMetric() {
+	since := s.checkpoint.GlobalPointSaveTime()
+	if since.IsZero() {
+		since = s.start.Load()
+	}
+	if since.IsZero() {
+		return
+	}
+	s.metricsProxies.Metrics.FlushedCheckpointAgeGauge.Set(time.Since(since).Seconds())
+}
+
 func (s *Syncer) updateTSOffset(ctx context.Context) error {
 	t1 := time.Now()
 	ts, tsErr := s.fromDB.GetServerUnixTS(ctx)
@@ -182,7 +182,13 @@ const (
     "enable-kv-connect-backoff": false,
     "puller": {
       "enable-resolved-ts-stuck-detection": false,
-      "resolved-ts-stuck-interval": 300000000000
+      "resolved-ts-stuck-interval": 300000000000,
+      "error-on-ddl-resolved-ts-regression": false,
+      "split-update-grace-window": 0,
+      "compute-ddl-digest": false,
+      "ddl-resolved-ts-min-interval": 0,
+      "table-resolved-ts-stall-check-interval": 0,
+      "table-resolved-ts-stall-threshold": 0
     }
   },
   "cluster-id": "default",
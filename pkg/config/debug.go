@@ -54,4 +54,28 @@ type PullerConfig struct {
 	EnableResolvedTsStuckDetection bool `toml:"enable-resolved-ts-stuck-detection" json:"enable-resolved-ts-stuck-detection"`
 	// ResolvedTsStuckInterval is the interval of checking resolved ts stuck.
 	ResolvedTsStuckInterval TomlDuration `toml:"resolved-ts-stuck-interval" json:"resolved-ts-stuck-interval"`
+	// ErrorOnDDLResolvedTsRegression makes the DDL puller fail the changefeed
+	// instead of logging a warning when its resolved ts is about to regress,
+	// which means the pending DDL jobs are not ordered by FinishedTS.
+	ErrorOnDDLResolvedTsRegression bool `toml:"error-on-ddl-resolved-ts-regression" json:"error-on-ddl-resolved-ts-regression"`
+	// SplitUpdateGraceWindow makes the puller also split the update events
+	// committed within the window after the replicate ts of the table sink,
+	// if update events are only split at start. It's 0 by default.
+	SplitUpdateGraceWindow TomlDuration `toml:"split-update-grace-window" json:"split-update-grace-window"`
+	// ComputeDDLDigest makes the DDL puller attach the normalized digest of
+	// each DDL query to the output, which ignores literals and table names.
+	// It's off by default since it parses every DDL query.
+	ComputeDDLDigest bool `toml:"compute-ddl-digest" json:"compute-ddl-digest"`
+	// DDLResolvedTsMinInterval is the minimum interval between handling
+	// consecutive resolved ts entries in the DDL puller without multiplexing,
+	// the entries within the interval are coalesced into the latest one. DDL
+	// jobs are never delayed. It's 0 by default, which disables coalescing.
+	DDLResolvedTsMinInterval TomlDuration `toml:"ddl-resolved-ts-min-interval" json:"ddl-resolved-ts-min-interval"`
+	// TableResolvedTsStallCheckInterval is the interval of checking whether the
+	// resolved ts of tables in the source manager stalls.
+	TableResolvedTsStallCheckInterval TomlDuration `toml:"table-resolved-ts-stall-check-interval" json:"table-resolved-ts-stall-check-interval"`
+	// TableResolvedTsStallThreshold is how long the resolved ts of a table
+	// doesn't advance before it's reported as stalled. The check is disabled
+	// if either it or TableResolvedTsStallCheckInterval is 0, which is the default.
+	TableResolvedTsStallThreshold TomlDuration `toml:"table-resolved-ts-stall-threshold" json:"table-resolved-ts-stall-threshold"`
 }
@@ -16,4 +16,7 @@ package config
 // MounterConfig represents mounter config for a changefeed
 type MounterConfig struct {
 	WorkerNum int `toml:"worker-num" json:"worker-num"`
+	// FetchBatchSize is the max number of events fetched from the sort engine
+	// and mounted in one batch. 256 is used if it's not set.
+	FetchBatchSize *int `toml:"fetch-batch-size" json:"fetch-batch-size,omitempty"`
 }
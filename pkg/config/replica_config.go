@@ -312,6 +312,12 @@ func (c *ReplicaConfig) ValidateAndAdjust(sinkURI *url.URL) error { // check sin
 		}
 	}
 
+	if c.Mounter != nil && c.Mounter.FetchBatchSize != nil && *c.Mounter.FetchBatchSize <= 0 {
+		return cerror.ErrInvalidReplicaConfig.
+			FastGenByArgs(fmt.Sprintf("The FetchBatchSize:%d must be positive",
+				*c.Mounter.FetchBatchSize))
+	}
+
 	if c.ChangefeedErrorStuckDuration != nil &&
 		*c.ChangefeedErrorStuckDuration < minChangeFeedErrorStuckDuration {
 		return cerror.ErrInvalidReplicaConfig.
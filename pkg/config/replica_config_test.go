@@ -299,6 +299,15 @@ func TestValidateAndAdjust(t *testing.T) {
 	duration = minChangeFeedErrorStuckDuration
 	cfg.ChangefeedErrorStuckDuration = &duration
 	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
+
+	// fetch batch size must be positive
+	cfg = GetDefaultReplicaConfig()
+	cfg.Mounter.FetchBatchSize = util.AddressOf(0)
+	err = cfg.ValidateAndAdjust(sinkURL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "The FetchBatchSize")
+	cfg.Mounter.FetchBatchSize = util.AddressOf(64)
+	require.NoError(t, cfg.ValidateAndAdjust(sinkURL))
 }
 
 func TestIsSinkCompatibleWithSpanReplication(t *testing.T) {
@@ -54,6 +54,10 @@ var (
 		"cannot find mysql.tidb_ddl_job schema",
 		errors.RFCCodeText("CDC:ErrDDLSchemaNotFound"),
 	)
+	ErrDDLPullerResolvedTsRegressed = errors.Normalize(
+		"ddl puller resolved ts regressed from %d to %d, DDL jobs are out of order",
+		errors.RFCCodeText("CDC:ErrDDLPullerResolvedTsRegressed"),
+	)
 	ErrGRPCDialFailed = errors.Normalize(
 		"grpc dial failed",
 		errors.RFCCodeText("CDC:ErrGRPCDialFailed"),
@@ -329,9 +333,13 @@ var (
 		errors.RFCCodeText("CDC:ErrMySQLTxnError"),
 	)
 	ErrMySQLDuplicateEntry = errors.Normalize(
-		"MySQL duplicate entry error",
+		"MySQL duplicate entry error, key: %s",
 		errors.RFCCodeText("CDC:ErrMySQLDuplicateEntry"),
 	)
+	ErrMySQLZeroAutoIncrementKey = errors.Normalize(
+		"auto increment handle key %s of table %s is zero, startTs: %d",
+		errors.RFCCodeText("CDC:ErrMySQLZeroAutoIncrementKey"),
+	)
 	ErrMySQLQueryError = errors.Normalize(
 		"MySQL query error",
 		errors.RFCCodeText("CDC:ErrMySQLQueryError"),
@@ -388,6 +396,14 @@ var (
 		"canal decode failed",
 		errors.RFCCodeText("CDC:ErrCanalDecodeFailed"),
 	)
+	ErrCanalClaimCheckNotFetched = errors.Normalize(
+		"canal message is a claim check reference to %s, but the claim check storage is not configured",
+		errors.RFCCodeText("CDC:ErrCanalClaimCheckNotFetched"),
+	)
+	ErrCanalDDLActionTypeMismatch = errors.Normalize(
+		"canal ddl action type mismatch, query: %s, guessed type: %s, supplied type: %s",
+		errors.RFCCodeText("CDC:ErrCanalDDLActionTypeMismatch"),
+	)
 	ErrCanalEncodeFailed = errors.Normalize(
 		"canal encode failed",
 		errors.RFCCodeText("CDC:ErrCanalEncodeFailed"),
@@ -688,6 +704,10 @@ var (
 		"checkpointTs(%v) should not larger than resolvedTs(%v)",
 		errors.RFCCodeText("CDC:ErrInvalidCheckpointTs"),
 	)
+	ErrInvalidForcedResolvedTs = errors.Normalize(
+		"forced resolvedTs(%v) is invalid, %s",
+		errors.RFCCodeText("CDC:ErrInvalidForcedResolvedTs"),
+	)
 
 	// EtcdWorker related errors. Internal use only.
 	// ErrEtcdTryAgain is used by a PatchFunc to force a transaction abort.
@@ -931,6 +951,12 @@ var (
 			"if you want to replicate this table, please add its old name to filter rule.",
 		errors.RFCCodeText("CDC:ErrSyncRenameTableFailed"),
 	)
+	ErrSyncMultiSchemaChangeFailed = errors.Normalize(
+		"some sub-changes of the multi-schema-change ddl are in filter rule, and the others are not, "+
+			"table id '%d', ddl query: [%s], it's an unexpected behavior, "+
+			"please split the ddl or adjust the filter rule.",
+		errors.RFCCodeText("CDC:ErrSyncMultiSchemaChangeFailed"),
+	)
 
 	// changefeed config error
 	ErrInvalidReplicaConfig = errors.Normalize(
@@ -162,7 +162,7 @@ func (f *filter) ShouldIgnoreDMLEvent(
 // 1. By schema name.
 // 2. By table name.
 func (f *filter) ShouldDiscardDDL(ddlType timodel.ActionType, schema, table string) bool {
-	if !isAllowedDDL(ddlType) {
+	if !IsAllowedDDL(ddlType) {
 		return true
 	}
 
@@ -221,7 +221,8 @@ func (f *filter) shouldIgnoreStartTs(ts uint64) bool {
 	return false
 }
 
-func isAllowedDDL(actionType timodel.ActionType) bool {
+// IsAllowedDDL returns true if the action type can be applied to cdc's schema storage.
+func IsAllowedDDL(actionType timodel.ActionType) bool {
 	for _, action := range allowDDLList {
 		if actionType == action {
 			return true
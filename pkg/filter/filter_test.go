@@ -380,7 +380,7 @@ func TestIsAllowedDDL(t *testing.T) {
 	testCases = append(testCases, testCase{timodel.ActionAlterSequence, false})
 	testCases = append(testCases, testCase{timodel.ActionDropSequence, false})
 	for _, tc := range testCases {
-		require.Equal(t, tc.allowed, isAllowedDDL(tc.ActionType), "%#v", tc)
+		require.Equal(t, tc.allowed, IsAllowedDDL(tc.ActionType), "%#v", tc)
 	}
 }
 
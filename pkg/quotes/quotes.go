@@ -28,6 +28,30 @@ func QuoteName(name string) string {
 	return "`" + EscapeName(name) + "`"
 }
 
+// QuoteStyle is the style of quoting identifiers.
+type QuoteStyle int
+
+const (
+	// QuoteStyleBacktick quotes identifiers with "`", which is the default.
+	QuoteStyleBacktick QuoteStyle = iota
+	// QuoteStyleDoubleQuote quotes identifiers with `"`, which is required
+	// if the ANSI_QUOTES SQL mode is enabled.
+	QuoteStyleDoubleQuote
+)
+
+// QuoteName wraps a name with the quote character of the style.
+func (s QuoteStyle) QuoteName(name string) string {
+	if s == QuoteStyleDoubleQuote {
+		return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+	}
+	return QuoteName(name)
+}
+
+// QuoteSchema quotes a full table name with the quote character of the style.
+func (s QuoteStyle) QuoteSchema(schema string, table string) string {
+	return s.QuoteName(schema) + "." + s.QuoteName(table)
+}
+
 // EscapeName replaces all "`" in name with double "`"
 func EscapeName(name string) string {
 	return strings.Replace(name, "`", "``", -1)
@@ -72,3 +72,21 @@ func TestEscapeName(t *testing.T) {
 		require.Equal(t, testCase.expected, escaped)
 	}
 }
+
+func TestQuoteStyle(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		style          QuoteStyle
+		name           string
+		expectedName   string
+		expectedSchema string
+	}{
+		{QuoteStyleBacktick, "t`b\"l", "`t``b\"l`", "`db`.`t``b\"l`"},
+		{QuoteStyleDoubleQuote, "t`b\"l", "\"t`b\"\"l\"", "\"db\".\"t`b\"\"l\""},
+	}
+	for _, testCase := range cases {
+		require.Equal(t, testCase.expectedName, testCase.style.QuoteName(testCase.name))
+		require.Equal(t, testCase.expectedSchema, testCase.style.QuoteSchema("db", testCase.name))
+	}
+}
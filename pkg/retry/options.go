@@ -38,6 +38,7 @@ type retryOptions struct {
 	maxTries           uint64
 	backoffBaseInMs    float64
 	backoffCapInMs     float64
+	jitterFraction     float64
 	isRetryable        IsRetryable
 }
 
@@ -69,6 +70,21 @@ func WithBackoffMaxDelay(delayInMs int64) Option {
 	}
 }
 
+// WithJitter configures the fraction of random jitter applied to each backoff.
+// A backoff d becomes a random value in [d*(1-fraction), d*(1+fraction)],
+// which is still capped by the maximum delay.
+// If fraction <= 0 no extra jitter is applied, and it is capped at 1.
+func WithJitter(fraction float64) Option {
+	return func(o *retryOptions) {
+		if fraction > 1 {
+			fraction = 1
+		}
+		if fraction > 0 {
+			o.jitterFraction = fraction
+		}
+	}
+}
+
 // WithMaxTries configures maximum tries, if tries is 0, 1 will be used
 func WithMaxTries(tries uint64) Option {
 	return func(o *retryOptions) {
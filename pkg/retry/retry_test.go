@@ -202,3 +202,33 @@ func TestRetryError(t *testing.T) {
 	require.Regexp(t, ".*some error info.*", err.Error())
 	require.Regexp(t, ".*CDC:ErrReachMaxTry.*", err.Error())
 }
+
+func TestApplyJitter(t *testing.T) {
+	t.Parallel()
+
+	backOff := 100 * time.Millisecond
+	// No jitter configured.
+	require.Equal(t, backOff, applyJitter(backOff, 0, time.Second))
+
+	fraction := 0.2
+	lower := time.Duration(float64(backOff) * (1 - fraction))
+	upper := time.Duration(float64(backOff) * (1 + fraction))
+	for i := 0; i < 1000; i++ {
+		d := applyJitter(backOff, fraction, time.Second)
+		require.GreaterOrEqual(t, d, lower)
+		require.LessOrEqual(t, d, upper)
+	}
+	// The jittered backoff never exceeds the max backoff.
+	for i := 0; i < 1000; i++ {
+		d := applyJitter(backOff, fraction, backOff)
+		require.GreaterOrEqual(t, d, lower)
+		require.LessOrEqual(t, d, backOff)
+	}
+
+	opts := setOptions(WithJitter(-1))
+	require.Equal(t, float64(0), opts.jitterFraction)
+	opts = setOptions(WithJitter(2))
+	require.Equal(t, float64(1), opts.jitterFraction)
+	opts = setOptions(WithJitter(fraction))
+	require.Equal(t, fraction, opts.jitterFraction)
+}
@@ -78,6 +78,8 @@ func run(ctx context.Context, op Operation, retryOption *retryOptions) error {
 		}
 
 		backOff = getBackoffInMs(retryOption.backoffBaseInMs, retryOption.backoffCapInMs, float64(try))
+		backOff = applyJitter(backOff, retryOption.jitterFraction,
+			time.Duration(retryOption.backoffCapInMs)*time.Millisecond)
 		if t == nil {
 			t = time.NewTimer(backOff)
 			defer t.Stop()
@@ -107,3 +109,21 @@ func getBackoffInMs(backoffBaseInMs, backoffCapInMs, try float64) time.Duration
 	backOff := math.Min(backoffCapInMs, float64(rand.Int63n(sleep))+backoffBaseInMs)
 	return time.Duration(backOff) * time.Millisecond
 }
+
+// applyJitter spreads the backoff randomly in [backOff*(1-fraction), backOff*(1+fraction)],
+// so that concurrent callers failing at the same time do not retry in lockstep.
+// The jittered backoff is clamped to maxBackOff.
+func applyJitter(backOff time.Duration, fraction float64, maxBackOff time.Duration) time.Duration {
+	if fraction <= 0 || backOff <= 0 {
+		return backOff
+	}
+	delta := float64(backOff) * fraction
+	jittered := float64(backOff) - delta + rand.Float64()*2*delta
+	if jittered < 0 {
+		jittered = 0
+	}
+	if jittered > float64(maxBackOff) {
+		jittered = float64(maxBackOff)
+	}
+	return time.Duration(jittered)
+}
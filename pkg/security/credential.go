@@ -129,6 +129,10 @@ func (s *Credential) AddSelfCommonName() error {
 // ToTLSConfigWithVerify constructs a `*tls.Config` from the CA, certification and key
 // paths, and add verify for CN.
 //
+// The certificate and key files are loaded on every TLS handshake, so rotated
+// files are picked up by new connections without rebuilding the config. The CA
+// is only read once here.
+//
 // If the CA path is empty, returns nil.
 func ToTLSConfigWithVerify(
 	caPath, certPath, keyPath string, verifyCN []string,
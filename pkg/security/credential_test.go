@@ -14,6 +14,7 @@
 package security
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -34,3 +35,27 @@ func TestGetCommonName(t *testing.T) {
 	require.NotNil(t, err)
 	require.Contains(t, err.Error(), "failed to decode PEM block to certificate")
 }
+
+func TestClientCertificateReloaded(t *testing.T) {
+	ca, cred, err := NewServerCredential4Test("cdc")
+	require.NoError(t, err)
+	tlsCfg, err := cred.ToTLSConfig()
+	require.NoError(t, err)
+
+	before, err := tlsCfg.GetClientCertificate(nil)
+	require.NoError(t, err)
+
+	// Simulate a certificate rotation by replacing the files in place.
+	certPEM, keyPEM, err := ca.GenerateCerts("cdc")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cred.CertPath, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(cred.KeyPath, keyPEM, 0o600))
+
+	after, err := tlsCfg.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, before.Certificate[0], after.Certificate[0])
+
+	again, err := tlsCfg.GetClientCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, after.Certificate[0], again.Certificate[0])
+}
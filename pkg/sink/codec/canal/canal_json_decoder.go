@@ -18,18 +18,27 @@ import (
 	"context"
 	"database/sql"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	tiTypes "github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
 	"github.com/pingcap/tiflow/pkg/util"
+	canal "github.com/pingcap/tiflow/proto/canal"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
@@ -46,11 +55,123 @@ type batchDecoder struct {
 
 	upstreamTiDB *sql.DB
 	bytesDecoder *encoding.Decoder
+
+	tableInfoProvider TableInfoProvider
+	columnOrder       ColumnOrder
+
+	// errCounter counts the decode errors by category.
+	errCounter *prometheus.CounterVec
+	// registerer is used to register the decoder metrics, if it's set.
+	registerer prometheus.Registerer
+
+	// snapshotMarkerTypes is the `type` of the snapshot marker messages.
+	snapshotMarkerTypes []string
+
+	keyColumnsProvider KeyColumnsProvider
+	// lenientOldColumns indicates whether to skip the `old` columns absent
+	// from `mysqlType` instead of failing the event.
+	lenientOldColumns bool
+
+	// sourceClusterID is the upstream cluster ID of the last message.
+	sourceClusterID string
+	// claimCheckLocation is the claim check location of the last message.
+	claimCheckLocation string
+	// mysqlTypeKeys is the column names in the `mysqlType` field of the last
+	// message, in the encoded order. It's only set for ColumnOrderNone.
+	mysqlTypeKeys []string
+	// pendingRows is the rest rows of the last message which carries multiple rows,
+	// each of them is returned as a single row message by the following `HasNext`.
+	pendingRows []canalJSONMessageInterface
+}
+
+// TableInfoProvider returns the table info of the given table,
+// nil should be returned if the table is not found.
+type TableInfoProvider func(schema, table string) *model.TableInfo
+
+// DecoderOption is used to customize the canal-json batch decoder.
+type DecoderOption func(*batchDecoder)
+
+// WithTableInfoProvider sets the table info provider used by the decoder.
+// If the provider is set, columns absent from an INSERT event but have a
+// default value in the table info, are backfilled with the default value.
+func WithTableInfoProvider(provider TableInfoProvider) DecoderOption {
+	return func(b *batchDecoder) {
+		b.tableInfoProvider = provider
+	}
+}
+
+// KeyColumnsProvider returns the key columns of the given table, which has
+// the given columns. It's used if the message carries no `pkNames`.
+type KeyColumnsProvider func(schema, table string, columns []string) []string
+
+// AllColumnsAsKey is a KeyColumnsProvider which uses all columns as the
+// composite key of the table.
+func AllColumnsAsKey(_, _ string, columns []string) []string {
+	return columns
+}
+
+// WithKeyColumnsProvider sets the provider of the key columns for the messages
+// without `pkNames`. Without the provider, the tables of such messages have no
+// key, so their rows can't be matched by the handle key in the downstream.
+func WithKeyColumnsProvider(provider KeyColumnsProvider) DecoderOption {
+	return func(b *batchDecoder) {
+		b.keyColumnsProvider = provider
+	}
+}
+
+// ColumnOrder is the order of the columns of the decoded row changed events.
+type ColumnOrder int
+
+const (
+	// ColumnOrderDescending sorts the columns by name in descending order.
+	ColumnOrderDescending ColumnOrder = iota
+	// ColumnOrderAscending sorts the columns by name in ascending order.
+	ColumnOrderAscending
+	// ColumnOrderNone keeps the columns in the order of the `mysqlType` field,
+	// which is the column definition order for messages produced by TiCDC.
+	// If the order is unknown, the columns are sorted by name in ascending order.
+	ColumnOrderNone
+)
+
+// WithColumnOrder sets the order of the columns of the decoded row changed events,
+// ColumnOrderDescending is used by default for compatibility.
+func WithColumnOrder(order ColumnOrder) DecoderOption {
+	return func(b *batchDecoder) {
+		b.columnOrder = order
+	}
+}
+
+// WithLenientOldColumns makes the decoder skip the columns of the `old` field of
+// update events which are absent from the `mysqlType` field with a warning,
+// instead of failing the whole event. Such columns may be sent by producers
+// if a column is dropped mid-stream.
+func WithLenientOldColumns() DecoderOption {
+	return func(b *batchDecoder) {
+		b.lenientOldColumns = true
+	}
+}
+
+// WithSnapshotMarkerTypes sets the `type` of the initial DDL or snapshot marker
+// messages, which are decoded as model.MessageTypeSnapshotMarker instead of
+// row or DDL events. `INIT_DDL` is used by default, the producers which emit
+// markers of other types should set it to match them.
+func WithSnapshotMarkerTypes(types ...string) DecoderOption {
+	return func(b *batchDecoder) {
+		b.snapshotMarkerTypes = types
+	}
+}
+
+// WithMetricsRegisterer registers the decoder metrics to the given registerer,
+// so that the decode errors are exported by the process running the decoder.
+func WithMetricsRegisterer(registerer prometheus.Registerer) DecoderOption {
+	return func(b *batchDecoder) {
+		b.registerer = registerer
+	}
 }
 
 // NewBatchDecoder return a decoder for canal-json
 func NewBatchDecoder(
-	ctx context.Context, codecConfig *common.Config, db *sql.DB,
+	ctx context.Context, codecConfig *common.Config, db *sql.DB, opts ...DecoderOption,
 ) (codec.RowEventDecoder, error) {
 	var (
 		externalStorage storage.ExternalStorage
@@ -69,12 +190,26 @@ func NewBatchDecoder(
 			GenWithStack("handle-key-only is enabled, but upstream TiDB is not provided")
 	}
 
-	return &batchDecoder{
-		config:       codecConfig,
-		storage:      externalStorage,
-		upstreamTiDB: db,
-		bytesDecoder: charmap.ISO8859_1.NewDecoder(),
-	}, nil
+	decoder := &batchDecoder{
+		config:              codecConfig,
+		storage:             externalStorage,
+		upstreamTiDB:        db,
+		bytesDecoder:        charmap.ISO8859_1.NewDecoder(),
+		snapshotMarkerTypes: []string{defaultSnapshotMarkerType},
+		errCounter: decodeErrorsCounter.MustCurryWith(prometheus.Labels{
+			"namespace":  codecConfig.ChangefeedID.Namespace,
+			"changefeed": codecConfig.ChangefeedID.ID,
+		}),
+	}
+	for _, opt := range opts {
+		opt(decoder)
+	}
+	if decoder.registerer != nil {
+		if err := registerMetrics(decoder.registerer); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return decoder, nil
 }
 
 // AddKeyValue implements the RowEventDecoder interface
@@ -93,6 +228,11 @@ func (b *batchDecoder) AddKeyValue(_, value []byte) error {
 
 // HasNext implements the RowEventDecoder interface
 func (b *batchDecoder) HasNext() (model.MessageType, bool, error) {
+	if len(b.pendingRows) > 0 {
+		b.msg = b.pendingRows[0]
+		b.pendingRows = b.pendingRows[1:]
+		return b.msg.messageType(), true, nil
+	}
 	if b.data == nil {
 		return model.MessageTypeUnknown, false, nil
 	}
@@ -132,10 +272,108 @@ func (b *batchDecoder) HasNext() (model.MessageType, bool, error) {
 		return model.MessageTypeUnknown, false, err
 	}
 	b.msg = msg
+	b.sourceClusterID = ""
+	b.claimCheckLocation = ""
+	if b.isSnapshotMarker(msg) {
+		// the marker carries no changes, so it can't be decoded by the following
+		// `NextRowChangedEvent` or `NextDDLEvent`.
+		b.msg = nil
+		return model.MessageTypeSnapshotMarker, true, nil
+	}
+	if err := b.resetMySQLTypeKeys(encodedData); err != nil {
+		return model.MessageTypeUnknown, false, err
+	}
+	if withExtension, ok := msg.(*canalJSONMessageWithTiDBExtension); ok {
+		// the extension field may be set to null explicitly.
+		if withExtension.Extensions == nil {
+			withExtension.Extensions = &tidbExtension{}
+		}
+		b.sourceClusterID = withExtension.Extensions.SourceClusterID
+		b.claimCheckLocation = withExtension.Extensions.ClaimCheckLocation
+	}
+	if b.msg.messageType() == model.MessageTypeRow {
+		rows, err := splitRows(b.msg)
+		if err != nil {
+			b.errCounter.WithLabelValues(decodeErrorOldDataMismatch).Inc()
+			return model.MessageTypeUnknown, false, err
+		}
+		b.msg = rows[0]
+		b.pendingRows = rows[1:]
+	}
 
 	return b.msg.messageType(), true, nil
 }
 
+func (b *batchDecoder) isSnapshotMarker(msg canalJSONMessageInterface) bool {
+	var eventType string
+	switch m := msg.(type) {
+	case *JSONMessage:
+		eventType = m.EventType
+	case *canalJSONMessageWithTiDBExtension:
+		eventType = m.EventType
+	}
+	for _, tp := range b.snapshotMarkerTypes {
+		if eventType == tp {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRows splits the message which carries multiple rows into single row
+// messages, `Old[i]` is paired with `Data[i]` for the `UPDATE` event.
+func splitRows(msg canalJSONMessageInterface) ([]canalJSONMessageInterface, error) {
+	var jsonMessage *JSONMessage
+	switch m := msg.(type) {
+	case *JSONMessage:
+		jsonMessage = m
+	case *canalJSONMessageWithTiDBExtension:
+		jsonMessage = m.JSONMessage
+	}
+	if msg.eventType() == canal.EventType_UPDATE && len(jsonMessage.Old) != len(jsonMessage.Data) {
+		return nil, cerror.ErrCanalDecodeFailed.GenWithStack(
+			"the count of old rows mismatches the count of rows for update event, "+
+				"schema: %s, table: %s, old: %d, data: %d",
+			jsonMessage.Schema, jsonMessage.Table, len(jsonMessage.Old), len(jsonMessage.Data))
+	}
+	if len(jsonMessage.Data) <= 1 {
+		return []canalJSONMessageInterface{msg}, nil
+	}
+
+	result := make([]canalJSONMessageInterface, 0, len(jsonMessage.Data))
+	for i := range jsonMessage.Data {
+		row := *jsonMessage
+		row.Data = jsonMessage.Data[i : i+1]
+		if i < len(jsonMessage.Old) {
+			row.Old = jsonMessage.Old[i : i+1]
+		}
+		switch m := msg.(type) {
+		case *JSONMessage:
+			result = append(result, &row)
+		case *canalJSONMessageWithTiDBExtension:
+			result = append(result, &canalJSONMessageWithTiDBExtension{
+				JSONMessage: &row,
+				Extensions:  m.Extensions,
+			})
+		}
+	}
+	return result, nil
+}
+
+// SourceClusterID returns the upstream cluster ID of the message found by the
+// last `HasNext`, it's empty if the message doesn't carry one.
+func (b *batchDecoder) SourceClusterID() string {
+	return b.sourceClusterID
+}
+
+// ClaimCheckLocation returns the claim check location of the message found by
+// the last `HasNext`, it's empty if the message is not a claim check reference.
+// The real payload of the message is stored in the location, if the claim check
+// storage is not configured, the caller should fetch and decode it by itself.
+func (b *batchDecoder) ClaimCheckLocation() string {
+	return b.claimCheckLocation
+}
+
 func (b *batchDecoder) assembleClaimCheckRowChangedEvent(ctx context.Context, claimCheckLocation string) (*model.RowChangedEvent, error) {
 	_, claimCheckFileName := filepath.Split(claimCheckLocation)
 	data, err := b.storage.ReadFile(ctx, claimCheckFileName)
@@ -156,11 +394,55 @@ func (b *batchDecoder) assembleClaimCheckRowChangedEvent(ctx context.Context, cl
 	if err != nil {
 		return nil, err
 	}
+	if err = b.resetMySQLTypeKeys(value); err != nil {
+		return nil, err
+	}
 
 	b.msg = message
 	return b.NextRowChangedEvent()
 }
 
+// resetMySQLTypeKeys records the column names of the `mysqlType` field of the
+// encoded message in order, it's only required by ColumnOrderNone.
+func (b *batchDecoder) resetMySQLTypeKeys(encodedData []byte) error {
+	b.mysqlTypeKeys = nil
+	if b.columnOrder != ColumnOrderNone {
+		return nil
+	}
+	var holder struct {
+		MySQLType json.RawMessage `json:"mysqlType"`
+	}
+	if err := json.Unmarshal(encodedData, &holder); err != nil {
+		return errors.Trace(err)
+	}
+	if len(holder.MySQLType) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(holder.MySQLType))
+	token, err := decoder.Token()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// the `mysqlType` field is null.
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil
+	}
+	var keys []string
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var value string
+		if err := decoder.Decode(&value); err != nil {
+			return errors.Trace(err)
+		}
+		keys = append(keys, key.(string))
+	}
+	b.mysqlTypeKeys = keys
+	return nil
+}
+
 func (b *batchDecoder) buildData(holder *common.ColumnsHolder) (map[string]interface{}, map[string]string, error) {
 	columnsCount := holder.Length()
 	data := make(map[string]interface{}, columnsCount)
@@ -277,18 +559,175 @@ func (b *batchDecoder) NextRowChangedEvent() (*model.RowChangedEvent, error) {
 			return b.assembleHandleKeyOnlyRowChangedEvent(ctx, message)
 		}
 		if message.Extensions.ClaimCheckLocation != "" {
+			if b.storage == nil {
+				return nil, cerror.ErrCanalClaimCheckNotFetched.
+					GenWithStackByArgs(message.Extensions.ClaimCheckLocation)
+			}
 			return b.assembleClaimCheckRowChangedEvent(ctx, message.Extensions.ClaimCheckLocation)
 		}
 	}
 
-	result, err := canalJSONMessage2RowChange(b.msg)
+	b.fillMissingPKNames()
+	result, err := canalJSONMessage2RowChange(
+		b.msg, b.columnOrder, b.mysqlTypeKeys, b.errCounter, b.lenientOldColumns)
 	if err != nil {
 		return nil, err
 	}
+	if b.tableInfoProvider != nil && b.msg.eventType() == canal.EventType_INSERT {
+		tableInfo := b.tableInfoProvider(result.Table.Schema, result.Table.Table)
+		if tableInfo != nil {
+			if err = backfillDefaultColumns(result, tableInfo, b.columnOrder, b.config.TimeZone); err != nil {
+				return nil, err
+			}
+		}
+	}
 	b.msg = nil
 	return result, nil
 }
 
+// fillMissingPKNames sets the `pkNames` of the message by the key columns
+// provider if it's empty, so that the decoded table has a key.
+func (b *batchDecoder) fillMissingPKNames() {
+	if b.keyColumnsProvider == nil {
+		return
+	}
+	var jsonMessage *JSONMessage
+	switch m := b.msg.(type) {
+	case *JSONMessage:
+		jsonMessage = m
+	case *canalJSONMessageWithTiDBExtension:
+		jsonMessage = m.JSONMessage
+	default:
+		return
+	}
+	if len(jsonMessage.PKNames) != 0 {
+		return
+	}
+	columns := b.mysqlTypeKeys
+	if len(columns) == 0 {
+		columns = make([]string, 0, len(jsonMessage.MySQLType))
+		for name := range jsonMessage.MySQLType {
+			columns = append(columns, name)
+		}
+		sort.Strings(columns)
+	}
+	jsonMessage.PKNames = b.keyColumnsProvider(jsonMessage.Schema, jsonMessage.Table, columns)
+}
+
+// backfillDefaultColumns appends the columns which are absent from the row,
+// but have a default value in the table info. This happens when the column is
+// added by a DDL after the message was produced, without the backfill, the
+// downstream may insert NULL instead of the default value. The table info of
+// the row is extended with the backfilled columns.
+func backfillDefaultColumns(
+	row *model.RowChangedEvent, tableInfo *model.TableInfo, order ColumnOrder, tz *time.Location,
+) error {
+	present := make(map[string]struct{}, len(row.Columns))
+	for _, col := range row.Columns {
+		present[strings.ToLower(col.Name)] = struct{}{}
+	}
+
+	var backfilled []*timodel.ColumnInfo
+	for _, colInfo := range tableInfo.Columns {
+		if _, ok := present[colInfo.Name.L]; ok {
+			continue
+		}
+		// expression default values and generated columns are calculated by the downstream.
+		if colInfo.IsGenerated() || colInfo.DefaultIsExpr || isCurrentTimestampDefault(colInfo) {
+			continue
+		}
+		if colInfo.GetDefaultValue() == nil {
+			continue
+		}
+		value, err := getColumnDefaultValue(colInfo, tz)
+		if err != nil {
+			return cerror.ErrCanalDecodeFailed.GenWithStack(
+				"invalid default value of column %s.%s: %v", row.Table, colInfo.Name.O, err)
+		}
+		col := &model.Column{
+			Name:      colInfo.Name.O,
+			Type:      colInfo.GetType(),
+			Charset:   colInfo.GetCharset(),
+			Collation: colInfo.GetCollate(),
+			Value:     value,
+		}
+		if mysql.HasUnsignedFlag(colInfo.GetFlag()) {
+			col.Flag.SetIsUnsigned()
+		}
+		row.Columns = append(row.Columns, col)
+		backfilled = append(backfilled, colInfo)
+	}
+	if len(backfilled) == 0 {
+		return nil
+	}
+	// for ColumnOrderNone, the backfilled columns are appended in the
+	// column definition order.
+	sortColumns(row.Columns, order)
+
+	if row.TableInfo != nil {
+		info := row.TableInfo.TableInfo.Clone()
+		for _, colInfo := range backfilled {
+			colInfo = colInfo.Clone()
+			colInfo.ID = int64(len(info.Columns))
+			colInfo.Offset = len(info.Columns)
+			// the key of the row is decided by the message.
+			colInfo.DelFlag(mysql.PriKeyFlag)
+			info.Columns = append(info.Columns, colInfo)
+		}
+		row.TableInfo = model.WrapTableInfo(
+			row.TableInfo.SchemaID, row.TableInfo.TableName.Schema, row.TableInfo.Version, info)
+	}
+	return nil
+}
+
+// getColumnDefaultValue converts the default value of the column by its field
+// type, timestamps are stored in UTC and converted to tz. The value is
+// formatted like the decoded columns of the same type.
+func getColumnDefaultValue(colInfo *timodel.ColumnInfo, tz *time.Location) (interface{}, error) {
+	sc := new(stmtctx.StatementContext)
+	sc.SetTimeZone(tz)
+	datum := tiTypes.NewDatum(colInfo.GetDefaultValue())
+	d, err := datum.ConvertTo(sc, &colInfo.FieldType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch d.Kind() {
+	case tiTypes.KindNull:
+		return nil, nil
+	case tiTypes.KindMysqlEnum:
+		return d.GetMysqlEnum().Value, nil
+	case tiTypes.KindMysqlSet:
+		return d.GetMysqlSet().Value, nil
+	case tiTypes.KindMysqlBit, tiTypes.KindBinaryLiteral:
+		return d.GetBinaryLiteral().ToInt(sc)
+	case tiTypes.KindMysqlTime:
+		t := d.GetMysqlTime()
+		if t.Type() == mysql.TypeTimestamp && colInfo.Version >= timodel.ColumnInfoVersion1 {
+			if err := t.ConvertTimeZone(time.UTC, tz); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		return t.String(), nil
+	case tiTypes.KindMysqlDuration:
+		return d.GetMysqlDuration().String(), nil
+	case tiTypes.KindMysqlDecimal:
+		return d.GetMysqlDecimal().String(), nil
+	}
+	return d.GetValue(), nil
+}
+
+// isCurrentTimestampDefault returns true if the default value of the column is
+// CURRENT_TIMESTAMP, which is stored as a string without DefaultIsExpr.
+func isCurrentTimestampDefault(colInfo *timodel.ColumnInfo) bool {
+	switch colInfo.GetType() {
+	case mysql.TypeTimestamp, mysql.TypeDatetime:
+	default:
+		return false
+	}
+	defaultValue, ok := colInfo.GetDefaultValue().(string)
+	return ok && strings.EqualFold(defaultValue, ast.CurrentTimestamp)
+}
+
 // NextDDLEvent implements the RowEventDecoder interface
 // `HasNext` should be called before this.
 func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
@@ -297,6 +736,13 @@ func (b *batchDecoder) NextDDLEvent() (*model.DDLEvent, error) {
 			GenWithStack("not found ddl event message")
 	}
 
+	if withExtension, ok := b.msg.(*canalJSONMessageWithTiDBExtension); ok && b.config.ValidateDDLActionType {
+		if err := checkDDLActionType(withExtension.getQuery(), withExtension.Extensions.DDLActionType); err != nil {
+			b.msg = nil
+			return nil, err
+		}
+	}
+
 	result := canalJSONMessage2DDLEvent(b.msg)
 	b.msg = nil
 	return result, nil
@@ -310,13 +756,13 @@ func (b *batchDecoder) NextResolvedEvent() (uint64, error) {
 			GenWithStack("not found resolved event message")
 	}
 
-	withExtensionEvent, ok := b.msg.(*canalJSONMessageWithTiDBExtension)
-	if !ok {
+	if _, ok := b.msg.(*canalJSONMessageWithTiDBExtension); !ok {
 		log.Error("canal-json resolved event message should have tidb extension, but not found",
 			zap.Any("msg", b.msg))
 		return 0, cerror.ErrCanalDecodeFailed.
 			GenWithStack("MessageTypeResolved tidb extension not found")
 	}
+	watermarkTs := b.msg.getWatermarkTs()
 	b.msg = nil
-	return withExtensionEvent.Extensions.WatermarkTs, nil
+	return watermarkTs, nil
 }
@@ -15,11 +15,20 @@ package canal
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 
+	timodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/util/rowcodec"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -142,6 +151,125 @@ func TestNewCanalJSONBatchDecoder4DDLMessage(t *testing.T) {
 	}
 }
 
+func TestCanalJSONBatchDecoderValidateDDLActionType(t *testing.T) {
+	t.Parallel()
+
+	encodedValue := fmt.Sprintf(`{"id":0,"database":"test","table":"t","pkNames":null,"isDdl":true,"type":"CREATE","es":1668067205238,"ts":1668067206650,"sql":"CREATE TABLE t (id int primary key)","sqlType":null,"mysqlType":null,"data":null,"old":null,"_tidb":{"commitTs":417318403368288260,"ddlActionType":%d}}`,
+		timodel.ActionDropTable)
+
+	ctx := context.Background()
+	for _, validate := range []bool{false, true} {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		codecConfig.EnableTiDBExtension = true
+		codecConfig.ValidateDDLActionType = validate
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(nil, []byte(encodedValue))
+		require.NoError(t, err)
+
+		ty, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeDDL, ty)
+
+		consumed, err := decoder.NextDDLEvent()
+		if !validate {
+			require.NoError(t, err)
+			require.Equal(t, "CREATE TABLE t (id int primary key)", consumed.Query)
+			continue
+		}
+		require.True(t, cerror.ErrCanalDDLActionTypeMismatch.Equal(err))
+		require.Nil(t, consumed)
+	}
+
+	// the DDL type cannot be told from the query, no validation
+	require.NoError(t, checkDDLActionType("ALTER TABLE t ADD COLUMN a int", timodel.ActionAddColumn))
+	// no supplied DDL type, no validation
+	require.NoError(t, checkDDLActionType("CREATE TABLE t (id int)", timodel.ActionNone))
+	require.NoError(t, checkDDLActionType(" create table t (id int)", timodel.ActionCreateTable))
+	require.NoError(t, checkDDLActionType("RENAME TABLE a TO b, c TO d", timodel.ActionRenameTables))
+	require.True(t, cerror.ErrCanalDDLActionTypeMismatch.Equal(
+		checkDDLActionType("RENAME TABLE a TO b, c TO d", timodel.ActionRenameTable)))
+	require.True(t, cerror.ErrCanalDDLActionTypeMismatch.Equal(
+		checkDDLActionType("DROP INDEX idx ON t", timodel.ActionDropPrimaryKey)))
+}
+
+func TestGetDDLActionType(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		query    string
+		expected timodel.ActionType
+	}{
+		{"CREATE SCHEMA test", timodel.ActionCreateSchema},
+		{"create database test", timodel.ActionCreateSchema},
+		{"DROP SCHEMA test", timodel.ActionDropSchema},
+		{"drop database test", timodel.ActionDropSchema},
+		{"CREATE TABLE t (id int primary key)", timodel.ActionCreateTable},
+		{"DROP TABLE t", timodel.ActionDropTable},
+		{"ALTER TABLE t ADD COLUMN a int", timodel.ActionNone},
+		{"TRUNCATE TABLE t", timodel.ActionTruncateTable},
+		{"RENAME TABLE t TO t1", timodel.ActionRenameTable},
+		{"RENAME TABLE t TO t1, t2 TO t3", timodel.ActionRenameTables},
+		{"rename table test.t to test.t1,test.t2 to test.t3", timodel.ActionRenameTables},
+		{"CREATE INDEX idx ON t (a)", timodel.ActionAddIndex},
+		{"CREATE UNIQUE INDEX idx ON t (a)", timodel.ActionAddIndex},
+		{"DROP INDEX idx ON t", timodel.ActionDropIndex},
+		{"CREATE VIEW v AS SELECT 1", timodel.ActionCreateView},
+		{"DROP VIEW v", timodel.ActionDropView},
+		// leading comments and whitespaces are ignored.
+		{"  \n\tCREATE\n  TABLE t (id int)", timodel.ActionCreateTable},
+		{"/* comment */ DROP TABLE t", timodel.ActionDropTable},
+		{"-- comment\n# another comment\nTRUNCATE TABLE t", timodel.ActionTruncateTable},
+		{"/* unclosed comment DROP TABLE t", timodel.ActionNone},
+		// ambiguous and unknown DDLs fallback to ActionNone.
+		{"CREATE SEQUENCE seq", timodel.ActionNone},
+		{"", timodel.ActionNone},
+	} {
+		require.Equal(t, tc.expected, getDDLActionType(tc.query), tc.query)
+	}
+}
+
+func TestCanalJSONBatchDecoderDDLCommitTs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		extension        string
+		enableExtension  bool
+		expectedCommitTs uint64
+	}{
+		{`,"_tidb":{"commitTs":417318403368288260}`, true, 417318403368288260},
+		// the extension field is absent or empty.
+		{``, true, 0},
+		{`,"_tidb":null`, true, 0},
+		// the extension field is ignored in the non-extension format.
+		{`,"_tidb":{"commitTs":417318403368288260}`, false, 0},
+	} {
+		encodedValue := `{"id":0,"database":"test","table":"t","pkNames":null,"isDdl":true,"type":"CREATE",` +
+			`"es":1668067205238,"ts":1668067206650,"sql":"CREATE TABLE t (id int primary key)",` +
+			`"sqlType":null,"mysqlType":null,"data":null,"old":null` + tc.extension + `}`
+
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		codecConfig.EnableTiDBExtension = tc.enableExtension
+		codecConfig.ValidateDDLActionType = true
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(nil, []byte(encodedValue))
+		require.NoError(t, err)
+
+		ty, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeDDL, ty)
+
+		consumed, err := decoder.NextDDLEvent()
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedCommitTs, consumed.CommitTs)
+		require.Equal(t, "CREATE TABLE t (id int primary key)", consumed.Query)
+	}
+}
+
 func TestCanalJSONBatchDecoderWithTerminator(t *testing.T) {
 	encodedValue := `{"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"INSERT","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"FirstName":12,"HireDate":91,"LastName":12,"OfficeLocation":12,"id":4},"mysqlType":{"FirstName":"varchar","HireDate":"date","LastName":"varchar","OfficeLocation":"varchar","id":"int"},"data":[{"FirstName":"Bob","HireDate":"2014-06-04","LastName":"Smith","OfficeLocation":"New York","id":"101"}],"old":null}
 {"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"UPDATE","es":1668067229137,"ts":1668067230720,"sql":"","sqlType":{"FirstName":12,"HireDate":91,"LastName":12,"OfficeLocation":12,"id":4},"mysqlType":{"FirstName":"varchar","HireDate":"date","LastName":"varchar","OfficeLocation":"varchar","id":"int"},"data":[{"FirstName":"Bob","HireDate":"2015-10-08","LastName":"Smith","OfficeLocation":"Los Angeles","id":"101"}],"old":[{"FirstName":"Bob","HireDate":"2014-06-04","LastName":"Smith","OfficeLocation":"New York","id":"101"}]}
@@ -170,3 +298,705 @@ func TestCanalJSONBatchDecoderWithTerminator(t *testing.T) {
 	}
 	require.Equal(t, 3, cnt)
 }
+
+func TestCanalJSONBatchDecoderBackfillDefaultColumns(t *testing.T) {
+	encodedValue := `{"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"INSERT","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"FirstName":12,"id":4},"mysqlType":{"FirstName":"varchar","id":"int"},"data":[{"FirstName":"Bob","id":"101"}],"old":null}`
+
+	idCol := &timodel.ColumnInfo{ID: 1, Name: timodel.NewCIStr("id"), State: timodel.StatePublic}
+	idCol.SetType(mysql.TypeLong)
+	idCol.AddFlag(mysql.PriKeyFlag)
+	nameCol := &timodel.ColumnInfo{ID: 2, Name: timodel.NewCIStr("FirstName"), State: timodel.StatePublic}
+	nameCol.SetType(mysql.TypeVarchar)
+	// added by a later DDL, with a default value.
+	levelCol := &timodel.ColumnInfo{ID: 3, Name: timodel.NewCIStr("Level"), State: timodel.StatePublic}
+	levelCol.SetType(mysql.TypeLong)
+	require.NoError(t, levelCol.SetDefaultValue("10"))
+	// added by a later DDL, without a default value.
+	nickCol := &timodel.ColumnInfo{ID: 4, Name: timodel.NewCIStr("Nick"), State: timodel.StatePublic}
+	nickCol.SetType(mysql.TypeVarchar)
+	// added by a later DDL, with `DEFAULT CURRENT_TIMESTAMP`.
+	createdCol := &timodel.ColumnInfo{ID: 5, Name: timodel.NewCIStr("Created"), State: timodel.StatePublic}
+	createdCol.SetType(mysql.TypeTimestamp)
+	require.NoError(t, createdCol.SetDefaultValue("CURRENT_TIMESTAMP"))
+	// added by a later DDL, with an enum default value.
+	gradeCol := &timodel.ColumnInfo{ID: 6, Name: timodel.NewCIStr("Grade"), State: timodel.StatePublic}
+	gradeCol.SetType(mysql.TypeEnum)
+	gradeCol.SetElems([]string{"a", "b", "c"})
+	require.NoError(t, gradeCol.SetDefaultValue("b"))
+	// added by a later DDL, with a datetime default value.
+	joinedCol := &timodel.ColumnInfo{ID: 7, Name: timodel.NewCIStr("Joined"), State: timodel.StatePublic}
+	joinedCol.SetType(mysql.TypeDatetime)
+	require.NoError(t, joinedCol.SetDefaultValue("2024-01-02 03:04:05"))
+	tableInfo := model.WrapTableInfo(100, "test", 1, &timodel.TableInfo{
+		ID:         100,
+		Name:       timodel.NewCIStr("employee"),
+		Columns:    []*timodel.ColumnInfo{idCol, nameCol, levelCol, nickCol, createdCol, gradeCol, joinedCol},
+		PKIsHandle: true,
+	})
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+
+	// without table info, the absent column is not backfilled.
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(nil, []byte(encodedValue))
+	require.NoError(t, err)
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	event, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Len(t, event.Columns, 2)
+
+	var requested []string
+	decoder, err = NewBatchDecoder(ctx, codecConfig, nil,
+		WithTableInfoProvider(func(schema, table string) *model.TableInfo {
+			requested = append(requested, schema+"."+table)
+			return tableInfo
+		}))
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(nil, []byte(encodedValue))
+	require.NoError(t, err)
+	_, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	event, err = decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Equal(t, []string{"test.employee"}, requested)
+
+	// the CURRENT_TIMESTAMP default is calculated by the downstream.
+	require.Len(t, event.Columns, 5)
+	names := make([]string, 0, len(event.Columns))
+	for _, col := range event.Columns {
+		names = append(names, col.Name)
+		switch col.Name {
+		case "Level":
+			require.Equal(t, int64(10), col.Value)
+			require.Equal(t, mysql.TypeLong, col.Type)
+		case "Grade":
+			require.Equal(t, uint64(2), col.Value)
+			require.Equal(t, mysql.TypeEnum, col.Type)
+		case "Joined":
+			require.Equal(t, "2024-01-02 03:04:05", col.Value)
+			require.Equal(t, mysql.TypeDatetime, col.Type)
+		}
+	}
+	require.Equal(t, []string{"id", "Level", "Joined", "Grade", "FirstName"}, names)
+
+	// the table info of the row has the backfilled columns.
+	require.Len(t, event.TableInfo.Columns, 5)
+	for _, col := range event.Columns {
+		_, ok := event.TableInfo.ColumnIDByName(col.Name)
+		require.True(t, ok, col.Name)
+	}
+	id, ok := event.TableInfo.ColumnIDByName("Grade")
+	require.True(t, ok)
+	colInfo, ok := event.TableInfo.GetColumnInfo(id)
+	require.True(t, ok)
+	require.Equal(t, mysql.TypeEnum, colInfo.GetType())
+	require.Equal(t, []string{"a", "b", "c"}, colInfo.GetElems())
+}
+
+func TestCanalJSONBatchDecoderEnumAndSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+
+	testCases := []struct {
+		name      string
+		mysqlType string
+		data      string
+		elems     map[string][]string
+		enum      uint64
+		set       uint64
+	}{
+		{
+			name:      "element name",
+			mysqlType: `{"id":"int","e":"enum('a','b','it''s')","s":"set('x','y','z')"}`,
+			data:      `{"id":"1","e":"it's","s":"x,z"}`,
+			elems:     map[string][]string{"e": {"a", "b", "it's"}, "s": {"x", "y", "z"}},
+			enum:      3,
+			set:       5,
+		},
+		{
+			name:      "numeric value",
+			mysqlType: `{"id":"int","e":"enum('a','b','it''s')","s":"set('x','y','z')"}`,
+			data:      `{"id":"1","e":"2","s":"6"}`,
+			elems:     map[string][]string{"e": {"a", "b", "it's"}, "s": {"x", "y", "z"}},
+			enum:      2,
+			set:       6,
+		},
+		{
+			name:      "numeric value of numeric elements",
+			mysqlType: `{"id":"int","e":"enum('0','1','2')","s":"set('2','1')"}`,
+			data:      `{"id":"1","e":"1","s":"1"}`,
+			elems:     map[string][]string{"e": {"0", "1", "2"}, "s": {"2", "1"}},
+			enum:      1,
+			set:       1,
+		},
+		{
+			name:      "numeric value without elements",
+			mysqlType: `{"id":"int","e":"enum","s":"set"}`,
+			data:      `{"id":"1","e":"2","s":"6"}`,
+			elems:     map[string][]string{"e": nil, "s": nil},
+			enum:      2,
+			set:       6,
+		},
+	}
+	for _, tc := range testCases {
+		encodedValue := `{"id":0,"database":"test","table":"t","pkNames":["id"],"isDdl":false,"type":"INSERT",` +
+			`"es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"id":4,"e":4,"s":-7},` +
+			`"mysqlType":` + tc.mysqlType + `,"data":[` + tc.data + `],"old":null}`
+
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(nil, []byte(encodedValue))
+		require.NoError(t, err)
+		_, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		event, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err, tc.name)
+
+		// the element definitions are kept in the table info.
+		for name, elems := range tc.elems {
+			colID, ok := event.TableInfo.ColumnIDByName(name)
+			require.True(t, ok)
+			require.Equal(t, elems, event.TableInfo.ForceGetColumnInfo(colID).GetElems(), tc.name)
+		}
+		for _, col := range event.Columns {
+			switch col.Name {
+			case "e":
+				require.Equal(t, mysql.TypeEnum, col.Type, tc.name)
+				require.Equal(t, tc.enum, col.Value, tc.name)
+			case "s":
+				require.Equal(t, mysql.TypeSet, col.Type, tc.name)
+				require.Equal(t, tc.set, col.Value, tc.name)
+			}
+		}
+	}
+
+	// the element name which is not defined cannot be decoded.
+	encodedValue := `{"id":0,"database":"test","table":"t","pkNames":["id"],"isDdl":false,"type":"INSERT",` +
+		`"es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"id":4,"e":4},` +
+		`"mysqlType":{"id":"int","e":"enum('a','b')"},"data":[{"id":"1","e":"c"}],"old":null}`
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(nil, []byte(encodedValue))
+	require.NoError(t, err)
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	_, err = decoder.NextRowChangedEvent()
+	require.True(t, cerror.ErrCanalDecodeFailed.Equal(err))
+}
+
+func TestCanalJSONBatchDecoderColumnOrder(t *testing.T) {
+	t.Parallel()
+
+	encodedValue := `{"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"UPDATE","es":1668067229137,"ts":1668067230720,"sql":"","sqlType":{"id":4,"LastName":12,"FirstName":12,"HireDate":91},"mysqlType":{"id":"int","LastName":"varchar","FirstName":"varchar","HireDate":"date"},"data":[{"id":"101","LastName":"Smith","FirstName":"Bob","HireDate":"2015-10-08"}],"old":[{"HireDate":"2014-06-04"}]}`
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		opts     []DecoderOption
+		expected []string
+	}{
+		{nil, []string{"id", "LastName", "HireDate", "FirstName"}},
+		{[]DecoderOption{WithColumnOrder(ColumnOrderDescending)}, []string{"id", "LastName", "HireDate", "FirstName"}},
+		{[]DecoderOption{WithColumnOrder(ColumnOrderAscending)}, []string{"FirstName", "HireDate", "LastName", "id"}},
+		{[]DecoderOption{WithColumnOrder(ColumnOrderNone)}, []string{"id", "LastName", "FirstName", "HireDate"}},
+	} {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil, tc.opts...)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(nil, []byte(encodedValue))
+		require.NoError(t, err)
+		_, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		event, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+
+		for _, cols := range [][]*model.Column{event.Columns, event.PreColumns} {
+			names := make([]string, 0, len(cols))
+			for _, col := range cols {
+				names = append(names, col.Name)
+			}
+			require.Equal(t, tc.expected, names)
+		}
+	}
+
+	// the names of the mysqlType are sorted if the encoded order is unknown.
+	cols := map[string]interface{}{"b": "1", "a": "2", "c": "3"}
+	mysqlType := map[string]string{"c": "int", "b": "int", "a": "int"}
+	require.Equal(t, []string{"a", "b", "c"}, columnNamesInMySQLTypeOrder(cols, mysqlType, nil))
+	require.Equal(t, []string{"c", "a", "b"},
+		columnNamesInMySQLTypeOrder(cols, mysqlType, []string{"c", "a", "b"}))
+}
+
+func TestCanalJSONBatchDecoderUnsignedAndZerofill(t *testing.T) {
+	t.Parallel()
+
+	encodedValue := `{"id":0,"database":"test","table":"t","pkNames":["id"],"isDdl":false,"type":"INSERT","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"id":-5,"a":4,"b":4,"c":4},"mysqlType":{"id":"bigint unsigned","a":"int(10) unsigned zerofill","b":"int","c":"enum('unsigned')"},"data":[{"id":"18446744073709551615","a":"0000000001","b":"-1","c":"1"}],"old":null}`
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(nil, []byte(encodedValue))
+	require.NoError(t, err)
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	event, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+
+	for _, col := range event.Columns {
+		colID, ok := event.TableInfo.ColumnIDByName(col.Name)
+		require.True(t, ok)
+		flag := event.TableInfo.ForceGetColumnInfo(colID).GetFlag()
+		switch col.Name {
+		case "id":
+			require.Equal(t, "18446744073709551615", col.Value)
+			require.Equal(t, mysql.TypeLonglong, col.Type)
+			require.True(t, col.Flag.IsUnsigned())
+			require.False(t, col.Flag.IsZerofill())
+			require.True(t, mysql.HasUnsignedFlag(flag))
+			require.False(t, mysql.HasZerofillFlag(flag))
+		case "a":
+			require.True(t, col.Flag.IsUnsigned())
+			require.True(t, col.Flag.IsZerofill())
+			require.True(t, mysql.HasUnsignedFlag(flag))
+			require.True(t, mysql.HasZerofillFlag(flag))
+		case "b", "c":
+			require.False(t, col.Flag.IsUnsigned())
+			require.False(t, col.Flag.IsZerofill())
+			require.False(t, mysql.HasUnsignedFlag(flag))
+			require.False(t, mysql.HasZerofillFlag(flag))
+		}
+	}
+}
+
+func TestCanalJSONBatchDecoderFractionalSeconds(t *testing.T) {
+	t.Parallel()
+
+	encodedValue := `{"id":0,"database":"test","table":"t","pkNames":["id"],"isDdl":false,"type":"INSERT","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"id":4,"a":93,"b":93,"c":93},"mysqlType":{"id":"int","a":"datetime(3)","b":"timestamp(6)","c":"datetime"},"data":[{"id":"1","a":"2023-01-02 03:04:05.123","b":"2023-01-02 03:04:05.123456","c":"2023-01-02 03:04:05"}],"old":null}`
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(nil, []byte(encodedValue))
+	require.NoError(t, err)
+	_, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	event, err := decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+
+	expectedFsp := map[string]int{"a": 3, "b": 6, "c": 0}
+	for _, col := range event.Columns {
+		colID, ok := event.TableInfo.ColumnIDByName(col.Name)
+		require.True(t, ok)
+		colInfo := event.TableInfo.ForceGetColumnInfo(colID)
+		if fsp, ok := expectedFsp[col.Name]; ok {
+			require.Equal(t, col.Type, colInfo.GetType(), col.Name)
+			require.Equal(t, fsp, colInfo.GetDecimal(), col.Name)
+		}
+		event.ColInfos = append(event.ColInfos, rowcodec.ColInfo{
+			ID: colID,
+			Ft: &colInfo.FieldType,
+		})
+	}
+
+	// The fractional seconds survive encoding the decoded event again.
+	codecConfig.ContentCompatible = true
+	builder, err := NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+	require.NoError(t, err)
+	encoder := builder.Build()
+	err = encoder.AppendRowChangedEvent(ctx, "", event, func() {})
+	require.NoError(t, err)
+	messages := encoder.Build()
+	require.Len(t, messages, 1)
+
+	var msg JSONMessage
+	require.NoError(t, json.Unmarshal(messages[0].Value, &msg))
+	require.Equal(t, "datetime(3)", msg.MySQLType["a"])
+	require.Equal(t, "timestamp(6)", msg.MySQLType["b"])
+	require.Equal(t, "datetime", msg.MySQLType["c"])
+	require.Equal(t, "2023-01-02 03:04:05.123", msg.Data[0]["a"])
+	require.Equal(t, "2023-01-02 03:04:05.123456", msg.Data[0]["b"])
+	require.Equal(t, "2023-01-02 03:04:05", msg.Data[0]["c"])
+}
+
+func TestCanalJSONBatchDecoderMultipleRows(t *testing.T) {
+	t.Parallel()
+
+	insertValue := `{"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"INSERT","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"FirstName":12,"id":4},"mysqlType":{"FirstName":"varchar","id":"int"},"data":[{"FirstName":"Bob","id":"101"},{"FirstName":"Alice","id":"102"},{"FirstName":"Tom","id":"103"}],"old":null,"_tidb":{"commitTs":417318403368288260}}`
+	updateValue := `{"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"UPDATE","es":1668067229137,"ts":1668067230720,"sql":"","sqlType":{"FirstName":12,"id":4},"mysqlType":{"FirstName":"varchar","id":"int"},"data":[{"FirstName":"Bob2","id":"101"},{"FirstName":"Alice2","id":"102"},{"FirstName":"Tom2","id":"103"}],"old":[{"FirstName":"Bob"},{"FirstName":"Alice"},{"FirstName":"Tom"}],"_tidb":{"commitTs":417318403368288261}}`
+
+	ctx := context.Background()
+	for _, enableExtension := range []bool{false, true} {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		codecConfig.EnableTiDBExtension = enableExtension
+		codecConfig.Terminator = config.CRLF
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(nil, []byte(insertValue+config.CRLF+updateValue))
+		require.NoError(t, err)
+
+		names := []string{"Bob", "Alice", "Tom"}
+		ids := []string{"101", "102", "103"}
+		for i := 0; i < 3; i++ {
+			ty, hasNext, err := decoder.HasNext()
+			require.NoError(t, err)
+			require.True(t, hasNext)
+			require.Equal(t, model.MessageTypeRow, ty)
+			event, err := decoder.NextRowChangedEvent()
+			require.NoError(t, err)
+			require.True(t, event.IsInsert())
+			if enableExtension {
+				require.Equal(t, uint64(417318403368288260), event.CommitTs)
+			}
+			require.Equal(t, ids[i], event.Columns[0].Value)
+			require.Equal(t, names[i], event.Columns[1].Value)
+		}
+		for i := 0; i < 3; i++ {
+			ty, hasNext, err := decoder.HasNext()
+			require.NoError(t, err)
+			require.True(t, hasNext)
+			require.Equal(t, model.MessageTypeRow, ty)
+			event, err := decoder.NextRowChangedEvent()
+			require.NoError(t, err)
+			require.True(t, event.IsUpdate())
+			if enableExtension {
+				require.Equal(t, uint64(417318403368288261), event.CommitTs)
+			}
+			require.Equal(t, ids[i], event.Columns[0].Value)
+			require.Equal(t, names[i]+"2", event.Columns[1].Value)
+			require.Equal(t, ids[i], event.PreColumns[0].Value)
+			require.Equal(t, names[i], event.PreColumns[1].Value)
+		}
+		_, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.False(t, hasNext)
+	}
+
+	// the count of old rows mismatches the count of rows.
+	mismatchValue := `{"id":0,"database":"test","table":"employee","pkNames":["id"],"isDdl":false,"type":"UPDATE","es":1668067229137,"ts":1668067230720,"sql":"","sqlType":{"FirstName":12,"id":4},"mysqlType":{"FirstName":"varchar","id":"int"},"data":[{"FirstName":"Bob2","id":"101"},{"FirstName":"Alice2","id":"102"}],"old":[{"FirstName":"Bob"}]}`
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(nil, []byte(mismatchValue))
+	require.NoError(t, err)
+	_, _, err = decoder.HasNext()
+	require.True(t, cerror.ErrCanalDecodeFailed.Equal(err))
+}
+
+func TestCanalJSONBatchDecoderWatermarkTs(t *testing.T) {
+	t.Parallel()
+
+	encodedValue := `{"id":0,"database":"","table":"","pkNames":null,"isDdl":false,"type":"TIDB_WATERMARK","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":null,"mysqlType":null,"data":null,"old":null,"_tidb":{"watermarkTs":417318403368288260}}`
+
+	ctx := context.Background()
+	for _, enableExtension := range []bool{false, true} {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		codecConfig.EnableTiDBExtension = enableExtension
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(nil, []byte(encodedValue))
+		require.NoError(t, err)
+
+		ty, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeResolved, ty)
+
+		msg := decoder.(*batchDecoder).msg
+		if !enableExtension {
+			require.Equal(t, uint64(0), msg.getWatermarkTs())
+			_, err = decoder.NextResolvedEvent()
+			require.True(t, cerror.ErrCanalDecodeFailed.Equal(err))
+			continue
+		}
+		require.Equal(t, uint64(417318403368288260), msg.getWatermarkTs())
+		require.Equal(t, uint64(0), msg.getCommitTs())
+		ts, err := decoder.NextResolvedEvent()
+		require.NoError(t, err)
+		require.Equal(t, uint64(417318403368288260), ts)
+	}
+}
+
+func TestCanalJSONBatchDecoderSnapshotMarker(t *testing.T) {
+	t.Parallel()
+
+	marker := `{"id":0,"database":"test","table":"t","pkNames":["id"],"isDdl":%s,"type":"%s","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"id":4},"mysqlType":{"id":"int"},"data":[{"id":"1"}],"old":null}`
+	insert := `{"id":0,"database":"test","table":"t","pkNames":["id"],"isDdl":false,"type":"INSERT","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"id":4},"mysqlType":{"id":"int"},"data":[{"id":"2"}],"old":null}`
+
+	testCases := []struct {
+		marker string
+		opts   []DecoderOption
+	}{
+		{marker: fmt.Sprintf(marker, "false", "INIT_DDL")},
+		{marker: fmt.Sprintf(marker, "true", "INIT_DDL")},
+		{
+			marker: fmt.Sprintf(marker, "false", "SNAPSHOT"),
+			opts:   []DecoderOption{WithSnapshotMarkerTypes("SNAPSHOT")},
+		},
+	}
+	ctx := context.Background()
+	for _, tc := range testCases {
+		for _, enableExtension := range []bool{false, true} {
+			codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+			codecConfig.EnableTiDBExtension = enableExtension
+			codecConfig.Terminator = config.CRLF
+			decoder, err := NewBatchDecoder(ctx, codecConfig, nil, tc.opts...)
+			require.NoError(t, err)
+			err = decoder.AddKeyValue(nil, []byte(tc.marker+config.CRLF+insert))
+			require.NoError(t, err)
+
+			ty, hasNext, err := decoder.HasNext()
+			require.NoError(t, err)
+			require.True(t, hasNext)
+			require.Equal(t, model.MessageTypeSnapshotMarker, ty)
+			_, err = decoder.NextRowChangedEvent()
+			require.True(t, cerror.ErrCanalDecodeFailed.Equal(err))
+			_, err = decoder.NextDDLEvent()
+			require.True(t, cerror.ErrCanalDecodeFailed.Equal(err))
+
+			ty, hasNext, err = decoder.HasNext()
+			require.NoError(t, err)
+			require.True(t, hasNext)
+			require.Equal(t, model.MessageTypeRow, ty)
+			event, err := decoder.NextRowChangedEvent()
+			require.NoError(t, err)
+			require.Equal(t, "2", event.Columns[0].Value)
+
+			_, hasNext, err = decoder.HasNext()
+			require.NoError(t, err)
+			require.False(t, hasNext)
+		}
+	}
+
+	// the marker is decoded as a row if its type is not configured.
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(nil, []byte(fmt.Sprintf(marker, "false", "SNAPSHOT")))
+	require.NoError(t, err)
+	ty, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, ty)
+}
+
+func TestCanalJSONBatchDecoderClaimCheckLocation(t *testing.T) {
+	t.Parallel()
+
+	encodedValue := `{"id":0,"database":"test","table":"t","pkNames":["id"],"isDdl":false,"type":"INSERT","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"id":4},"mysqlType":{"id":"int"},"data":[{"id":"1"}],"old":null,"_tidb":{"commitTs":417318403368288260,"onlyHandleKey":false,"claimCheckLocation":"file:///tmp/claim-check/test-t-1.json"}}`
+
+	ctx := context.Background()
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	codecConfig.EnableTiDBExtension = true
+	// the claim check storage is not configured.
+	decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+	require.NoError(t, err)
+	err = decoder.AddKeyValue(nil, []byte(encodedValue))
+	require.NoError(t, err)
+
+	ty, hasNext, err := decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Equal(t, model.MessageTypeRow, ty)
+	require.Equal(t, "file:///tmp/claim-check/test-t-1.json", decoder.(*batchDecoder).ClaimCheckLocation())
+
+	event, err := decoder.NextRowChangedEvent()
+	require.True(t, cerror.ErrCanalClaimCheckNotFetched.Equal(err))
+	require.ErrorContains(t, err, "file:///tmp/claim-check/test-t-1.json")
+	require.Nil(t, event)
+
+	// the message is not a claim check reference.
+	err = decoder.AddKeyValue(nil, []byte(strings.Replace(encodedValue,
+		`"file:///tmp/claim-check/test-t-1.json"`, `""`, 1)))
+	require.NoError(t, err)
+	_, hasNext, err = decoder.HasNext()
+	require.NoError(t, err)
+	require.True(t, hasNext)
+	require.Empty(t, decoder.(*batchDecoder).ClaimCheckLocation())
+	event, err = decoder.NextRowChangedEvent()
+	require.NoError(t, err)
+	require.Equal(t, "1", event.Columns[0].Value)
+}
+
+func TestCanalJSONBatchDecoderErrorMetrics(t *testing.T) {
+	t.Parallel()
+
+	prefix := `{"id":0,"database":"test","table":"t","pkNames":["id"],"isDdl":false,"es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"id":4,"e":4},`
+	testCases := []struct {
+		category string
+		value    string
+	}{
+		{
+			category: decodeErrorMissingMySQLType,
+			value:    prefix + `"type":"INSERT","mysqlType":{"id":"int"},"data":[{"id":"1","e":"a"}],"old":null}`,
+		},
+		{
+			category: decodeErrorTypeConversion,
+			value:    prefix + `"type":"INSERT","mysqlType":{"id":"int","e":"enum('a','b')"},"data":[{"id":"1","e":"c"}],"old":null}`,
+		},
+		{
+			category: decodeErrorTypeConversion,
+			value:    prefix + `"type":"INSERT","mysqlType":{"id":"int","e":"bit(8)"},"data":[{"id":"1","e":"x"}],"old":null}`,
+		},
+		{
+			category: decodeErrorOldDataMismatch,
+			value:    prefix + `"type":"UPDATE","mysqlType":{"id":"int","e":"enum('a','b')"},"data":[{"id":"1","e":"a"},{"id":"2","e":"b"}],"old":[{"e":"b"}]}`,
+		},
+	}
+
+	ctx := context.Background()
+	for i, tc := range testCases {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON).
+			WithChangefeedID(model.DefaultChangeFeedID(fmt.Sprintf("decode-errors-%d", i)))
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+		require.NoError(t, err)
+		require.NoError(t, decoder.AddKeyValue(nil, []byte(tc.value)))
+		_, _, err = decoder.HasNext()
+		if err == nil {
+			_, err = decoder.NextRowChangedEvent()
+		}
+		require.True(t, cerror.ErrCanalDecodeFailed.Equal(err), "%s %v", tc.category, err)
+
+		// the error is only counted in its own category of the changefeed.
+		for _, category := range []string{
+			decodeErrorMissingMySQLType, decodeErrorTypeConversion, decodeErrorOldDataMismatch,
+		} {
+			expected := float64(0)
+			if category == tc.category {
+				expected = 1
+			}
+			counter := decodeErrorsCounter.WithLabelValues(
+				codecConfig.ChangefeedID.Namespace, codecConfig.ChangefeedID.ID, category)
+			require.Equal(t, expected, testutil.ToFloat64(counter), tc.category)
+		}
+	}
+}
+
+func TestCanalJSONBatchDecoderWithMetricsRegisterer(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+	// the metrics can be registered by multiple decoders.
+	for i := 0; i < 2; i++ {
+		_, err := NewBatchDecoder(context.Background(), codecConfig, nil, WithMetricsRegisterer(registry))
+		require.NoError(t, err)
+	}
+	decodeErrorsCounter.WithLabelValues("default", "registerer", decodeErrorMissingMySQLType).Inc()
+	count, err := testutil.GatherAndCount(registry, "ticdc_canal_json_decoder_decode_errors")
+	require.NoError(t, err)
+	require.Positive(t, count)
+}
+
+func TestCanalJSONBatchDecoderWithoutPKNames(t *testing.T) {
+	t.Parallel()
+
+	encodedValue := `{"id":0,"database":"test","table":"t","pkNames":null,"isDdl":false,"type":"INSERT","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"a":4,"b":12},"mysqlType":{"a":"int","b":"varchar"},"data":[{"a":"1","b":"x"}],"old":null}`
+
+	testCases := []struct {
+		opts       []DecoderOption
+		keyColumns []string
+	}{
+		{},
+		{
+			opts:       []DecoderOption{WithKeyColumnsProvider(AllColumnsAsKey)},
+			keyColumns: []string{"a", "b"},
+		},
+		{
+			opts: []DecoderOption{WithKeyColumnsProvider(func(schema, table string, _ []string) []string {
+				require.Equal(t, "test", schema)
+				require.Equal(t, "t", table)
+				return []string{"b"}
+			})},
+			keyColumns: []string{"b"},
+		},
+	}
+	ctx := context.Background()
+	for _, tc := range testCases {
+		for _, enableExtension := range []bool{false, true} {
+			codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+			codecConfig.EnableTiDBExtension = enableExtension
+			decoder, err := NewBatchDecoder(ctx, codecConfig, nil, tc.opts...)
+			require.NoError(t, err)
+			err = decoder.AddKeyValue(nil, []byte(encodedValue))
+			require.NoError(t, err)
+
+			ty, hasNext, err := decoder.HasNext()
+			require.NoError(t, err)
+			require.True(t, hasNext)
+			require.Equal(t, model.MessageTypeRow, ty)
+			event, err := decoder.NextRowChangedEvent()
+			require.NoError(t, err)
+
+			var keyColumns []string
+			for _, col := range event.Columns {
+				if col.Flag.IsHandleKey() {
+					keyColumns = append(keyColumns, col.Name)
+				}
+			}
+			require.ElementsMatch(t, tc.keyColumns, keyColumns)
+			require.Equal(t, len(tc.keyColumns) != 0, event.TableInfo.PKIsHandle)
+			if len(tc.keyColumns) == 0 {
+				require.Empty(t, event.TableInfo.Indices)
+				continue
+			}
+			require.Len(t, event.TableInfo.Indices, 1)
+			require.True(t, event.TableInfo.Indices[0].Primary)
+			require.Len(t, event.TableInfo.Indices[0].Columns, len(tc.keyColumns))
+		}
+	}
+}
+
+func TestCanalJSONBatchDecoderUpdateOldColumns(t *testing.T) {
+	t.Parallel()
+
+	changedOnly := `{"id":0,"database":"test","table":"t","pkNames":["a"],"isDdl":false,"type":"UPDATE","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"a":4,"b":12},"mysqlType":{"a":"int","b":"varchar"},"data":[{"a":"1","b":"new"}],"old":[{"b":"old"}]}`
+	// the column `c` of `old` is dropped before the update.
+	extraKey := `{"id":0,"database":"test","table":"t","pkNames":["a"],"isDdl":false,"type":"UPDATE","es":1668067205238,"ts":1668067206650,"sql":"","sqlType":{"a":4,"b":12},"mysqlType":{"a":"int","b":"varchar"},"data":[{"a":"1","b":"new"}],"old":[{"b":"old","c":"dropped"}]}`
+
+	ctx := context.Background()
+	decode := func(message string, opts ...DecoderOption) (*model.RowChangedEvent, error) {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil, opts...)
+		require.NoError(t, err)
+		require.NoError(t, decoder.AddKeyValue(nil, []byte(message)))
+		ty, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, ty)
+		return decoder.NextRowChangedEvent()
+	}
+	preValues := func(event *model.RowChangedEvent) map[string]interface{} {
+		values := make(map[string]interface{}, len(event.PreColumns))
+		for _, col := range event.PreColumns {
+			values[col.Name] = col.Value
+		}
+		return values
+	}
+
+	// the unchanged columns are taken from `data`.
+	for _, opts := range [][]DecoderOption{nil, {WithLenientOldColumns()}} {
+		event, err := decode(changedOnly, opts...)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"a": "1", "b": "old"}, preValues(event))
+	}
+
+	_, err := decode(extraKey)
+	require.True(t, cerror.ErrCanalDecodeFailed.Equal(err))
+	event, err := decode(extraKey, WithLenientOldColumns())
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"a": "1", "b": "old"}, preValues(event))
+	require.Len(t, event.Columns, 2)
+}
@@ -15,19 +15,29 @@ package canal
 
 import (
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/pingcap/log"
 	timodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/types"
+	tiTypes "github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tiflow/cdc/model"
 	cerrors "github.com/pingcap/tiflow/pkg/errors"
 	"github.com/pingcap/tiflow/pkg/sink/codec/internal"
 	"github.com/pingcap/tiflow/pkg/sink/codec/utils"
 	canal "github.com/pingcap/tiflow/proto/canal"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
-const tidbWaterMarkType = "TIDB_WATERMARK"
+const (
+	tidbWaterMarkType = "TIDB_WATERMARK"
+	// defaultSnapshotMarkerType is the `type` of the initial DDL message
+	// emitted by some canal producers before the snapshot.
+	defaultSnapshotMarkerType = "INIT_DDL"
+)
 
 // The TiCDC Canal-JSON implementation extend the official format with a TiDB extension field.
 // canalJSONMessageInterface is used to support this without affect the original format.
@@ -35,6 +45,7 @@ type canalJSONMessageInterface interface {
 	getSchema() *string
 	getTable() *string
 	getCommitTs() uint64
+	getWatermarkTs() uint64
 	getQuery() string
 	getOld() map[string]interface{}
 	getData() map[string]interface{}
@@ -82,6 +93,11 @@ func (c *JSONMessage) getCommitTs() uint64 {
 	return 0
 }
 
+// for JSONMessage, there is no watermark.
+func (c *JSONMessage) getWatermarkTs() uint64 {
+	return 0
+}
+
 func (c *JSONMessage) getQuery() string {
 	return c.Query
 }
@@ -137,6 +153,9 @@ type tidbExtension struct {
 	WatermarkTs        uint64 `json:"watermarkTs,omitempty"`
 	OnlyHandleKey      bool   `json:"onlyHandleKey,omitempty"`
 	ClaimCheckLocation string `json:"claimCheckLocation,omitempty"`
+	SourceClusterID    string `json:"sourceClusterID,omitempty"`
+	// DDLActionType is the authoritative DDL type supplied by the producer.
+	DDLActionType timodel.ActionType `json:"ddlActionType,omitempty"`
 }
 
 type canalJSONMessageWithTiDBExtension struct {
@@ -148,11 +167,22 @@ type canalJSONMessageWithTiDBExtension struct {
 	Extensions *tidbExtension `json:"_tidb"`
 }
 
+// getCommitTs returns the commitTs carried by the extension field, it's used by
+// both the row changed events and the DDL events.
 func (c *canalJSONMessageWithTiDBExtension) getCommitTs() uint64 {
 	return c.Extensions.CommitTs
 }
 
-func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*model.RowChangedEvent, error) {
+// getWatermarkTs returns the watermark ts carried by the extension field,
+// it's only set for the `TIDB_WATERMARK` message.
+func (c *canalJSONMessageWithTiDBExtension) getWatermarkTs() uint64 {
+	return c.Extensions.WatermarkTs
+}
+
+func canalJSONMessage2RowChange(
+	msg canalJSONMessageInterface, order ColumnOrder, mysqlTypeKeys []string,
+	errCounter *prometheus.CounterVec, lenientOldColumns bool,
+) (*model.RowChangedEvent, error) {
 	result := new(model.RowChangedEvent)
 	result.CommitTs = msg.getCommitTs()
 	result.TableInfo = newTableInfo(msg)
@@ -165,7 +195,7 @@ func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*model.RowChange
 	var err error
 	if msg.eventType() == canal.EventType_DELETE {
 		// for `DELETE` event, `data` contain the old data, set it as the `PreColumns`
-		result.PreColumns, err = canalJSONColumnMap2RowChangeColumns(msg.getData(), mysqlType)
+		result.PreColumns, err = canalJSONColumnMap2RowChangeColumns(msg.getData(), mysqlType, order, mysqlTypeKeys, errCounter)
 		// canal-json encoder does not encode `Flag` information into the result,
 		// we have to set the `Flag` to make it can be handled by MySQL Sink.
 		// see https://github.com/pingcap/tiflow/blob/7bfce98/cdc/sink/mysql.go#L869-L888
@@ -174,7 +204,7 @@ func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*model.RowChange
 	}
 
 	// for `INSERT` and `UPDATE`, `data` contain fresh data, set it as the `Columns`
-	result.Columns, err = canalJSONColumnMap2RowChangeColumns(msg.getData(), mysqlType)
+	result.Columns, err = canalJSONColumnMap2RowChangeColumns(msg.getData(), mysqlType, order, mysqlTypeKeys, errCounter)
 	if err != nil {
 		return nil, err
 	}
@@ -182,12 +212,24 @@ func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*model.RowChange
 	// for `UPDATE`, `old` contain old data, set it as the `PreColumns`
 	if msg.eventType() == canal.EventType_UPDATE {
 		oldColumns := msg.getOld()
+		if lenientOldColumns {
+			for key := range oldColumns {
+				if _, ok := mysqlType[key]; ok {
+					continue
+				}
+				log.Warn("skip the old column absent from mysql type",
+					zap.String("schema", result.Table.Schema),
+					zap.String("table", result.Table.Table),
+					zap.String("column", key))
+				delete(oldColumns, key)
+			}
+		}
 		for key, value := range msg.getData() {
 			if _, ok := oldColumns[key]; !ok {
 				oldColumns[key] = value
 			}
 		}
-		result.PreColumns, err = canalJSONColumnMap2RowChangeColumns(oldColumns, mysqlType)
+		result.PreColumns, err = canalJSONColumnMap2RowChangeColumns(oldColumns, mysqlType, order, mysqlTypeKeys, errCounter)
 		if err != nil {
 			return nil, err
 		}
@@ -196,31 +238,114 @@ func canalJSONMessage2RowChange(msg canalJSONMessageInterface) (*model.RowChange
 	return result, nil
 }
 
-func canalJSONColumnMap2RowChangeColumns(cols map[string]interface{}, mysqlType map[string]string) ([]*model.Column, error) {
+func canalJSONColumnMap2RowChangeColumns(
+	cols map[string]interface{}, mysqlType map[string]string,
+	order ColumnOrder, mysqlTypeKeys []string, errCounter *prometheus.CounterVec,
+) ([]*model.Column, error) {
+	var names []string
+	if order == ColumnOrderNone {
+		names = columnNamesInMySQLTypeOrder(cols, mysqlType, mysqlTypeKeys)
+	} else {
+		names = make([]string, 0, len(cols))
+		for name := range cols {
+			names = append(names, name)
+		}
+	}
+
 	result := make([]*model.Column, 0, len(cols))
-	for name, value := range cols {
+	for _, name := range names {
+		value := cols[name]
 		mysqlTypeStr, ok := mysqlType[name]
 		if !ok {
 			// this should not happen, else we have to check encoding for mysqlType.
+			errCounter.WithLabelValues(decodeErrorMissingMySQLType).Inc()
 			return nil, cerrors.ErrCanalDecodeFailed.GenWithStack(
 				"mysql type does not found, column: %+v, mysqlType: %+v", name, mysqlType)
 		}
-		mysqlTypeStr = extractBasicMySQLType(mysqlTypeStr)
-		isBinary := isBinaryMySQLType(mysqlTypeStr)
-		mysqlType := types.StrToType(mysqlTypeStr)
-		col := internal.NewColumn(value, mysqlType).
+		basicType := extractBasicMySQLType(mysqlTypeStr)
+		isBinary := isBinaryMySQLType(basicType)
+		mysqlType := types.StrToType(basicType)
+		if mysqlType == mysql.TypeEnum || mysqlType == mysql.TypeSet {
+			col, err := newEnumOrSetColumn(name, value, mysqlType, extractEnumOrSetElems(mysqlTypeStr))
+			if err != nil {
+				errCounter.WithLabelValues(decodeErrorTypeConversion).Inc()
+				return nil, err
+			}
+			result = append(result, col)
+			continue
+		}
+		col, err := internal.NewColumn(value, mysqlType).
 			ToCanalJSONFormatColumn(name, isBinary)
+		if err != nil {
+			errCounter.WithLabelValues(decodeErrorTypeConversion).Inc()
+			return nil, err
+		}
+		unsigned, zerofill := extractMySQLTypeAttributes(mysqlTypeStr)
+		if unsigned {
+			col.Flag.SetIsUnsigned()
+		}
+		if zerofill {
+			col.Flag.SetZeroFill()
+		}
 		result = append(result, col)
 	}
 	if len(result) == 0 {
 		return nil, nil
 	}
-	sort.Slice(result, func(i, j int) bool {
-		return strings.Compare(result[i].Name, result[j].Name) > 0
-	})
+	sortColumns(result, order)
 	return result, nil
 }
 
+// columnNamesInMySQLTypeOrder returns the column names of cols in the order of
+// mysqlTypeKeys. The names of the mysqlType are sorted in ascending order if
+// mysqlTypeKeys is not given, to keep the order stable.
+func columnNamesInMySQLTypeOrder(
+	cols map[string]interface{}, mysqlType map[string]string, mysqlTypeKeys []string,
+) []string {
+	if len(mysqlTypeKeys) == 0 {
+		mysqlTypeKeys = make([]string, 0, len(mysqlType))
+		for name := range mysqlType {
+			mysqlTypeKeys = append(mysqlTypeKeys, name)
+		}
+		sort.Strings(mysqlTypeKeys)
+	}
+
+	result := make([]string, 0, len(cols))
+	seen := make(map[string]struct{}, len(cols))
+	for _, name := range mysqlTypeKeys {
+		if _, ok := cols[name]; ok {
+			result = append(result, name)
+			seen[name] = struct{}{}
+		}
+	}
+	// the columns absent from the mysqlType are kept, so that the caller can
+	// report the error.
+	if len(result) != len(cols) {
+		for name := range cols {
+			if _, ok := seen[name]; !ok {
+				result = append(result, name)
+			}
+		}
+	}
+	return result
+}
+
+// sortColumns sorts the columns by name according to the order,
+// the columns are kept as is for ColumnOrderNone.
+func sortColumns(cols []*model.Column, order ColumnOrder) {
+	switch order {
+	case ColumnOrderAscending:
+		sort.Slice(cols, func(i, j int) bool {
+			return strings.Compare(cols[i].Name, cols[j].Name) < 0
+		})
+	case ColumnOrderNone:
+	default:
+		sort.Slice(cols, func(i, j int) bool {
+			return strings.Compare(cols[i].Name, cols[j].Name) > 0
+		})
+	}
+}
+
 func extractBasicMySQLType(mysqlType string) string {
 	for i := 0; i < len(mysqlType); i++ {
 		if mysqlType[i] == '(' || mysqlType[i] == ' ' {
@@ -230,6 +355,146 @@ func extractBasicMySQLType(mysqlType string) string {
 	return mysqlType
 }
 
+// newEnumOrSetColumn decodes the value of an enum or set column to its numeric
+// value. The value can be either the numeric value, which is sent by TiCDC, or
+// the element names, which requires the elements to be known.
+func newEnumOrSetColumn(name string, value interface{}, tp byte, elems []string) (*model.Column, error) {
+	col := &model.Column{
+		Name: name,
+		Type: tp,
+	}
+	if value == nil {
+		return col, nil
+	}
+	data, ok := value.(string)
+	if !ok {
+		return nil, cerrors.ErrCanalDecodeFailed.GenWithStack(
+			"canal-json encoded message should have type in `string`, column: %s, value: %+v", name, value)
+	}
+	if data == "" && tp == mysql.TypeEnum {
+		col.Value = uint64(0)
+		return col, nil
+	}
+	if len(elems) == 0 {
+		number, err := strconv.ParseUint(data, 10, 64)
+		if err != nil {
+			return nil, cerrors.ErrCanalDecodeFailed.GenWithStack(
+				"cannot decode the %s value without elements, column: %s, value: %s",
+				types.TypeToStr(tp, ""), name, data)
+		}
+		col.Value = number
+		return col, nil
+	}
+
+	// The encoder writes the numeric value, so a number is parsed as the value
+	// first, it's ambiguous with the names of elements like enum('0','1').
+	number, parseNumberErr := strconv.ParseUint(data, 10, 64)
+	var err error
+	if tp == mysql.TypeEnum {
+		var enum tiTypes.Enum
+		if parseNumberErr == nil {
+			enum, err = tiTypes.ParseEnumValue(elems, number)
+		} else {
+			enum, err = tiTypes.ParseEnum(elems, data, mysql.DefaultCollationName)
+		}
+		col.Value = enum.Value
+	} else {
+		var set tiTypes.Set
+		if parseNumberErr == nil {
+			set, err = tiTypes.ParseSetValue(elems, number)
+		} else {
+			set, err = tiTypes.ParseSet(elems, data, mysql.DefaultCollationName)
+		}
+		col.Value = set.Value
+	}
+	if err != nil {
+		return nil, cerrors.ErrCanalDecodeFailed.GenWithStack(
+			"parse %s value failed, column: %s, elems: %v, value: %s, err: %s",
+			types.TypeToStr(tp, ""), name, elems, data, err)
+	}
+	return col, nil
+}
+
+// extractEnumOrSetElems extracts the elements from the mysql type of an enum
+// or set column, such as `enum('a','b')`. It returns nil if the mysql type
+// carries no elements, which is the case if not in the content compatible mode.
+func extractEnumOrSetElems(mysqlType string) []string {
+	start := strings.IndexByte(mysqlType, '(')
+	if start < 0 {
+		return nil
+	}
+	var (
+		result  []string
+		elem    strings.Builder
+		inQuote bool
+	)
+	for i := start + 1; i < len(mysqlType); i++ {
+		ch := mysqlType[i]
+		if !inQuote {
+			switch ch {
+			case '\'':
+				inQuote = true
+				elem.Reset()
+			case ')':
+				return result
+			}
+			continue
+		}
+		switch {
+		case ch == '\\' && i+1 < len(mysqlType):
+			i++
+			elem.WriteByte(mysqlType[i])
+		case ch == '\'' && i+1 < len(mysqlType) && mysqlType[i+1] == '\'':
+			i++
+			elem.WriteByte(ch)
+		case ch == '\'':
+			inQuote = false
+			result = append(result, elem.String())
+		default:
+			elem.WriteByte(ch)
+		}
+	}
+	return result
+}
+
+// extractMySQLTypeAttributes tells whether the mysql type is unsigned or zerofill,
+// such as `int unsigned` or `int(10) unsigned zerofill`.
+// zerofill implies unsigned, the same as MySQL.
+func extractMySQLTypeAttributes(mysqlType string) (unsigned bool, zerofill bool) {
+	// skip the length or the elements, such as `enum('unsigned')`.
+	if i := strings.LastIndexByte(mysqlType, ')'); i >= 0 {
+		mysqlType = mysqlType[i+1:]
+	}
+	for _, token := range strings.Fields(strings.ToLower(mysqlType)) {
+		switch token {
+		case "unsigned":
+			unsigned = true
+		case "zerofill":
+			unsigned = true
+			zerofill = true
+		}
+	}
+	return unsigned, zerofill
+}
+
+// extractFsp returns the fractional seconds precision of a temporal type,
+// such as 6 for `datetime(6)`. It's 0 if the precision is absent.
+func extractFsp(mysqlType string) int {
+	start := strings.IndexByte(mysqlType, '(')
+	if start < 0 {
+		return 0
+	}
+	end := strings.IndexByte(mysqlType[start:], ')')
+	if end < 0 {
+		return 0
+	}
+	fsp, err := strconv.Atoi(strings.TrimSpace(mysqlType[start+1 : start+end]))
+	if err != nil || fsp < 0 || fsp > tiTypes.MaxFsp {
+		return 0
+	}
+	return fsp
+}
+
 func isBinaryMySQLType(mysqlType string) bool {
 	return strings.Contains(mysqlType, "blob") || strings.Contains(mysqlType, "binary")
 }
@@ -257,15 +522,87 @@ func canalJSONMessage2DDLEvent(msg canalJSONMessageInterface) *model.DDLEvent {
 // return DDL ActionType by the prefix
 // see https://github.com/pingcap/tidb/blob/6dbf2de2f/parser/model/ddl.go#L101-L102
 func getDDLActionType(query string) timodel.ActionType {
-	query = strings.ToLower(query)
-	if strings.HasPrefix(query, "create schema") || strings.HasPrefix(query, "create database") {
-		return timodel.ActionCreateSchema
+	normalized := normalizeDDLQuery(query)
+	for _, item := range ddlActionTypePrefixes {
+		if !strings.HasPrefix(normalized, item.prefix) {
+			continue
+		}
+		// e.g. `rename table a to b, c to d`
+		if item.actionType == timodel.ActionRenameTable && strings.Contains(normalized, ",") {
+			return timodel.ActionRenameTables
+		}
+		return item.actionType
 	}
-	if strings.HasPrefix(query, "drop schema") || strings.HasPrefix(query, "drop database") {
-		return timodel.ActionDropSchema
+	return timodel.ActionNone
+}
+
+// ddlActionTypePrefixes are the query prefixes used to tell the DDL type.
+// The ambiguous ones, which may be mapped to more than one DDL type, such as
+// `alter table`, are mapped to ActionNone, and are not used to validate the DDL
+// type supplied by the producer.
+var ddlActionTypePrefixes = []struct {
+	prefix     string
+	actionType timodel.ActionType
+}{
+	{"create schema", timodel.ActionCreateSchema},
+	{"create database", timodel.ActionCreateSchema},
+	{"drop schema", timodel.ActionDropSchema},
+	{"drop database", timodel.ActionDropSchema},
+	{"create table", timodel.ActionCreateTable},
+	{"drop table", timodel.ActionDropTable},
+	{"truncate table", timodel.ActionTruncateTable},
+	{"create view", timodel.ActionCreateView},
+	{"drop view", timodel.ActionDropView},
+	{"create index", timodel.ActionAddIndex},
+	{"create unique index", timodel.ActionAddIndex},
+	// renaming several tables in one statement is ActionRenameTables.
+	{"rename table", timodel.ActionRenameTable},
+	{"drop index", timodel.ActionDropIndex},
+	// e.g. add column, modify column, or several of them in one statement.
+	{"alter table", timodel.ActionNone},
+}
+
+// normalizeDDLQuery lowers the query, strips the leading comments and
+// collapses the whitespaces, so that the DDL type can be told by the prefix.
+func normalizeDDLQuery(query string) string {
+	query = strings.TrimSpace(query)
+	for {
+		switch {
+		case strings.HasPrefix(query, "/*"):
+			end := strings.Index(query, "*/")
+			if end < 0 {
+				return ""
+			}
+			query = query[end+2:]
+		case strings.HasPrefix(query, "--"), strings.HasPrefix(query, "#"):
+			end := strings.IndexByte(query, '\n')
+			if end < 0 {
+				return ""
+			}
+			query = query[end+1:]
+		default:
+			return strings.ToLower(strings.Join(strings.Fields(query), " "))
+		}
+		query = strings.TrimSpace(query)
 	}
+}
 
-	return timodel.ActionNone
+// checkDDLActionType returns an error if the DDL type guessed from the query
+// mismatches the supplied one. It's skipped if no type is supplied, or the type
+// cannot be told from the query exactly.
+func checkDDLActionType(query string, supplied timodel.ActionType) error {
+	if supplied == timodel.ActionNone {
+		return nil
+	}
+	actionType := getDDLActionType(query)
+	if actionType == timodel.ActionNone {
+		return nil
+	}
+	if actionType != supplied {
+		return cerrors.ErrCanalDDLActionTypeMismatch.GenWithStackByArgs(
+			query, actionType, supplied)
+	}
+	return nil
 }
 
 func newTableInfo(msg canalJSONMessageInterface) *model.TableInfo {
@@ -291,6 +628,24 @@ func newTiColumns(msg canalJSONMessageInterface) []*timodel.ColumnInfo {
 		if utils.IsBinaryMySQLType(mysqlType) {
 			col.AddFlag(mysql.BinaryFlag)
 		}
+		unsigned, zerofill := extractMySQLTypeAttributes(mysqlType)
+		if unsigned {
+			col.AddFlag(mysql.UnsignedFlag)
+		}
+		if zerofill {
+			col.AddFlag(mysql.ZerofillFlag)
+		}
+		tp := types.StrToType(extractBasicMySQLType(mysqlType))
+		switch tp {
+		case mysql.TypeEnum, mysql.TypeSet:
+			col.SetType(tp)
+			col.SetElems(extractEnumOrSetElems(mysqlType))
+		case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+			// keep the fractional seconds precision, so that the values are
+			// not truncated when they are encoded again.
+			col.SetType(tp)
+			col.SetDecimal(extractFsp(mysqlType))
+		}
 		if _, isPK := msg.pkNameSet()[name]; isPK {
 			col.AddFlag(mysql.PriKeyFlag)
 		}
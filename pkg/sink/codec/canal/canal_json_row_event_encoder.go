@@ -263,6 +263,11 @@ func newJSONMessageForDML(
 		out.RawByte('{')
 		out.RawString("\"commitTs\":")
 		out.Uint64(e.CommitTs)
+		if config.SourceClusterID != "" {
+			out.RawByte(',')
+			out.RawString("\"sourceClusterID\":")
+			out.String(config.SourceClusterID)
+		}
 
 		// only send handle key may happen in 2 cases:
 		// 1. delete event, and set only handle key config. no need to encode `onlyHandleKey` field
@@ -307,6 +312,41 @@ type JSONRowEventEncoder struct {
 	claimCheck *claimcheck.ClaimCheck
 
 	config *common.Config
+
+	stats EncodeStats
+	// rawLength is the length of the last compressed value before compression.
+	rawLength int
+}
+
+// EncodeStats is the statistics of the row changed events encoded by the
+// canal-json encoder, it can be used to size topics and tune compression.
+type EncodeStats struct {
+	InsertCount uint64
+	UpdateCount uint64
+	DeleteCount uint64
+	// MessageCount is the number of encoded row messages.
+	MessageCount uint64
+	// RawBytes is the total length of the message values before compression.
+	RawBytes uint64
+	// EncodedBytes is the total length of the message values after compression.
+	EncodedBytes uint64
+}
+
+// AvgMessageSize returns the average length of the encoded message values.
+func (s EncodeStats) AvgMessageSize() float64 {
+	if s.MessageCount == 0 {
+		return 0
+	}
+	return float64(s.EncodedBytes) / float64(s.MessageCount)
+}
+
+// CompressionRatio returns the ratio of the raw length to the encoded length,
+// it's 1 if the compression is disabled.
+func (s EncodeStats) CompressionRatio() float64 {
+	if s.EncodedBytes == 0 {
+		return 0
+	}
+	return float64(s.RawBytes) / float64(s.EncodedBytes)
 }
 
 // newJSONRowEventEncoder creates a new JSONRowEventEncoder
@@ -339,7 +379,10 @@ func (c *JSONRowEventEncoder) newJSONMessageForDDL(e *model.DDLEvent) canalJSONM
 
 	return &canalJSONMessageWithTiDBExtension{
 		JSONMessage: msg,
-		Extensions:  &tidbExtension{CommitTs: e.CommitTs},
+		Extensions: &tidbExtension{
+			CommitTs:        e.CommitTs,
+			SourceClusterID: c.config.SourceClusterID,
+		},
 	}
 }
 
@@ -354,7 +397,10 @@ func (c *JSONRowEventEncoder) newJSONMessage4CheckpointEvent(
 			ExecutionTime: convertToCanalTs(ts),
 			BuildTime:     time.Now().UnixNano() / int64(time.Millisecond), // converts to milliseconds
 		},
-		Extensions: &tidbExtension{WatermarkTs: ts},
+		Extensions: &tidbExtension{
+			WatermarkTs:     ts,
+			SourceClusterID: c.config.SourceClusterID,
+		},
 	}
 }
 
@@ -392,9 +438,7 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 		return errors.Trace(err)
 	}
 
-	value, err = common.Compress(
-		c.config.ChangefeedID, c.config.LargeMessageHandle.LargeMessageHandleCompression, value,
-	)
+	value, err = c.compress(value)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -426,9 +470,7 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 			if err != nil {
 				return cerror.ErrMessageTooLarge.GenWithStackByArgs()
 			}
-			value, err = common.Compress(
-				c.config.ChangefeedID, c.config.LargeMessageHandle.LargeMessageHandleCompression, value,
-			)
+			value, err = c.compress(value)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -464,9 +506,37 @@ func (c *JSONRowEventEncoder) AppendRowChangedEvent(
 	}
 
 	c.messages = append(c.messages, m)
+	c.recordStats(e, m)
 	return nil
 }
 
+// compress compresses the value and records its raw length for statistics.
+func (c *JSONRowEventEncoder) compress(value []byte) ([]byte, error) {
+	c.rawLength = len(value)
+	return common.Compress(
+		c.config.ChangefeedID, c.config.LargeMessageHandle.LargeMessageHandleCompression, value,
+	)
+}
+
+func (c *JSONRowEventEncoder) recordStats(e *model.RowChangedEvent, m *common.Message) {
+	switch {
+	case e.IsDelete():
+		c.stats.DeleteCount++
+	case e.IsInsert():
+		c.stats.InsertCount++
+	default:
+		c.stats.UpdateCount++
+	}
+	c.stats.MessageCount++
+	c.stats.RawBytes += uint64(c.rawLength)
+	c.stats.EncodedBytes += uint64(len(m.Value))
+}
+
+// EncodeStats returns the statistics of the encoded row changed events.
+func (c *JSONRowEventEncoder) EncodeStats() EncodeStats {
+	return c.stats
+}
+
 func (c *JSONRowEventEncoder) newClaimCheckLocationMessage(
 	event *model.RowChangedEvent, callback func(), fileName string,
 ) (*common.Message, error) {
@@ -476,9 +546,7 @@ func (c *JSONRowEventEncoder) newClaimCheckLocationMessage(
 		return nil, cerror.WrapError(cerror.ErrCanalEncodeFailed, err)
 	}
 
-	value, err = common.Compress(
-		c.config.ChangefeedID, c.config.LargeMessageHandle.LargeMessageHandleCompression, value,
-	)
+	value, err = c.compress(value)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -765,6 +765,54 @@ func TestCanalJSONContentCompatibleE2E(t *testing.T) {
 	}
 }
 
+func TestCanalJSONSourceClusterIDE2E(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, sourceClusterID := range []string{"", "cluster-1"} {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		codecConfig.EnableTiDBExtension = true
+		codecConfig.SourceClusterID = sourceClusterID
+
+		builder, err := NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+		require.NoError(t, err)
+		encoder := builder.Build()
+
+		err = encoder.AppendRowChangedEvent(ctx, "", testCaseInsert, func() {})
+		require.NoError(t, err)
+		message := encoder.Build()[0]
+		if sourceClusterID == "" {
+			require.NotContains(t, string(message.Value), "sourceClusterID")
+		}
+
+		decoder, err := NewBatchDecoder(ctx, codecConfig, nil)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(message.Key, message.Value)
+		require.NoError(t, err)
+
+		messageType, hasNext, err := decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeRow, messageType)
+		require.Equal(t, sourceClusterID, decoder.(*batchDecoder).SourceClusterID())
+
+		decodedEvent, err := decoder.NextRowChangedEvent()
+		require.NoError(t, err)
+		require.Equal(t, testCaseInsert.CommitTs, decodedEvent.CommitTs)
+
+		// checkpoint event also carries the source cluster ID.
+		message, err = encoder.EncodeCheckpointEvent(testCaseInsert.CommitTs)
+		require.NoError(t, err)
+		err = decoder.AddKeyValue(message.Key, message.Value)
+		require.NoError(t, err)
+		messageType, hasNext, err = decoder.HasNext()
+		require.NoError(t, err)
+		require.True(t, hasNext)
+		require.Equal(t, model.MessageTypeResolved, messageType)
+		require.Equal(t, sourceClusterID, decoder.(*batchDecoder).SourceClusterID())
+	}
+}
+
 func TestE2EPartitionTable(t *testing.T) {
 	helper := entry.NewSchemaTestHelper(t)
 	defer helper.Close()
@@ -816,3 +864,46 @@ func TestE2EPartitionTable(t *testing.T) {
 		require.Equal(t, decodedEvent.GetTableID(), int64(0))
 	}
 }
+
+func TestCanalJSONEncodeStats(t *testing.T) {
+	t.Parallel()
+
+	for _, compressionCodec := range []string{compression.None, compression.LZ4} {
+		codecConfig := common.NewConfig(config.ProtocolCanalJSON)
+		codecConfig.LargeMessageHandle.LargeMessageHandleCompression = compressionCodec
+
+		ctx := context.Background()
+		builder, err := NewJSONRowEventEncoderBuilder(ctx, codecConfig)
+		require.NoError(t, err)
+		encoder := builder.Build().(*JSONRowEventEncoder)
+		require.Equal(t, EncodeStats{}, encoder.EncodeStats())
+
+		events := []*model.RowChangedEvent{
+			testCaseInsert, testCaseInsert, testCaseUpdate, testCaseDelete,
+		}
+		for _, e := range events {
+			err = encoder.AppendRowChangedEvent(ctx, "", e, func() {})
+			require.NoError(t, err)
+		}
+		messages := encoder.Build()
+		require.Len(t, messages, len(events))
+		encodedBytes := 0
+		for _, m := range messages {
+			encodedBytes += len(m.Value)
+		}
+
+		stats := encoder.EncodeStats()
+		require.Equal(t, uint64(2), stats.InsertCount)
+		require.Equal(t, uint64(1), stats.UpdateCount)
+		require.Equal(t, uint64(1), stats.DeleteCount)
+		require.Equal(t, uint64(len(events)), stats.MessageCount)
+		require.Equal(t, uint64(encodedBytes), stats.EncodedBytes)
+		require.Equal(t, float64(encodedBytes)/float64(len(events)), stats.AvgMessageSize())
+		if compressionCodec == compression.None {
+			require.Equal(t, stats.EncodedBytes, stats.RawBytes)
+			require.Equal(t, float64(1), stats.CompressionRatio())
+		} else {
+			require.NotEqual(t, stats.EncodedBytes, stats.RawBytes)
+		}
+	}
+}
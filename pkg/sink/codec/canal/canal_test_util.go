@@ -416,8 +416,13 @@ var (
 
 		{
 			&model.Column{Name: "enum", Type: mysql.TypeEnum, Value: uint64(1)},
-			rowcodec.ColInfo{ID: 49, IsPKHandle: false, VirtualGenCol: false, Ft: types.NewFieldType(mysql.TypeEnum)},
-			"1", "1",
+			rowcodec.ColInfo{
+				ID:            49,
+				IsPKHandle:    false,
+				VirtualGenCol: false,
+				Ft:            utils.SetElems(types.NewFieldType(mysql.TypeEnum), []string{"a", "b", "c"}),
+			},
+			"1", uint64(1),
 		},
 		{
 			&model.Column{Name: "set", Type: mysql.TypeSet, Value: uint64(2)},
@@ -0,0 +1,45 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canal
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// The categories of the errors found when decoding canal-json messages.
+const (
+	// decodeErrorMissingMySQLType means a column is absent from the `mysqlType` field.
+	decodeErrorMissingMySQLType = "missing-mysql-type"
+	// decodeErrorTypeConversion means a column value can't be converted to its mysql type.
+	decodeErrorTypeConversion = "type-conversion-failure"
+	// decodeErrorOldDataMismatch means the count of the `old` rows mismatches
+	// the count of the `data` rows of an UPDATE event.
+	decodeErrorOldDataMismatch = "old-data-length-mismatch"
+)
+
+var decodeErrorsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "ticdc",
+		Subsystem: "canal_json_decoder",
+		Name:      "decode_errors",
+		Help:      "The number of errors found when decoding canal-json messages",
+	}, []string{"namespace", "changefeed", "category"})
+
+// registerMetrics registers all metrics in this file, it's safe to be called
+// multiple times with the same registerer.
+func registerMetrics(registerer prometheus.Registerer) error {
+	err := registerer.Register(decodeErrorsCounter)
+	if _, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		return nil
+	}
+	return err
+}
@@ -63,6 +63,12 @@ type Config struct {
 
 	// canal-json only
 	ContentCompatible bool
+	// SourceClusterID is written into the TiDB extension of canal-json messages
+	// to tell which upstream cluster the message comes from, omitted if empty.
+	SourceClusterID string
+	// ValidateDDLActionType makes the decoder compare the DDL type guessed from the query
+	// with the one supplied in the TiDB extension, and fail on mismatch.
+	ValidateDDLActionType bool
 
 	// for sinking to cloud storage
 	Delimiter            string
@@ -157,6 +163,10 @@ type urlConfig struct {
 	// can be `json` and `avro`, default to `json`.
 	EncodingFormatType *string `form:"encoding-format"`
 	ContentCompatible  *bool   `form:"content-compatible"`
+	SourceClusterID    *string `form:"source-cluster-id"`
+
+	// ValidateDDLActionType is only used by the canal-json decoder.
+	ValidateDDLActionType *bool `form:"validate-ddl-action-type"`
 }
 
 // Apply fill the Config
@@ -264,6 +274,8 @@ func (c *Config) Apply(sinkURI *url.URL, replicaConfig *config.ReplicaConfig) er
 		if c.ContentCompatible {
 			c.OnlyOutputUpdatedColumns = true
 		}
+		c.SourceClusterID = util.GetOrZero(urlParameter.SourceClusterID)
+		c.ValidateDDLActionType = util.GetOrZero(urlParameter.ValidateDDLActionType)
 	}
 
 	return nil
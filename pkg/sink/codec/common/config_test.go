@@ -482,6 +482,24 @@ func TestApplyConfig4CanalJSON(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, codecConfig.ContentCompatible)
 	require.True(t, codecConfig.OnlyOutputUpdatedColumns)
+	require.Equal(t, "", codecConfig.SourceClusterID)
+	require.False(t, codecConfig.ValidateDDLActionType)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=canal-json&source-cluster-id=cluster-1"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+	codecConfig = NewConfig(config.ProtocolCanalJSON)
+	err = codecConfig.Apply(sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.Equal(t, "cluster-1", codecConfig.SourceClusterID)
+
+	uri = "kafka://127.0.0.1:9092/abc?protocol=canal-json&validate-ddl-action-type=true"
+	sinkURI, err = url.Parse(uri)
+	require.NoError(t, err)
+	codecConfig = NewConfig(config.ProtocolCanalJSON)
+	err = codecConfig.Apply(sinkURI, config.GetDefaultReplicaConfig())
+	require.NoError(t, err)
+	require.True(t, codecConfig.ValidateDDLActionType)
 }
 
 func TestConfig4Simple(t *testing.T) {
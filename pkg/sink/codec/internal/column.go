@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
+	cerror "github.com/pingcap/tiflow/pkg/errors"
 	"go.uber.org/zap"
 	"golang.org/x/text/encoding/charmap"
 )
@@ -103,28 +104,30 @@ func (c *Column) ToRowChangeColumn(name string) *model.Column {
 }
 
 // ToCanalJSONFormatColumn converts from a codec column to a row changed column in canal-json format.
-func (c *Column) ToCanalJSONFormatColumn(name string, isBlob bool) *model.Column {
+func (c *Column) ToCanalJSONFormatColumn(name string, isBlob bool) (*model.Column, error) {
 	col := new(model.Column)
 	col.Type = c.Type
 	col.Flag = c.Flag
 	col.Name = name
 	col.Value = c.Value
 	if col.Value == nil {
-		return col
+		return col, nil
 	}
 
 	value, ok := col.Value.(string)
 	if !ok {
-		log.Panic("canal-json encoded message should have type in `string`")
+		return nil, cerror.ErrCanalDecodeFailed.GenWithStack(
+			"canal-json encoded message should have type in `string`, column: %s, value: %v", name, col.Value)
 	}
 
 	if col.Type == mysql.TypeBit || col.Type == mysql.TypeSet {
 		val, err := strconv.ParseUint(value, 10, 64)
 		if err != nil {
-			log.Panic("invalid column value for bit", zap.Any("col", c), zap.Error(err))
+			return nil, cerror.ErrCanalDecodeFailed.GenWithStack(
+				"invalid column value for bit or set, column: %s, value: %s, err: %v", name, value, err)
 		}
 		col.Value = val
-		return col
+		return col, nil
 	}
 
 	var err error
@@ -133,12 +136,13 @@ func (c *Column) ToCanalJSONFormatColumn(name string, isBlob bool) *model.Column
 		encoder := charmap.ISO8859_1.NewEncoder()
 		value, err = encoder.String(value)
 		if err != nil {
-			log.Panic("invalid column value, please report a bug", zap.Any("col", col), zap.Error(err))
+			return nil, cerror.ErrCanalDecodeFailed.GenWithStack(
+				"invalid column value for binary, column: %s, err: %v", name, err)
 		}
 	}
 
 	col.Value = value
-	return col
+	return col, nil
 }
 
 // FormatColumn formats a codec column.
@@ -0,0 +1,126 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiflow/engine/pkg/clock"
+)
+
+// defaultCapabilityCacheTTL is short, so that a downstream upgrade is detected
+// soon after it's done.
+const defaultCapabilityCacheTTL = 30 * time.Second
+
+// DefaultCapabilityCache is the capability cache shared by the MySQL sinks of
+// all changefeeds.
+var DefaultCapabilityCache = NewCapabilityCache(defaultCapabilityCacheTTL)
+
+// Capabilities is the capabilities of a downstream.
+type Capabilities struct {
+	// IsTiDB indicates whether the downstream is TiDB.
+	IsTiDB bool
+	// IsBDRModeSupported indicates whether the downstream supports BDR mode.
+	// It's only probed if required.
+	IsBDRModeSupported bool
+}
+
+type capabilityCacheEntry struct {
+	Capabilities
+	bdrModeProbed bool
+	expireAt      time.Time
+}
+
+// CapabilityCache caches the capabilities of the downstreams keyed by host,
+// so that the changefeeds replicating to the same downstream don't need to
+// probe it repeatedly. A nil cache probes the downstream every time.
+type CapabilityCache struct {
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]capabilityCacheEntry
+}
+
+// NewCapabilityCache creates a CapabilityCache, whose entries expire after ttl.
+func NewCapabilityCache(ttl time.Duration) *CapabilityCache {
+	return &CapabilityCache{
+		ttl:     ttl,
+		clock:   clock.New(),
+		entries: make(map[string]capabilityCacheEntry),
+	}
+}
+
+// Probe returns the capabilities of the downstream of the host, they are probed
+// through db if not cached. The result is not cached if the downstream can't
+// be connected during the probe, and the cached entry is invalidated.
+func (c *CapabilityCache) Probe(
+	ctx context.Context, db *sql.DB, host string, probeBDRMode bool,
+) (Capabilities, error) {
+	if c != nil {
+		c.mu.Lock()
+		entry, ok := c.entries[host]
+		c.mu.Unlock()
+		if ok && c.clock.Now().Before(entry.expireAt) && (entry.bdrModeProbed || !probeBDRMode) {
+			return entry.Capabilities, nil
+		}
+	}
+
+	var caps Capabilities
+	isTiDB, probeErr := checkIsTiDB(ctx, db)
+	caps.IsTiDB = isTiDB
+	if probeBDRMode && isTiDB {
+		supported, err := checkWriteSourceSupported(ctx, db)
+		if err != nil {
+			c.Invalidate(host)
+			return Capabilities{}, err
+		}
+		caps.IsBDRModeSupported = supported
+	}
+	if c == nil {
+		return caps, nil
+	}
+
+	// A MySQL error is an answer of the downstream, other errors mean the
+	// downstream is unreachable, so the result is unreliable.
+	if _, ok := errors.Cause(probeErr).(*dmysql.MySQLError); probeErr != nil && !ok {
+		c.Invalidate(host)
+		return caps, nil
+	}
+	c.mu.Lock()
+	c.entries[host] = capabilityCacheEntry{
+		Capabilities:  caps,
+		bdrModeProbed: probeBDRMode,
+		expireAt:      c.clock.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+	return caps, nil
+}
+
+// Invalidate removes the cached capabilities of the downstream of the host,
+// it should be called on connection errors, since the downstream may be
+// restarted with another version.
+func (c *CapabilityCache) Invalidate(host string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, host)
+	c.mu.Unlock()
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	dmysql "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tiflow/engine/pkg/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilityCache(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	expectTiDB := func() {
+		mock.ExpectQuery("select tidb_version()").
+			WillReturnRows(sqlmock.NewRows([]string{"tidb_version()"}).AddRow("5.7.25-TiDB-v7.5.0"))
+	}
+	expectWriteSource := func() {
+		mock.ExpectExec("SET SESSION tidb_cdc_write_source = 1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	ctx := context.Background()
+	mockClock := clock.NewMock()
+	cache := NewCapabilityCache(time.Minute)
+	cache.clock = mockClock
+
+	// probed only once within the ttl.
+	expectTiDB()
+	caps, err := cache.Probe(ctx, db, "127.0.0.1:4000", false)
+	require.NoError(t, err)
+	require.Equal(t, Capabilities{IsTiDB: true}, caps)
+	caps, err = cache.Probe(ctx, db, "127.0.0.1:4000", false)
+	require.NoError(t, err)
+	require.Equal(t, Capabilities{IsTiDB: true}, caps)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// probed again if BDR mode is not probed before.
+	expectTiDB()
+	expectWriteSource()
+	caps, err = cache.Probe(ctx, db, "127.0.0.1:4000", true)
+	require.NoError(t, err)
+	require.Equal(t, Capabilities{IsTiDB: true, IsBDRModeSupported: true}, caps)
+	caps, err = cache.Probe(ctx, db, "127.0.0.1:4000", false)
+	require.NoError(t, err)
+	require.Equal(t, Capabilities{IsTiDB: true, IsBDRModeSupported: true}, caps)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// probed again after the entry expires.
+	mockClock.Add(time.Minute)
+	expectTiDB()
+	_, err = cache.Probe(ctx, db, "127.0.0.1:4000", false)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// probed again after the entry is invalidated, and the result of a
+	// connection error is not cached.
+	cache.Invalidate("127.0.0.1:4000")
+	mock.ExpectQuery("select tidb_version()").WillReturnError(dmysql.ErrInvalidConn)
+	caps, err = cache.Probe(ctx, db, "127.0.0.1:4000", false)
+	require.NoError(t, err)
+	require.Equal(t, Capabilities{}, caps)
+	expectTiDB()
+	caps, err = cache.Probe(ctx, db, "127.0.0.1:4000", false)
+	require.NoError(t, err)
+	require.Equal(t, Capabilities{IsTiDB: true}, caps)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// a nil cache always probes.
+	var nilCache *CapabilityCache
+	expectTiDB()
+	expectTiDB()
+	for i := 0; i < 2; i++ {
+		caps, err = nilCache.Probe(ctx, db, "127.0.0.1:4000", false)
+		require.NoError(t, err)
+		require.Equal(t, Capabilities{IsTiDB: true}, caps)
+	}
+	nilCache.Invalidate("127.0.0.1:4000")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
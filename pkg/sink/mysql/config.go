@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
 	cerror "github.com/pingcap/tiflow/pkg/errors"
+	"github.com/pingcap/tiflow/pkg/quotes"
 	"github.com/pingcap/tiflow/pkg/security"
 	"github.com/pingcap/tiflow/pkg/sink"
 	"github.com/pingcap/tiflow/pkg/util"
@@ -71,31 +73,95 @@ const (
 	BackoffBaseDelay = 500 * time.Millisecond
 	// BackoffMaxDelay indicates the max delay time for retrying.
 	BackoffMaxDelay = 60 * time.Second
+	// defaultDMLRetryJitter is the default fraction of jitter applied to
+	// the backoff when retrying DMLs.
+	defaultDMLRetryJitter = 0.2
+	// defaultDMLMaxRetry is the default max number of tries to execute DMLs.
+	defaultDMLMaxRetry uint64 = 8
 
 	defaultBatchDMLEnable  = true
 	defaultMultiStmtEnable = true
+	// defaultBatchDMLRowThreshold is the default threshold of rows in a
+	// transaction, batch dml is used only when the row count reaches it.
+	defaultBatchDMLRowThreshold = 2
 
 	// defaultcachePrepStmts is the default value of cachePrepStmts
 	defaultCachePrepStmts = true
+
+	defaultEnableWriteSource = true
+
+	defaultPingBeforeFlush = false
+
+	defaultAnnotateCommitTs = false
+
+	// ZeroAutoIncrementKeyPolicyError returns an error when meeting a row whose
+	// auto increment handle key is zero.
+	ZeroAutoIncrementKeyPolicyError = "error"
+	// ZeroAutoIncrementKeyPolicySkip skips the rows whose auto increment handle
+	// key is zero.
+	ZeroAutoIncrementKeyPolicySkip = "skip"
+	// ZeroAutoIncrementKeyPolicyPassThrough writes the rows whose auto increment
+	// handle key is zero to the downstream as usual.
+	ZeroAutoIncrementKeyPolicyPassThrough = "pass-through"
+
+	defaultZeroAutoIncrementKeyPolicy = ZeroAutoIncrementKeyPolicyPassThrough
+
+	defaultDryRun = false
+
+	defaultStrictStartTsGrouping = false
+
+	defaultVerifyRowsAffected = false
+
+	// defaultMaxTxnSizeBytes 0 means flushes are not split by size.
+	defaultMaxTxnSizeBytes = 0
+
+	defaultCoalesceUpdates = false
+
+	// defaultMaxTxnBufferBytes 0 means buffered events are not flushed by size.
+	defaultMaxTxnBufferBytes = 0
+
+	// quoteStyleBacktick and quoteStyleDoubleQuote are the values of the
+	// `quote-style` parameter.
+	quoteStyleBacktick    = "backtick"
+	quoteStyleDoubleQuote = "double-quote"
 )
 
 type urlConfig struct {
-	WorkerCount                  *int    `form:"worker-count"`
-	MaxTxnRow                    *int    `form:"max-txn-row"`
-	MaxMultiUpdateRowSize        *int    `form:"max-multi-update-row-size"`
-	MaxMultiUpdateRowCount       *int    `form:"max-multi-update-row"`
-	TiDBTxnMode                  *string `form:"tidb-txn-mode"`
-	SSLCa                        *string `form:"ssl-ca"`
-	SSLCert                      *string `form:"ssl-cert"`
-	SSLKey                       *string `form:"ssl-key"`
-	SafeMode                     *bool   `form:"safe-mode"`
-	TimeZone                     *string `form:"time-zone"`
-	WriteTimeout                 *string `form:"write-timeout"`
-	ReadTimeout                  *string `form:"read-timeout"`
-	Timeout                      *string `form:"timeout"`
-	EnableBatchDML               *bool   `form:"batch-dml-enable"`
-	EnableMultiStatement         *bool   `form:"multi-stmt-enable"`
-	EnableCachePreparedStatement *bool   `form:"cache-prep-stmts"`
+	WorkerCount                  *int     `form:"worker-count"`
+	MaxTxnRow                    *int     `form:"max-txn-row"`
+	MaxMultiUpdateRowSize        *int     `form:"max-multi-update-row-size"`
+	MaxMultiUpdateRowCount       *int     `form:"max-multi-update-row"`
+	TiDBTxnMode                  *string  `form:"tidb-txn-mode"`
+	SSLCa                        *string  `form:"ssl-ca"`
+	SSLCert                      *string  `form:"ssl-cert"`
+	SSLKey                       *string  `form:"ssl-key"`
+	SafeMode                     *bool    `form:"safe-mode"`
+	TimeZone                     *string  `form:"time-zone"`
+	WriteTimeout                 *string  `form:"write-timeout"`
+	ReadTimeout                  *string  `form:"read-timeout"`
+	Timeout                      *string  `form:"timeout"`
+	EnableBatchDML               *bool    `form:"batch-dml-enable"`
+	BatchDMLRowThreshold         *int     `form:"batch-dml-row-threshold"`
+	EnableMultiStatement         *bool    `form:"multi-stmt-enable"`
+	EnableCachePreparedStatement *bool    `form:"cache-prep-stmts"`
+	DMLRetryJitter               *float64 `form:"dml-retry-jitter"`
+	DMLRetryOverrides            *string  `form:"dml-retry-overrides"`
+	DMLMaxRetry                  *int64   `form:"dml-max-retry"`
+	EnableWriteSource            *bool    `form:"enable-write-source"`
+	PingBeforeFlush              *bool    `form:"ping-before-flush"`
+	AnnotateCommitTs             *bool    `form:"annotate-commit-ts"`
+	ZeroAutoIncrementKeyPolicy   *string  `form:"zero-auto-increment-key-policy"`
+	DryRun                       *bool    `form:"dry-run"`
+	StrictStartTsGrouping        *bool    `form:"strict-start-ts-grouping"`
+	VerifyRowsAffected           *bool    `form:"verify-rows-affected"`
+	MaxTxnSizeBytes              *int64   `form:"max-txn-size-bytes"`
+	CoalesceUpdates              *bool    `form:"coalesce-updates"`
+	MaxOpenConns                 *int     `form:"max-open-conns"`
+	MaxIdleConns                 *int     `form:"max-idle-conns"`
+	MaxTxnBufferBytes            *int64   `form:"max-txn-buffer-bytes"`
+	QuoteStyle                   *string  `form:"quote-style"`
+	DMLFileDir                   *string  `form:"dml-file-dir"`
+	DMLFileMaxSize               *int64   `form:"dml-file-max-size"`
 }
 
 // Config is the configs for MySQL backend.
@@ -122,6 +188,76 @@ type Config struct {
 	BatchDMLEnable  bool
 	MultiStmtEnable bool
 	CachePrepStmts  bool
+	// BatchDMLRowThreshold is the threshold of rows in a transaction,
+	// batch dml is used only when the row count is greater than or equal to it.
+	BatchDMLRowThreshold int
+	// EnableWriteSource indicates whether to set the `tidb_cdc_write_source`
+	// session variable for each transaction when the downstream supports it.
+	// If it's false, the downstream treats the writes of TiCDC as normal writes,
+	// so it must not be false in BDR mode.
+	EnableWriteSource bool
+	// DMLRetryJitter is the fraction of random jitter applied to the backoff
+	// between DML retries, it must be in [0, 1].
+	DMLRetryJitter float64
+	// DMLRetryOverrides overrides whether a DML error with the given MySQL
+	// error code is retryable. Codes not in it use the built-in classification.
+	DMLRetryOverrides map[uint16]bool
+	// DMLMaxRetry is the max number of tries to execute DMLs, it's at least 1.
+	DMLMaxRetry uint64
+	// PingBeforeFlush indicates whether to ping the downstream before each
+	// flush, so that a dead connection can be detected and recycled early.
+	PingBeforeFlush bool
+	// AnnotateCommitTs indicates whether to prepend a comment with the commit
+	// ts to the first statement of each transaction, which helps correlating
+	// downstream slow query logs with upstream transactions.
+	AnnotateCommitTs bool
+	// ZeroAutoIncrementKeyPolicy controls how to handle inserted or deleted rows
+	// whose auto increment handle key is zero, it can be error, skip or pass-through.
+	ZeroAutoIncrementKeyPolicy string
+	// DryRun indicates whether to only build and log DMLs without executing
+	// them in the downstream, it's used to validate the SQL generation.
+	DryRun bool
+	// StrictStartTsGrouping indicates whether to record the startTs of every
+	// event in a flush and check that events of the same transaction are
+	// grouped together, which helps surfacing sorter issues in the upstream.
+	StrictStartTsGrouping bool
+	// VerifyRowsAffected indicates whether to check that the rows affected in
+	// the downstream match the rows of each flush, mismatches are only logged
+	// and counted. DMLs are executed one by one to get the rows affected, so
+	// multi-stmt-enable is disabled if it's not set explicitly.
+	VerifyRowsAffected bool
+	// MaxTxnSizeBytes is the approximate max size of a downstream transaction.
+	// A flush larger than it is split into several transactions at upstream
+	// transaction boundaries, 0 means no limit.
+	MaxTxnSizeBytes int64
+	// CoalesceUpdates indicates whether to collapse the consecutive updates to
+	// the same row in a flush into a single update with the final values.
+	CoalesceUpdates bool
+	// MaxOpenConns and MaxIdleConns limit the connection pool to the downstream,
+	// 0 means WorkerCount+1, see ConnPoolLimits. The prepared statement cache
+	// is disabled if MaxOpenConns is not greater than WorkerCount.
+	MaxOpenConns int
+	MaxIdleConns int
+	// MaxTxnBufferBytes is the approximate max size of the events buffered by
+	// a worker, a flush is requested once it's reached regardless of the row
+	// count, 0 means no limit.
+	MaxTxnBufferBytes int64
+	// QuoteStyle is the style of quoting identifiers in DMLs. Identifiers are
+	// quoted with backticks by default, double quotes are required if the
+	// downstream enables the ANSI_QUOTES SQL mode.
+	QuoteStyle quotes.QuoteStyle
+	// DMLFileDir is the local directory to write the DMLs to as SQL files
+	// instead of executing them in the downstream, e.g. for auditing. DDLs
+	// are still executed in the downstream. DMLs are executed in the
+	// downstream if it's empty.
+	DMLFileDir string
+	// DMLFileMaxSize is the approximate max size of each SQL file in
+	// DMLFileDir, 0 means files are never rotated.
+	DMLFileMaxSize int64
+	// TableRewriteFunc maps the upstream table of a DML to the downstream table
+	// it's written to. DMLs are written to the upstream table name if it's nil.
+	// It can only be set programmatically, not through the sink URI.
+	TableRewriteFunc func(src model.TableName) model.TableName
 }
 
 // NewConfig returns the default mysql backend config.
@@ -137,9 +273,24 @@ func NewConfig() *Config {
 		DialTimeout:            defaultDialTimeout,
 		SafeMode:               defaultSafeMode,
 		BatchDMLEnable:         defaultBatchDMLEnable,
+		BatchDMLRowThreshold:   defaultBatchDMLRowThreshold,
 		MultiStmtEnable:        defaultMultiStmtEnable,
 		CachePrepStmts:         defaultCachePrepStmts,
+		DMLRetryJitter:         defaultDMLRetryJitter,
+		DMLMaxRetry:            defaultDMLMaxRetry,
+		EnableWriteSource:      defaultEnableWriteSource,
+		PingBeforeFlush:        defaultPingBeforeFlush,
+		AnnotateCommitTs:       defaultAnnotateCommitTs,
+		DryRun:                 defaultDryRun,
+		StrictStartTsGrouping:  defaultStrictStartTsGrouping,
+		VerifyRowsAffected:     defaultVerifyRowsAffected,
+		MaxTxnSizeBytes:        defaultMaxTxnSizeBytes,
+		CoalesceUpdates:        defaultCoalesceUpdates,
+		MaxTxnBufferBytes:      defaultMaxTxnBufferBytes,
+		QuoteStyle:             quotes.QuoteStyleBacktick,
 		SourceID:               config.DefaultTiDBSourceID,
+
+		ZeroAutoIncrementKeyPolicy: defaultZeroAutoIncrementKeyPolicy,
 	}
 }
 
@@ -196,8 +347,66 @@ func (c *Config) Apply(
 		return err
 	}
 	getBatchDMLEnable(urlParameter, &c.BatchDMLEnable)
+	if err = getBatchDMLRowThreshold(urlParameter, &c.BatchDMLRowThreshold); err != nil {
+		return err
+	}
 	getMultiStmtEnable(urlParameter, &c.MultiStmtEnable)
 	getCachePrepStmts(urlParameter, &c.CachePrepStmts)
+	if err = getDMLRetryJitter(urlParameter, &c.DMLRetryJitter); err != nil {
+		return err
+	}
+	if err = getDMLRetryOverrides(urlParameter, &c.DMLRetryOverrides); err != nil {
+		return err
+	}
+	if err = getDMLMaxRetry(urlParameter, &c.DMLMaxRetry); err != nil {
+		return err
+	}
+	if err = getEnableWriteSource(urlParameter, util.GetOrZero(replicaConfig.BDRMode),
+		&c.EnableWriteSource); err != nil {
+		return err
+	}
+	getPingBeforeFlush(urlParameter, &c.PingBeforeFlush)
+	getAnnotateCommitTs(urlParameter, &c.AnnotateCommitTs)
+	if err = getZeroAutoIncrementKeyPolicy(urlParameter, &c.ZeroAutoIncrementKeyPolicy); err != nil {
+		return err
+	}
+	getDryRun(urlParameter, &c.DryRun)
+	getStrictStartTsGrouping(urlParameter, &c.StrictStartTsGrouping)
+	getVerifyRowsAffected(urlParameter, &c.VerifyRowsAffected)
+	if c.VerifyRowsAffected && c.MultiStmtEnable {
+		// The driver only reports the rows affected by the last statement
+		// executed in the multi statements way.
+		if urlParameter.EnableMultiStatement != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				errors.New("verify-rows-affected can't be enabled along with multi-stmt-enable"))
+		}
+		log.Info("multi-stmt-enable is disabled, since verify-rows-affected is enabled")
+		c.MultiStmtEnable = false
+	}
+	if err = getMaxTxnSizeBytes(urlParameter, &c.MaxTxnSizeBytes); err != nil {
+		return err
+	}
+	getCoalesceUpdates(urlParameter, &c.CoalesceUpdates)
+	if err = getMaxTxnBufferBytes(urlParameter, &c.MaxTxnBufferBytes); err != nil {
+		return err
+	}
+	if err = getQuoteStyle(urlParameter, &c.QuoteStyle); err != nil {
+		return err
+	}
+	if err = getDMLFile(urlParameter, &c.DMLFileDir, &c.DMLFileMaxSize); err != nil {
+		return err
+	}
+	if err = getConnPoolLimit(urlParameter.MaxOpenConns, "max-open-conns", &c.MaxOpenConns); err != nil {
+		return err
+	}
+	if err = getConnPoolLimit(urlParameter.MaxIdleConns, "max-idle-conns", &c.MaxIdleConns); err != nil {
+		return err
+	}
+	if maxOpen, maxIdle := c.ConnPoolLimits(); maxIdle > maxOpen {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid max-idle-conns %d, which must not be greater than max-open-conns %d",
+				maxIdle, maxOpen))
+	}
 	c.ForceReplicate = replicaConfig.ForceReplicate
 
 	// Note(dongmen): The TiDBSourceID should never be 0 here, but we have found that
@@ -364,6 +573,11 @@ func getTiDBTxnMode(values *urlConfig, mode *string) {
 	}
 }
 
+// getSSLCA registers a TLS config for the changefeed to the MySQL driver.
+// The client certificate is reloaded from ssl-cert and ssl-key whenever the
+// driver dials a new connection, so replacing the files in place rotates the
+// certificate without restarting the changefeed. Existing connections keep
+// the old certificate until they are closed.
 func getSSLCA(values *urlConfig, changefeedID model.ChangeFeedID, tls *string) error {
 	if values.SSLCa == nil || len(*values.SSLCa) == 0 {
 		return nil
@@ -472,6 +686,19 @@ func getBatchDMLEnable(values *urlConfig, batchDMLEnable *bool) {
 	}
 }
 
+func getBatchDMLRowThreshold(values *urlConfig, threshold *int) error {
+	if values.BatchDMLRowThreshold == nil {
+		return nil
+	}
+	c := *values.BatchDMLRowThreshold
+	if c < 0 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid batch-dml-row-threshold %d, which must be greater than or equal to 0", c))
+	}
+	*threshold = c
+	return nil
+}
+
 func getMultiStmtEnable(values *urlConfig, multiStmtEnable *bool) {
 	if values.EnableMultiStatement != nil {
 		*multiStmtEnable = *values.EnableMultiStatement
@@ -483,3 +710,222 @@ func getCachePrepStmts(values *urlConfig, cachePrepStmts *bool) {
 		*cachePrepStmts = *values.EnableCachePreparedStatement
 	}
 }
+
+func getEnableWriteSource(values *urlConfig, bdrMode bool, enableWriteSource *bool) error {
+	if values.EnableWriteSource == nil {
+		return nil
+	}
+	// The write source is how the downstream TiDB tells the writes of TiCDC
+	// from the others in BDR mode, replicating them back causes a loop.
+	if bdrMode && !*values.EnableWriteSource {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			errors.New("enable-write-source can't be false in bdr mode"))
+	}
+	*enableWriteSource = *values.EnableWriteSource
+	return nil
+}
+
+func getPingBeforeFlush(values *urlConfig, pingBeforeFlush *bool) {
+	if values.PingBeforeFlush != nil {
+		*pingBeforeFlush = *values.PingBeforeFlush
+	}
+}
+
+func getAnnotateCommitTs(values *urlConfig, annotateCommitTs *bool) {
+	if values.AnnotateCommitTs != nil {
+		*annotateCommitTs = *values.AnnotateCommitTs
+	}
+}
+
+func getZeroAutoIncrementKeyPolicy(values *urlConfig, policy *string) error {
+	if values.ZeroAutoIncrementKeyPolicy == nil {
+		return nil
+	}
+	p := strings.ToLower(*values.ZeroAutoIncrementKeyPolicy)
+	switch p {
+	case ZeroAutoIncrementKeyPolicyError, ZeroAutoIncrementKeyPolicySkip,
+		ZeroAutoIncrementKeyPolicyPassThrough:
+		*policy = p
+		return nil
+	}
+	return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+		fmt.Errorf("invalid zero-auto-increment-key-policy %s, "+
+			"which must be one of error, skip and pass-through", p))
+}
+
+func getDryRun(values *urlConfig, dryRun *bool) {
+	if values.DryRun != nil {
+		*dryRun = *values.DryRun
+	}
+}
+
+func getStrictStartTsGrouping(values *urlConfig, strict *bool) {
+	if values.StrictStartTsGrouping != nil {
+		*strict = *values.StrictStartTsGrouping
+	}
+}
+
+func getVerifyRowsAffected(values *urlConfig, verify *bool) {
+	if values.VerifyRowsAffected != nil {
+		*verify = *values.VerifyRowsAffected
+	}
+}
+
+func getMaxTxnSizeBytes(values *urlConfig, maxTxnSize *int64) error {
+	if values.MaxTxnSizeBytes == nil {
+		return nil
+	}
+
+	c := *values.MaxTxnSizeBytes
+	if c < 0 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid max-txn-size-bytes %d, which must not be negative", c))
+	}
+	*maxTxnSize = c
+	return nil
+}
+
+func getMaxTxnBufferBytes(values *urlConfig, maxBufferBytes *int64) error {
+	if values.MaxTxnBufferBytes == nil {
+		return nil
+	}
+
+	c := *values.MaxTxnBufferBytes
+	if c < 0 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid max-txn-buffer-bytes %d, which must not be negative", c))
+	}
+	*maxBufferBytes = c
+	return nil
+}
+
+func getQuoteStyle(values *urlConfig, quoteStyle *quotes.QuoteStyle) error {
+	if values.QuoteStyle == nil {
+		return nil
+	}
+	s := strings.ToLower(*values.QuoteStyle)
+	switch s {
+	case quoteStyleBacktick:
+		*quoteStyle = quotes.QuoteStyleBacktick
+		return nil
+	case quoteStyleDoubleQuote:
+		*quoteStyle = quotes.QuoteStyleDoubleQuote
+		return nil
+	}
+	return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+		fmt.Errorf("invalid quote-style %s, which must be one of backtick and double-quote", s))
+}
+
+func getDMLFile(values *urlConfig, dir *string, maxSize *int64) error {
+	if values.DMLFileDir != nil {
+		*dir = *values.DMLFileDir
+	}
+	if values.DMLFileMaxSize == nil {
+		return nil
+	}
+
+	c := *values.DMLFileMaxSize
+	if c < 0 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid dml-file-max-size %d, which must not be negative", c))
+	}
+	if *dir == "" {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			errors.New("dml-file-max-size takes no effect unless dml-file-dir is set"))
+	}
+	*maxSize = c
+	return nil
+}
+
+// IsDMLFileSink returns whether the DMLs are written to SQL files by the
+// `dml-file-dir` parameter instead of being executed in the downstream.
+func IsDMLFileSink(sinkURI *url.URL) bool {
+	return sinkURI.Query().Get("dml-file-dir") != ""
+}
+
+func getCoalesceUpdates(values *urlConfig, coalesce *bool) {
+	if values.CoalesceUpdates != nil {
+		*coalesce = *values.CoalesceUpdates
+	}
+}
+
+func getConnPoolLimit(value *int, name string, limit *int) error {
+	if value == nil {
+		return nil
+	}
+	c := *value
+	if c <= 0 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid %s %d, which must be greater than 0", name, c))
+	}
+	*limit = c
+	return nil
+}
+
+// ConnPoolLimits returns the max open and idle connections to the downstream.
+// By default, one more connection than WorkerCount is used, since a worker may
+// need another connection to prepare statements while holding a transaction.
+func (c *Config) ConnPoolLimits() (maxOpen, maxIdle int) {
+	maxOpen, maxIdle = c.WorkerCount+1, c.WorkerCount+1
+	if c.MaxOpenConns > 0 {
+		maxOpen = c.MaxOpenConns
+		maxIdle = c.MaxOpenConns
+	}
+	if c.MaxIdleConns > 0 {
+		maxIdle = c.MaxIdleConns
+	}
+	return maxOpen, maxIdle
+}
+
+func getDMLRetryJitter(values *urlConfig, jitter *float64) error {
+	if values.DMLRetryJitter == nil {
+		return nil
+	}
+	j := *values.DMLRetryJitter
+	if j < 0 || j > 1 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid dml-retry-jitter %v, which must be in [0, 1]", j))
+	}
+	*jitter = j
+	return nil
+}
+
+func getDMLMaxRetry(values *urlConfig, maxRetry *uint64) error {
+	if values.DMLMaxRetry == nil {
+		return nil
+	}
+	c := *values.DMLMaxRetry
+	if c < 1 {
+		return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+			fmt.Errorf("invalid dml-max-retry %d, which must be greater than or equal to 1", c))
+	}
+	*maxRetry = uint64(c)
+	return nil
+}
+
+// getDMLRetryOverrides parses the retry classification overrides, which are
+// in the format of `code:retryable,code:retryable`, e.g. `1062:true,8028:false`.
+func getDMLRetryOverrides(values *urlConfig, overrides *map[uint16]bool) error {
+	if values.DMLRetryOverrides == nil || *values.DMLRetryOverrides == "" {
+		return nil
+	}
+	result := make(map[uint16]bool)
+	for _, item := range strings.Split(*values.DMLRetryOverrides, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 2 {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig,
+				fmt.Errorf("invalid dml-retry-overrides item %q, which must be in the format of code:retryable", item))
+		}
+		code, err := strconv.ParseUint(parts[0], 10, 16)
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		retryable, err := strconv.ParseBool(parts[1])
+		if err != nil {
+			return cerror.WrapError(cerror.ErrMySQLInvalidConfig, err)
+		}
+		result[uint16(code)] = retryable
+	}
+	*overrides = result
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	dmysql "github.com/go-sql-driver/mysql"
 	"github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/pkg/config"
+	"github.com/pingcap/tiflow/pkg/quotes"
 	"github.com/pingcap/tiflow/pkg/util"
 	"github.com/stretchr/testify/require"
 )
@@ -240,6 +241,116 @@ func TestParseSinkURIOverride(t *testing.T) {
 		checker: func(sp *Config) {
 			require.EqualValues(t, sp.CachePrepStmts, false)
 		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?dml-retry-jitter=0.5",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.DMLRetryJitter, 0.5)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?dml-max-retry=3",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.DMLMaxRetry, 3)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?batch-dml-row-threshold=3",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.BatchDMLRowThreshold, 3)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?dml-retry-overrides=1062:true,8028:false",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.DMLRetryOverrides, map[uint16]bool{1062: true, 8028: false})
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?ping-before-flush=true",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.PingBeforeFlush, true)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?annotate-commit-ts=true",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.AnnotateCommitTs, true)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?zero-auto-increment-key-policy=Skip",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.ZeroAutoIncrementKeyPolicy, ZeroAutoIncrementKeyPolicySkip)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?dry-run=true",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.DryRun, true)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?strict-start-ts-grouping=true",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.StrictStartTsGrouping, true)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?verify-rows-affected=true",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.VerifyRowsAffected, true)
+			// multi-stmt-enable is true by default.
+			require.EqualValues(t, sp.MultiStmtEnable, false)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?verify-rows-affected=true&multi-stmt-enable=false",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.VerifyRowsAffected, true)
+			require.EqualValues(t, sp.MultiStmtEnable, false)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?max-txn-size-bytes=1048576",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.MaxTxnSizeBytes, 1048576)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?max-txn-buffer-bytes=67108864",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.MaxTxnBufferBytes, 67108864)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?quote-style=double-quote",
+		checker: func(sp *Config) {
+			require.Equal(t, quotes.QuoteStyleDoubleQuote, sp.QuoteStyle)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?dml-file-dir=/tmp/dml&dml-file-max-size=1048576",
+		checker: func(sp *Config) {
+			require.Equal(t, "/tmp/dml", sp.DMLFileDir)
+			require.EqualValues(t, 1048576, sp.DMLFileMaxSize)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?coalesce-updates=true",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.CoalesceUpdates, true)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?worker-count=8",
+		checker: func(sp *Config) {
+			maxOpen, maxIdle := sp.ConnPoolLimits()
+			require.Equal(t, 9, maxOpen)
+			require.Equal(t, 9, maxIdle)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?worker-count=8&max-open-conns=4",
+		checker: func(sp *Config) {
+			maxOpen, maxIdle := sp.ConnPoolLimits()
+			require.Equal(t, 4, maxOpen)
+			require.Equal(t, 4, maxIdle)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?worker-count=8&max-open-conns=4&max-idle-conns=2",
+		checker: func(sp *Config) {
+			maxOpen, maxIdle := sp.ConnPoolLimits()
+			require.Equal(t, 4, maxOpen)
+			require.Equal(t, 2, maxIdle)
+		},
+	}, {
+		uri: "mysql://127.0.0.1:3306/?enable-write-source=false",
+		checker: func(sp *Config) {
+			require.EqualValues(t, sp.EnableWriteSource, false)
+		},
 	}}
 	var uri *url.URL
 	var err error
@@ -277,6 +388,24 @@ func TestParseSinkURIBadQueryString(t *testing.T) {
 		"mysql://127.0.0.1:3306/?write-timeout=badduration",
 		"mysql://127.0.0.1:3306/?read-timeout=badduration",
 		"mysql://127.0.0.1:3306/?timeout=badduration",
+		"mysql://127.0.0.1:3306/?dml-retry-jitter=-0.1",
+		"mysql://127.0.0.1:3306/?dml-retry-jitter=1.5",
+		"mysql://127.0.0.1:3306/?dml-max-retry=0",
+		"mysql://127.0.0.1:3306/?batch-dml-row-threshold=-1",
+		"mysql://127.0.0.1:3306/?dml-retry-overrides=1062",
+		"mysql://127.0.0.1:3306/?dml-retry-overrides=abc:true",
+		"mysql://127.0.0.1:3306/?dml-retry-overrides=1062:maybe",
+		"mysql://127.0.0.1:3306/?zero-auto-increment-key-policy=ignore",
+		"mysql://127.0.0.1:3306/?max-txn-size-bytes=-1",
+		"mysql://127.0.0.1:3306/?max-txn-buffer-bytes=-1",
+		"mysql://127.0.0.1:3306/?quote-style=bracket",
+		"mysql://127.0.0.1:3306/?verify-rows-affected=true&multi-stmt-enable=true",
+		"mysql://127.0.0.1:3306/?dml-file-dir=/tmp/dml&dml-file-max-size=-1",
+		"mysql://127.0.0.1:3306/?dml-file-max-size=1048576",
+		"mysql://127.0.0.1:3306/?max-open-conns=0",
+		"mysql://127.0.0.1:3306/?max-idle-conns=-1",
+		"mysql://127.0.0.1:3306/?max-open-conns=2&max-idle-conns=3",
+		"mysql://127.0.0.1:3306/?worker-count=2&max-idle-conns=4",
 	}
 	var uri *url.URL
 	var err error
@@ -294,6 +423,24 @@ func TestParseSinkURIBadQueryString(t *testing.T) {
 	}
 }
 
+func TestApplyEnableWriteSourceInBDRMode(t *testing.T) {
+	t.Parallel()
+
+	replicaConfig := config.GetDefaultReplicaConfig()
+	replicaConfig.BDRMode = util.AddressOf(true)
+	uri, err := url.Parse("mysql://127.0.0.1:3306/?enable-write-source=false")
+	require.NoError(t, err)
+	cfg := NewConfig()
+	err = cfg.Apply("UTC", model.DefaultChangeFeedID("changefeed-01"), uri, replicaConfig)
+	require.ErrorContains(t, err, "enable-write-source can't be false in bdr mode")
+
+	uri, err = url.Parse("mysql://127.0.0.1:3306/?enable-write-source=true")
+	require.NoError(t, err)
+	cfg = NewConfig()
+	require.NoError(t, cfg.Apply("UTC", model.DefaultChangeFeedID("changefeed-01"), uri, replicaConfig))
+	require.True(t, cfg.EnableWriteSource)
+}
+
 func TestCheckTiDBVariable(t *testing.T) {
 	t.Parallel()
 
@@ -294,12 +294,18 @@ func CheckIfBDRModeIsSupported(ctx context.Context, db *sql.DB) (bool, error) {
 	if err != nil || !isTiDB {
 		return false, err
 	}
+	return checkWriteSourceSupported(ctx, db)
+}
+
+// checkWriteSourceSupported checks if the downstream TiDB supports setting the
+// `tidb_cdc_write_source` variable.
+func checkWriteSourceSupported(ctx context.Context, db *sql.DB) (bool, error) {
 	testSourceID := 1
 	// downstream is TiDB, set system variables.
 	// We should always try to set this variable, and ignore the error if
 	// downstream does not support this variable, it is by design.
 	query := fmt.Sprintf("SET SESSION %s = %d", "tidb_cdc_write_source", testSourceID)
-	_, err = db.ExecContext(ctx, query)
+	_, err := db.ExecContext(ctx, query)
 	if err != nil {
 		if mysqlErr, ok := errors.Cause(err).(*dmysql.MySQLError); ok &&
 			mysqlErr.Number == tmysql.ErrUnknownSystemVariable {
@@ -312,13 +318,20 @@ func CheckIfBDRModeIsSupported(ctx context.Context, db *sql.DB) (bool, error) {
 
 // CheckIsTiDB checks if the downstream is TiDB.
 func CheckIsTiDB(ctx context.Context, db *sql.DB) (bool, error) {
+	isTiDB, _ := checkIsTiDB(ctx, db)
+	return isTiDB, nil
+}
+
+// checkIsTiDB is the same as CheckIsTiDB, but it also returns the error of the
+// probe, which tells whether the downstream is not TiDB or it's unreachable.
+func checkIsTiDB(ctx context.Context, db *sql.DB) (bool, error) {
 	var tidbVer string
 	// check if downstream is TiDB
 	row := db.QueryRowContext(ctx, "select tidb_version()")
 	err := row.Scan(&tidbVer)
 	if err != nil {
 		log.Error("check tidb version error", zap.Error(err))
-		return false, nil
+		return false, err
 	}
 	return true, nil
 }
@@ -334,6 +347,29 @@ func QueryMaxPreparedStmtCount(ctx context.Context, db *sql.DB) (int, error) {
 	return int(maxPreparedStmtCount.Int32), err
 }
 
+// SessionVariables is the session variables negotiated with the downstream,
+// which affect how the written values are interpreted.
+type SessionVariables struct {
+	SQLMode             string
+	CharacterSetClient  string
+	CollationConnection string
+}
+
+// QuerySessionVariables gets the session variables of a connection of db.
+func QuerySessionVariables(ctx context.Context, db *sql.DB) (SessionVariables, error) {
+	row := db.QueryRowContext(ctx,
+		"SELECT @@SESSION.sql_mode, @@SESSION.character_set_client, @@SESSION.collation_connection;")
+	var sqlMode, charset, collation sql.NullString
+	if err := row.Scan(&sqlMode, &charset, &collation); err != nil {
+		return SessionVariables{}, cerror.WrapError(cerror.ErrMySQLQueryError, err)
+	}
+	return SessionVariables{
+		SQLMode:             sqlMode.String,
+		CharacterSetClient:  charset.String,
+		CollationConnection: collation.String,
+	}, nil
+}
+
 // QueryMaxAllowedPacket gets the value of max_allowed_packet
 func QueryMaxAllowedPacket(ctx context.Context, db *sql.DB) (int64, error) {
 	row := db.QueryRowContext(ctx, "select @@global.max_allowed_packet;")
@@ -17,7 +17,6 @@ import (
 	"strings"
 
 	"github.com/pingcap/log"
-	"github.com/pingcap/tiflow/pkg/quotes"
 	"go.uber.org/zap"
 )
 
@@ -80,7 +79,7 @@ func GenDeleteSQL(changes ...*RowChange) (string, []interface{}) {
 	var buf strings.Builder
 	buf.Grow(1024)
 	buf.WriteString("DELETE FROM ")
-	buf.WriteString(first.targetTable.QuoteString())
+	buf.WriteString(first.quotedTargetTable())
 	buf.WriteString(" WHERE (")
 
 	whereColumns, _ := first.whereColumnsAndValues()
@@ -111,7 +110,7 @@ func GenUpdateSQL(changes ...*RowChange) (string, []any) {
 	// Generate UPDATE `db`.`table` SET
 	first := changes[0]
 	buf.WriteString("UPDATE ")
-	buf.WriteString(first.targetTable.QuoteString())
+	buf.WriteString(first.quotedTargetTable())
 	buf.WriteString(" SET ")
 
 	// Pre-generate essential sub statements used after WHEN, WHERE.
@@ -142,7 +141,7 @@ func GenUpdateSQL(changes ...*RowChange) (string, []any) {
 			buf.WriteString(", ")
 		}
 
-		buf.WriteString(quotes.QuoteName(column.Name.String()) + "=CASE")
+		buf.WriteString(first.quoteStyle.QuoteName(column.Name.String()) + "=CASE")
 		for i := range changes {
 			buf.WriteString(" WHEN ")
 			buf.WriteString(whenCaseStmts[i])
@@ -231,7 +230,7 @@ func GenInsertSQL(tp DMLType, changes ...*RowChange) (string, []interface{}) {
 	} else {
 		buf.WriteString("INSERT INTO ")
 	}
-	buf.WriteString(first.targetTable.QuoteString())
+	buf.WriteString(first.quotedTargetTable())
 	buf.WriteString(" (")
 	columnNum := 0
 	var skipColIdx []int
@@ -245,7 +244,7 @@ func GenInsertSQL(tp DMLType, changes ...*RowChange) (string, []interface{}) {
 			buf.WriteByte(',')
 		}
 		columnNum++
-		buf.WriteString(quotes.QuoteName(col.Name.O))
+		buf.WriteString(first.quoteStyle.QuoteName(col.Name.O))
 	}
 	buf.WriteString(") VALUES ")
 	holder := valuesHolder(columnNum)
@@ -271,7 +270,7 @@ func GenInsertSQL(tp DMLType, changes ...*RowChange) (string, []interface{}) {
 			}
 			writtenFirstCol = true
 
-			colName := quotes.QuoteName(col.Name.O)
+			colName := first.quoteStyle.QuoteName(col.Name.O)
 			buf.WriteString(colName + "=VALUES(" + colName + ")")
 		}
 	}
@@ -71,6 +71,7 @@ type RowChange struct {
 	whereHandle *WhereHandle
 
 	approximateDataSize int64
+	quoteStyle          quotes.QuoteStyle
 }
 
 // NewRowChange creates a new RowChange.
@@ -217,6 +218,17 @@ func (r *RowChange) SetApproximateDataSize(approximateDataSize int64) {
 	r.approximateDataSize = approximateDataSize
 }
 
+// SetQuoteStyle sets the style of quoting identifiers in the generated SQLs,
+// identifiers are quoted with backticks by default.
+func (r *RowChange) SetQuoteStyle(quoteStyle quotes.QuoteStyle) {
+	r.quoteStyle = quoteStyle
+}
+
+// quotedTargetTable returns the target table quoted by the quote style.
+func (r *RowChange) quotedTargetTable() string {
+	return r.quoteStyle.QuoteSchema(r.targetTable.Schema, r.targetTable.Table)
+}
+
 func (r *RowChange) lazyInitWhereHandle() {
 	if r.whereHandle != nil {
 		return
@@ -264,7 +276,7 @@ func (r *RowChange) genWhere(buf *strings.Builder) []interface{} {
 		if i != 0 {
 			buf.WriteString(" AND ")
 		}
-		buf.WriteString(quotes.QuoteName(col))
+		buf.WriteString(r.quoteStyle.QuoteName(col))
 		if whereValues[i] == nil {
 			buf.WriteString(" IS ?")
 		} else {
@@ -285,7 +297,7 @@ func (r *RowChange) genDeleteSQL() (string, []interface{}) {
 	var buf strings.Builder
 	buf.Grow(1024)
 	buf.WriteString("DELETE FROM ")
-	buf.WriteString(r.targetTable.QuoteString())
+	buf.WriteString(r.quotedTargetTable())
 	buf.WriteString(" WHERE ")
 	whereArgs := r.genWhere(&buf)
 	buf.WriteString(" LIMIT 1")
@@ -304,7 +316,7 @@ func (r *RowChange) genUpdateSQL() (string, []interface{}) {
 	var buf strings.Builder
 	buf.Grow(2048)
 	buf.WriteString("UPDATE ")
-	buf.WriteString(r.targetTable.QuoteString())
+	buf.WriteString(r.quotedTargetTable())
 	buf.WriteString(" SET ")
 
 	args := make([]interface{}, 0, len(r.preValues)+len(r.postValues))
@@ -318,7 +330,7 @@ func (r *RowChange) genUpdateSQL() (string, []interface{}) {
 			buf.WriteString(", ")
 		}
 		writtenFirstCol = true
-		fmt.Fprintf(&buf, "%s = ?", quotes.QuoteName(col.Name.O))
+		fmt.Fprintf(&buf, "%s = ?", r.quoteStyle.QuoteName(col.Name.O))
 		args = append(args, r.postValues[i])
 	}
 
@@ -26,6 +26,7 @@ import (
 	cdcmodel "github.com/pingcap/tiflow/cdc/model"
 	"github.com/pingcap/tiflow/dm/pkg/log"
 	"github.com/pingcap/tiflow/dm/pkg/utils"
+	"github.com/pingcap/tiflow/pkg/quotes"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -365,3 +366,28 @@ func TestGenInsert(t *testing.T) {
 		require.Equal(t, c.expectedArgs, args)
 	}
 }
+
+func TestGenSQLWithDoubleQuote(t *testing.T) {
+	t.Parallel()
+
+	source := &cdcmodel.TableName{Schema: "db", Table: "tb1"}
+	ti := mockTableInfo(t, "CREATE TABLE tb1 (id INT PRIMARY KEY, name INT)")
+
+	change := NewRowChange(source, nil, nil, []interface{}{1, 2}, ti, nil, nil)
+	change.SetQuoteStyle(quotes.QuoteStyleDoubleQuote)
+	sql, args := change.GenSQL(DMLInsert)
+	require.Equal(t, `INSERT INTO "db"."tb1" ("id","name") VALUES (?,?)`, sql)
+	require.Equal(t, []interface{}{1, 2}, args)
+
+	change = NewRowChange(source, nil, []interface{}{1, 2}, []interface{}{1, 3}, ti, nil, nil)
+	change.SetQuoteStyle(quotes.QuoteStyleDoubleQuote)
+	sql, args = change.GenSQL(DMLUpdate)
+	require.Equal(t, `UPDATE "db"."tb1" SET "id" = ?, "name" = ? WHERE "id" = ? LIMIT 1`, sql)
+	require.Equal(t, []interface{}{1, 3, 1}, args)
+
+	change = NewRowChange(source, nil, []interface{}{1, 2}, nil, ti, nil, nil)
+	change.SetQuoteStyle(quotes.QuoteStyleDoubleQuote)
+	sql, args = change.GenSQL(DMLDelete)
+	require.Equal(t, `DELETE FROM "db"."tb1" WHERE "id" = ? LIMIT 1`, sql)
+	require.Equal(t, []interface{}{1}, args)
+}
@@ -104,6 +104,24 @@ func SetServiceGCSafepoint(
 	return
 }
 
+// GetGCSafePoint returns the current GC safe point of the upstream. PD never
+// moves the GC safe point backward, so updating it to 0 leaves it unchanged.
+func GetGCSafePoint(ctx context.Context, pdCli pd.Client) (safePoint uint64, err error) {
+	err = retry.Do(ctx,
+		func() error {
+			var err1 error
+			safePoint, err1 = pdCli.UpdateGCSafePoint(ctx, 0)
+			if err1 != nil {
+				log.Warn("Get GC safepoint failed, retry later", zap.Error(err1))
+			}
+			return err1
+		},
+		retry.WithBackoffBaseDelay(gcServiceBackoffDelay),
+		retry.WithMaxTries(gcServiceMaxRetries),
+		retry.WithIsRetryableErr(cerrors.IsRetryableError))
+	return
+}
+
 // RemoveServiceGCSafepoint removes a service safepoint from PD.
 func RemoveServiceGCSafepoint(ctx context.Context, pdCli pd.Client, serviceID string) error {
 	// Set TTL to 0 second to delete the service safe point.
@@ -29,6 +29,7 @@ type MockPDClient struct {
 	GetAllStoresFunc func(ctx context.Context, opts ...pd.GetStoreOption) ([]*metapb.Store, error)
 
 	UpdateServiceGCSafePointFunc func(ctx context.Context, serviceID string, ttl int64, safePoint uint64) (uint64, error)
+	UpdateGCSafePointFunc        func(ctx context.Context, safePoint uint64) (uint64, error)
 }
 
 // UpdateServiceGCSafePoint implements pd.Client.UpdateServiceGCSafePoint.
@@ -36,6 +37,11 @@ func (m *MockPDClient) UpdateServiceGCSafePoint(ctx context.Context, serviceID s
 	return m.UpdateServiceGCSafePointFunc(ctx, serviceID, ttl, safePoint)
 }
 
+// UpdateGCSafePoint implements pd.Client.UpdateGCSafePoint.
+func (m *MockPDClient) UpdateGCSafePoint(ctx context.Context, safePoint uint64) (uint64, error) {
+	return m.UpdateGCSafePointFunc(ctx, safePoint)
+}
+
 // GetTS implements pd.Client.GetTS.
 func (m *MockPDClient) GetTS(ctx context.Context) (int64, int64, error) {
 	return oracle.GetPhysical(time.Now()), 0, nil